@@ -0,0 +1,38 @@
+/*
+Copyright © 2022 - 2025 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup_restore_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/rancher-sandbox/ele-testhelpers/kubectl"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var _ = Describe("BackupRestoreProvisioning", func() {
+	k := kubectl.New()
+
+	It("Do a full backup/restore test", func() {
+		f := newFixture()
+		f.SetQaseID(164) // Report to Qase
+		BackupRestoreChecks(f, k)
+	})
+
+	It("Do a full encrypted backup/restore test", func() {
+		f := newFixture()
+		f.SetQaseID(helpers.QaseCasePending)
+		EncryptedBackupRestoreChecks(f, k)
+	})
+})
@@ -1,12 +1,24 @@
 package helper
 
 import (
+	"encoding/json"
 	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/Masterminds/semver/v3"
+	sdkerrors "github.com/aliyun/alibaba-cloud-sdk-go/sdk/errors"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/cs"
 	"github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 	"github.com/rancher-sandbox/ele-testhelpers/tools"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
 	"github.com/rancher/shepherd/clients/rancher"
@@ -34,6 +46,27 @@ func CreateACKHostedCluster(client *rancher.Client, displayName, cloudCredential
 
 }
 
+// ImportACKHostedCluster imports the ACK cluster clusterID of region into Rancher
+func ImportACKHostedCluster(client *rancher.Client, displayName, cloudCredentialID, clusterID, region string) (*management.Cluster, error) {
+	cluster := &management.Cluster{
+		DockerRootDir: "/var/lib/docker",
+		ACKConfig: &management.ACKClusterConfigSpec{
+			AliyunCredentialSecret: cloudCredentialID,
+			ClusterID:              clusterID,
+			Name:                   displayName,
+			Imported:               true,
+			RegionID:               region,
+		},
+		Name: displayName,
+	}
+
+	clusterResp, err := client.Management.Cluster.Create(cluster)
+	if err != nil {
+		return nil, err
+	}
+	return clusterResp, err
+}
+
 func ListACKAllVersions(client *rancher.Client) (allVersions []string, err error) {
 	serverVersion, err := helpers.GetRancherServerVersion(client)
 	if err != nil {
@@ -55,6 +88,39 @@ func ListACKAllVersions(client *rancher.Client) (allVersions []string, err error
 	return helpers.FilterUIUnsupportedVersions(allVersions, client), nil
 }
 
+// ListACKAvailableVersions lists the UI supported ACK versions the cluster can be upgraded to, latest first;
+// ACK only upgrades one minor version at a time, so the versions of later minors are skipped
+func ListACKAvailableVersions(client *rancher.Client, cluster *management.Cluster) (availableVersions []string, err error) {
+	currentVersion, err := semver.NewVersion(cluster.Version.GitVersion)
+	if err != nil {
+		return
+	}
+	allVersions, err := ListACKAllVersions(client)
+	if err != nil {
+		return
+	}
+
+	// the vendor suffix of the versions is ignored, it is not relevant to the upgrade path
+	current := semver.New(currentVersion.Major(), currentVersion.Minor(), currentVersion.Patch(), "", "")
+	var validVersions []*semver.Version
+	for _, version := range allVersions {
+		v, err := semver.NewVersion(version)
+		if err != nil {
+			continue
+		}
+		if v.Minor() > current.Minor()+1 || !semver.New(v.Major(), v.Minor(), v.Patch(), "", "").GreaterThan(current) {
+			continue
+		}
+		validVersions = append(validVersions, v)
+	}
+
+	sort.Sort(sort.Reverse(semver.Collection(validVersions)))
+	for _, v := range validVersions {
+		availableVersions = append(availableVersions, v.Original())
+	}
+	return
+}
+
 // GetK8sVersion returns the k8s version to be used by the test;
 // this value can either be a variant of envvar DOWNSTREAM_K8S_MINOR_VERSION or the highest available version
 // or second-highest minor version in case of upgrade scenarios
@@ -249,3 +315,364 @@ func DeleteNodePool(cluster *management.Cluster, client *rancher.Client, wait, c
 	}
 	return cluster, nil
 }
+
+// UpdateCluster is a generic function to update a cluster
+func UpdateCluster(cluster *management.Cluster, client *rancher.Client, updateFunc func(*management.Cluster)) (*management.Cluster, error) {
+	upgradedCluster := cluster
+
+	updateFunc(upgradedCluster)
+
+	return client.Management.Cluster.Update(cluster, &upgradedCluster)
+}
+
+// <==============================ACK on Alibaba Cloud==============================>
+
+// ackCloudClient returns a Container Service client for region authenticated with ALIYUN_ACCESS_KEY_ID and ALIYUN_ACCESS_KEY_SECRET
+func ackCloudClient(region string) *cs.Client {
+	client, err := cs.NewClientWithAccessKey(region, os.Getenv("ALIYUN_ACCESS_KEY_ID"), os.Getenv("ALIYUN_ACCESS_KEY_SECRET"))
+	Expect(err).To(BeNil())
+	return client
+}
+
+// ackCreateClusterRequest is the body of the CreateCluster API
+type ackCreateClusterRequest struct {
+	Name                 string            `json:"name"`
+	ClusterType          string            `json:"cluster_type"`
+	ClusterSpec          string            `json:"cluster_spec,omitempty"`
+	KubernetesVersion    string            `json:"kubernetes_version,omitempty"`
+	RegionID             string            `json:"region_id"`
+	VpcID                string            `json:"vpcid,omitempty"`
+	VswitchIDs           []string          `json:"vswitch_ids,omitempty"`
+	PodVswitchIDs        []string          `json:"pod_vswitch_ids,omitempty"`
+	ContainerCidr        string            `json:"container_cidr,omitempty"`
+	ServiceCidr          string            `json:"service_cidr,omitempty"`
+	NodeCidrMask         string            `json:"node_cidr_mask,omitempty"`
+	ProxyMode            string            `json:"proxy_mode,omitempty"`
+	SnatEntry            bool              `json:"snat_entry"`
+	EndpointPublicAccess bool              `json:"endpoint_public_access"`
+	KeyPair              string            `json:"key_pair,omitempty"`
+	LoginPassword        string            `json:"login_password,omitempty"`
+	ResourceGroupID      string            `json:"resource_group_id,omitempty"`
+	SecurityGroupID      string            `json:"security_group_id,omitempty"`
+	Addons               []ack.Addon       `json:"addons,omitempty"`
+	Tags                 []ackTag          `json:"tags,omitempty"`
+	Nodepools            []ackNodepoolSpec `json:"nodepools,omitempty"`
+}
+
+type ackTag struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type ackNodepoolSpec struct {
+	NodepoolInfo struct {
+		Name string `json:"name"`
+	} `json:"nodepool_info"`
+	ScalingGroup struct {
+		VswitchIDs         []string `json:"vswitch_ids,omitempty"`
+		InstanceTypes      []string `json:"instance_types"`
+		InstanceChargeType string   `json:"instance_charge_type,omitempty"`
+		SystemDiskCategory string   `json:"system_disk_category,omitempty"`
+		SystemDiskSize     int64    `json:"system_disk_size,omitempty"`
+		KeyPair            string   `json:"key_pair,omitempty"`
+		LoginPassword      string   `json:"login_password,omitempty"`
+		ImageType          string   `json:"image_type,omitempty"`
+		DesiredSize        int64    `json:"desired_size"`
+	} `json:"scaling_group"`
+}
+
+// newACKCreateClusterRequest converts the ackClusterConfig used for provisioning to a CreateCluster body
+func newACKCreateClusterRequest(ackClusterConfig ack.ClusterConfig, tags map[string]string) ackCreateClusterRequest {
+	vswitchIDs := ackClusterConfig.VswitchIds
+	if len(vswitchIDs) == 0 {
+		// the master vswitches may list the same vswitch once per master
+		vswitchIDs = slices.Compact(slices.Sorted(slices.Values(ackClusterConfig.MasterVswitchIds)))
+	}
+
+	request := ackCreateClusterRequest{
+		Name:                 ackClusterConfig.Name,
+		ClusterType:          ackClusterConfig.ClusterType,
+		ClusterSpec:          ackClusterConfig.ClusterSpec,
+		KubernetesVersion:    ackClusterConfig.KubernetesVersion,
+		RegionID:             ackClusterConfig.RegionID,
+		VpcID:                ackClusterConfig.VpcID,
+		VswitchIDs:           vswitchIDs,
+		PodVswitchIDs:        ackClusterConfig.PodVswitchIds,
+		ContainerCidr:        ackClusterConfig.ContainerCidr,
+		ServiceCidr:          ackClusterConfig.ServiceCidr,
+		ProxyMode:            ackClusterConfig.ProxyMode,
+		SnatEntry:            ackClusterConfig.SnatEntry,
+		EndpointPublicAccess: ackClusterConfig.EndpointPublicAccess,
+		KeyPair:              ackClusterConfig.KeyPair,
+		LoginPassword:        ackClusterConfig.LoginPassword,
+		ResourceGroupID:      ackClusterConfig.ResourceGroupID,
+		SecurityGroupID:      ackClusterConfig.SecurityGroupID,
+		Addons:               ackClusterConfig.Addons,
+	}
+	if request.ClusterType == "" {
+		request.ClusterType = "ManagedKubernetes"
+	}
+	if ackClusterConfig.NodeCidrMask != 0 {
+		request.NodeCidrMask = strconv.FormatInt(ackClusterConfig.NodeCidrMask, 10)
+	}
+	for _, key := range slices.Sorted(maps.Keys(tags)) {
+		request.Tags = append(request.Tags, ackTag{Key: key, Value: tags[key]})
+	}
+	for _, nodePool := range ackClusterConfig.NodePoolList {
+		var nodepool ackNodepoolSpec
+		nodepool.NodepoolInfo.Name = nodePool.Name
+		nodepool.ScalingGroup.VswitchIDs = nodePool.VSwitchIds
+		if len(nodepool.ScalingGroup.VswitchIDs) == 0 {
+			nodepool.ScalingGroup.VswitchIDs = vswitchIDs
+		}
+		nodepool.ScalingGroup.InstanceTypes = nodePool.InstanceTypes
+		nodepool.ScalingGroup.InstanceChargeType = nodePool.InstanceChargeType
+		nodepool.ScalingGroup.SystemDiskCategory = nodePool.SystemDiskCategory
+		nodepool.ScalingGroup.SystemDiskSize = nodePool.SystemDiskSize
+		nodepool.ScalingGroup.KeyPair = nodePool.KeyPair
+		nodepool.ScalingGroup.LoginPassword = nodePool.LoginPassword
+		nodepool.ScalingGroup.ImageType = nodePool.Platform
+		nodepool.ScalingGroup.DesiredSize = nodePool.InstancesNum
+		request.Nodepools = append(request.Nodepools, nodepool)
+	}
+	return request
+}
+
+// CreateACKClusterOnAlibaba creates an ACK cluster on Alibaba Cloud from the ackClusterConfig of CATTLE_TEST_CONFIG
+// and waits until it is running; it returns the ID of the ACK cluster
+func CreateACKClusterOnAlibaba(region, clusterName, k8sVersion string, tags map[string]string, updateFunc func(clusterConfig *ack.ClusterConfig)) (string, error) {
+	var ackClusterConfig ack.ClusterConfig
+	config.LoadConfig(ack.ACKClusterConfigConfigurationFileKey, &ackClusterConfig)
+
+	ackClusterConfig.Name = clusterName
+	ackClusterConfig.RegionID = region
+	ackClusterConfig.KubernetesVersion = k8sVersion
+	if updateFunc != nil {
+		updateFunc(&ackClusterConfig)
+	}
+
+	body, err := json.Marshal(newACKCreateClusterRequest(ackClusterConfig, tags))
+	if err != nil {
+		return "", err
+	}
+	request := cs.CreateCreateClusterRequest()
+	request.SetContentType(requests.Json)
+	request.SetContent(body)
+
+	client := ackCloudClient(region)
+	fmt.Println("Creating ACK cluster ...")
+	response, err := client.CreateCluster(request)
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to create cluster")
+	}
+
+	var state string
+	Eventually(func() string {
+		state, err = ackClusterState(client, response.ClusterId)
+		Expect(err).To(BeNil())
+		return state
+	}, tools.SetTimeout(30*time.Minute), 30*time.Second).Should(BeElementOf("running", "failed"))
+	if state != "running" {
+		return response.ClusterId, errors.Errorf("ACK cluster %s (%s) is %s", clusterName, response.ClusterId, state)
+	}
+	fmt.Println("Created ACK cluster: ", clusterName, response.ClusterId)
+	return response.ClusterId, nil
+}
+
+// DeleteACKClusterOnAlibaba deletes an ACK cluster along with all its resources and waits until it is gone
+func DeleteACKClusterOnAlibaba(region, clusterID string) error {
+	request := cs.CreateDeleteClusterRequest()
+	request.ClusterId = clusterID
+	request.RetainAllResources = requests.NewBoolean(false)
+
+	client := ackCloudClient(region)
+	fmt.Println("Deleting ACK cluster ...")
+	if _, err := client.DeleteCluster(request); err != nil {
+		if isACKClusterNotFound(err) {
+			return nil
+		}
+		return errors.Wrap(err, "Failed to delete cluster")
+	}
+
+	Eventually(func() bool {
+		state, err := ackClusterState(client, clusterID)
+		if isACKClusterNotFound(err) {
+			return true
+		}
+		Expect(err).To(BeNil())
+		return state == "deleted"
+	}, tools.SetTimeout(30*time.Minute), 30*time.Second).Should(BeTrue())
+	fmt.Println("Deleted ACK cluster: ", clusterID)
+	return nil
+}
+
+// UpgradeACKClusterOnAlibaba upgrades the control plane of an ACK cluster to k8sVersion and waits until it is running again
+func UpgradeACKClusterOnAlibaba(region, clusterID, k8sVersion string) error {
+	body, err := json.Marshal(map[string]interface{}{"next_version": k8sVersion, "master_only": true})
+	if err != nil {
+		return err
+	}
+	request := cs.CreateUpgradeClusterRequest()
+	request.ClusterId = clusterID
+	request.SetContentType(requests.Json)
+	request.SetContent(body)
+
+	client := ackCloudClient(region)
+	fmt.Println("Upgrading ACK cluster ...")
+	if _, err = client.UpgradeCluster(request); err != nil {
+		return errors.Wrap(err, "Failed to upgrade cluster")
+	}
+
+	Eventually(func() bool {
+		detail, err := describeACKCluster(client, clusterID)
+		Expect(err).To(BeNil())
+		return detail.State == "running" && detail.CurrentVersion == k8sVersion
+	}, tools.SetTimeout(40*time.Minute), 30*time.Second).Should(BeTrue())
+	fmt.Println("Upgraded ACK cluster: ", clusterID, k8sVersion)
+	return nil
+}
+
+// ACKNodePool is the Alibaba Cloud view of an ACK node pool
+type ACKNodePool struct {
+	ID          string
+	Name        string
+	State       string
+	DesiredSize int64
+}
+
+// ListACKNodePoolsOnAlibaba lists the node pools of an ACK cluster
+func ListACKNodePoolsOnAlibaba(region, clusterID string) ([]ACKNodePool, error) {
+	request := cs.CreateDescribeClusterNodePoolsRequest()
+	request.ClusterId = clusterID
+	response, err := ackCloudClient(region).DescribeClusterNodePools(request)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to list node pools of cluster %s", clusterID)
+	}
+	var described struct {
+		Nodepools []struct {
+			NodepoolInfo struct {
+				NodepoolID string `json:"nodepool_id"`
+				Name       string `json:"name"`
+			} `json:"nodepool_info"`
+			ScalingGroup struct {
+				DesiredSize int64 `json:"desired_size"`
+			} `json:"scaling_group"`
+			Status struct {
+				State string `json:"state"`
+			} `json:"status"`
+		} `json:"nodepools"`
+	}
+	if err = json.Unmarshal(response.GetHttpContentBytes(), &described); err != nil {
+		return nil, errors.Wrapf(err, "decoding node pools of ACK cluster %s", clusterID)
+	}
+	var nodePools []ACKNodePool
+	for _, np := range described.Nodepools {
+		nodePools = append(nodePools, ACKNodePool{
+			ID:          np.NodepoolInfo.NodepoolID,
+			Name:        np.NodepoolInfo.Name,
+			State:       np.Status.State,
+			DesiredSize: np.ScalingGroup.DesiredSize,
+		})
+	}
+	return nodePools, nil
+}
+
+// ScaleACKNodePoolOnAlibaba changes the desired size of an ACK node pool and waits until the node pool is active again
+func ScaleACKNodePoolOnAlibaba(region, clusterID, nodePoolID string, desiredSize int64) error {
+	body, err := json.Marshal(map[string]interface{}{"scaling_group": map[string]int64{"desired_size": desiredSize}})
+	if err != nil {
+		return err
+	}
+	request := cs.CreateModifyClusterNodePoolRequest()
+	request.ClusterId = clusterID
+	request.NodepoolId = nodePoolID
+	request.SetContentType(requests.Json)
+	request.SetContent(body)
+
+	fmt.Println("Scaling ACK node pool ...")
+	if _, err = ackCloudClient(region).ModifyClusterNodePool(request); err != nil {
+		return errors.Wrap(err, "Failed to scale node pool")
+	}
+
+	Eventually(func() bool {
+		nodePool, err := describeACKNodePool(region, clusterID, nodePoolID)
+		Expect(err).To(BeNil())
+		return nodePool != nil && nodePool.State == "active" && nodePool.DesiredSize == desiredSize
+	}, tools.SetTimeout(20*time.Minute), 30*time.Second).Should(BeTrue())
+	fmt.Println("Scaled ACK node pool: ", nodePoolID, desiredSize)
+	return nil
+}
+
+// DeleteACKNodePoolOnAlibaba deletes an ACK node pool along with its nodes and waits until it is gone
+func DeleteACKNodePoolOnAlibaba(region, clusterID, nodePoolID string) error {
+	request := cs.CreateDeleteClusterNodepoolRequest()
+	request.ClusterId = clusterID
+	request.NodepoolId = nodePoolID
+	request.QueryParams["force"] = "true"
+
+	fmt.Println("Deleting ACK node pool ...")
+	if _, err := ackCloudClient(region).DeleteClusterNodepool(request); err != nil {
+		if isACKClusterNotFound(err) {
+			return nil
+		}
+		return errors.Wrap(err, "Failed to delete node pool")
+	}
+
+	Eventually(func() *ACKNodePool {
+		nodePool, err := describeACKNodePool(region, clusterID, nodePoolID)
+		Expect(err).To(BeNil())
+		return nodePool
+	}, tools.SetTimeout(20*time.Minute), 30*time.Second).Should(BeNil())
+	fmt.Println("Deleted ACK node pool: ", nodePoolID)
+	return nil
+}
+
+// describeACKNodePool returns the node pool nodePoolID of an ACK cluster, nil if it does not exist
+func describeACKNodePool(region, clusterID, nodePoolID string) (*ACKNodePool, error) {
+	nodePools, err := ListACKNodePoolsOnAlibaba(region, clusterID)
+	if err != nil {
+		return nil, err
+	}
+	for _, nodePool := range nodePools {
+		if nodePool.ID == nodePoolID {
+			return &nodePool, nil
+		}
+	}
+	return nil, nil
+}
+
+type ackClusterDetail struct {
+	// State is the state of the cluster, e.g. initial, running, updating, failed or deleted
+	State          string `json:"state"`
+	CurrentVersion string `json:"current_version"`
+}
+
+func describeACKCluster(client *cs.Client, clusterID string) (*ackClusterDetail, error) {
+	request := cs.CreateDescribeClusterDetailRequest()
+	request.ClusterId = clusterID
+	response, err := client.DescribeClusterDetail(request)
+	if err != nil {
+		return nil, err
+	}
+	var detail ackClusterDetail
+	if err = json.Unmarshal(response.GetHttpContentBytes(), &detail); err != nil {
+		return nil, errors.Wrapf(err, "decoding ACK cluster %s", clusterID)
+	}
+	return &detail, nil
+}
+
+// ackClusterState returns the state of an ACK cluster, e.g. initial, running, failed or deleted
+func ackClusterState(client *cs.Client, clusterID string) (string, error) {
+	detail, err := describeACKCluster(client, clusterID)
+	if err != nil {
+		return "", err
+	}
+	return detail.State, nil
+}
+
+func isACKClusterNotFound(err error) bool {
+	var serverErr *sdkerrors.ServerError
+	return errors.As(err, &serverErr) && serverErr.HttpStatus() == http.StatusNotFound
+}
+
+// <==============================ACK on Alibaba Cloud(end)==============================>
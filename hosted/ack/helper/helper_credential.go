@@ -0,0 +1,25 @@
+package helper
+
+import (
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+// CredentialOperations returns the operations of the cloud credential lifecycle checks on ACK clusters;
+// the cloud credentials of Alibaba Cloud hold long-term AccessKeys, whose expiry cannot be simulated
+func CredentialOperations() helpers.CredentialOperations {
+	return helpers.CredentialOperations{Trigger: toggleNodeCount}
+}
+
+// toggleNodeCount scales the node pools of the cluster to their toggled node count, see helpers.ToggledNodeCount
+func toggleNodeCount(client *rancher.Client, cluster *management.Cluster) error {
+	_, err := UpdateCluster(cluster, client, func(upgradedCluster *management.Cluster) {
+		for i := range upgradedCluster.ACKConfig.NodePoolList {
+			nodePool := &upgradedCluster.ACKConfig.NodePoolList[i]
+			nodePool.InstancesNum = helpers.ToggledNodeCount(nodePool.InstancesNum)
+		}
+	})
+	return err
+}
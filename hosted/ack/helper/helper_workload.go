@@ -0,0 +1,14 @@
+package helper
+
+import (
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/workload"
+)
+
+// WorkloadOptions returns the options of the workload checks on ACK clusters: a cloud disk, and an internet-facing CLB created by the cloud controller
+func WorkloadOptions() workload.Options {
+	return workload.Options{
+		StorageClass: "alicloud-disk-topology-alltype",
+		// cloud disks are at least 20GiB
+		StorageSize: "20Gi",
+	}
+}
@@ -0,0 +1,46 @@
+package k8s_chart_support_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/ack/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var _ = Describe("K8sChartSupportImport", func() {
+	var (
+		cluster *management.Cluster
+		// ackClusterID is the ID of the cluster on Alibaba Cloud, it is needed to import and delete it
+		ackClusterID string
+	)
+	BeforeEach(func() {
+		var err error
+		ackClusterID, err = helper.CreateACKClusterOnAlibaba(region, clusterName, k8sVersion, helpers.GetCommonMetadataLabels(), nil)
+		Expect(err).To(BeNil())
+
+		cluster, err = helper.ImportACKHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, ackClusterID, region)
+		Expect(err).To(BeNil())
+		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+		Expect(err).To(BeNil())
+
+	})
+	AfterEach(func() {
+		if ctx.ClusterCleanup && cluster != nil {
+			err := helper.DeleteACKHostCluster(cluster, ctx.RancherAdminClient)
+			Expect(err).To(BeNil())
+			err = helper.DeleteACKClusterOnAlibaba(region, ackClusterID)
+			Expect(err).To(BeNil())
+		} else {
+			fmt.Println("Skipping downstream cluster deletion: ", clusterName)
+		}
+	})
+
+	It("should successfully test k8s chart support import", func() {
+		testCaseID = 65 // Report to Qase
+		commonchecks(ctx.RancherAdminClient, cluster)
+	})
+})
@@ -0,0 +1,139 @@
+package k8s_chart_support_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/kubectl"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	. "github.com/rancher-sandbox/qase-ginkgo"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	namegen "github.com/rancher/shepherd/pkg/namegenerator"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/ack/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+const (
+	increaseBy = 1
+)
+
+var (
+	ctx                     helpers.RancherContext
+	clusterName, k8sVersion string
+	region                  = helpers.GetACKRegion()
+	testCaseID              int64
+)
+
+func TestK8sChartSupport(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "K8sChartSupport Suite")
+}
+
+var _ = SynchronizedBeforeSuite(func() []byte {
+	helpers.CommonSynchronizedBeforeSuite()
+	return nil
+}, func() {
+	Expect(helpers.Kubeconfig).ToNot(BeEmpty())
+
+	By("Adding the necessary chart repos", func() {
+		helpers.AddRancherCharts()
+	})
+	ctx = helpers.CommonBeforeSuite()
+})
+
+var _ = BeforeEach(func() {
+	var err error
+	clusterName = namegen.AppendRandomString(helpers.ClusterNamePrefix)
+
+	k8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, false)
+	Expect(err).To(BeNil())
+	Expect(k8sVersion).ToNot(BeEmpty())
+
+	GinkgoLogr.Info(fmt.Sprintf("Using ACK version %s for cluster %s", k8sVersion, clusterName))
+
+})
+
+var _ = AfterEach(func() {
+
+	By("Uninstalling the existing operator charts", func() {
+		helpers.UninstallOperatorCharts()
+	})
+})
+
+var _ = ReportBeforeEach(func(report SpecReport) {
+	// Reset case ID
+	testCaseID = -1
+})
+
+var _ = ReportAfterEach(func(report SpecReport) {
+	// Add result in Qase if asked
+	Qase(testCaseID, report)
+})
+
+func commonchecks(client *rancher.Client, cluster *management.Cluster) {
+	var originalChartVersion string
+
+	By("checking the chart version", func() {
+		originalChartVersion = helpers.GetCurrentOperatorChartVersion()
+		Expect(originalChartVersion).ToNot(BeEmpty())
+		GinkgoLogr.Info("Original chart version: " + originalChartVersion)
+	})
+
+	var downgradedVersion string
+	By("obtaining a version to downgrade", func() {
+		downgradedVersion = helpers.GetDowngradeOperatorChartVersion(originalChartVersion)
+		Expect(downgradedVersion).ToNot(BeEmpty())
+		GinkgoLogr.Info("Downgrading to version: " + downgradedVersion)
+	})
+
+	By("downgrading the chart version", func() {
+		helpers.DowngradeProviderChart(downgradedVersion)
+	})
+
+	initialNodeCount := cluster.ACKConfig.NodePoolList[0].InstancesNum
+
+	By("making a change(scaling nodegroup up) to the cluster to validate functionality after chart downgrade", func() {
+		var err error
+		cluster, err = helper.ScaleNodeGroup(cluster, client, initialNodeCount+increaseBy, true, true)
+		Expect(err).To(BeNil())
+	})
+
+	By("uninstalling the operator chart", func() {
+		helpers.UninstallOperatorCharts()
+	})
+
+	By("making a change(scaling nodegroup down) to the cluster to re-install the operator and validating it is re-installed to the latest/original version", func() {
+		var err error
+		cluster, err = helper.ScaleNodeGroup(cluster, client, initialNodeCount, false, false)
+		Expect(err).To(BeNil())
+
+		By("ensuring that the chart is re-installed to the latest/original version", func() {
+			helpers.WaitUntilOperatorChartInstallation(originalChartVersion, "", 0)
+		})
+
+		By("ensuring that rancher is up", func() {
+			helpers.CheckRancherDeployments(kubectl.New())
+		})
+
+		// We do not use WaitClusterToBeUpgraded because it has been flaky here and times out
+		Eventually(func() bool {
+			GinkgoLogr.Info("Waiting for the node count change to appear in ACKStatus.UpstreamSpec ...")
+			Expect(err).To(BeNil())
+			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+			Expect(err).To(BeNil())
+			for _, np := range cluster.ACKStatus.UpstreamSpec.NodePoolList {
+				if np.InstancesNum != initialNodeCount {
+					return false
+				}
+			}
+			return true
+		}, tools.SetTimeout(15*time.Minute), 10*time.Second).Should(BeTrue())
+
+	})
+
+}
@@ -0,0 +1,43 @@
+package k8s_chart_support_upgrade_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/ack/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var _ = Describe("K8sChartSupportUpgradeImport", func() {
+	var (
+		f *helpers.Fixture
+		// ackClusterID is the ID of the cluster on Alibaba Cloud, it is needed to import and delete it
+		ackClusterID string
+	)
+	BeforeEach(func() {
+		f = newFixture()
+		f.GuardCost()
+		var err error
+		ackClusterID, err = helper.CreateACKClusterOnAlibaba(region, f.ClusterName, f.K8sVersion, helpers.GetCommonMetadataLabels(), nil)
+		Expect(err).To(BeNil())
+
+		f.Cluster, err = helper.ImportACKHostedCluster(f.Client, f.ClusterName, ctx.CloudCredID, ackClusterID, region)
+		Expect(err).To(BeNil())
+		f.AddClusterCleanup(func() {
+			err := helper.DeleteACKHostCluster(f.Cluster, f.Client)
+			Expect(err).To(BeNil())
+			err = helper.DeleteACKClusterOnAlibaba(region, ackClusterID)
+			Expect(err).To(BeNil())
+		})
+		f.Cluster, err = helpers.WaitUntilClusterIsReady(f.Cluster, f.Client)
+		Expect(err).To(BeNil())
+	})
+	It("should successfully test k8s chart support import in an upgrade scenario", func() {
+		GinkgoLogr.Info(fmt.Sprintf("Testing K8s %s chart support for import on Rancher upgraded from %s to %s", helpers.K8sUpgradedMinorVersion, helpers.RancherFullVersion, helpers.RancherUpgradeFullVersion))
+		f.SetQaseID(167) // Report to Qase
+
+		commonchecks(f, helpers.RancherUpgradeFullVersion, helpers.K8sUpgradedMinorVersion)
+	})
+})
@@ -0,0 +1,91 @@
+/*
+Copyright © 2022 - 2025 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package p0_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/ack/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var _ = Describe("P0Import", func() {
+	// ackClusterID is the ID of the cluster on Alibaba Cloud, it is needed to import and delete it
+	var ackClusterID string
+
+	for _, testData := range []struct {
+		isUpgrade bool
+		testBody  func(f *helpers.Fixture)
+		testTitle string
+	}{
+		{
+			isUpgrade: false,
+			testBody:  p0NodesChecks,
+			testTitle: "should successfully import the cluster & add, delete, scale nodepool",
+		},
+		{
+			isUpgrade: true,
+			testBody:  p0UpgradeK8sVersionChecks,
+			testTitle: "should be able to upgrade k8s version of the imported cluster",
+		},
+	} {
+		testData := testData
+		When("a cluster is created", func() {
+			var f *helpers.Fixture
+
+			BeforeEach(func() {
+				ackClusterID = ""
+				if testData.isUpgrade && helpers.SkipUpgradeTests {
+					Skip(helpers.SkipUpgradeTestsLog)
+				}
+
+				f = helpers.NewFixture(&ctx)
+				f.AddClusterCleanup(func() {
+					if f.Cluster != nil && f.Cluster.ID != "" {
+						GinkgoLogr.Info(fmt.Sprintf("Cleaning up resource cluster: %s %s", f.Cluster.Name, f.Cluster.ID))
+						err := helper.DeleteACKHostCluster(f.Cluster, f.Client)
+						Expect(err).To(BeNil())
+					}
+					if ackClusterID != "" {
+						err := helper.DeleteACKClusterOnAlibaba(region, ackClusterID)
+						Expect(err).To(BeNil())
+					}
+				})
+
+				var err error
+				f.K8sVersion, err = helper.GetK8sVersion(f.Client, testData.isUpgrade)
+				Expect(err).To(BeNil())
+				GinkgoLogr.Info(fmt.Sprintf("Using K8s version %s for cluster %s", f.K8sVersion, f.ClusterName))
+				ackClusterID, err = helper.CreateACKClusterOnAlibaba(region, f.ClusterName, f.K8sVersion, helpers.GetCommonMetadataLabels(), nil)
+				Expect(err).To(BeNil())
+
+				f.Cluster, err = helper.ImportACKHostedCluster(f.Client, f.ClusterName, ctx.CloudCredID, ackClusterID, region)
+				Expect(err).To(BeNil())
+				// WaitUntilClusterIsReady replaces ACKConfig with ACKStatus.UpstreamSpec for imported clusters,
+				// the node pools and version of the imported cluster are only known from the upstream spec
+				f.Cluster, err = helpers.WaitUntilClusterIsReady(f.Cluster, f.Client)
+				Expect(err).To(BeNil())
+				Expect(f.Cluster.ACKConfig.NodePoolList).ToNot(BeEmpty())
+			})
+
+			It(testData.testTitle, func() {
+				testData.testBody(f)
+			})
+		})
+	}
+})
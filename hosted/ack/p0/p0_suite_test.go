@@ -8,10 +8,6 @@ import (
 	. "github.com/onsi/gomega"
 	. "github.com/rancher-sandbox/qase-ginkgo"
 
-	"github.com/rancher/shepherd/clients/rancher"
-	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
-	namegen "github.com/rancher/shepherd/pkg/namegenerator"
-
 	ackhelper "github.com/rancher/hosted-providers-e2e/hosted/ack/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
 )
@@ -21,10 +17,8 @@ const (
 )
 
 var (
-	ctx         helpers.RancherContext
-	cluster     *management.Cluster
-	clusterName string
-	testCaseID  int64
+	ctx    helpers.RancherContext
+	region = helpers.GetACKRegion()
 )
 
 // go test 入口：注册断言失败处理并启动 Ginkgo
@@ -40,54 +34,50 @@ var _ = SynchronizedBeforeSuite(func() []byte {
 	ctx = helpers.CommonBeforeSuite()
 })
 
-var _ = BeforeEach(func() {
-	cluster = nil
-	clusterName = namegen.AppendRandomString(helpers.ClusterNamePrefix)
-})
-
-var _ = ReportBeforeEach(func(_ SpecReport) { testCaseID = -1 })
-var _ = ReportAfterEach(func(report SpecReport) { Qase(testCaseID, report) })
+var _ = ReportAfterEach(func(report SpecReport) { Qase(helpers.QaseID(report), report) })
 
-func p0UpgradeK8sVersionChecks(c *management.Cluster, client *rancher.Client, name string) {
-	helpers.ClusterIsReadyChecks(c, client, name)
+func p0UpgradeK8sVersionChecks(f *helpers.Fixture) {
+	helpers.ClusterIsReadyChecks(f.Cluster, f.Client, f.ClusterName)
 
-	upgradeTo, err := ackhelper.GetK8sVersion(client, true)
+	var err error
+	f.UpgradeToVersion, err = ackhelper.GetK8sVersion(f.Client, true)
 	Expect(err).To(BeNil())
-	GinkgoLogr.Info(fmt.Sprintf("Upgrading ACK cluster to version %s", upgradeTo))
+	GinkgoLogr.Info(fmt.Sprintf("Upgrading ACK cluster to version %s", f.UpgradeToVersion))
 
 	By("upgrading the ACK cluster", func() {
-		c, err = ackhelper.UpgradeClusterKubernetesVersion(c, upgradeTo, client, true)
+		f.Cluster, err = ackhelper.UpgradeClusterKubernetesVersion(f.Cluster, f.UpgradeToVersion, f.Client, true)
 		Expect(err).To(BeNil())
 	})
 }
 
-func p0NodesChecks(c *management.Cluster, client *rancher.Client, name string) {
-	helpers.ClusterIsReadyChecks(c, client, name)
+func p0NodesChecks(f *helpers.Fixture) {
+	helpers.ClusterIsReadyChecks(f.Cluster, f.Client, f.ClusterName)
+	helpers.WorkloadChecks(f.Cluster, f.Client, ackhelper.WorkloadOptions())
 
-	cfgPools := c.ACKConfig.NodePoolList
+	cfgPools := f.Cluster.ACKConfig.NodePoolList
 	initial := cfgPools[0].InstancesNum
 
 	By("scaling up the NodePool", func() {
 		var err error
-		c, err = ackhelper.ScaleNodeGroup(c, client, initial+increaseBy, true, true)
+		f.Cluster, err = ackhelper.ScaleNodeGroup(f.Cluster, f.Client, initial+increaseBy, true, true)
 		Expect(err).To(BeNil())
 	})
 
 	By("scaling down the NodePool", func() {
 		var err error
-		c, err = ackhelper.ScaleNodeGroup(c, client, initial, true, true)
+		f.Cluster, err = ackhelper.ScaleNodeGroup(f.Cluster, f.Client, initial, true, true)
 		Expect(err).To(BeNil())
 	})
 
 	By("adding a NodePool", func() {
 		var err error
-		c, err = ackhelper.AddNodePool(c, increaseBy, client, true, true)
+		f.Cluster, err = ackhelper.AddNodePool(f.Cluster, increaseBy, f.Client, true, true)
 		Expect(err).To(BeNil())
 	})
 
 	By("deleting the NodePool", func() {
 		var err error
-		c, err = ackhelper.DeleteNodePool(c, client, true, true)
+		f.Cluster, err = ackhelper.DeleteNodePool(f.Cluster, f.Client, true, true)
 		Expect(err).To(BeNil())
 	})
 }
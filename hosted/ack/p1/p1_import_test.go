@@ -0,0 +1,148 @@
+/*
+Copyright © 2022 - 2025 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package p1_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/ack/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var _ = Describe("P1Import", func() {
+	var (
+		k8sVersion string
+		// ackClusterID is the ID of the cluster on Alibaba Cloud, it is needed to import and delete it
+		ackClusterID string
+	)
+
+	BeforeEach(func() {
+		// assigning cluster nil value so that every new test has a fresh value of the variable
+		// this is to avoid using residual value of a cluster in a test that does not use it
+		cluster = nil
+		ackClusterID = ""
+
+		var err error
+		k8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, false)
+		Expect(err).To(BeNil())
+		GinkgoLogr.Info(fmt.Sprintf("Using kubernetes version %s for cluster %s", k8sVersion, clusterName))
+	})
+
+	AfterEach(func() {
+		if ctx.ClusterCleanup {
+			if cluster != nil && cluster.ID != "" {
+				deleteACKCluster(cluster, ctx.RancherAdminClient)
+			}
+			if ackClusterID != "" {
+				err := helper.DeleteACKClusterOnAlibaba(region, ackClusterID)
+				Expect(err).To(BeNil())
+			}
+		} else {
+			fmt.Println("Skipping downstream cluster deletion: ", clusterName)
+		}
+	})
+
+	importCluster := func() {
+		var err error
+		ackClusterID, err = helper.CreateACKClusterOnAlibaba(region, clusterName, k8sVersion, helpers.GetCommonMetadataLabels(), nil)
+		Expect(err).To(BeNil())
+		cluster, err = helper.ImportACKHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, ackClusterID, region)
+		Expect(err).To(BeNil())
+		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+		Expect(err).To(BeNil())
+	}
+
+	Context("Upgrade Testing", func() {
+		var upgradeToVersion string
+
+		BeforeEach(func() {
+			if helpers.SkipUpgradeTests {
+				Skip(helpers.SkipUpgradeTestsLog)
+			}
+
+			var err error
+			k8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, true)
+			Expect(err).To(BeNil())
+			GinkgoLogr.Info(fmt.Sprintf("Using kubernetes version %s for cluster %s", k8sVersion, clusterName))
+			upgradeToVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, false)
+			Expect(err).To(BeNil())
+		})
+
+		When("a cluster is imported", func() {
+
+			BeforeEach(func() {
+				importCluster()
+			})
+
+			It("should successfully update a cluster while it is still in updating state", func() {
+				updateClusterInUpdatingState(cluster, ctx.RancherAdminClient, upgradeToVersion)
+			})
+		})
+	})
+
+	When("a cluster is imported", func() {
+
+		var _ = BeforeEach(func() {
+			importCluster()
+		})
+
+		It("Delete & re-import cluster", func() {
+			err := helper.DeleteACKHostCluster(cluster, ctx.RancherAdminClient)
+			Expect(err).To(BeNil())
+
+			Eventually(func() string {
+				cluster, _ = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
+				return cluster.ID
+			}, "30s", "3s").Should(BeEmpty())
+
+			cluster, err = helper.ImportACKHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, ackClusterID, region)
+			Expect(err).To(BeNil())
+			cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+			Expect(err).To(BeNil())
+
+			helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
+		})
+
+		It("Update the cloud creds", func() {
+			updateCloudCredentialsCheck(cluster, ctx.RancherAdminClient)
+		})
+
+		It("should not delete all the nodepools", func() {
+			deleteAllNodePoolsCheck(cluster, ctx.RancherAdminClient)
+		})
+
+		It("Scale a nodepool in ACK -> Syncs to Rancher -> Update cluster, the nodepool is intact", func() {
+			nodePool := cluster.ACKStatus.UpstreamSpec.NodePoolList[0]
+			nodeCount := nodePool.InstancesNum + increaseBy
+			err := helper.ScaleACKNodePoolOnAlibaba(region, ackClusterID, nodePool.NodepoolId, nodeCount)
+			Expect(err).To(BeNil())
+			Eventually(func() int64 {
+				cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
+				Expect(err).To(BeNil())
+				return cluster.ACKStatus.UpstreamSpec.NodePoolList[0].InstancesNum
+			}, "10m", "7s").Should(Equal(nodeCount), "Timed out while waiting for rancher to sync")
+
+			cluster.ACKConfig = cluster.ACKStatus.UpstreamSpec
+			cluster, err = helper.AddNodePool(cluster, increaseBy, ctx.RancherAdminClient, true, true)
+			Expect(err).To(BeNil())
+
+			// verify that the scaled nodepool is intact
+			Expect(cluster.ACKStatus.UpstreamSpec.NodePoolList[0].InstancesNum).To(Equal(nodeCount))
+		})
+	})
+})
@@ -0,0 +1,143 @@
+/*
+Copyright © 2022 - 2025 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package p1_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher/shepherd/extensions/clusters/ack"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/ack/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var _ = Describe("P1Provisioning", func() {
+	var k8sVersion string
+	var _ = BeforeEach(func() {
+		// assigning cluster nil value so that every new test has a fresh value of the variable
+		// this is to avoid using residual value of a cluster in a test that does not use it
+		cluster = nil
+
+		var err error
+		k8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, false)
+		Expect(err).To(BeNil())
+		GinkgoLogr.Info(fmt.Sprintf("While provisioning, using kubernetes version %s for cluster %s", k8sVersion, clusterName))
+	})
+
+	AfterEach(func() {
+		if ctx.ClusterCleanup {
+			if cluster != nil && cluster.ID != "" {
+				deleteACKCluster(cluster, ctx.RancherAdminClient)
+			}
+		} else {
+			fmt.Println("Skipping downstream cluster deletion: ", clusterName)
+		}
+	})
+
+	Context("Provisioning/Editing a cluster with invalid config", func() {
+
+		It("should fail to provision a cluster with duplicate nodepool names", func() {
+			updateFunc := func(clusterConfig *ack.ClusterConfig) {
+				duplicate := clusterConfig.NodePoolList[0]
+				clusterConfig.NodePoolList = append(clusterConfig.NodePoolList, duplicate)
+				for i := range clusterConfig.NodePoolList {
+					clusterConfig.NodePoolList[i].Name = "duplicate"
+				}
+			}
+			var err error
+			cluster, err = helper.CreateACKHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, updateFunc)
+			Expect(err).To(BeNil())
+
+			Eventually(func() bool {
+				cluster, err := ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
+				Expect(err).To(BeNil())
+				return cluster.Transitioning == "error"
+			}, "5m", "3s").Should(BeTrue())
+		})
+
+		It("should fail to provision a cluster with an invalid k8s version", func() {
+			var err error
+			cluster, err = helper.CreateACKHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, "1.0.0-aliyun.1", nil)
+			Expect(err).To(BeNil())
+
+			Eventually(func() bool {
+				cluster, err := ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
+				Expect(err).To(BeNil())
+				return cluster.Transitioning == "error"
+			}, "5m", "3s").Should(BeTrue())
+		})
+	})
+
+	Context("Upgrade testing", func() {
+		var upgradeToVersion string
+
+		BeforeEach(func() {
+			if helpers.SkipUpgradeTests {
+				Skip(helpers.SkipUpgradeTestsLog)
+			}
+
+			var err error
+			k8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, true)
+			Expect(err).To(BeNil())
+			upgradeToVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, false)
+			Expect(err).To(BeNil())
+			GinkgoLogr.Info(fmt.Sprintf("While provisioning, using kubernetes version %s for cluster %s", k8sVersion, clusterName))
+		})
+
+		When("a cluster is created", func() {
+
+			BeforeEach(func() {
+				var err error
+				cluster, err = helper.CreateACKHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, nil)
+				Expect(err).To(BeNil())
+				cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+				Expect(err).To(BeNil())
+			})
+
+			It("should successfully update a cluster while it is still in updating state", func() {
+				updateClusterInUpdatingState(cluster, ctx.RancherAdminClient, upgradeToVersion)
+			})
+		})
+	})
+
+	When("a cluster is created", func() {
+
+		BeforeEach(func() {
+			var err error
+			cluster, err = helper.CreateACKHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, nil)
+			Expect(err).To(BeNil())
+			cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+			Expect(err).To(BeNil())
+		})
+
+		It("Update the cloud creds", func() {
+			updateCloudCredentialsCheck(cluster, ctx.RancherAdminClient)
+		})
+
+		It("should report the failures of its cloud credential and recover once it is valid again", func() {
+			cluster = helpers.CloudCredentialLifecycleChecks(cluster, ctx.RancherAdminClient, helper.CredentialOperations())
+		})
+
+		It("should not delete all the nodepools", func() {
+			deleteAllNodePoolsCheck(cluster, ctx.RancherAdminClient)
+		})
+
+		It("should not add a nodepool with a duplicate name", func() {
+			duplicateNodePoolNameCheck(cluster, ctx.RancherAdminClient)
+		})
+	})
+})
@@ -0,0 +1,77 @@
+/*
+Copyright © 2022 - 2025 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package p1_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/ack/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var _ = Describe("SyncProvisioning", func() {
+	var (
+		cluster    *management.Cluster
+		k8sVersion string
+	)
+
+	AfterEach(func() {
+		if ctx.ClusterCleanup && (cluster != nil && cluster.ID != "") {
+			deleteACKCluster(cluster, ctx.RancherAdminClient)
+		} else {
+			GinkgoLogr.Info(fmt.Sprintf("Skipping downstream cluster deletion: %s", clusterName))
+		}
+	})
+
+	When("a cluster is created for sync", func() {
+		var upgradeToVersion string
+
+		BeforeEach(func() {
+			if helpers.SkipUpgradeTests {
+				Skip(helpers.SkipUpgradeTestsLog)
+			}
+			var err error
+			k8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, true)
+			Expect(err).To(BeNil())
+			upgradeToVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, false)
+			Expect(err).To(BeNil())
+			GinkgoLogr.Info(fmt.Sprintf("While provisioning, using kubernetes version %s for cluster %s", k8sVersion, clusterName))
+
+			cluster, err = helper.CreateACKHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, nil)
+			Expect(err).To(BeNil())
+			cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+			Expect(err).To(BeNil())
+		})
+
+		It("Upgrade k8s version of cluster from ACK and verify it is synced back to Rancher", func() {
+			By("upgrading the control plane", func() {
+				syncK8sVersionUpgradeCheck(cluster, ctx.RancherAdminClient, upgradeToVersion)
+			})
+		})
+
+		It("Sync from Alibaba Cloud console to Rancher", func() {
+			syncAlibabaToRancherCheck(cluster, ctx.RancherAdminClient, upgradeToVersion)
+		})
+
+		It("Sync from Rancher to Alibaba Cloud console after a sync from Alibaba Cloud console to Rancher", func() {
+			syncRancherToAlibabaCheck(cluster, ctx.RancherAdminClient, upgradeToVersion)
+		})
+	})
+
+})
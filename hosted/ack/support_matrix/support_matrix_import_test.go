@@ -0,0 +1,77 @@
+/*
+Copyright © 2022 - 2025 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package support_matrix_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"fmt"
+
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	namegen "github.com/rancher/shepherd/pkg/namegenerator"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/ack/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var _ = Describe("SupportMatrixImport", func() {
+
+	for _, version := range availableVersionList {
+		version := version
+
+		When(fmt.Sprintf("a cluster is created with kubernetes version %s", version), func() {
+			var (
+				clusterName string
+				// ackClusterID is the ID of the cluster on Alibaba Cloud, it is needed to import and delete it
+				ackClusterID string
+				cluster      *management.Cluster
+			)
+			BeforeEach(func() {
+				clusterName = namegen.AppendRandomString(helpers.ClusterNamePrefix)
+				helpers.GuardClusterCost(clusterName)
+				var err error
+				ackClusterID, err = helper.CreateACKClusterOnAlibaba(region, clusterName, version, helpers.GetCommonMetadataLabels(), nil)
+				Expect(err).To(BeNil())
+				cluster, err = helper.ImportACKHostedCluster(ctx.StdUserClient, clusterName, ctx.CloudCredID, ackClusterID, region)
+				Expect(err).To(BeNil())
+				// Requires RancherAdminClient
+				cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+				Expect(err).To(BeNil())
+			})
+			AfterEach(func() {
+				if ctx.ClusterCleanup {
+					if cluster != nil {
+						err := helper.DeleteACKHostCluster(cluster, ctx.StdUserClient)
+						Expect(err).To(BeNil())
+					}
+					if ackClusterID != "" {
+						err := helper.DeleteACKClusterOnAlibaba(region, ackClusterID)
+						Expect(err).To(BeNil())
+					}
+				} else {
+					fmt.Println("Skipping downstream cluster deletion: ", clusterName)
+				}
+			})
+
+			It("should successfully import the cluster", func() {
+				// Report to Qase
+				testCaseID = 70
+
+				helpers.ClusterIsReadyChecks(cluster, ctx.StdUserClient, clusterName)
+			})
+		})
+	}
+})
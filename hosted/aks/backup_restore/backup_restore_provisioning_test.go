@@ -17,13 +17,22 @@ package backup_restore_test
 import (
 	. "github.com/onsi/ginkgo/v2"
 	"github.com/rancher-sandbox/ele-testhelpers/kubectl"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
 )
 
 var _ = Describe("BackupRestoreProvisioning", func() {
 	k := kubectl.New()
 
 	It("Do a full backup/restore test", func() {
-		testCaseID = 246 // Report to Qase
-		BackupRestoreChecks(k)
+		f := newFixture()
+		f.SetQaseID(246) // Report to Qase
+		BackupRestoreChecks(f, k)
+	})
+
+	It("Do a full encrypted backup/restore test", func() {
+		f := newFixture()
+		f.SetQaseID(helpers.QaseCasePending)
+		EncryptedBackupRestoreChecks(f, k)
 	})
 })
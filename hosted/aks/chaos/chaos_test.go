@@ -0,0 +1,119 @@
+/*
+Copyright © 2022 - 2025 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chaos_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/aks/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/chaos"
+)
+
+// Rancher is restarted under the feet of the other specs, they must not run in parallel
+var _ = Describe("RancherChaos", Serial, func() {
+	var f *helpers.Fixture
+
+	BeforeEach(func() {
+		f = helpers.NewFixture(&ctx)
+		f.AddClusterCleanup(func() {
+			if f.Cluster != nil && f.Cluster.ID != "" {
+				GinkgoLogr.Info(fmt.Sprintf("Cleaning up resource cluster: %s %s", f.Cluster.Name, f.Cluster.ID))
+				err := helper.DeleteAKSHostCluster(f.Cluster, f.Client)
+				Expect(err).To(BeNil())
+			}
+		})
+	})
+
+	createCluster := func(forUpgrade, wait bool) {
+		var err error
+		f.K8sVersion, err = helper.GetK8sVersion(f.Client, ctx.CloudCredID, location, forUpgrade)
+		Expect(err).NotTo(HaveOccurred())
+		GinkgoLogr.Info(fmt.Sprintf("Using K8s version %s for cluster %s", f.K8sVersion, f.ClusterName))
+
+		f.Cluster, err = helper.CreateAKSHostedCluster(f.Client, f.ClusterName, ctx.CloudCredID, f.K8sVersion, location, nil)
+		Expect(err).To(BeNil())
+		if wait {
+			f.Cluster, err = helpers.WaitUntilClusterIsReady(f.Cluster, f.Client)
+			Expect(err).To(BeNil())
+		}
+	}
+
+	When("a cluster is being provisioned", func() {
+		BeforeEach(func() {
+			createCluster(false, false)
+		})
+
+		It("should be ready after Rancher restarts while it is provisioning", func() {
+			helpers.RancherChaosChecks(f.Cluster, f.Client, chaos.RancherDeployment, chaos.Provisioning)
+
+			var err error
+			f.Cluster, err = helpers.WaitUntilClusterIsReady(f.Cluster, f.Client)
+			Expect(err).To(BeNil())
+			helpers.ClusterIsReadyChecks(f.Cluster, f.Client, f.ClusterName)
+		})
+
+		It("should be ready after the Rancher leader changes while it is provisioning", func() {
+			helpers.RancherChaosChecks(f.Cluster, f.Client, chaos.RancherLeader, chaos.Provisioning)
+
+			var err error
+			f.Cluster, err = helpers.WaitUntilClusterIsReady(f.Cluster, f.Client)
+			Expect(err).To(BeNil())
+			helpers.ClusterIsReadyChecks(f.Cluster, f.Client, f.ClusterName)
+		})
+	})
+
+	When("a cluster is created", func() {
+		BeforeEach(func() {
+			createCluster(false, true)
+		})
+
+		It("should be deleted after Rancher restarts while it is being deleted", func() {
+			err := helper.DeleteAKSHostCluster(f.Cluster, f.Client)
+			Expect(err).To(BeNil())
+			helpers.RancherChaosChecks(f.Cluster, f.Client, chaos.RancherDeployment, chaos.Deleting)
+
+			helpers.WaitUntilClusterIsDeleted(f.Cluster, f.Client)
+			// the cluster is gone, there is nothing left to clean up
+			f.Cluster = nil
+		})
+	})
+
+	When("a cluster is created for upgrade", func() {
+		BeforeEach(func() {
+			if helpers.SkipUpgradeTests {
+				Skip(helpers.SkipUpgradeTestsLog)
+			}
+			createCluster(true, true)
+		})
+
+		It("should be upgraded after k3s restarts while it is upgrading", func() {
+			versions, err := helper.ListAKSAvailableVersions(f.Client, f.Cluster.ID)
+			Expect(err).To(BeNil())
+			Expect(versions).ToNot(BeEmpty())
+			f.UpgradeToVersion = versions[0]
+
+			f.Cluster, err = helper.UpgradeClusterKubernetesVersion(f.Cluster, f.UpgradeToVersion, f.Client, false)
+			Expect(err).To(BeNil())
+			helpers.RancherChaosChecks(f.Cluster, f.Client, chaos.K3sService, chaos.Upgrading)
+
+			f.Cluster = helpers.WaitForConvergence(f.Cluster, f.Client, helper.FaultOperations())
+			helpers.ClusterIsReadyChecks(f.Cluster, f.Client, f.ClusterName)
+		})
+	})
+})
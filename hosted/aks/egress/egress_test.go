@@ -0,0 +1,61 @@
+/*
+Copyright © 2022 - 2025 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package egress_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/aks/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/egress"
+)
+
+// the cloud API is faulted for every cluster behind the proxy, the other specs must not run in parallel
+var _ = Describe("EgressFault", Serial, func() {
+	var f *helpers.Fixture
+
+	BeforeEach(func() {
+		if !helpers.GetEnvironmentProfile().Proxy.Enabled {
+			Skip("Skipping the egress fault tests, they need Rancher behind the proxy (RANCHER_BEHIND_PROXY=enabled)")
+		}
+
+		f = helpers.NewFixture(&ctx)
+		f.AddClusterCleanup(func() {
+			if f.Cluster != nil && f.Cluster.ID != "" {
+				GinkgoLogr.Info(fmt.Sprintf("Cleaning up resource cluster: %s %s", f.Cluster.Name, f.Cluster.ID))
+				err := helper.DeleteAKSHostCluster(f.Cluster, f.Client)
+				Expect(err).To(BeNil())
+			}
+		})
+
+		var err error
+		f.K8sVersion, err = helper.GetK8sVersion(f.Client, ctx.CloudCredID, location, false)
+		Expect(err).NotTo(HaveOccurred())
+		GinkgoLogr.Info(fmt.Sprintf("Using K8s version %s for cluster %s", f.K8sVersion, f.ClusterName))
+
+		f.Cluster, err = helper.CreateAKSHostedCluster(f.Client, f.ClusterName, ctx.CloudCredID, f.K8sVersion, location, nil)
+		Expect(err).To(BeNil())
+		f.Cluster, err = helpers.WaitUntilClusterIsReady(f.Cluster, f.Client)
+		Expect(err).To(BeNil())
+	})
+
+	It("should report the faults of the cloud API and recover from them", func() {
+		f.Cluster = helpers.EgressFaultChecks(f.Cluster, f.Client, helper.CredentialOperations().Trigger, egress.CloudAPIFaults(helpers.Provider)...)
+		helpers.ClusterIsReadyChecks(f.Cluster, f.Client, f.ClusterName)
+	})
+})
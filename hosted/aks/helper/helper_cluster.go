@@ -564,6 +564,18 @@ func ScaleNodePoolOnAzure(npName, clusterName, resourceGroupName, nodeCount stri
 	return nil
 }
 
+// ListNodePoolsOnAzure lists the names of the nodepools of an AKS cluster via CLI
+func ListNodePoolsOnAzure(clusterName, resourceGroupName string) ([]string, error) {
+	fmt.Println("Listing node pools ...")
+	args := []string{"aks", "nodepool", "list", "--resource-group", resourceGroupName, "--cluster-name", clusterName, "--subscription", subscriptionID, "--query", "[].name", "-o", "tsv"}
+	fmt.Printf("Running command: az %v\n", args)
+	out, err := proc.RunW("az", args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list node pools: "+out)
+	}
+	return strings.Fields(out), nil
+}
+
 // UpdateClusterTagOnAzure updates the tags of an existing AKS cluster via CLI
 func UpdateClusterTagOnAzure(tags map[string]string, clusterName, resourceGroupName string, extraArgs ...string) error {
 	fmt.Println("Adding tags on Azure ...")
@@ -603,32 +615,41 @@ func ClusterExistsOnAzure(clusterName, resourceGroup string) (bool, error) {
 	return false, nil
 }
 
-// RunCommand executes `aks command invoke` which runs a command inside a cluster;  useful when registering a private cluster with rancher
-func RunCommand(clusterName, resourceGroup, command string) error {
-	currentKubeconfig := os.Getenv("KUBECONFIG")
-	downstreamKubeconfig := helpers.DownstreamKubeconfig(clusterName)
+// RunCommand executes `aks command invoke` which runs a command inside a cluster and returns its output;  useful when registering a private cluster with rancher
+func RunCommand(clusterName, resourceGroup, command string) (string, error) {
+	// the credentials are written to a kubeconfig file of their own, so that the local cluster kubeconfig is left untouched
+	kubeconfig := helpers.DownstreamKubeconfig(clusterName)
 	defer func() {
-		_ = os.Setenv("KUBECONFIG", currentKubeconfig)
-		_ = os.Remove(downstreamKubeconfig) // clean up
+		_ = helpers.Kubeconfigs.Release(clusterName) // clean up
 	}()
-	_ = os.Setenv("KUBECONFIG", downstreamKubeconfig)
 
 	fmt.Printf("Logging into the cluster")
-	loginArgs := []string{"aks", "get-credentials", "--resource-group", resourceGroup, "--name", clusterName, "--overwrite-existing", "--subscription", subscriptionID}
+	loginArgs := []string{"aks", "get-credentials", "--resource-group", resourceGroup, "--name", clusterName, "--overwrite-existing", "--subscription", subscriptionID, "--file", kubeconfig.Path}
 	fmt.Printf("Running command: az %v\n", loginArgs)
-	out, err := proc.RunW("az", loginArgs...)
+	out, err := kubeconfig.Run("az", loginArgs...)
 	if err != nil {
-		return errors.Wrap(err, "Failed to run command: "+out)
+		return out, errors.Wrap(err, "Failed to run command: "+out)
 	}
 
 	args := []string{"aks", "command", "invoke", "--resource-group", resourceGroup, "--name", clusterName, "--subscription", subscriptionID, "--command", command}
 	fmt.Printf("Running command inside the cluster: az %v\n", args)
 
-	out, err = proc.RunW("az", args...)
+	out, err = kubeconfig.Run("az", args...)
 	if err != nil {
-		return errors.Wrap(err, "Failed to run command: "+out)
+		return out, errors.Wrap(err, "Failed to run command: "+out)
 	}
-	return nil
+	return out, nil
+}
+
+// APIServerEndpointsOnAzure returns the FQDNs of the API server of a cluster: the public one, if any, and the private one of a private cluster
+func APIServerEndpointsOnAzure(clusterName, resourceGroup string) ([]string, error) {
+	args := []string{"aks", "show", "--subscription", subscriptionID, "--name", clusterName, "--resource-group", resourceGroup, "--query", "[fqdn, privateFqdn]", "--output", "tsv"}
+	fmt.Printf("Running command: az %v\n", args)
+	out, err := proc.RunW("az", args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to show cluster: "+out)
+	}
+	return strings.Fields(out), nil
 }
 
 // UpgradeAKSOnAzure upgrade the AKS cluster using az CLI
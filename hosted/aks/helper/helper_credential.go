@@ -0,0 +1,11 @@
+package helper
+
+import (
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+// CredentialOperations returns the operations of the cloud credential lifecycle checks on AKS clusters;
+// the expiry of the client secret of a service principal cannot be simulated without rights on Microsoft Entra ID
+func CredentialOperations() helpers.CredentialOperations {
+	return helpers.CredentialOperations{Trigger: toggleNodeCount}
+}
@@ -0,0 +1,45 @@
+package helper
+
+import (
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/fault"
+)
+
+// FaultOperations returns the operations of the operator fault checks on AKS clusters
+func FaultOperations() helpers.FaultOperations {
+	return helpers.FaultOperations{
+		Converged: func(cluster *management.Cluster) bool {
+			if cluster.AKSStatus == nil || cluster.AKSStatus.UpstreamSpec == nil {
+				return false
+			}
+			config, upstream := cluster.AKSConfig, cluster.AKSStatus.UpstreamSpec
+			if config.KubernetesVersion == nil || upstream.KubernetesVersion == nil || *config.KubernetesVersion != *upstream.KubernetesVersion {
+				return false
+			}
+			missing, extra := fault.Compare(nodePoolNames(config.NodePools), nodePoolNames(upstream.NodePools))
+			return len(missing) == 0 && len(extra) == 0
+		},
+		UpstreamNodePools: func(cluster *management.Cluster) []string {
+			return nodePoolNames(cluster.AKSStatus.UpstreamSpec.NodePools)
+		},
+		CloudNodePools: func(cluster *management.Cluster) ([]string, error) {
+			return ListNodePoolsOnAzure(cluster.AKSConfig.ClusterName, cluster.AKSConfig.ResourceGroup)
+		},
+	}
+}
+
+// nodePoolNames returns the names of the node pools
+func nodePoolNames(nodePools *[]management.AKSNodePool) []string {
+	if nodePools == nil {
+		return nil
+	}
+	var names []string
+	for _, np := range *nodePools {
+		if np.Name != nil {
+			names = append(names, *np.Name)
+		}
+	}
+	return names
+}
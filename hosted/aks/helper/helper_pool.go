@@ -0,0 +1,83 @@
+package helper
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"github.com/rancher/shepherd/extensions/clusters"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/pool"
+)
+
+// PoolProvisioner returns the provisioner of a cluster pool of AKS clusters created from the default config
+func PoolProvisioner(location string) helpers.ClusterProvisioner {
+	return helpers.ClusterProvisioner{
+		Shape: "default",
+		Create: func(client *rancher.Client, cloudCredID, clusterName string) (*management.Cluster, error) {
+			k8sVersion, err := GetK8sVersion(client, cloudCredID, location, false)
+			if err != nil {
+				return nil, err
+			}
+			return CreateAKSHostedCluster(client, clusterName, cloudCredID, k8sVersion, location, nil)
+		},
+		Baseline: poolBaseline,
+		Reset:    resetToBaseline,
+		Delete:   DeleteAKSHostCluster,
+	}
+}
+
+// poolBaseline returns the k8s version, node pools and tags of a cluster
+func poolBaseline(cluster *management.Cluster) pool.Baseline {
+	nodePools := *cluster.AKSConfig.NodePools
+	baseline := pool.Baseline{
+		KubernetesVersion: *cluster.AKSConfig.KubernetesVersion,
+		NodePools:         len(nodePools),
+		Tags:              maps.Clone(cluster.AKSConfig.Tags),
+	}
+	if len(nodePools) > 0 && nodePools[0].Count != nil {
+		baseline.NodeCount = *nodePools[0].Count
+	}
+	return baseline
+}
+
+// resetToBaseline deletes the node pools added to a cluster, scales it back and restores its tags;
+// an upgraded k8s version or a deleted node pool cannot be restored
+func resetToBaseline(cluster *management.Cluster, client *rancher.Client, baseline pool.Baseline) (*management.Cluster, error) {
+	current := poolBaseline(cluster)
+	if current.KubernetesVersion != baseline.KubernetesVersion {
+		return cluster, fmt.Errorf("k8s version %s has been changed to %s", baseline.KubernetesVersion, current.KubernetesVersion)
+	}
+	// DeleteNodePool keeps the first node pool only
+	if current.NodePools < baseline.NodePools || (current.NodePools > baseline.NodePools && baseline.NodePools != 1) {
+		return cluster, fmt.Errorf("%d node pools cannot be restored to %d", current.NodePools, baseline.NodePools)
+	}
+
+	var err error
+	if current.NodePools > baseline.NodePools {
+		cluster, err = DeleteNodePool(cluster, client, true, false)
+		if err != nil {
+			return cluster, err
+		}
+	}
+	if current.NodeCount != baseline.NodeCount {
+		cluster, err = ScaleNodePool(cluster, client, baseline.NodeCount, true, false)
+		if err != nil {
+			return cluster, err
+		}
+	}
+	if !maps.Equal(current.Tags, baseline.Tags) {
+		cluster, err = UpdateCluster(cluster, client, func(cluster *management.Cluster) {
+			cluster.AKSConfig.Tags = maps.Clone(baseline.Tags)
+		})
+		if err != nil {
+			return cluster, err
+		}
+		if err = clusters.WaitClusterToBeUpgraded(client, cluster.ID); err != nil {
+			return cluster, err
+		}
+	}
+	return cluster, nil
+}
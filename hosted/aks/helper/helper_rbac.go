@@ -0,0 +1,53 @@
+package helper
+
+import (
+	"maps"
+
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	namegen "github.com/rancher/shepherd/pkg/namegenerator"
+	"k8s.io/utils/pointer"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+// RBACOperations returns the operations of the RBAC matrix on AKS clusters: the created clusters run k8sVersion, the cluster under test is upgraded to upgradeToVersion
+func RBACOperations(k8sVersion, upgradeToVersion, location string) helpers.RBACOperations {
+	return helpers.RBACOperations{
+		Create: func(client *rancher.Client, clusterName, cloudCredID string) (*management.Cluster, error) {
+			return CreateAKSHostedCluster(client, clusterName, cloudCredID, k8sVersion, location, nil)
+		},
+		Import: func(client *rancher.Client, clusterName, cloudCredID string) (*management.Cluster, error) {
+			return ImportAKSHostedCluster(client, clusterName, cloudCredID, location, helpers.GetCommonMetadataLabels())
+		},
+		Edit: func(client *rancher.Client, cluster *management.Cluster) error {
+			_, err := UpdateCluster(cluster, client, func(upgradedCluster *management.Cluster) {
+				tags := maps.Clone(upgradedCluster.AKSConfig.Tags)
+				if tags == nil {
+					tags = map[string]string{}
+				}
+				tags["rbac-edit"] = namegen.RandStringLower(5)
+				upgradedCluster.AKSConfig.Tags = tags
+			})
+			return err
+		},
+		Scale: toggleNodeCount,
+		Upgrade: func(client *rancher.Client, cluster *management.Cluster) error {
+			_, err := UpdateCluster(cluster, client, func(upgradedCluster *management.Cluster) {
+				upgradedCluster.AKSConfig.KubernetesVersion = &upgradeToVersion
+			})
+			return err
+		},
+	}
+}
+
+// toggleNodeCount scales the node pools of the cluster to their toggled node count, see helpers.ToggledNodeCount
+func toggleNodeCount(client *rancher.Client, cluster *management.Cluster) error {
+	_, err := UpdateCluster(cluster, client, func(upgradedCluster *management.Cluster) {
+		nodePools := *upgradedCluster.AKSConfig.NodePools
+		for i := range nodePools {
+			nodePools[i].Count = pointer.Int64(helpers.ToggledNodeCount(*nodePools[i].Count))
+		}
+	})
+	return err
+}
@@ -5,7 +5,6 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
 
 	"github.com/rancher/hosted-providers-e2e/hosted/aks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
@@ -13,30 +12,27 @@ import (
 
 var _ = Describe("K8sChartSupportUpgradeProvisioning", func() {
 	var (
-		cluster *management.Cluster
+		f *helpers.Fixture
 	)
 	BeforeEach(func() {
+		f = newFixture()
+		f.GuardCost()
 		var err error
-		cluster, err = helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, nil)
+		f.Cluster, err = helper.CreateAKSHostedCluster(f.Client, f.ClusterName, ctx.CloudCredID, f.K8sVersion, location, nil)
 		Expect(err).To(BeNil())
-		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
-		Expect(err).To(BeNil())
-	})
-
-	AfterEach(func() {
-		if ctx.ClusterCleanup && cluster != nil {
-			err := helper.DeleteAKSHostCluster(cluster, ctx.RancherAdminClient)
+		f.AddClusterCleanup(func() {
+			err := helper.DeleteAKSHostCluster(f.Cluster, f.Client)
 			Expect(err).To(BeNil())
-		} else {
-			fmt.Println("Skipping downstream cluster deletion: ", clusterName)
-		}
+		})
+		f.Cluster, err = helpers.WaitUntilClusterIsReady(f.Cluster, f.Client)
+		Expect(err).To(BeNil())
 	})
 
 	It("should successfully test k8s chart support provisioning in an upgrade scenario", func() {
 		GinkgoLogr.Info(fmt.Sprintf("Testing K8s %s chart support for provisioning on Rancher upgraded from %s to %s", helpers.K8sUpgradedMinorVersion, helpers.RancherFullVersion, helpers.RancherUpgradeFullVersion))
 
-		testCaseID = 251 // Report to Qase
-		commonchecks(&ctx, cluster, clusterName, helpers.RancherUpgradeFullVersion, helpers.K8sUpgradedMinorVersion)
+		f.SetQaseID(251) // Report to Qase
+		commonchecks(f, helpers.RancherUpgradeFullVersion, helpers.K8sUpgradedMinorVersion)
 	})
 
 })
@@ -16,18 +16,16 @@ import (
 	nodestat "github.com/rancher/shepherd/extensions/nodes"
 	"github.com/rancher/shepherd/extensions/workloads/pods"
 	"github.com/rancher/shepherd/pkg/config"
-	namegen "github.com/rancher/shepherd/pkg/namegenerator"
 
 	"github.com/rancher/hosted-providers-e2e/hosted/aks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
 )
 
 var (
-	ctx                     helpers.RancherContext
-	clusterName, k8sVersion string
-	testCaseID              int64
-	location                = helpers.GetAKSLocation()
-	k                       = kubectl.New()
+	ctx         helpers.RancherContext
+	location    = helpers.GetAKSLocation()
+	k           = kubectl.New()
+	environment helpers.EnvironmentProfile
 )
 
 func TestK8sChartSupportUpgrade(t *testing.T) {
@@ -42,14 +40,28 @@ var _ = BeforeEach(func() {
 	Expect(helpers.RancherUpgradeFullVersion).ToNot(BeEmpty())
 	Expect(helpers.K8sUpgradedMinorVersion).ToNot(BeEmpty())
 	Expect(helpers.Kubeconfig).ToNot(BeEmpty())
+	helpers.ExpectNoOperatorOverride()
+	environment = helpers.StockEnvironmentProfile()
+
+	// Registered before the fixture of the spec, so that it runs once the downstream cluster has been deleted
+	DeferCleanup(func() {
+		// The test must restore the env to its original state, so we install rancher back to its original version and uninstall the operator charts
+		By(fmt.Sprintf("Installing Rancher back to its original version %s", helpers.RancherFullVersion), func() {
+			helpers.InstallRancherManager(k, environment)
+			helpers.CheckRancherDeployments(k)
+		})
+
+		By("Uninstalling the existing operator charts", func() {
+			helpers.UninstallOperatorCharts()
+		})
+	})
 
 	By("Adding the necessary chart repos", func() {
 		helpers.AddRancherCharts()
 	})
 
 	By(fmt.Sprintf("Installing Rancher Manager %s", helpers.RancherFullVersion), func() {
-		rancherChannel, rancherVersion, rancherHeadVersion := helpers.GetRancherVersions(helpers.RancherFullVersion)
-		helpers.InstallRancherManager(k, helpers.RancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, "", "")
+		helpers.InstallRancherManager(k, environment)
 		helpers.CheckRancherDeployments(k)
 	})
 
@@ -68,42 +80,27 @@ var _ = BeforeEach(func() {
 		Expect(err).To(BeNil())
 		ctx.RancherAdminClient = rancherAdminClient
 	})
+})
 
-	clusterName = namegen.AppendRandomString(helpers.ClusterNamePrefix)
+// newFixture returns the fixture of the current spec with the k8s version its cluster is created with
+func newFixture() *helpers.Fixture {
+	f := helpers.NewFixture(&ctx)
 	var err error
 	// For k8s chart support upgrade we want to begin with the default k8s version; we will upgrade rancher and then upgrade k8s to the default available there.
-	k8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, ctx.CloudCredID, location, false)
+	f.K8sVersion, err = helper.GetK8sVersion(f.Client, ctx.CloudCredID, location, false)
 	Expect(err).To(BeNil())
-	Expect(k8sVersion).ToNot(BeEmpty())
-	GinkgoLogr.Info(fmt.Sprintf("Using AKS version %s for cluster %s", k8sVersion, clusterName))
-
-})
-
-var _ = AfterEach(func() {
-	// The test must restore the env to its original state, so we install rancher back to its original version and uninstall the operator charts
-	By(fmt.Sprintf("Installing Rancher back to its original version %s", helpers.RancherFullVersion), func() {
-		rancherChannel, rancherVersion, rancherHeadVersion := helpers.GetRancherVersions(helpers.RancherFullVersion)
-		helpers.InstallRancherManager(k, helpers.RancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, "", "")
-		helpers.CheckRancherDeployments(k)
-	})
-
-	By("Uninstalling the existing operator charts", func() {
-		helpers.UninstallOperatorCharts()
-	})
-})
-
-var _ = ReportBeforeEach(func(report SpecReport) {
-	// Reset case ID
-	testCaseID = -1
-})
+	Expect(f.K8sVersion).ToNot(BeEmpty())
+	GinkgoLogr.Info(fmt.Sprintf("Using AKS version %s for cluster %s", f.K8sVersion, f.ClusterName))
+	return f
+}
 
 var _ = ReportAfterEach(func(report SpecReport) {
 	// Add result in Qase if asked
-	Qase(testCaseID, report)
+	Qase(helpers.QaseID(report), report)
 })
 
-func commonchecks(ctx *helpers.RancherContext, cluster *management.Cluster, clusterName, rancherUpgradedVersion, k8sUpgradedVersion string) {
-	helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
+func commonchecks(f *helpers.Fixture, rancherUpgradedVersion, k8sUpgradedVersion string) {
+	helpers.ClusterIsReadyChecks(f.Cluster, f.Client, f.ClusterName)
 
 	var originalChartVersion string
 
@@ -114,8 +111,7 @@ func commonchecks(ctx *helpers.RancherContext, cluster *management.Cluster, clus
 	})
 
 	By("upgrading rancher", func() {
-		rancherChannel, rancherVersion, rancherHeadVersion := helpers.GetRancherVersions(rancherUpgradedVersion)
-		helpers.InstallRancherManager(k, helpers.RancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, "", "")
+		helpers.InstallRancherManager(k, environment.WithRancherVersion(rancherUpgradedVersion))
 		helpers.CheckRancherDeployments(k)
 
 		By("ensuring operator pods are also up", func() {
@@ -124,7 +120,7 @@ func commonchecks(ctx *helpers.RancherContext, cluster *management.Cluster, clus
 			}, tools.SetTimeout(4*time.Minute), 30*time.Second).Should(BeNil())
 		})
 		By("ensuring the rancher client is connected", func() {
-			isConnected, err := ctx.RancherAdminClient.IsConnected()
+			isConnected, err := f.Client.IsConnected()
 			Expect(err).To(BeNil())
 			Expect(isConnected).To(BeTrue())
 		})
@@ -133,12 +129,12 @@ func commonchecks(ctx *helpers.RancherContext, cluster *management.Cluster, clus
 	By("making sure the local cluster is ready", func() {
 		const localClusterID = "local"
 		By("checking all management nodes are ready", func() {
-			err := nodestat.AllManagementNodeReady(ctx.RancherAdminClient, localClusterID, helpers.Timeout)
+			err := nodestat.AllManagementNodeReady(f.Client, localClusterID, helpers.Timeout)
 			Expect(err).To(BeNil())
 		})
 
 		By("checking all pods are ready", func() {
-			podErrors := pods.StatusPods(ctx.RancherAdminClient, localClusterID)
+			podErrors := pods.StatusPods(f.Client, localClusterID)
 			Expect(podErrors).To(BeEmpty())
 		})
 	})
@@ -152,27 +148,27 @@ func commonchecks(ctx *helpers.RancherContext, cluster *management.Cluster, clus
 
 	By("making sure the downstream cluster is ready", func() {
 		var err error
-		cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
+		f.Cluster, err = f.Client.Management.Cluster.ByID(f.Cluster.ID)
 		Expect(err).To(BeNil())
-		helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
+		helpers.ClusterIsReadyChecks(f.Cluster, f.Client, f.ClusterName)
 
 		// since no changes have been made to the cluster so far, we need reinstantiate AKSConfig after fetching the cluster
 		if helpers.IsImport {
-			cluster.AKSConfig = cluster.AKSStatus.UpstreamSpec
+			f.Cluster.AKSConfig = f.Cluster.AKSStatus.UpstreamSpec
 		}
 	})
 
 	var latestK8sVersion string
 	By(fmt.Sprintf("fetching a list of available k8s versions and ensure the v%s is present in the list and upgrading the cluster to it", k8sUpgradedVersion), func() {
-		versions, err := helper.ListAKSAvailableVersions(ctx.RancherAdminClient, cluster.ID)
+		versions, err := helper.ListAKSAvailableVersions(f.Client, f.Cluster.ID)
 		Expect(err).To(BeNil())
 		Expect(versions).ToNot(BeEmpty())
 		GinkgoLogr.Info(fmt.Sprintf("Available AKS versions: %v", versions))
 
 		latestK8sVersion = versions[len(versions)-1]
 		Expect(latestK8sVersion).To(ContainSubstring(k8sUpgradedVersion))
-		Expect(helpers.VersionCompare(latestK8sVersion, cluster.Version.GitVersion)).To(BeNumerically("==", 1))
-		cluster, err = helper.UpgradeClusterKubernetesVersion(cluster, latestK8sVersion, ctx.RancherAdminClient, true)
+		Expect(helpers.VersionCompare(latestK8sVersion, f.Cluster.Version.GitVersion)).To(BeNumerically("==", 1))
+		f.Cluster, err = helper.UpgradeClusterKubernetesVersion(f.Cluster, latestK8sVersion, f.Client, true)
 		Expect(err).To(BeNil())
 	})
 
@@ -187,7 +183,7 @@ func commonchecks(ctx *helpers.RancherContext, cluster *management.Cluster, clus
 
 	By("making a change to the cluster (upgrade nodepool k8s version) to validate functionality after chart downgrade", func() {
 		var err error
-		cluster, err = helper.UpgradeNodeKubernetesVersion(cluster, latestK8sVersion, ctx.RancherAdminClient, true, true)
+		f.Cluster, err = helper.UpgradeNodeKubernetesVersion(f.Cluster, latestK8sVersion, f.Client, true, true)
 		Expect(err).To(BeNil())
 	})
 
@@ -196,23 +192,23 @@ func commonchecks(ctx *helpers.RancherContext, cluster *management.Cluster, clus
 	})
 
 	By("making a change(adding a nodepool) to the cluster to re-install the operator and validating it is re-installed to the latest/upgraded version", func() {
-		currentNodePoolNumber := len(*cluster.AKSConfig.NodePools)
+		currentNodePoolNumber := len(*f.Cluster.AKSConfig.NodePools)
 		var err error
-		cluster, err = helper.AddNodePool(cluster, 1, ctx.RancherAdminClient, false, false)
+		f.Cluster, err = helper.AddNodePool(f.Cluster, 1, f.Client, false, false)
 		Expect(err).To(BeNil())
-		Expect(len(*cluster.AKSConfig.NodePools)).To(BeNumerically("==", currentNodePoolNumber+1))
+		Expect(len(*f.Cluster.AKSConfig.NodePools)).To(BeNumerically("==", currentNodePoolNumber+1))
 
 		By("ensuring that the chart is re-installed to the latest/upgraded version", func() {
 			helpers.WaitUntilOperatorChartInstallation(upgradedChartVersion, "", 0)
 		})
 
-		err = clusters.WaitClusterToBeUpgraded(ctx.RancherAdminClient, cluster.ID)
+		err = clusters.WaitClusterToBeUpgraded(f.Client, f.Cluster.ID)
 		Expect(err).To(BeNil())
 		// Check if the desired config has been applied in Rancher
 		Eventually(func() int {
-			cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
+			f.Cluster, err = f.Client.Management.Cluster.ByID(f.Cluster.ID)
 			Expect(err).To(BeNil())
-			return len(*cluster.AKSStatus.UpstreamSpec.NodePools)
+			return len(*f.Cluster.AKSStatus.UpstreamSpec.NodePools)
 		}, tools.SetTimeout(10*time.Minute), 3*time.Second).Should(BeNumerically("==", currentNodePoolNumber+1))
 
 	})
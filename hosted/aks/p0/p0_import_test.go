@@ -19,8 +19,6 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	"github.com/rancher/shepherd/clients/rancher"
-	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
 
 	"github.com/rancher/hosted-providers-e2e/hosted/aks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
@@ -30,7 +28,7 @@ var _ = Describe("P0Import", func() {
 	for _, testData := range []struct {
 		qaseID    int64
 		isUpgrade bool
-		testBody  func(cluster *management.Cluster, client *rancher.Client, clusterName string)
+		testBody  func(f *helpers.Fixture)
 		testTitle string
 	}{
 		{
@@ -48,40 +46,40 @@ var _ = Describe("P0Import", func() {
 	} {
 		testData := testData
 		When("a cluster is imported", func() {
+			var f *helpers.Fixture
+
 			BeforeEach(func() {
 				if testData.isUpgrade && helpers.SkipUpgradeTests {
 					Skip("Skipping upgrade tests ...")
 				}
-				k8sVersion, err := helper.GetK8sVersion(ctx.RancherAdminClient, ctx.CloudCredID, location, testData.isUpgrade)
+
+				f = helpers.NewFixture(&ctx)
+				f.AddClusterCleanup(func() {
+					if f.Cluster != nil && f.Cluster.ID != "" {
+						GinkgoLogr.Info(fmt.Sprintf("Cleaning up resource cluster: %s %s", f.Cluster.Name, f.Cluster.ID))
+						err := helper.DeleteAKSHostCluster(f.Cluster, f.Client)
+						Expect(err).To(BeNil())
+					}
+					err := helper.DeleteAKSClusteronAzure(f.ClusterName)
+					Expect(err).To(BeNil())
+				})
+				var err error
+				f.K8sVersion, err = helper.GetK8sVersion(f.Client, ctx.CloudCredID, location, testData.isUpgrade)
 				Expect(err).NotTo(HaveOccurred())
-				GinkgoLogr.Info(fmt.Sprintf("Using K8s version %s for cluster %s", k8sVersion, clusterName))
+				GinkgoLogr.Info(fmt.Sprintf("Using K8s version %s for cluster %s", f.K8sVersion, f.ClusterName))
 
-				err = helper.CreateAKSClusterOnAzure(location, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
+				err = helper.CreateAKSClusterOnAzure(location, f.ClusterName, f.K8sVersion, "1", helpers.GetCommonMetadataLabels())
 				Expect(err).To(BeNil())
 
-				cluster, err = helper.ImportAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, location, helpers.GetCommonMetadataLabels())
+				f.Cluster, err = helper.ImportAKSHostedCluster(f.Client, f.ClusterName, ctx.CloudCredID, location, helpers.GetCommonMetadataLabels())
 				Expect(err).To(BeNil())
-				cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+				f.Cluster, err = helpers.WaitUntilClusterIsReady(f.Cluster, f.Client)
 				Expect(err).To(BeNil())
 			})
 
-			AfterEach(func() {
-				if ctx.ClusterCleanup {
-					if cluster != nil && cluster.ID != "" {
-						GinkgoLogr.Info(fmt.Sprintf("Cleaning up resource cluster: %s %s", cluster.Name, cluster.ID))
-						err := helper.DeleteAKSHostCluster(cluster, ctx.RancherAdminClient)
-						Expect(err).To(BeNil())
-					}
-					err := helper.DeleteAKSClusteronAzure(clusterName)
-					Expect(err).To(BeNil())
-				} else {
-					fmt.Println("Skipping downstream cluster deletion: ", clusterName)
-				}
-			})
-
 			It(testData.testTitle, func() {
-				testCaseID = testData.qaseID
-				testData.testBody(cluster, ctx.RancherAdminClient, clusterName)
+				f.SetQaseID(testData.qaseID)
+				testData.testBody(f)
 			})
 		})
 	}
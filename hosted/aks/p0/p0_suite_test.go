@@ -21,9 +21,6 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	. "github.com/rancher-sandbox/qase-ginkgo"
-	"github.com/rancher/shepherd/clients/rancher"
-	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
-	namegen "github.com/rancher/shepherd/pkg/namegenerator"
 
 	"github.com/rancher/hosted-providers-e2e/hosted/aks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
@@ -34,11 +31,8 @@ const (
 )
 
 var (
-	ctx         helpers.RancherContext
-	cluster     *management.Cluster
-	clusterName string
-	testCaseID  int64
-	location    = helpers.GetAKSLocation()
+	ctx      helpers.RancherContext
+	location = helpers.GetAKSLocation()
 )
 
 func TestP0(t *testing.T) {
@@ -53,66 +47,62 @@ var _ = SynchronizedBeforeSuite(func() []byte {
 	ctx = helpers.CommonBeforeSuite()
 })
 
-var _ = BeforeEach(func() {
-	// Setting this to nil ensures we do not use the `cluster` variable value from another test running in parallel with this one.
-	cluster = nil
-	clusterName = namegen.AppendRandomString(helpers.ClusterNamePrefix)
-})
-
-var _ = ReportBeforeEach(func(report SpecReport) {
-	// Reset case ID
-	testCaseID = -1
-})
-
 var _ = ReportAfterEach(func(report SpecReport) {
 	// Add result in Qase if asked
-	Qase(testCaseID, report)
+	Qase(helpers.QaseID(report), report)
 })
 
-func p0upgradeK8sVersionCheck(cluster *management.Cluster, client *rancher.Client, clusterName string) {
-	versions, err := helper.ListAKSAvailableVersions(client, cluster.ID)
+func p0upgradeK8sVersionCheck(f *helpers.Fixture) {
+	versions, err := helper.ListAKSAvailableVersions(f.Client, f.Cluster.ID)
 	Expect(err).To(BeNil())
 	Expect(versions).ToNot(BeEmpty())
-	upgradeToVersion := versions[0]
-	GinkgoLogr.Info(fmt.Sprintf("Upgrading cluster to AKS version %s", upgradeToVersion))
+	f.UpgradeToVersion = versions[0]
+	GinkgoLogr.Info(fmt.Sprintf("Upgrading cluster to AKS version %s", f.UpgradeToVersion))
 
 	By("upgrading the ControlPlane", func() {
-		cluster, err = helper.UpgradeClusterKubernetesVersion(cluster, upgradeToVersion, client, true)
+		f.Cluster, err = helper.UpgradeClusterKubernetesVersion(f.Cluster, f.UpgradeToVersion, f.Client, true)
 		Expect(err).To(BeNil())
 	})
 
 	By("upgrading the NodePools", func() {
-		cluster, err = helper.UpgradeNodeKubernetesVersion(cluster, upgradeToVersion, client, true, true)
+		f.Cluster, err = helper.UpgradeNodeKubernetesVersion(f.Cluster, f.UpgradeToVersion, f.Client, true, true)
 		Expect(err).To(BeNil())
 	})
 }
 
-func p0NodesChecks(cluster *management.Cluster, client *rancher.Client, clusterName string) {
+func p0NodesChecks(f *helpers.Fixture) {
 
-	helpers.ClusterIsReadyChecks(cluster, client, clusterName)
-	configNodePools := *cluster.AKSConfig.NodePools
+	helpers.ClusterIsReadyChecks(f.Cluster, f.Client, f.ClusterName)
+	helpers.WorkloadChecks(f.Cluster, f.Client, helper.WorkloadOptions())
+	configNodePools := *f.Cluster.AKSConfig.NodePools
 	initialNodeCount := *configNodePools[0].Count
 
 	By("adding a nodepool", func() {
 		var err error
-		cluster, err = helper.AddNodePool(cluster, increaseBy, client, true, true)
+		f.Cluster, err = helper.AddNodePool(f.Cluster, increaseBy, f.Client, true, true)
 		Expect(err).To(BeNil())
 	})
 	By("deleting the nodepool", func() {
 		var err error
-		cluster, err = helper.DeleteNodePool(cluster, client, true, true)
+		f.Cluster, err = helper.DeleteNodePool(f.Cluster, f.Client, true, true)
 		Expect(err).To(BeNil())
 	})
 
 	By("scaling up the nodepool", func() {
 		var err error
-		cluster, err = helper.ScaleNodePool(cluster, client, initialNodeCount+1, true, true)
+		f.Cluster, err = helper.ScaleNodePool(f.Cluster, f.Client, initialNodeCount+1, true, true)
 		Expect(err).To(BeNil())
 	})
 
 	By("scaling down the nodepool", func() {
 		var err error
-		cluster, err = helper.ScaleNodePool(cluster, client, initialNodeCount, true, true)
+		f.Cluster, err = helper.ScaleNodePool(f.Cluster, f.Client, initialNodeCount, true, true)
 		Expect(err).To(BeNil())
 	})
+
+	if helpers.GetEnvironmentProfile().Proxy.Enabled {
+		By("checking that the operator routes through the proxy", func() {
+			helpers.ProxyRoutingChecks()
+		})
+	}
 }
@@ -14,7 +14,6 @@ import (
 	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
 	"github.com/rancher/shepherd/extensions/clusters"
 	"github.com/rancher/shepherd/extensions/clusters/aks"
-	"github.com/rancher/shepherd/extensions/tokenregistration"
 	namegen "github.com/rancher/shepherd/pkg/namegenerator"
 	"k8s.io/utils/pointer"
 
@@ -367,11 +366,6 @@ var _ = Describe("P1Provisioning", func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 
-		It("should successfully update with new cloud credentials", func() {
-			testCaseID = 221
-			updateCloudCredentialsCheck(cluster, ctx.RancherAdminClient)
-		})
-
 		It("should fail to update with invalid (deleted) cloud credential and update when the cloud credentials becomes valid", func() {
 			testCaseID = 299
 			invalidateCloudCredentialsCheck(cluster, ctx.RancherAdminClient, ctx.CloudCredID)
@@ -428,35 +422,6 @@ var _ = Describe("P1Provisioning", func() {
 			Expect(out).To(ContainSubstring(fmt.Sprintf("\"name\": \"%s\"", clusterName)))
 		})
 
-		It("should be able to update autoscaling", func() {
-			testCaseID = 176
-			updateAutoScaling(cluster, ctx.RancherAdminClient)
-		})
-
-		It("should be able to update tags", func() {
-			testCaseID = 177
-			updateTagsCheck(cluster, ctx.RancherAdminClient)
-		})
-
-		It("should have cluster monitoring disabled by default", func() {
-			testCaseID = 198
-			Expect(cluster.AKSConfig.Monitoring).To(BeNil())
-			Expect(cluster.AKSStatus.UpstreamSpec.Monitoring).To(BeNil())
-		})
-
-		It("should fail to change system nodepool count to 0", func() {
-			testCaseID = 202
-			updateSystemNodePoolCountToZeroCheck(cluster, ctx.RancherAdminClient)
-		})
-
-		It("should be able to update cluster monitoring", func() {
-			if helpers.SkipUpgradeTests {
-				Skip(helpers.SkipUpgradeTestsLog)
-			}
-			testCaseID = 200
-			updateMonitoringCheck(cluster, ctx.RancherAdminClient)
-		})
-
 		It("recreating a cluster while it is being deleted should recreate the cluster", func() {
 			testCaseID = 219
 
@@ -488,6 +453,60 @@ var _ = Describe("P1Provisioning", func() {
 	})
 
 	// Refer: https://github.com/rancher/hosted-providers-e2e/issues/192
+	When("a cluster is leased from the pool", func() {
+		var (
+			f     *helpers.Fixture
+			lease *helpers.Lease
+		)
+
+		BeforeEach(func() {
+			f = helpers.NewFixture(&ctx)
+			lease = clusterPool.Lease(f)
+		})
+
+		It("should successfully update with new cloud credentials", func() {
+			testCaseID = 221
+			updateCloudCredentialsCheck(f.Cluster, f.Client)
+		})
+
+		It("should report the failures of its cloud credential and recover once it is valid again", func() {
+			f.Cluster = helpers.CloudCredentialLifecycleChecks(f.Cluster, f.Client, helper.CredentialOperations())
+		})
+
+		It("should be able to update autoscaling", func() {
+			testCaseID = 176
+			updateAutoScaling(f.Cluster, f.Client)
+		})
+
+		It("should be able to update tags", func() {
+			testCaseID = 177
+			updateTagsCheck(f.Cluster, f.Client)
+		})
+
+		It("should have cluster monitoring disabled by default", func() {
+			testCaseID = 198
+			Expect(f.Cluster.AKSConfig.Monitoring).To(BeNil())
+			Expect(f.Cluster.AKSStatus.UpstreamSpec.Monitoring).To(BeNil())
+		})
+
+		It("should fail to change system nodepool count to 0", func() {
+			testCaseID = 202
+			// the cluster is left in error
+			lease.Retire("the count of the system node pool has been set to 0")
+			updateSystemNodePoolCountToZeroCheck(f.Cluster, f.Client)
+		})
+
+		It("should be able to update cluster monitoring", func() {
+			if helpers.SkipUpgradeTests {
+				Skip(helpers.SkipUpgradeTestsLog)
+			}
+			testCaseID = 200
+			// the monitoring is not part of the baseline of the pool clusters
+			lease.Retire("the cluster monitoring has been updated")
+			updateMonitoringCheck(f.Cluster, f.Client)
+		})
+	})
+
 	It("should successfully create 2 clusters in the same RG", func() {
 		testCaseID = 214
 
@@ -802,6 +821,8 @@ var _ = Describe("P1Provisioning", func() {
 				cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
 				Expect(err).To(BeNil())
 				helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
+				// without a network policy, AKS accepts the NetworkPolicies but nothing enforces them
+				helpers.NetworkPolicyChecks(cluster, ctx.RancherAdminClient, helper.WorkloadOptions(), data.networkPolicy != none)
 			})
 		}
 	})
@@ -809,15 +830,8 @@ var _ = Describe("P1Provisioning", func() {
 	Context("Private Cluster", func() {
 		// Previously blocked on: https://github.com/rancher/rancher/issues/43772
 		BeforeEach(func() {
+			helpers.SkipUnsupportedPrivateClusters(ctx.RancherAdminClient)
 			var err error
-			serverVersion, err := helpers.GetRancherServerVersion(ctx.RancherAdminClient)
-			Expect(err).NotTo(HaveOccurred())
-			// The feature is supported from v2.12+
-			isSupported, err := helpers.IsRancherVersionGreaterThanOrEqualTo(serverVersion, "2.12.0")
-			Expect(err).NotTo(HaveOccurred())
-			if !isSupported {
-				Skip("Private cluster tests are not supported on Rancher versions older than v2.12")
-			}
 			k8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, ctx.CloudCredID, location, true)
 			Expect(err).NotTo(HaveOccurred())
 			GinkgoLogr.Info(fmt.Sprintf("Using K8s version %s for cluster %s", k8sVersion, clusterName))
@@ -829,22 +843,9 @@ var _ = Describe("P1Provisioning", func() {
 			cluster, err = helper.CreateAKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, location, createFunc)
 			Expect(err).ToNot(HaveOccurred())
 
-			Eventually(func() bool {
-				cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
-				Expect(err).To(BeNil())
-				return cluster.Transitioning == "error" && strings.Contains(cluster.TransitioningMessage, "failed to communicate with cluster: error generating service account token") && strings.Contains(cluster.TransitioningMessage, "cluster agent disconnected")
-			}, "12m", "10s").Should(BeTrue(), "Timed out while waiting for cluster to be ready for registration")
-
-			registrationToken, err1 := tokenregistration.GetRegistrationToken(ctx.RancherAdminClient, cluster.ID)
-			Expect(err1).To(BeNil())
-			err = helper.RunCommand(cluster.AKSConfig.ClusterName, cluster.AKSConfig.ResourceGroup, registrationToken.InsecureCommand)
-			Expect(err).To(BeNil())
-
-			//Failed to communicate with cluster: error generating service account token: Post "https://auto-aks-pvala-hp-ci-rqbte-dns-gs1f40rx.006641f3-b627-466d-917b-c2c6bca16c4c.privatelink.centralindia.azmk8s.io:443/api/v1/namespaces": dial tcp: address auto-aks-pvala-hp-ci-rqbte-dns-gs1f40rx.006641f3-b627-466d-917b-c2c6bca16c4c.priva
-
-			//Failed to communicate with cluster: error generating service account token: Post "https://pvala-aks-dns-i0lznum3.9f8ba64d-e36e-4aaa-84d2-d0a8935cdb32.privatelink.centralindia.azmk8s.io:443/api/v1/namespaces": cluster agent disconnected
-			cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
-			Expect(err).To(BeNil())
+			cluster = helpers.RegisterPrivateCluster(cluster, ctx.RancherAdminClient, func(command string) (string, error) {
+				return helper.RunCommand(cluster.AKSConfig.ClusterName, cluster.AKSConfig.ResourceGroup, command)
+			})
 		})
 		It("should successfully Create a private cluster", func() {
 			testCaseID = 240 // 241, 242
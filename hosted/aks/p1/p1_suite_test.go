@@ -16,6 +16,7 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/aks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/credential"
 )
 
 var (
@@ -23,6 +24,8 @@ var (
 	cluster               *management.Cluster
 	clusterName, location string
 	testCaseID            int64
+	// clusterPool leases the default shaped clusters of the specs which do not need a cluster of their own
+	clusterPool *helpers.ClusterPool
 )
 
 func TestP1(t *testing.T) {
@@ -32,9 +35,17 @@ func TestP1(t *testing.T) {
 
 var _ = SynchronizedBeforeSuite(func() []byte {
 	helpers.CommonSynchronizedBeforeSuite()
-	return nil
-}, func() {
+	return helpers.CreateClusterPool(helper.PoolProvisioner(helpers.GetAKSLocation()))
+}, func(data []byte) {
 	ctx = helpers.CommonBeforeSuite()
+	clusterPool = helpers.OpenClusterPool(data, &ctx, helper.PoolProvisioner(helpers.GetAKSLocation()))
+})
+
+var _ = SynchronizedAfterSuite(func() {}, func() {
+	// the pool is not created when the BeforeSuite failed
+	if clusterPool != nil {
+		clusterPool.Drain()
+	}
 })
 
 var _ = BeforeEach(func() {
@@ -400,21 +411,9 @@ func updateNodePoolModeCheck(cluster *management.Cluster, client *rancher.Client
 
 // Qase ID: 221 and 292
 func updateCloudCredentialsCheck(cluster *management.Cluster, client *rancher.Client) {
-	newCCID, err := helpers.CreateCloudCredentials(client)
-	Expect(err).To(BeNil())
-	updateFunc := func(cluster *management.Cluster) {
-		cluster.AKSConfig.AzureCredentialSecret = newCCID
-	}
-	cluster, err = helper.UpdateCluster(cluster, client, updateFunc)
-	Expect(err).To(BeNil())
-	Expect(cluster.AKSConfig.AzureCredentialSecret).To(Equal(newCCID))
-	Eventually(func() bool {
-		cluster, err = client.Management.Cluster.ByID(cluster.ID)
-		Expect(err).NotTo(HaveOccurred())
-		return cluster.AKSStatus.UpstreamSpec.AzureCredentialSecret == newCCID
-	}, "5m", "5s").Should(BeTrue(), "Failed while upstream cloud credentials update")
+	cluster, _ = helpers.RotateCloudCredential(cluster, client)
 
-	cluster, err = helper.AddNodePool(cluster, 1, client, true, true)
+	cluster, err := helper.AddNodePool(cluster, 1, client, true, true)
 	Expect(err).To(BeNil())
 }
 
@@ -556,42 +555,24 @@ func noAvailabilityZoneP0Checks(cluster *management.Cluster, client *rancher.Cli
 
 // Qase ID: 299, and 238
 func invalidateCloudCredentialsCheck(cluster *management.Cluster, client *rancher.Client, cloudCredID string) {
-	currentCC, err := client.Management.CloudCredential.ByID(cloudCredID)
-	Expect(err).To(BeNil())
-	err = client.Management.CloudCredential.Delete(currentCC)
-	Expect(err).To(BeNil())
-	GinkgoLogr.Info(fmt.Sprintf("Deleting existing Cloud Credentials: %s:%s", currentCC.Name, currentCC.ID))
 	const scaleCount int64 = 2
-	cluster, err = helper.ScaleNodePool(cluster, client, scaleCount, false, false)
-	Expect(err).To(BeNil())
-	Eventually(func() string {
-		cluster, err = client.Management.Cluster.ByID(cluster.ID)
-		Expect(err).NotTo(HaveOccurred())
-		return cluster.Transitioning
-	}, "3m", "2s").Should(Equal("error"), "Timed out waiting for cluster to transition into error")
+	helpers.DeleteCloudCredential(client, cloudCredID)
+	scale := func(client *rancher.Client, cluster *management.Cluster) error {
+		_, err := helper.ScaleNodePool(cluster, client, scaleCount, false, false)
+		return err
+	}
+	cluster = helpers.WaitForCloudCredentialError(cluster, client, helpers.CredentialOperations{Trigger: scale}, credential.Deleted)
 
 	// Create new cloud credentials and update the cluster config with it
-	newCCID, err := helpers.CreateCloudCredentials(client)
-	Expect(err).To(BeNil())
-	updateFunc := func(cluster *management.Cluster) {
-		cluster.AKSConfig.AzureCredentialSecret = newCCID
-	}
-	cluster, err = helper.UpdateCluster(cluster, client, updateFunc)
-	Expect(err).To(BeNil())
-	Expect(cluster.AKSConfig.AzureCredentialSecret).To(Equal(newCCID))
-	err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
-	Expect(err).To(BeNil())
-	Eventually(func() bool {
-		cluster, err = client.Management.Cluster.ByID(cluster.ID)
-		Expect(err).NotTo(HaveOccurred())
-		return cluster.AKSStatus.UpstreamSpec.AzureCredentialSecret == newCCID
-	}, "5m", "5s").Should(BeTrue())
+	cluster, newCCID := helpers.RotateCloudCredential(cluster, client)
+	cluster = helpers.WaitForCloudCredentialRecovery(cluster, client)
 
 	for _, nodepool := range *cluster.AKSConfig.NodePools {
 		Expect(*nodepool.Count).To(Equal(scaleCount))
 	}
 
 	// This is sometimes flaky, so using Eventually
+	var err error
 	Eventually(func() bool {
 		cluster, err = client.Management.Cluster.ByID(cluster.ID)
 		Expect(err).NotTo(HaveOccurred())
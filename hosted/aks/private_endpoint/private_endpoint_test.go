@@ -0,0 +1,67 @@
+/*
+Copyright © 2022 - 2025 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package private_endpoint_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher/shepherd/extensions/clusters/aks"
+	"k8s.io/utils/pointer"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/aks/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var _ = Describe("PrivateEndpoint", func() {
+	var f *helpers.Fixture
+
+	BeforeEach(func() {
+		helpers.SkipUnsupportedPrivateClusters(ctx.RancherAdminClient)
+
+		f = helpers.NewFixture(&ctx)
+		f.AddClusterCleanup(func() {
+			if f.Cluster != nil && f.Cluster.ID != "" {
+				GinkgoLogr.Info(fmt.Sprintf("Cleaning up resource cluster: %s %s", f.Cluster.Name, f.Cluster.ID))
+				err := helper.DeleteAKSHostCluster(f.Cluster, f.Client)
+				Expect(err).To(BeNil())
+			}
+		})
+		var err error
+		f.K8sVersion, err = helper.GetK8sVersion(f.Client, ctx.CloudCredID, location, false)
+		Expect(err).NotTo(HaveOccurred())
+		GinkgoLogr.Info(fmt.Sprintf("Using K8s version %s for cluster %s", f.K8sVersion, f.ClusterName))
+
+		createFunc := func(clusterConfig *aks.ClusterConfig) {
+			clusterConfig.PrivateCluster = pointer.Bool(true)
+		}
+		f.Cluster, err = helper.CreateAKSHostedCluster(f.Client, f.ClusterName, ctx.CloudCredID, f.K8sVersion, location, createFunc)
+		Expect(err).To(BeNil())
+	})
+
+	It("should register a private cluster with command invoke and reach it through the agent tunnel only", func() {
+		clusterName, resourceGroup := f.Cluster.AKSConfig.ClusterName, f.Cluster.AKSConfig.ResourceGroup
+		f.Cluster = helpers.RegisterPrivateCluster(f.Cluster, f.Client, func(command string) (string, error) {
+			return helper.RunCommand(clusterName, resourceGroup, command)
+		})
+		helpers.ClusterIsReadyChecks(f.Cluster, f.Client, f.ClusterName)
+
+		// the public FQDN of a private cluster resolves to the private address of the API server, the private FQDN only resolves in the VNet
+		endpoints, err := helper.APIServerEndpointsOnAzure(clusterName, resourceGroup)
+		Expect(err).To(BeNil())
+		helpers.PrivateEndpointChecks(f.Cluster, f.Client, endpoints...)
+	})
+})
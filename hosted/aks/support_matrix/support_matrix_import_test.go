@@ -39,6 +39,7 @@ var _ = Describe("SupportMatrixImport", func() {
 			)
 			BeforeEach(func() {
 				clusterName = namegen.AppendRandomString(helpers.ClusterNamePrefix)
+				helpers.GuardClusterCost(clusterName)
 				err := helper.CreateAKSClusterOnAzure(location, clusterName, version, "1", helpers.GetCommonMetadataLabels())
 				Expect(err).To(BeNil())
 				cluster, err = helper.ImportAKSHostedCluster(ctx.StdUserClient, clusterName, ctx.CloudCredID, location, helpers.GetCommonMetadataLabels())
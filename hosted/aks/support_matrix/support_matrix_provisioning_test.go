@@ -39,6 +39,7 @@ var _ = Describe("SupportMatrixProvisioning", func() {
 			)
 			BeforeEach(func() {
 				clusterName = namegen.AppendRandomString(helpers.ClusterNamePrefix)
+				helpers.GuardClusterCost(clusterName)
 				var err error
 				cluster, err = helper.CreateAKSHostedCluster(ctx.StdUserClient, clusterName, ctx.CloudCredID, version, location, nil)
 				Expect(err).To(BeNil())
@@ -0,0 +1,285 @@
+/*
+Copyright © 2022 - 2025 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup_restore_test
+
+import (
+	"fmt"
+	"os/exec"
+	"slices"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/kubectl"
+	. "github.com/rancher-sandbox/qase-ginkgo"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/cce/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+const (
+	increaseBy                   = 1
+	backupResourceName           = "hp-backup"
+	restoreResourceName          = "hp-restore"
+	encryptedBackupResourceName  = "hp-backup-encrypted"
+	encryptedRestoreResourceName = "hp-restore-encrypted"
+)
+
+var (
+	ctx         helpers.RancherContext
+	region      = helpers.GetCCERegion()
+	environment helpers.EnvironmentProfile
+)
+
+func TestBackupRestore(t *testing.T) {
+	RegisterFailHandler(Fail)
+	helpers.CommonSynchronizedBeforeSuite()
+	ctx = helpers.CommonBeforeSuite()
+	helpers.ExpectNoOperatorOverride()
+	environment = helpers.StockEnvironmentProfile()
+	RunSpecs(t, "BackupRestore Suite")
+}
+
+var _ = ReportAfterEach(func(report SpecReport) {
+	// Add result in Qase if asked
+	Qase(helpers.QaseID(report), report)
+})
+
+var _ = BeforeEach(func() {
+	if slices.Contains(CurrentSpecReport().Labels(), "migration") {
+		// For migration tests, the rancher version should not be an unreleased version (for e.g. 2.9-head)
+		Expect(helpers.RancherFullVersion).To(SatisfyAll(Not(BeEmpty()), Not(ContainSubstring("devel"))))
+		Expect(helpers.RancherUpgradeFullVersion).ToNot(BeEmpty())
+	}
+})
+
+// newFixture provisions or imports the cluster of the current spec, it is deleted once the spec is done
+func newFixture() *helpers.Fixture {
+	f := helpers.NewFixture(&ctx)
+	// cceClusterID is the ID of the imported cluster on Huawei Cloud, it is needed to delete it
+	var cceClusterID string
+	var err error
+	f.K8sVersion, err = helper.GetK8sVersion(f.Client, false)
+	Expect(err).To(BeNil())
+	GinkgoLogr.Info(fmt.Sprintf("Using K8s version %s for cluster %s", f.K8sVersion, f.ClusterName))
+
+	f.GuardCost()
+	if helpers.IsImport {
+		By("importing the cluster")
+		cceClusterID, err = helper.CreateCCEClusterOnHuawei(region, f.ClusterName, f.K8sVersion, 164, helpers.GetCommonMetadataLabels(), nil)
+		Expect(err).To(BeNil())
+		f.Cluster, err = helper.ImportCCEHostedCluster(f.Client, f.ClusterName, ctx.CloudCredID, cceClusterID, region)
+		Expect(err).To(BeNil())
+	} else {
+		By("provisioning the cluster")
+		f.Cluster, err = helper.CreateCCEHostedCluster(f.Client, f.ClusterName, ctx.CloudCredID, f.K8sVersion, region, 164, nil)
+		Expect(err).To(BeNil())
+	}
+	helper.WaitCCEClusterNodeIP(f.Client, f.Cluster)
+	f.AddClusterCleanup(func() {
+		helper.DeleteCCEHostClusterNodeEIPs(f.Cluster, f.Client)
+		err := helper.DeleteCCEHostCluster(f.Cluster, f.Client)
+		Expect(err).To(BeNil())
+		if helpers.IsImport {
+			err = helper.DeleteCCEClusterOnHuawei(region, cceClusterID)
+			Expect(err).To(BeNil())
+		}
+	})
+	f.Cluster, err = helpers.WaitUntilClusterIsReady(f.Cluster, f.Client)
+	Expect(err).To(BeNil())
+	return f
+}
+
+func restoreNodesChecks(f *helpers.Fixture) {
+	helpers.ClusterIsReadyChecks(f.Cluster, f.Client, f.ClusterName)
+	initialNodeCount := f.Cluster.CCEConfig.NodePools[0].InitialNodeCount
+
+	By("scaling up the NodePool", func() {
+		var err error
+		f.Cluster, err = helper.ScaleNodeGroup(f.Cluster, f.Client, initialNodeCount+increaseBy, true, true)
+		Expect(err).To(BeNil())
+	})
+
+	By("adding a NodePool", func() {
+		var err error
+		f.Cluster, err = helper.AddNodePool(f.Cluster, increaseBy, f.Client, true, true)
+		Expect(err).To(BeNil())
+	})
+}
+
+func BackupRestoreChecks(f *helpers.Fixture, k *kubectl.Kubectl) {
+	var backupFile string
+	By("Checking hosted cluster is ready", func() {
+		helpers.ClusterIsReadyChecks(f.Cluster, f.Client, f.ClusterName)
+	})
+
+	By("Performing a backup", func() {
+		backupFile = helpers.ExecuteBackup(k, backupResourceName)
+	})
+
+	By("Checking the backup content", func() {
+		helpers.InspectBackup(backupFile, f.Cluster)
+	})
+
+	By("Perform restore pre-requisites: Uninstalling k3s", func() {
+		out, err := exec.Command("k3s-uninstall.sh").CombinedOutput()
+		Expect(err).To(Not(HaveOccurred()), out)
+	})
+
+	By("Perform restore pre-requisites: Getting k3s ready", func() {
+		helpers.InstallK3S(k, environment)
+	})
+
+	By("Performing a restore", func() {
+		helpers.ExecuteRestore(k, restoreResourceName, backupFile)
+	})
+
+	By("Performing post migration installations: Installing CertManager", func() {
+		helpers.InstallCertManager(k, environment)
+	})
+
+	By("Performing post migration installations: Installing Rancher Manager", func() {
+		helpers.InstallRancherManager(k, environment)
+	})
+
+	By("Performing post migration installations: Checking Rancher Deployments", func() {
+		helpers.CheckRancherDeployments(k)
+	})
+
+	By("Checking hosted cluster can be modified", func() {
+		restoreNodesChecks(f)
+	})
+}
+
+func EncryptedBackupRestoreChecks(f *helpers.Fixture, k *kubectl.Kubectl) {
+	var backupFile string
+	var encryptionConfigFile string
+
+	By("Checking hosted cluster is ready", func() {
+		helpers.ClusterIsReadyChecks(f.Cluster, f.Client, f.ClusterName)
+	})
+
+	By("Performing an encrypted backup", func() {
+		backupFile, encryptionConfigFile = helpers.ExecuteEncryptedBackup(k, encryptedBackupResourceName)
+	})
+
+	By("Checking the encrypted backup content", func() {
+		helpers.InspectBackup(backupFile, f.Cluster)
+	})
+
+	By("Perform restore pre-requisites: Uninstalling k3s", func() {
+		out, err := exec.Command("k3s-uninstall.sh").CombinedOutput()
+		Expect(err).To(Not(HaveOccurred()), out)
+	})
+
+	By("Perform restore pre-requisites: Getting k3s ready", func() {
+		helpers.InstallK3S(k, environment)
+	})
+
+	By("Checking that a restore without the encryption configuration fails", func() {
+		helpers.ExecuteRestoreWithoutEncryptionConfig(k, restoreResourceName, backupFile)
+	})
+
+	By("Performing an encrypted restore", func() {
+		helpers.ExecuteEncryptedRestore(k, encryptedRestoreResourceName, backupFile, encryptionConfigFile)
+	})
+
+	By("Performing post migration installations: Installing CertManager", func() {
+		helpers.InstallCertManager(k, environment)
+	})
+
+	By("Performing post migration installations: Installing Rancher Manager", func() {
+		helpers.InstallRancherManager(k, environment)
+	})
+
+	By("Performing post migration installations: Checking Rancher Deployments", func() {
+		helpers.CheckRancherDeployments(k)
+	})
+
+	By("Checking hosted cluster can be modified", func() {
+		restoreNodesChecks(f)
+	})
+}
+
+// MigrationBackupRestoreChecks backs up Rancher RANCHER_VERSION and restores it onto Rancher RANCHER_UPGRADE_VERSION,
+// running on k3s INSTALL_K3S_UPGRADE_VERSION if it is set
+func MigrationBackupRestoreChecks(f *helpers.Fixture, k *kubectl.Kubectl) {
+	var backupFile string
+	var originalChartVersion string
+
+	migrationEnvironment := environment.WithRancherVersion(helpers.RancherUpgradeFullVersion)
+	if helpers.K3sUpgradeVersion != "" {
+		migrationEnvironment = migrationEnvironment.WithK3sVersion(helpers.K3sUpgradeVersion)
+	}
+
+	By("Checking hosted cluster is ready", func() {
+		helpers.ClusterIsReadyChecks(f.Cluster, f.Client, f.ClusterName)
+	})
+
+	By("Checking the operator chart version", func() {
+		originalChartVersion = helpers.GetCurrentOperatorChartVersion()
+		Expect(originalChartVersion).ToNot(BeEmpty())
+		GinkgoLogr.Info("Original chart version: " + originalChartVersion)
+	})
+
+	By("Performing a backup", func() {
+		backupFile = helpers.ExecuteBackup(k, backupResourceName)
+	})
+
+	By("Checking the backup content", func() {
+		helpers.InspectBackup(backupFile, f.Cluster)
+	})
+
+	By("Perform restore pre-requisites: Uninstalling k3s", func() {
+		out, err := exec.Command("k3s-uninstall.sh").CombinedOutput()
+		Expect(err).To(Not(HaveOccurred()), out)
+	})
+
+	By(fmt.Sprintf("Perform restore pre-requisites: Getting k3s %s ready", migrationEnvironment.K3sVersion), func() {
+		helpers.InstallK3S(k, migrationEnvironment)
+	})
+
+	By("Performing a restore", func() {
+		helpers.ExecuteRestore(k, restoreResourceName, backupFile)
+	})
+
+	By("Performing post migration installations: Installing CertManager", func() {
+		helpers.InstallCertManager(k, environment)
+	})
+
+	By(fmt.Sprintf("Performing post migration installations: Installing Rancher Manager %s", helpers.RancherUpgradeFullVersion), func() {
+		helpers.InstallRancherManager(k, migrationEnvironment)
+	})
+
+	By("Performing post migration installations: Checking Rancher Deployments", func() {
+		helpers.CheckRancherDeployments(k)
+	})
+
+	By("Checking hosted cluster is active after migration", func() {
+		var err error
+		f.Cluster, err = helpers.WaitUntilClusterIsReady(f.Cluster, f.Client)
+		Expect(err).To(BeNil())
+		helpers.ClusterIsReadyChecks(f.Cluster, f.Client, f.ClusterName)
+	})
+
+	By("Checking the operator chart has not been downgraded by the upgraded Rancher version", func() {
+		helpers.WaitUntilOperatorChartInstallation(originalChartVersion, ">=", 0)
+		GinkgoLogr.Info("Upgraded chart version: " + helpers.GetCurrentOperatorChartVersion())
+	})
+
+	By("Checking hosted cluster can be modified", func() {
+		restoreNodesChecks(f)
+	})
+}
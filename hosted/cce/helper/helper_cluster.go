@@ -1,20 +1,34 @@
 package helper
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/Masterminds/semver/v3"
+	ccev1 "github.com/cnrancher/cce-operator/pkg/apis/cce.pandaria.io/v1"
+	"github.com/cnrancher/cce-operator/pkg/controller"
+	huaweicce "github.com/cnrancher/cce-operator/pkg/huawei/cce"
+	"github.com/cnrancher/cce-operator/pkg/huawei/common"
+	huaweieip "github.com/cnrancher/cce-operator/pkg/huawei/eip"
+	"github.com/cnrancher/cce-operator/pkg/utils"
+	"github.com/huaweicloud/huaweicloud-sdk-go-v3/core/sdkerr"
+	ccemodel "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/cce/v3/model"
+	eipmodel "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/eip/v2/model"
 	"github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 	"github.com/rancher-sandbox/ele-testhelpers/tools"
 
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
 
 	"github.com/rancher/shepherd/clients/rancher"
 	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"github.com/rancher/shepherd/extensions/cloudcredentials"
 	"github.com/rancher/shepherd/extensions/clusters"
 	"github.com/rancher/shepherd/extensions/clusters/cce"
 	"github.com/rancher/shepherd/pkg/config"
@@ -40,11 +54,13 @@ func CreateCCEHostedCluster(client *rancher.Client, displayName, cloudCredential
 	return cce.CreateCCEHostedCluster(client, displayName, cloudCredentialID, cceClusterConfig, false, false, false, false, nil)
 }
 
-func ImportCCEHostedCluster(client *rancher.Client, displayName, cloudCredentialID, region string) (*management.Cluster, error) {
+// ImportCCEHostedCluster imports the CCE cluster clusterID of region into Rancher
+func ImportCCEHostedCluster(client *rancher.Client, displayName, cloudCredentialID, clusterID, region string) (*management.Cluster, error) {
 	cluster := &management.Cluster{
 		DockerRootDir: "/var/lib/docker",
 		CCEConfig: &management.CCEClusterConfigSpec{
 			HuaweiCredentialSecret: cloudCredentialID,
+			ClusterID:              clusterID,
 			Name:                   displayName,
 			Imported:               true,
 			RegionID:               region,
@@ -352,3 +368,292 @@ func GetK8sVersion(client *rancher.Client, forUpgrade bool) (string, error) {
 
 	return helpers.DefaultK8sVersion(allVariants, forUpgrade)
 }
+
+// <==============================CCE on Huawei Cloud==============================>
+
+// cceCloudDriver returns the Huawei Cloud clients of region, authenticated with the huaweiCredentials of CATTLE_TEST_CONFIG
+func cceCloudDriver(region string) *controller.HuaweiDriver {
+	var credentialConfig cloudcredentials.HuaweiCredentialConfig
+	config.LoadConfig(cloudcredentials.HuaweiCredentialConfigurationFileKey, &credentialConfig)
+	return controller.NewHuaweiDriver(common.NewClientAuth(credentialConfig.AccessKey, credentialConfig.SecretKey, region, credentialConfig.ProjectID))
+}
+
+// CreateCCEClusterOnHuawei creates a CCE cluster on Huawei Cloud from the cceClusterConfig of CATTLE_TEST_CONFIG,
+// adds the node pools of the config and waits until they are ready; it returns the ID of the CCE cluster
+func CreateCCEClusterOnHuawei(region, clusterName, k8sVersion string, id int64, tags map[string]string, updateFunc func(clusterConfig *cce.ClusterConfig)) (string, error) {
+	var cceClusterConfig cce.ClusterConfig
+	config.LoadConfig(cce.CCEClusterConfigConfigurationFileKey, &cceClusterConfig)
+
+	cceClusterConfig.Name = clusterName
+	cceClusterConfig.Version = k8sVersion
+	cceClusterConfig.RegionID = region
+	cceClusterConfig.Tags = tags
+	cceClusterConfig.ContainerNetwork.CIDR = fmt.Sprintf("10.%v.0.0/16", id%255)
+	if updateFunc != nil {
+		updateFunc(&cceClusterConfig)
+	}
+
+	// the test config shares the JSON keys of the operator spec, which the cce-operator requests are built from
+	var clusterConfig ccev1.CCEClusterConfig
+	data, err := json.Marshal(cceClusterConfig)
+	if err != nil {
+		return "", err
+	}
+	if err = json.Unmarshal(data, &clusterConfig.Spec); err != nil {
+		return "", err
+	}
+
+	driver := cceCloudDriver(region)
+	if clusterConfig.Spec.PublicAccess && clusterConfig.Spec.PublicIP.CreateEIP {
+		fmt.Println("Creating CCE cluster EIP ...")
+		eipResponse, err := huaweieip.CreatePublicIP(driver.EIP, &clusterConfig.Spec.PublicIP.Eip)
+		if err != nil {
+			return "", errors.Wrap(err, "Failed to create cluster EIP")
+		}
+		clusterConfig.Status.ClusterExternalIP = utils.Value(eipResponse.Publicip.PublicIpAddress)
+	}
+
+	fmt.Println("Creating CCE cluster ...")
+	response, err := huaweicce.CreateCluster(driver.CCE, &clusterConfig)
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to create cluster")
+	}
+	clusterID := utils.Value(response.Metadata.Uid)
+
+	var phase string
+	Eventually(func() string {
+		phase, err = cceClusterPhase(driver, clusterID)
+		Expect(err).To(BeNil())
+		return phase
+	}, tools.SetTimeout(30*time.Minute), 30*time.Second).Should(BeElementOf(huaweicce.ClusterStatusAvailable, huaweicce.ClusterStatusUnavailable))
+	if phase != huaweicce.ClusterStatusAvailable {
+		return clusterID, errors.Errorf("CCE cluster %s (%s) is %s", clusterName, clusterID, phase)
+	}
+
+	for i := range clusterConfig.Spec.NodePools {
+		fmt.Println("Creating CCE node pool ...")
+		nodePoolResponse, err := huaweicce.CreateNodePool(driver.CCE, clusterID, &clusterConfig.Spec.NodePools[i])
+		if err != nil {
+			return clusterID, errors.Wrap(err, "Failed to create node pool")
+		}
+		nodePoolID := utils.Value(nodePoolResponse.Metadata.Uid)
+		initialNodeCount := int64(clusterConfig.Spec.NodePools[i].InitialNodeCount)
+		Eventually(func() bool {
+			nodePool, err := describeCCENodePool(driver, clusterID, nodePoolID)
+			Expect(err).To(BeNil())
+			return nodePool != nil && nodePool.Phase == "" && nodePool.CurrentNodeCount == initialNodeCount
+		}, tools.SetTimeout(20*time.Minute), 30*time.Second).Should(BeTrue())
+	}
+	fmt.Println("Created CCE cluster: ", clusterName, clusterID)
+	return clusterID, nil
+}
+
+// DeleteCCEClusterOnHuawei deletes a CCE cluster along with its nodes and disks, waits until it is gone
+// and releases the EIP of its external endpoint
+func DeleteCCEClusterOnHuawei(region, clusterID string) error {
+	driver := cceCloudDriver(region)
+	cluster, err := huaweicce.ShowCluster(driver.CCE, clusterID)
+	if err != nil {
+		if isCCENotFound(err) {
+			return nil
+		}
+		return errors.Wrap(err, "Failed to describe cluster")
+	}
+	var externalIP string
+	if cluster.Status != nil && cluster.Status.Endpoints != nil {
+		for _, endpoint := range *cluster.Status.Endpoints {
+			if utils.Value(endpoint.Type) == "External" {
+				if endpointURL, err := url.Parse(utils.Value(endpoint.Url)); err == nil {
+					externalIP = endpointURL.Hostname()
+				}
+			}
+		}
+	}
+
+	fmt.Println("Deleting CCE cluster ...")
+	deleteEvs := ccemodel.GetDeleteClusterRequestDeleteEvsEnum().TRUE
+	if _, err = driver.CCE.DeleteCluster(&ccemodel.DeleteClusterRequest{ClusterId: clusterID, DeleteEvs: &deleteEvs}); err != nil {
+		return errors.Wrap(err, "Failed to delete cluster")
+	}
+	Eventually(func() bool {
+		_, err := huaweicce.ShowCluster(driver.CCE, clusterID)
+		if isCCENotFound(err) {
+			return true
+		}
+		Expect(err).To(BeNil())
+		return false
+	}, tools.SetTimeout(30*time.Minute), 30*time.Second).Should(BeTrue())
+
+	if externalIP != "" {
+		publicIPs, err := driver.EIP.ListPublicips(&eipmodel.ListPublicipsRequest{PublicIpAddress: &[]string{externalIP}})
+		if err != nil {
+			return errors.Wrap(err, "Failed to list cluster EIP")
+		}
+		if publicIPs.Publicips == nil {
+			return nil
+		}
+		for _, publicIP := range *publicIPs.Publicips {
+			fmt.Println("Releasing CCE cluster EIP ...")
+			if _, err = huaweieip.DeletePublicIP(driver.EIP, utils.Value(publicIP.Id)); err != nil {
+				return errors.Wrap(err, "Failed to release cluster EIP")
+			}
+		}
+	}
+	fmt.Println("Deleted CCE cluster: ", clusterID)
+	return nil
+}
+
+// UpgradeCCEClusterOnHuawei upgrades a CCE cluster to k8sVersion and waits until it is available again
+func UpgradeCCEClusterOnHuawei(region, clusterID, k8sVersion string) error {
+	driver := cceCloudDriver(region)
+	clusterConfig := &ccev1.CCEClusterConfig{Spec: ccev1.CCEClusterConfigSpec{ClusterID: clusterID, Version: k8sVersion}}
+
+	fmt.Println("Upgrading CCE cluster ...")
+	if _, err := huaweicce.UpgradeCluster(driver.CCE, clusterConfig); err != nil {
+		return errors.Wrap(err, "Failed to upgrade cluster")
+	}
+
+	Eventually(func() bool {
+		cluster, err := huaweicce.ShowCluster(driver.CCE, clusterID)
+		Expect(err).To(BeNil())
+		return utils.Value(cluster.Status.Phase) == huaweicce.ClusterStatusAvailable && strings.HasPrefix(utils.Value(cluster.Spec.Version), k8sVersion)
+	}, tools.SetTimeout(40*time.Minute), 30*time.Second).Should(BeTrue())
+	fmt.Println("Upgraded CCE cluster: ", clusterID, k8sVersion)
+	return nil
+}
+
+// CCENodePool is the Huawei Cloud view of a CCE node pool
+type CCENodePool struct {
+	ID               string
+	Name             string
+	InitialNodeCount int64
+	CurrentNodeCount int64
+	// Phase is empty once the node pool is ready, e.g. Synchronizing, Deleting or Error otherwise
+	Phase string
+	// Autoscaling is sent back unchanged when the node pool is updated, the update disables it otherwise
+	Autoscaling ccev1.CCENodePoolNodeAutoscaling
+}
+
+// ListCCENodePoolsOnHuawei lists the node pools of a CCE cluster, without the default node pool
+func ListCCENodePoolsOnHuawei(region, clusterID string) ([]CCENodePool, error) {
+	return listCCENodePools(cceCloudDriver(region), clusterID)
+}
+
+// ScaleCCENodePoolOnHuawei changes the node count of a CCE node pool and waits until the nodes are ready
+func ScaleCCENodePoolOnHuawei(region, clusterID, nodePoolID string, nodeCount int64) error {
+	driver := cceCloudDriver(region)
+	nodePool, err := describeCCENodePool(driver, clusterID, nodePoolID)
+	if err != nil {
+		return err
+	}
+	if nodePool == nil {
+		return errors.Errorf("node pool %s not found in CCE cluster %s", nodePoolID, clusterID)
+	}
+
+	fmt.Println("Scaling CCE node pool ...")
+	_, err = huaweicce.UpdateNodePool(driver.CCE, clusterID, &ccev1.CCENodePool{
+		ID:               nodePoolID,
+		Name:             nodePool.Name,
+		InitialNodeCount: int32(nodeCount),
+		Autoscaling:      nodePool.Autoscaling,
+	})
+	if err != nil {
+		return errors.Wrap(err, "Failed to scale node pool")
+	}
+
+	Eventually(func() bool {
+		nodePool, err = describeCCENodePool(driver, clusterID, nodePoolID)
+		Expect(err).To(BeNil())
+		return nodePool != nil && nodePool.Phase == "" && nodePool.CurrentNodeCount == nodeCount
+	}, tools.SetTimeout(20*time.Minute), 30*time.Second).Should(BeTrue())
+	fmt.Println("Scaled CCE node pool: ", nodePoolID, nodeCount)
+	return nil
+}
+
+// DeleteCCENodePoolOnHuawei deletes a CCE node pool along with its nodes and waits until it is gone
+func DeleteCCENodePoolOnHuawei(region, clusterID, nodePoolID string) error {
+	driver := cceCloudDriver(region)
+	fmt.Println("Deleting CCE node pool ...")
+	if _, err := huaweicce.DeleteNodePool(driver.CCE, clusterID, nodePoolID); err != nil {
+		if isCCENotFound(err) {
+			return nil
+		}
+		return errors.Wrap(err, "Failed to delete node pool")
+	}
+
+	Eventually(func() *CCENodePool {
+		nodePool, err := describeCCENodePool(driver, clusterID, nodePoolID)
+		Expect(err).To(BeNil())
+		return nodePool
+	}, tools.SetTimeout(20*time.Minute), 30*time.Second).Should(BeNil())
+	fmt.Println("Deleted CCE node pool: ", nodePoolID)
+	return nil
+}
+
+func listCCENodePools(driver *controller.HuaweiDriver, clusterID string) ([]CCENodePool, error) {
+	response, err := huaweicce.ListNodePools(driver.CCE, clusterID, false)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to list node pools of cluster %s", clusterID)
+	}
+	if response.Items == nil {
+		return nil, nil
+	}
+	var nodePools []CCENodePool
+	for _, np := range *response.Items {
+		nodePool := CCENodePool{
+			ID:               utils.Value(np.Metadata.Uid),
+			Name:             np.Metadata.Name,
+			InitialNodeCount: int64(utils.Value(np.Spec.InitialNodeCount)),
+		}
+		if autoscaling := np.Spec.Autoscaling; autoscaling != nil {
+			nodePool.Autoscaling = ccev1.CCENodePoolNodeAutoscaling{
+				Enable:                utils.Value(autoscaling.Enable),
+				MinNodeCount:          utils.Value(autoscaling.MinNodeCount),
+				MaxNodeCount:          utils.Value(autoscaling.MaxNodeCount),
+				ScaleDownCooldownTime: utils.Value(autoscaling.ScaleDownCooldownTime),
+				Priority:              utils.Value(autoscaling.Priority),
+			}
+		}
+		if np.Status != nil {
+			nodePool.CurrentNodeCount = int64(utils.Value(np.Status.CurrentNode))
+			if np.Status.Phase != nil {
+				nodePool.Phase = np.Status.Phase.Value()
+			}
+		}
+		nodePools = append(nodePools, nodePool)
+	}
+	return nodePools, nil
+}
+
+// describeCCENodePool returns the node pool nodePoolID of a CCE cluster, nil if it does not exist
+func describeCCENodePool(driver *controller.HuaweiDriver, clusterID, nodePoolID string) (*CCENodePool, error) {
+	nodePools, err := listCCENodePools(driver, clusterID)
+	if err != nil {
+		return nil, err
+	}
+	for _, nodePool := range nodePools {
+		if nodePool.ID == nodePoolID {
+			return &nodePool, nil
+		}
+	}
+	return nil, nil
+}
+
+// cceClusterPhase returns the phase of a CCE cluster, e.g. Creating, Available or Unavailable
+func cceClusterPhase(driver *controller.HuaweiDriver, clusterID string) (string, error) {
+	cluster, err := huaweicce.ShowCluster(driver.CCE, clusterID)
+	if err != nil {
+		return "", err
+	}
+	if cluster.Status == nil {
+		return "", nil
+	}
+	return utils.Value(cluster.Status.Phase), nil
+}
+
+func isCCENotFound(err error) bool {
+	var responseErr *sdkerr.ServiceResponseError
+	return errors.As(err, &responseErr) && responseErr.StatusCode == http.StatusNotFound
+}
+
+// <==============================CCE on Huawei Cloud(end)==============================>
@@ -0,0 +1,25 @@
+package helper
+
+import (
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+// CredentialOperations returns the operations of the cloud credential lifecycle checks on CCE clusters;
+// the cloud credentials of Huawei Cloud hold permanent AK/SK pairs, whose expiry cannot be simulated
+func CredentialOperations() helpers.CredentialOperations {
+	return helpers.CredentialOperations{Trigger: toggleNodeCount}
+}
+
+// toggleNodeCount scales the node pools of the cluster to their toggled node count, see helpers.ToggledNodeCount
+func toggleNodeCount(client *rancher.Client, cluster *management.Cluster) error {
+	_, err := UpdateCluster(cluster, client, func(upgradedCluster *management.Cluster) {
+		for i := range upgradedCluster.CCEConfig.NodePools {
+			nodePool := &upgradedCluster.CCEConfig.NodePools[i]
+			nodePool.InitialNodeCount = helpers.ToggledNodeCount(nodePool.InitialNodeCount)
+		}
+	})
+	return err
+}
@@ -0,0 +1,18 @@
+package helper
+
+import (
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/workload"
+)
+
+// WorkloadOptions returns the options of the workload checks on CCE clusters: an EVS disk, and a shared ELB with a public EIP created by the cloud controller
+func WorkloadOptions() workload.Options {
+	return workload.Options{
+		StorageClass: "csi-disk",
+		// EVS disks are at least 10GiB
+		StorageSize: "10Gi",
+		LoadBalancerAnnotations: map[string]string{
+			"kubernetes.io/elb.class":      "union",
+			"kubernetes.io/elb.autocreate": `{"type":"public","bandwidth_name":"hp-smoke","bandwidth_chargemode":"traffic","bandwidth_size":5,"bandwidth_sharetype":"PER","eip_type":"5_bgp"}`,
+		},
+	}
+}
@@ -0,0 +1,48 @@
+package k8s_chart_support_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/cce/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var _ = Describe("K8sChartSupportImport", func() {
+	var (
+		cluster *management.Cluster
+		// cceClusterID is the ID of the cluster on Huawei Cloud, it is needed to import and delete it
+		cceClusterID string
+	)
+	BeforeEach(func() {
+		var err error
+		cceClusterID, err = helper.CreateCCEClusterOnHuawei(region, clusterName, k8sVersion, 65, helpers.GetCommonMetadataLabels(), nil)
+		Expect(err).To(BeNil())
+
+		cluster, err = helper.ImportCCEHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, cceClusterID, region)
+		Expect(err).To(BeNil())
+		helper.WaitCCEClusterNodeIP(ctx.RancherAdminClient, cluster)
+		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+		Expect(err).To(BeNil())
+
+	})
+	AfterEach(func() {
+		if ctx.ClusterCleanup && cluster != nil {
+			helper.DeleteCCEHostClusterNodeEIPs(cluster, ctx.RancherAdminClient)
+			err := helper.DeleteCCEHostCluster(cluster, ctx.RancherAdminClient)
+			Expect(err).To(BeNil())
+			err = helper.DeleteCCEClusterOnHuawei(region, cceClusterID)
+			Expect(err).To(BeNil())
+		} else {
+			fmt.Println("Skipping downstream cluster deletion: ", clusterName)
+		}
+	})
+
+	It("should successfully test k8s chart support import", func() {
+		testCaseID = 65 // Report to Qase
+		commonchecks(ctx.RancherAdminClient, cluster)
+	})
+})
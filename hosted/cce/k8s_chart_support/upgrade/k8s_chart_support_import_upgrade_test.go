@@ -0,0 +1,45 @@
+package k8s_chart_support_upgrade_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/cce/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var _ = Describe("K8sChartSupportUpgradeImport", func() {
+	var (
+		f *helpers.Fixture
+		// cceClusterID is the ID of the cluster on Huawei Cloud, it is needed to import and delete it
+		cceClusterID string
+	)
+	BeforeEach(func() {
+		f = newFixture()
+		f.GuardCost()
+		var err error
+		cceClusterID, err = helper.CreateCCEClusterOnHuawei(region, f.ClusterName, f.K8sVersion, 167, helpers.GetCommonMetadataLabels(), nil)
+		Expect(err).To(BeNil())
+
+		f.Cluster, err = helper.ImportCCEHostedCluster(f.Client, f.ClusterName, ctx.CloudCredID, cceClusterID, region)
+		Expect(err).To(BeNil())
+		f.AddClusterCleanup(func() {
+			helper.DeleteCCEHostClusterNodeEIPs(f.Cluster, f.Client)
+			err := helper.DeleteCCEHostCluster(f.Cluster, f.Client)
+			Expect(err).To(BeNil())
+			err = helper.DeleteCCEClusterOnHuawei(region, cceClusterID)
+			Expect(err).To(BeNil())
+		})
+		helper.WaitCCEClusterNodeIP(f.Client, f.Cluster)
+		f.Cluster, err = helpers.WaitUntilClusterIsReady(f.Cluster, f.Client)
+		Expect(err).To(BeNil())
+	})
+	It("should successfully test k8s chart support import in an upgrade scenario", func() {
+		GinkgoLogr.Info(fmt.Sprintf("Testing K8s %s chart support for import on Rancher upgraded from %s to %s", helpers.K8sUpgradedMinorVersion, helpers.RancherFullVersion, helpers.RancherUpgradeFullVersion))
+		f.SetQaseID(167) // Report to Qase
+
+		commonchecks(f, helpers.RancherUpgradeFullVersion, helpers.K8sUpgradedMinorVersion)
+	})
+})
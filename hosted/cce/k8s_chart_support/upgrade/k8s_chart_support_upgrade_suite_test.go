@@ -0,0 +1,218 @@
+package k8s_chart_support_upgrade_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/kubectl"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	. "github.com/rancher-sandbox/qase-ginkgo"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"github.com/rancher/shepherd/extensions/clusters"
+	nodestat "github.com/rancher/shepherd/extensions/nodes"
+	"github.com/rancher/shepherd/extensions/workloads/pods"
+	"github.com/rancher/shepherd/pkg/config"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/cce/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var (
+	ctx         helpers.RancherContext
+	region      = helpers.GetCCERegion()
+	k           = kubectl.New()
+	environment helpers.EnvironmentProfile
+)
+
+func TestK8sChartSupportUpgrade(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "K8sChartSupportUpgrade Suite")
+}
+
+var _ = BeforeEach(func() {
+	// For upgrade tests, the rancher version should not be an unreleased version (for e.g. 2.9-head)
+	Expect(helpers.RancherFullVersion).To(SatisfyAll(Not(BeEmpty()), Not(ContainSubstring("devel"))))
+	Expect(helpers.RancherUpgradeFullVersion).ToNot(BeEmpty())
+	Expect(helpers.K8sUpgradedMinorVersion).ToNot(BeEmpty())
+	Expect(helpers.Kubeconfig).ToNot(BeEmpty())
+	helpers.ExpectNoOperatorOverride()
+	environment = helpers.StockEnvironmentProfile()
+
+	// Registered before the fixture of the spec, so that it runs once the downstream cluster has been deleted
+	DeferCleanup(func() {
+		// The test must restore the env to its original state, so we install rancher back to its original version and uninstall the operator charts
+		// Restoring rancher back to its original state is necessary because in case DOWNSTREAM_CLUSTER_CLEANUP is set to false; in which case clusters will be retained for the next test.
+		// Once the operator is uninstalled, it might be reinstalled since the cluster exists, and installing rancher back to its original state ensures that the version is not the one we want to test.
+		By(fmt.Sprintf("Installing Rancher back to its original version %s", helpers.RancherFullVersion), func() {
+			helpers.InstallRancherManager(k, environment)
+			helpers.CheckRancherDeployments(k)
+		})
+
+		By("Uninstalling the existing operator charts", func() {
+			helpers.UninstallOperatorCharts()
+		})
+	})
+
+	By("Adding the necessary chart repos", func() {
+		helpers.AddRancherCharts()
+	})
+
+	By(fmt.Sprintf("Installing Rancher Manager %s", helpers.RancherFullVersion), func() {
+		helpers.InstallRancherManager(k, environment)
+		helpers.CheckRancherDeployments(k)
+	})
+
+	helpers.CommonSynchronizedBeforeSuite()
+	ctx = helpers.CommonBeforeSuite()
+
+	By("creating and using a more permanent token", func() {
+		token, err := ctx.RancherAdminClient.Management.Token.Create(&management.Token{})
+		Expect(err).NotTo(HaveOccurred())
+		rancherConfig := new(rancher.Config)
+		config.LoadConfig(rancher.ConfigurationFileKey, rancherConfig)
+		rancherConfig.AdminToken = token.Token
+		config.UpdateConfig(rancher.ConfigurationFileKey, rancherConfig)
+
+		rancherAdminClient, err := rancher.NewClient(rancherConfig.AdminToken, ctx.Session)
+		Expect(err).To(BeNil())
+		ctx.RancherAdminClient = rancherAdminClient
+	})
+})
+
+// newFixture returns the fixture of the current spec with the k8s version its cluster is created with
+func newFixture() *helpers.Fixture {
+	f := helpers.NewFixture(&ctx)
+	var err error
+	f.K8sVersion, err = helper.GetK8sVersion(f.Client, false)
+	Expect(err).To(BeNil())
+	Expect(f.K8sVersion).ToNot(BeEmpty())
+	GinkgoLogr.Info(fmt.Sprintf("Using CCE version %s for cluster %s", f.K8sVersion, f.ClusterName))
+	return f
+}
+
+var _ = ReportAfterEach(func(report SpecReport) {
+	// Add result in Qase if asked
+	Qase(helpers.QaseID(report), report)
+})
+
+func commonchecks(f *helpers.Fixture, rancherUpgradedVersion, k8sUpgradedVersion string) {
+
+	helpers.ClusterIsReadyChecks(f.Cluster, f.Client, f.ClusterName)
+
+	var originalChartVersion string
+	By("checking the chart version", func() {
+		originalChartVersion = helpers.GetCurrentOperatorChartVersion()
+		Expect(originalChartVersion).ToNot(BeEmpty())
+		GinkgoLogr.Info("Original chart version: " + originalChartVersion)
+	})
+
+	By(fmt.Sprintf("upgrading rancher to %v", rancherUpgradedVersion), func() {
+		helpers.InstallRancherManager(k, environment.WithRancherVersion(rancherUpgradedVersion))
+		helpers.CheckRancherDeployments(k)
+
+		By("ensuring operator pods are also up", func() {
+			Eventually(func() error {
+				return k.WaitForNamespaceWithPod(helpers.CattleSystemNS, fmt.Sprintf("ke.cattle.io/operator=%s", helpers.Provider))
+			}, tools.SetTimeout(4*time.Minute), 30*time.Second).Should(BeNil())
+		})
+
+		By("ensuring the rancher client is connected", func() {
+			isConnected, err := f.Client.IsConnected()
+			Expect(err).To(BeNil())
+			Expect(isConnected).To(BeTrue())
+		})
+	})
+
+	By("making sure the local cluster is ready", func() {
+		const localClusterID = "local"
+		By("checking all management nodes are ready", func() {
+			err := nodestat.AllManagementNodeReady(f.Client, localClusterID, helpers.Timeout)
+			Expect(err).To(BeNil())
+		})
+
+		By("checking all pods are ready", func() {
+			podErrors := pods.StatusPods(f.Client, localClusterID)
+			Expect(podErrors).To(BeEmpty())
+		})
+	})
+
+	var upgradedChartVersion string
+	By("checking the chart version and validating it is > the old version", func() {
+		helpers.WaitUntilOperatorChartInstallation(originalChartVersion, "==", 1)
+		upgradedChartVersion = helpers.GetCurrentOperatorChartVersion()
+		GinkgoLogr.Info("Upgraded chart version: " + upgradedChartVersion)
+	})
+
+	By("making sure the downstream cluster is ready", func() {
+		var err error
+		f.Cluster, err = f.Client.Management.Cluster.ByID(f.Cluster.ID)
+		Expect(err).To(BeNil())
+		helpers.ClusterIsReadyChecks(f.Cluster, f.Client, f.ClusterName)
+
+		// since no changes have been made to the cluster so far, we need reinstantiate CCEConfig after fetching the cluster
+		if helpers.IsImport {
+			f.Cluster.CCEConfig = f.Cluster.CCEStatus.UpstreamSpec
+		}
+	})
+
+	var latestVersion *string
+	By(fmt.Sprintf("fetching a list of available k8s versions and ensure the v%s is present in the list and upgrading the cluster to it", k8sUpgradedVersion), func() {
+		versions, err := helper.ListCCEAvailableVersions(f.Client, f.Cluster)
+		Expect(err).To(BeNil())
+		Expect(versions).ToNot(BeEmpty())
+		GinkgoLogr.Info(fmt.Sprintf("Available CCE versions: %v", versions))
+
+		latestVersion = &versions[0]
+		Expect(*latestVersion).To(ContainSubstring(k8sUpgradedVersion))
+		Expect(helpers.VersionCompare(*latestVersion, f.Cluster.Version.GitVersion)).To(BeNumerically("==", 1))
+
+		// the nodes are upgraded along with the control plane, there is no separate nodepool upgrade
+		f.Cluster, err = helper.UpgradeClusterKubernetesVersion(f.Cluster, *latestVersion, f.Client, true)
+		Expect(err).To(BeNil())
+	})
+
+	var downgradeVersion string
+	By("fetching a value to downgrade to", func() {
+		downgradeVersion = helpers.GetDowngradeOperatorChartVersion(upgradedChartVersion)
+	})
+
+	By("downgrading the chart version", func() {
+		helpers.DowngradeProviderChart(downgradeVersion)
+	})
+
+	By("making a change to the cluster (scaling the node up) to validate functionality after chart downgrade", func() {
+		var err error
+		initialNodeCount := f.Cluster.CCEConfig.NodePools[0].InitialNodeCount
+		f.Cluster, err = helper.ScaleNodeGroup(f.Cluster, f.Client, initialNodeCount+1, true, true)
+		Expect(err).To(BeNil())
+	})
+
+	By("uninstalling the operator chart", func() {
+		helpers.UninstallOperatorCharts()
+	})
+
+	By("making a change(adding a nodepool) to the cluster to re-install the operator and validating it is re-installed to the latest/upgraded version", func() {
+		currentNodeGroupNumber := len(f.Cluster.CCEConfig.NodePools)
+		var err error
+		f.Cluster, err = helper.AddNodePool(f.Cluster, 1, f.Client, false, false)
+		Expect(err).To(BeNil())
+
+		By("ensuring that the chart is re-installed to the latest/upgraded version", func() {
+			helpers.WaitUntilOperatorChartInstallation(upgradedChartVersion, "", 0)
+		})
+
+		err = clusters.WaitClusterToBeUpgraded(f.Client, f.Cluster.ID)
+		Expect(err).To(BeNil())
+		// Check if the desired config has been applied in Rancher
+		Eventually(func() int {
+			f.Cluster, err = f.Client.Management.Cluster.ByID(f.Cluster.ID)
+			Expect(err).To(BeNil())
+			return len(f.Cluster.CCEStatus.UpstreamSpec.NodePools)
+		}, tools.SetTimeout(20*time.Minute), 10*time.Second).Should(BeNumerically("==", currentNodeGroupNumber+1))
+	})
+
+}
@@ -20,9 +20,6 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
-	"github.com/rancher/shepherd/clients/rancher"
-	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
-
 	"github.com/rancher/hosted-providers-e2e/hosted/cce/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
 )
@@ -31,7 +28,7 @@ var _ = Describe("P0Provisioning", func() {
 	for _, testData := range []struct {
 		qaseID    int64
 		isUpgrade bool
-		testBody  func(cluster *management.Cluster, client *rancher.Client, clusterName string)
+		testBody  func(f *helpers.Fixture)
 		testTitle string
 	}{
 		{
@@ -49,38 +46,39 @@ var _ = Describe("P0Provisioning", func() {
 	} {
 		testData := testData
 		When("a cluster is created", func() {
+			var f *helpers.Fixture
+
 			BeforeEach(func() {
 				if testData.isUpgrade && helpers.SkipUpgradeTests {
 					Skip(helpers.SkipUpgradeTestsLog)
 				}
 
-				k8sVersion, err := helper.GetK8sVersion(ctx.RancherAdminClient, testData.isUpgrade)
-				Expect(err).To(BeNil())
-				GinkgoLogr.Info(fmt.Sprintf("While provisioning, using K8s version %s for cluster %s", k8sVersion, clusterName))
-				cluster, err = helper.CreateCCEHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, region, testData.qaseID, nil)
-				Expect(err).To(BeNil())
-				helper.WaitCCEClusterNodeIP(ctx.RancherAdminClient, cluster)
-				cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
-				Expect(err).To(BeNil())
-			})
-			AfterEach(func() {
-				if ctx.ClusterCleanup {
-					if cluster != nil && cluster.ID != "" {
-						GinkgoLogr.Info(fmt.Sprintf("Cleaning up cluster %s node EIPs", cluster.Name))
-						helper.DeleteCCEHostClusterNodeEIPs(cluster, ctx.RancherAdminClient)
+				f = helpers.NewFixture(&ctx)
+				f.AddClusterCleanup(func() {
+					if f.Cluster != nil && f.Cluster.ID != "" {
+						GinkgoLogr.Info(fmt.Sprintf("Cleaning up cluster %s node EIPs", f.Cluster.Name))
+						helper.DeleteCCEHostClusterNodeEIPs(f.Cluster, f.Client)
 
-						GinkgoLogr.Info(fmt.Sprintf("Cleaning up resource cluster: %s %s", cluster.Name, cluster.ID))
-						err := helper.DeleteCCEHostCluster(cluster, ctx.RancherAdminClient)
+						GinkgoLogr.Info(fmt.Sprintf("Cleaning up resource cluster: %s %s", f.Cluster.Name, f.Cluster.ID))
+						err := helper.DeleteCCEHostCluster(f.Cluster, f.Client)
 						Expect(err).To(BeNil())
 					}
-				} else {
-					fmt.Println("Skipping downstream cluster deletion: ", clusterName)
-				}
+				})
+
+				var err error
+				f.K8sVersion, err = helper.GetK8sVersion(f.Client, testData.isUpgrade)
+				Expect(err).To(BeNil())
+				GinkgoLogr.Info(fmt.Sprintf("While provisioning, using K8s version %s for cluster %s", f.K8sVersion, f.ClusterName))
+				f.Cluster, err = helper.CreateCCEHostedCluster(f.Client, f.ClusterName, ctx.CloudCredID, f.K8sVersion, region, testData.qaseID, nil)
+				Expect(err).To(BeNil())
+				helper.WaitCCEClusterNodeIP(f.Client, f.Cluster)
+				f.Cluster, err = helpers.WaitUntilClusterIsReady(f.Cluster, f.Client)
+				Expect(err).To(BeNil())
 			})
 
 			It(testData.testTitle, func() {
-				testCaseID = testData.qaseID
-				testData.testBody(cluster, ctx.RancherAdminClient, clusterName)
+				f.SetQaseID(testData.qaseID)
+				testData.testBody(f)
 			})
 
 		})
@@ -22,10 +22,6 @@ import (
 	. "github.com/onsi/gomega"
 	. "github.com/rancher-sandbox/qase-ginkgo"
 
-	"github.com/rancher/shepherd/clients/rancher"
-	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
-	namegen "github.com/rancher/shepherd/pkg/namegenerator"
-
 	"github.com/rancher/hosted-providers-e2e/hosted/cce/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
 )
@@ -35,11 +31,8 @@ const (
 )
 
 var (
-	ctx         helpers.RancherContext
-	cluster     *management.Cluster
-	clusterName string
-	testCaseID  int64
-	region      = helpers.GetCCERegion()
+	ctx    helpers.RancherContext
+	region = helpers.GetCCERegion()
 )
 
 func TestP0(t *testing.T) {
@@ -54,61 +47,52 @@ var _ = SynchronizedBeforeSuite(func() []byte {
 	ctx = helpers.CommonBeforeSuite()
 })
 
-var _ = BeforeEach(func() {
-	// Setting this to nil ensures we do not use the `cluster` variable value from another test running in parallel with this one.
-	cluster = nil
-	clusterName = namegen.AppendRandomString(helpers.ClusterNamePrefix)
-})
-
-var _ = ReportBeforeEach(func(report SpecReport) {
-	// Reset case ID
-	testCaseID = -1
-})
-
 var _ = ReportAfterEach(func(report SpecReport) {
 	// Add result in Qase if asked
-	Qase(testCaseID, report)
+	Qase(helpers.QaseID(report), report)
 })
 
-func p0upgradeK8sVersionChecks(cluster *management.Cluster, client *rancher.Client, clusterName string) {
-	helpers.ClusterIsReadyChecks(cluster, client, clusterName)
+func p0upgradeK8sVersionChecks(f *helpers.Fixture) {
+	helpers.ClusterIsReadyChecks(f.Cluster, f.Client, f.ClusterName)
 
 	// Default version is highest supported version
-	upgradeToVersion, err := helper.GetK8sVersion(client, false)
+	var err error
+	f.UpgradeToVersion, err = helper.GetK8sVersion(f.Client, false)
 	Expect(err).To(BeNil())
-	GinkgoLogr.Info(fmt.Sprintf("Upgrading cluster to CCE version %s", upgradeToVersion))
+	GinkgoLogr.Info(fmt.Sprintf("Upgrading cluster to CCE version %s", f.UpgradeToVersion))
 
 	By("upgrading the CCE cluster", func() {
-		cluster, err = helper.UpgradeClusterKubernetesVersion(cluster, upgradeToVersion, client, true)
+		f.Cluster, err = helper.UpgradeClusterKubernetesVersion(f.Cluster, f.UpgradeToVersion, f.Client, true)
 		Expect(err).To(BeNil())
 	})
 }
 
-func p0NodesChecks(cluster *management.Cluster, client *rancher.Client, clusterName string) {
-	helpers.ClusterIsReadyChecks(cluster, client, clusterName)
-	configNodePools := cluster.CCEConfig.NodePools
+func p0NodesChecks(f *helpers.Fixture) {
+	helpers.ClusterIsReadyChecks(f.Cluster, f.Client, f.ClusterName)
+	helpers.WorkloadChecks(f.Cluster, f.Client, helper.WorkloadOptions())
+	configNodePools := f.Cluster.CCEConfig.NodePools
 	initialNodeCount := configNodePools[0].InitialNodeCount
 
 	By("scaling up the NodeGroup", func() {
 		var err error
-		cluster, err = helper.ScaleNodeGroup(cluster, client, initialNodeCount+increaseBy, true, true)
+		f.Cluster, err = helper.ScaleNodeGroup(f.Cluster, f.Client, initialNodeCount+increaseBy, true, true)
 		Expect(err).To(BeNil())
 	})
 
 	By("scaling down the NodeGroup", func() {
 		var err error
-		cluster, err = helper.ScaleNodeGroup(cluster, client, initialNodeCount, true, true)
+		f.Cluster, err = helper.ScaleNodeGroup(f.Cluster, f.Client, initialNodeCount, true, true)
 		Expect(err).To(BeNil())
 	})
 
 	By("adding a NodeGroup", func() {
 		var err error
-		cluster, err = helper.AddNodePool(cluster, increaseBy, client, true, true)
+		f.Cluster, err = helper.AddNodePool(f.Cluster, increaseBy, f.Client, true, true)
 		Expect(err).To(BeNil())
 	})
 	By("deleting the NodeGroup", func() {
 		var err error
-		cluster, err = helper.DeleteNodePool(cluster, client, true, true)
+		f.Cluster, err = helper.DeleteNodePool(f.Cluster, f.Client, true, true)
 		Expect(err).To(BeNil())
 	})
 }
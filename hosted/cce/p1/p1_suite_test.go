@@ -0,0 +1,295 @@
+/*
+Copyright © 2022 - 2025 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package p1_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	. "github.com/rancher-sandbox/qase-ginkgo"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"github.com/rancher/shepherd/extensions/clusters"
+	namegen "github.com/rancher/shepherd/pkg/namegenerator"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/cce/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+const (
+	increaseBy = 1
+)
+
+var (
+	ctx         helpers.RancherContext
+	cluster     *management.Cluster
+	clusterName string
+	testCaseID  int64
+	region      = helpers.GetCCERegion()
+)
+
+func TestP1(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "P1 Suite")
+}
+
+var _ = SynchronizedBeforeSuite(func() []byte {
+	helpers.CommonSynchronizedBeforeSuite()
+	return nil
+}, func() {
+	ctx = helpers.CommonBeforeSuite()
+})
+
+var _ = BeforeEach(func() {
+	// Setting this to nil ensures we do not use the `cluster` variable value from another test running in parallel with this one.
+	cluster = nil
+	clusterName = namegen.AppendRandomString(helpers.ClusterNamePrefix)
+})
+
+var _ = ReportBeforeEach(func(report SpecReport) {
+	// Reset case ID
+	testCaseID = -1
+})
+
+var _ = ReportAfterEach(func(report SpecReport) {
+	// Add result in Qase if asked
+	Qase(testCaseID, report)
+})
+
+// deleteCCECluster deletes the node EIPs and the cluster from Rancher
+func deleteCCECluster(cluster *management.Cluster, client *rancher.Client) {
+	GinkgoLogr.Info(fmt.Sprintf("Cleaning up cluster %s node EIPs", cluster.Name))
+	helper.DeleteCCEHostClusterNodeEIPs(cluster, client)
+
+	GinkgoLogr.Info(fmt.Sprintf("Cleaning up resource cluster: %s %s", cluster.Name, cluster.ID))
+	err := helper.DeleteCCEHostCluster(cluster, client)
+	Expect(err).To(BeNil())
+}
+
+// updateClusterInUpdatingState runs checks to ensure cluster in an updating state can be updated
+func updateClusterInUpdatingState(cluster *management.Cluster, client *rancher.Client, upgradeToVersion string) {
+	var err error
+	nodeCount := cluster.CCEConfig.NodePools[0].InitialNodeCount + increaseBy
+
+	cluster, err = helper.UpgradeClusterKubernetesVersion(cluster, upgradeToVersion, client, false)
+	Expect(err).To(BeNil())
+	Expect(cluster.CCEConfig.Version).To(Equal(upgradeToVersion))
+
+	err = clusters.WaitClusterToBeInUpgrade(client, cluster.ID)
+	Expect(err).To(BeNil())
+
+	cluster, err = helper.ScaleNodeGroup(cluster, client, nodeCount, false, false)
+	Expect(err).To(BeNil())
+
+	err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
+	Expect(err).To(BeNil())
+
+	Eventually(func() bool {
+		GinkgoLogr.Info("Waiting for the updated changes to appear in CCEStatus.UpstreamSpec ...")
+		cluster, err = client.Management.Cluster.ByID(cluster.ID)
+		Expect(err).To(BeNil())
+		for _, np := range cluster.CCEStatus.UpstreamSpec.NodePools {
+			if np.InitialNodeCount != nodeCount {
+				return false
+			}
+		}
+		return cluster.CCEStatus.UpstreamSpec.Version == upgradeToVersion
+	}, tools.SetTimeout(15*time.Minute), 30*time.Second).Should(BeTrue())
+}
+
+// updateCloudCredentialsCheck switches the cluster to a new cloud credential and makes sure it is still usable
+func updateCloudCredentialsCheck(cluster *management.Cluster, client *rancher.Client) {
+	cluster, _ = helpers.RotateCloudCredential(cluster, client)
+
+	if helpers.IsImport {
+		cluster.CCEConfig = cluster.CCEStatus.UpstreamSpec
+	}
+	cluster, err := helper.ScaleNodeGroup(cluster, client, cluster.CCEConfig.NodePools[0].InitialNodeCount+increaseBy, true, true)
+	Expect(err).To(BeNil())
+}
+
+// deleteAllNodePoolsCheck removes every node pool from the config, the operator must refuse it and keep the node pools on Huawei Cloud
+func deleteAllNodePoolsCheck(cluster *management.Cluster, client *rancher.Client) {
+	cceClusterID := cluster.CCEStatus.UpstreamSpec.ClusterID
+	nodePools, err := helper.ListCCENodePoolsOnHuawei(region, cceClusterID)
+	Expect(err).To(BeNil())
+
+	updateFunc := func(cluster *management.Cluster) {
+		// setting this to nil will do nothing, so we set it to empty array
+		cluster.CCEConfig.NodePools = []management.CCENodePool{}
+	}
+	cluster, err = helper.UpdateCluster(cluster, client, updateFunc)
+	Expect(err).To(BeNil())
+
+	Eventually(func() bool {
+		cluster, err = client.Management.Cluster.ByID(cluster.ID)
+		Expect(err).To(BeNil())
+		return cluster.Transitioning == "error"
+	}, "5m", "5s").Should(BeTrue())
+
+	Consistently(func() int {
+		cceNodePools, err := helper.ListCCENodePoolsOnHuawei(region, cceClusterID)
+		Expect(err).To(BeNil())
+		return len(cceNodePools)
+	}, "1m", "15s").Should(Equal(len(nodePools)))
+}
+
+// duplicateNodePoolNameCheck adds a node pool named after an existing one, the operator must refuse it
+func duplicateNodePoolNameCheck(cluster *management.Cluster, client *rancher.Client) {
+	cceClusterID := cluster.CCEStatus.UpstreamSpec.ClusterID
+	nodePools, err := helper.ListCCENodePoolsOnHuawei(region, cceClusterID)
+	Expect(err).To(BeNil())
+
+	updateFunc := func(cluster *management.Cluster) {
+		duplicate := cluster.CCEConfig.NodePools[0]
+		duplicate.ID = ""
+		cluster.CCEConfig.NodePools = append(cluster.CCEConfig.NodePools, duplicate)
+	}
+	cluster, err = helper.UpdateCluster(cluster, client, updateFunc)
+	Expect(err).To(BeNil())
+
+	Eventually(func() bool {
+		cluster, err = client.Management.Cluster.ByID(cluster.ID)
+		Expect(err).To(BeNil())
+		return cluster.Transitioning == "error"
+	}, "5m", "5s").Should(BeTrue())
+
+	cceNodePools, err := helper.ListCCENodePoolsOnHuawei(region, cceClusterID)
+	Expect(err).To(BeNil())
+	Expect(cceNodePools).To(HaveLen(len(nodePools)))
+}
+
+func syncK8sVersionUpgradeCheck(cluster *management.Cluster, client *rancher.Client, upgradeToVersion string) {
+	GinkgoLogr.Info("Upgrading cluster to version:" + upgradeToVersion)
+
+	err := helper.UpgradeCCEClusterOnHuawei(region, cluster.CCEStatus.UpstreamSpec.ClusterID, upgradeToVersion)
+	Expect(err).To(BeNil())
+
+	Eventually(func() string {
+		GinkgoLogr.Info("Waiting for k8s upgrade to appear in CCEStatus.UpstreamSpec ...")
+		cluster, err = client.Management.Cluster.ByID(cluster.ID)
+		Expect(err).To(BeNil())
+		return cluster.CCEStatus.UpstreamSpec.Version
+	}, tools.SetTimeout(10*time.Minute), 10*time.Second).Should(Equal(upgradeToVersion), "Failed while waiting for k8s upgrade to appear in CCEStatus.UpstreamSpec")
+
+	if !helpers.IsImport {
+		// For imported clusters, CCEConfig only holds the values edited in Rancher; so we check CCEConfig only when testing provisioned clusters
+		Expect(cluster.CCEConfig.Version).To(Equal(upgradeToVersion))
+	}
+}
+
+func syncHuaweiToRancherCheck(cluster *management.Cluster, client *rancher.Client, upgradeToVersion string) {
+	var err error
+	cceClusterID := cluster.CCEStatus.UpstreamSpec.ClusterID
+
+	By("upgrading control plane", func() {
+		syncK8sVersionUpgradeCheck(cluster, client, upgradeToVersion)
+	})
+
+	By("scaling up the node pool", func() {
+		nodePool := cluster.CCEStatus.UpstreamSpec.NodePools[0]
+		nodeCount := nodePool.InitialNodeCount + increaseBy
+		err = helper.ScaleCCENodePoolOnHuawei(region, cceClusterID, nodePool.ID, nodeCount)
+		Expect(err).To(BeNil())
+
+		Eventually(func() bool {
+			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+			Expect(err).To(BeNil())
+			updated := cluster.CCEStatus.UpstreamSpec.NodePools[0].InitialNodeCount == nodeCount
+			if !helpers.IsImport {
+				updated = updated && cluster.CCEConfig.NodePools[0].InitialNodeCount == nodeCount
+			}
+			return updated
+		}, "10m", "10s").Should(BeTrue(), "Timed out waiting for node pool scale to show in Rancher")
+	})
+
+	By("deleting a node pool", func() {
+		if helpers.IsImport {
+			cluster.CCEConfig = cluster.CCEStatus.UpstreamSpec
+		}
+		cluster, err = helper.AddNodePool(cluster, increaseBy, client, true, true)
+		Expect(err).To(BeNil())
+		upstreamNodePools := cluster.CCEStatus.UpstreamSpec.NodePools
+		nodePool := upstreamNodePools[len(upstreamNodePools)-1]
+
+		err = helper.DeleteCCENodePoolOnHuawei(region, cceClusterID, nodePool.ID)
+		Expect(err).To(BeNil())
+
+		Eventually(func() bool {
+			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+			Expect(err).To(BeNil())
+			nodePools := cluster.CCEStatus.UpstreamSpec.NodePools
+			if !helpers.IsImport {
+				nodePools = append(nodePools, cluster.CCEConfig.NodePools...)
+			}
+			for _, np := range nodePools {
+				if np.Name == nodePool.Name {
+					return false
+				}
+			}
+			return true
+		}, "10m", "10s").Should(BeTrue(), "Timed out waiting for node pool to delete from Rancher")
+	})
+}
+
+func syncRancherToHuaweiCheck(cluster *management.Cluster, client *rancher.Client, upgradeToVersion string) {
+	var err error
+	cceClusterID := cluster.CCEStatus.UpstreamSpec.ClusterID
+	if helpers.IsImport {
+		cluster.CCEConfig = cluster.CCEStatus.UpstreamSpec
+	}
+	currentNodePoolNumber := len(cluster.CCEConfig.NodePools)
+	initialNodeCount := cluster.CCEConfig.NodePools[0].InitialNodeCount
+
+	By("upgrading control plane", func() {
+		syncK8sVersionUpgradeCheck(cluster, client, upgradeToVersion)
+	})
+
+	By("scaling up the node pool", func() {
+		// fetch the cluster again so that the update does not revert the upgrade
+		cluster, err = client.Management.Cluster.ByID(cluster.ID)
+		Expect(err).To(BeNil())
+		if helpers.IsImport {
+			cluster.CCEConfig = cluster.CCEStatus.UpstreamSpec
+		}
+		cluster, err = helper.ScaleNodeGroup(cluster, client, initialNodeCount+increaseBy, true, true)
+		Expect(err).To(BeNil())
+
+		// Verify the new edits reflect in Huawei Cloud and existing details do NOT change
+		cceNodePools, err := helper.ListCCENodePoolsOnHuawei(region, cceClusterID)
+		Expect(err).To(BeNil())
+		Expect(cceNodePools).To(HaveLen(currentNodePoolNumber))
+		for _, np := range cceNodePools {
+			Expect(np.InitialNodeCount).To(Equal(initialNodeCount + increaseBy))
+		}
+	})
+
+	By("adding a node pool", func() {
+		cluster, err = helper.AddNodePool(cluster, increaseBy, client, true, true)
+		Expect(err).To(BeNil())
+
+		// Verify the existing details do NOT change in Rancher
+		Expect(cluster.CCEStatus.UpstreamSpec.Version).To(Equal(upgradeToVersion))
+
+		// Verify the new edits reflect in Huawei Cloud
+		cceNodePools, err := helper.ListCCENodePoolsOnHuawei(region, cceClusterID)
+		Expect(err).To(BeNil())
+		Expect(cceNodePools).To(HaveLen(currentNodePoolNumber + increaseBy))
+	})
+}
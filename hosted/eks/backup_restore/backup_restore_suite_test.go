@@ -16,8 +16,8 @@ package backup_restore_test
 
 import (
 	"fmt"
-	"os"
 	"os/exec"
+	"slices"
 	"testing"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -25,114 +25,118 @@ import (
 	"github.com/rancher-sandbox/ele-testhelpers/kubectl"
 	. "github.com/rancher-sandbox/qase-ginkgo"
 
-	"github.com/rancher/shepherd/clients/rancher"
-	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
-	namegen "github.com/rancher/shepherd/pkg/namegenerator"
-
 	"github.com/rancher/hosted-providers-e2e/hosted/eks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
 )
 
 const (
-	increaseBy          = 1
-	backupResourceName  = "hp-backup"
-	restoreResourceName = "hp-restore"
+	increaseBy                   = 1
+	backupResourceName           = "hp-backup"
+	restoreResourceName          = "hp-restore"
+	encryptedBackupResourceName  = "hp-backup-encrypted"
+	encryptedRestoreResourceName = "hp-restore-encrypted"
 )
 
 var (
-	testCaseID              int64
-	clusterName, backupFile string
-	ctx                     helpers.RancherContext
-	cluster                 *management.Cluster
-	region                  = helpers.GetEKSRegion()
-	k3sVersion              = os.Getenv("INSTALL_K3S_VERSION")
+	ctx         helpers.RancherContext
+	region      = helpers.GetEKSRegion()
+	environment helpers.EnvironmentProfile
 )
 
 func TestBackupRestore(t *testing.T) {
 	RegisterFailHandler(Fail)
 	helpers.CommonSynchronizedBeforeSuite()
 	ctx = helpers.CommonBeforeSuite()
+	helpers.ExpectNoOperatorOverride()
+	environment = helpers.StockEnvironmentProfile()
 	RunSpecs(t, "BackupRestore Suite")
 }
 
-var _ = ReportBeforeEach(func(report SpecReport) {
-	// Reset case ID
-	testCaseID = -1
-})
-
 var _ = ReportAfterEach(func(report SpecReport) {
 	// Add result in Qase if asked
-	Qase(testCaseID, report)
+	Qase(helpers.QaseID(report), report)
 })
 
 var _ = BeforeEach(func() {
-	clusterName = namegen.AppendRandomString(helpers.ClusterNamePrefix)
-	k8sVersion, err := helper.GetK8sVersion(ctx.RancherAdminClient, false)
+	if slices.Contains(CurrentSpecReport().Labels(), "migration") {
+		// For migration tests, the rancher version should not be an unreleased version (for e.g. 2.9-head)
+		Expect(helpers.RancherFullVersion).To(SatisfyAll(Not(BeEmpty()), Not(ContainSubstring("devel"))))
+		Expect(helpers.RancherUpgradeFullVersion).ToNot(BeEmpty())
+	}
+})
+
+// newFixture provisions or imports the cluster of the current spec, it is deleted once the spec is done
+func newFixture() *helpers.Fixture {
+	f := helpers.NewFixture(&ctx)
+	var err error
+	f.K8sVersion, err = helper.GetK8sVersion(f.Client, false)
 	Expect(err).To(BeNil())
-	GinkgoLogr.Info(fmt.Sprintf("Using K8s version %s for cluster %s", k8sVersion, clusterName))
+	GinkgoLogr.Info(fmt.Sprintf("Using K8s version %s for cluster %s", f.K8sVersion, f.ClusterName))
 
+	f.GuardCost()
 	if helpers.IsImport {
 		By("importing the cluster")
-		err = helper.CreateEKSClusterOnAWS(region, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
+		err = helper.CreateEKSClusterOnAWS(region, f.ClusterName, f.K8sVersion, 1, helpers.GetCommonMetadataLabels(), nil)
 		Expect(err).To(BeNil())
-		cluster, err = helper.ImportEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, region)
+		f.Cluster, err = helper.ImportEKSHostedCluster(f.Client, f.ClusterName, ctx.CloudCredID, region)
 		Expect(err).To(BeNil())
 	} else {
 		By("provisioning the cluster")
-		cluster, err = helper.CreateEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, region, nil)
+		f.Cluster, err = helper.CreateEKSHostedCluster(f.Client, f.ClusterName, ctx.CloudCredID, f.K8sVersion, region, nil)
 		Expect(err).To(BeNil())
 	}
-	cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
-	Expect(err).To(BeNil())
-})
-
-var _ = AfterEach(func() {
-	if ctx.ClusterCleanup && cluster != nil {
-		err := helper.DeleteEKSHostCluster(cluster, ctx.RancherAdminClient)
+	f.AddClusterCleanup(func() {
+		err := helper.DeleteEKSHostCluster(f.Cluster, f.Client)
 		Expect(err).To(BeNil())
 		if helpers.IsImport {
-			err = helper.DeleteEKSClusterOnAWS(region, clusterName)
+			err = helper.DeleteEKSClusterOnAWS(region, f.ClusterName)
 			Expect(err).To(BeNil())
 		}
-	} else {
-		fmt.Println("Skipping downstream cluster deletion: ", clusterName)
-	}
-})
+	})
+	f.Cluster, err = helpers.WaitUntilClusterIsReady(f.Cluster, f.Client)
+	Expect(err).To(BeNil())
+	return f
+}
 
-func restoreNodesChecks(cluster *management.Cluster, client *rancher.Client, clusterName string) {
-	helpers.ClusterIsReadyChecks(cluster, client, clusterName)
-	configNodeGroups := *cluster.EKSConfig.NodeGroups
+func restoreNodesChecks(f *helpers.Fixture) {
+	helpers.ClusterIsReadyChecks(f.Cluster, f.Client, f.ClusterName)
+	configNodeGroups := *f.Cluster.EKSConfig.NodeGroups
 	initialNodeCount := *configNodeGroups[0].DesiredSize
 
 	By("scaling up the NodeGroup", func() {
 		var err error
-		cluster, err = helper.ScaleNodeGroup(cluster, client, initialNodeCount+increaseBy, true, true)
+		f.Cluster, err = helper.ScaleNodeGroup(f.Cluster, f.Client, initialNodeCount+increaseBy, true, true)
 		Expect(err).To(BeNil())
 	})
 
 	By("adding a NodeGroup", func() {
 		var err error
-		cluster, err = helper.AddNodeGroup(cluster, increaseBy, client, true, true)
+		f.Cluster, err = helper.AddNodeGroup(f.Cluster, increaseBy, f.Client, true, true)
 		Expect(err).To(BeNil())
 	})
 }
 
-func BackupRestoreChecks(k *kubectl.Kubectl) {
+func BackupRestoreChecks(f *helpers.Fixture, k *kubectl.Kubectl) {
+	var backupFile string
 	By("Checking hosted cluster is ready", func() {
-		helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
+		helpers.ClusterIsReadyChecks(f.Cluster, f.Client, f.ClusterName)
 	})
 
 	By("Performing a backup", func() {
 		backupFile = helpers.ExecuteBackup(k, backupResourceName)
 	})
 
+	By("Checking the backup content", func() {
+		helpers.InspectBackup(backupFile, f.Cluster)
+	})
+
 	By("Perform restore pre-requisites: Uninstalling k3s", func() {
 		out, err := exec.Command("k3s-uninstall.sh").CombinedOutput()
 		Expect(err).To(Not(HaveOccurred()), out)
 	})
 
 	By("Perform restore pre-requisites: Getting k3s ready", func() {
-		helpers.InstallK3S(k, k3sVersion, "none", "none")
+		helpers.InstallK3S(k, environment)
 	})
 
 	By("Performing a restore", func() {
@@ -140,12 +144,11 @@ func BackupRestoreChecks(k *kubectl.Kubectl) {
 	})
 
 	By("Performing post migration installations: Installing CertManager", func() {
-		helpers.InstallCertManager(k, "none", "none")
+		helpers.InstallCertManager(k, environment)
 	})
 
 	By("Performing post migration installations: Installing Rancher Manager", func() {
-		rancherChannel, rancherVersion, rancherHeadVersion := helpers.GetRancherVersions(helpers.RancherFullVersion)
-		helpers.InstallRancherManager(k, helpers.RancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, "none", "none")
+		helpers.InstallRancherManager(k, environment)
 	})
 
 	By("Performing post migration installations: Checking Rancher Deployments", func() {
@@ -153,6 +156,127 @@ func BackupRestoreChecks(k *kubectl.Kubectl) {
 	})
 
 	By("Checking hosted cluster can be modified", func() {
-		restoreNodesChecks(cluster, ctx.RancherAdminClient, clusterName)
+		restoreNodesChecks(f)
+	})
+}
+
+func EncryptedBackupRestoreChecks(f *helpers.Fixture, k *kubectl.Kubectl) {
+	var backupFile string
+	var encryptionConfigFile string
+
+	By("Checking hosted cluster is ready", func() {
+		helpers.ClusterIsReadyChecks(f.Cluster, f.Client, f.ClusterName)
+	})
+
+	By("Performing an encrypted backup", func() {
+		backupFile, encryptionConfigFile = helpers.ExecuteEncryptedBackup(k, encryptedBackupResourceName)
+	})
+
+	By("Checking the encrypted backup content", func() {
+		helpers.InspectBackup(backupFile, f.Cluster)
+	})
+
+	By("Perform restore pre-requisites: Uninstalling k3s", func() {
+		out, err := exec.Command("k3s-uninstall.sh").CombinedOutput()
+		Expect(err).To(Not(HaveOccurred()), out)
+	})
+
+	By("Perform restore pre-requisites: Getting k3s ready", func() {
+		helpers.InstallK3S(k, environment)
+	})
+
+	By("Checking that a restore without the encryption configuration fails", func() {
+		helpers.ExecuteRestoreWithoutEncryptionConfig(k, restoreResourceName, backupFile)
+	})
+
+	By("Performing an encrypted restore", func() {
+		helpers.ExecuteEncryptedRestore(k, encryptedRestoreResourceName, backupFile, encryptionConfigFile)
+	})
+
+	By("Performing post migration installations: Installing CertManager", func() {
+		helpers.InstallCertManager(k, environment)
+	})
+
+	By("Performing post migration installations: Installing Rancher Manager", func() {
+		helpers.InstallRancherManager(k, environment)
+	})
+
+	By("Performing post migration installations: Checking Rancher Deployments", func() {
+		helpers.CheckRancherDeployments(k)
+	})
+
+	By("Checking hosted cluster can be modified", func() {
+		restoreNodesChecks(f)
+	})
+}
+
+// MigrationBackupRestoreChecks backs up Rancher RANCHER_VERSION and restores it onto Rancher RANCHER_UPGRADE_VERSION,
+// running on k3s INSTALL_K3S_UPGRADE_VERSION if it is set
+func MigrationBackupRestoreChecks(f *helpers.Fixture, k *kubectl.Kubectl) {
+	var backupFile string
+	var originalChartVersion string
+
+	migrationEnvironment := environment.WithRancherVersion(helpers.RancherUpgradeFullVersion)
+	if helpers.K3sUpgradeVersion != "" {
+		migrationEnvironment = migrationEnvironment.WithK3sVersion(helpers.K3sUpgradeVersion)
+	}
+
+	By("Checking hosted cluster is ready", func() {
+		helpers.ClusterIsReadyChecks(f.Cluster, f.Client, f.ClusterName)
+	})
+
+	By("Checking the operator chart version", func() {
+		originalChartVersion = helpers.GetCurrentOperatorChartVersion()
+		Expect(originalChartVersion).ToNot(BeEmpty())
+		GinkgoLogr.Info("Original chart version: " + originalChartVersion)
+	})
+
+	By("Performing a backup", func() {
+		backupFile = helpers.ExecuteBackup(k, backupResourceName)
+	})
+
+	By("Checking the backup content", func() {
+		helpers.InspectBackup(backupFile, f.Cluster)
+	})
+
+	By("Perform restore pre-requisites: Uninstalling k3s", func() {
+		out, err := exec.Command("k3s-uninstall.sh").CombinedOutput()
+		Expect(err).To(Not(HaveOccurred()), out)
+	})
+
+	By(fmt.Sprintf("Perform restore pre-requisites: Getting k3s %s ready", migrationEnvironment.K3sVersion), func() {
+		helpers.InstallK3S(k, migrationEnvironment)
+	})
+
+	By("Performing a restore", func() {
+		helpers.ExecuteRestore(k, restoreResourceName, backupFile)
+	})
+
+	By("Performing post migration installations: Installing CertManager", func() {
+		helpers.InstallCertManager(k, environment)
+	})
+
+	By(fmt.Sprintf("Performing post migration installations: Installing Rancher Manager %s", helpers.RancherUpgradeFullVersion), func() {
+		helpers.InstallRancherManager(k, migrationEnvironment)
+	})
+
+	By("Performing post migration installations: Checking Rancher Deployments", func() {
+		helpers.CheckRancherDeployments(k)
+	})
+
+	By("Checking hosted cluster is active after migration", func() {
+		var err error
+		f.Cluster, err = helpers.WaitUntilClusterIsReady(f.Cluster, f.Client)
+		Expect(err).To(BeNil())
+		helpers.ClusterIsReadyChecks(f.Cluster, f.Client, f.ClusterName)
+	})
+
+	By("Checking the operator chart has not been downgraded by the upgraded Rancher version", func() {
+		helpers.WaitUntilOperatorChartInstallation(originalChartVersion, ">=", 0)
+		GinkgoLogr.Info("Upgraded chart version: " + helpers.GetCurrentOperatorChartVersion())
+	})
+
+	By("Checking hosted cluster can be modified", func() {
+		restoreNodesChecks(f)
 	})
 }
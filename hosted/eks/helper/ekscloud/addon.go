@@ -0,0 +1,85 @@
+package ekscloud
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/pkg/errors"
+)
+
+const (
+	// VPCCNIAddon is the Amazon VPC CNI, its network policy agent enforces the NetworkPolicies of the cluster
+	VPCCNIAddon = "vpc-cni"
+	// networkPolicyConfiguration enables the network policy agent of the VPC CNI
+	networkPolicyConfiguration = `{"enableNetworkPolicy":"true"}`
+)
+
+func (c *client) EnableNetworkPolicy(clusterName string) error {
+	return c.configureAddon(clusterName, VPCCNIAddon, networkPolicyConfiguration)
+}
+
+// configureAddon installs an addon with the given configuration values, or updates its configuration when it is already installed;
+// the self-managed addons installed along with the cluster (e.g. the VPC CNI) are taken over
+func (c *client) configureAddon(clusterName, addonName, configurationValues string) error {
+	_, err := c.eks.CreateAddon(&eks.CreateAddonInput{
+		ClusterName:         aws.String(clusterName),
+		AddonName:           aws.String(addonName),
+		ConfigurationValues: aws.String(configurationValues),
+		ResolveConflicts:    aws.String(eks.ResolveConflictsOverwrite),
+	})
+	if isInUse(err) {
+		_, err = c.eks.UpdateAddon(&eks.UpdateAddonInput{
+			ClusterName:         aws.String(clusterName),
+			AddonName:           aws.String(addonName),
+			ConfigurationValues: aws.String(configurationValues),
+			ResolveConflicts:    aws.String(eks.ResolveConflictsOverwrite),
+		})
+	}
+	if err != nil {
+		return errors.Wrapf(err, "configuring addon %s of cluster %s", addonName, clusterName)
+	}
+	return c.waitForAddon(clusterName, addonName, configurationValues)
+}
+
+// waitForAddon polls an addon until it is active with the given configuration values
+func (c *client) waitForAddon(clusterName, addonName, configurationValues string) error {
+	input := &eks.DescribeAddonInput{
+		ClusterName: aws.String(clusterName),
+		AddonName:   aws.String(addonName),
+	}
+	deadline := time.Now().Add(updateTimeout)
+	for {
+		out, err := c.eks.DescribeAddon(input)
+		if err != nil {
+			return errors.Wrapf(err, "describing addon %s of cluster %s", addonName, clusterName)
+		}
+		switch status := aws.StringValue(out.Addon.Status); status {
+		case eks.AddonStatusActive:
+			if aws.StringValue(out.Addon.ConfigurationValues) == configurationValues {
+				return nil
+			}
+		case eks.AddonStatusCreateFailed, eks.AddonStatusUpdateFailed:
+			var details []string
+			if out.Addon.Health != nil {
+				for _, issue := range out.Addon.Health.Issues {
+					details = append(details, aws.StringValue(issue.Message))
+				}
+			}
+			return fmt.Errorf("addon %s of cluster %s is %s: %s", addonName, clusterName, status, strings.Join(details, "; "))
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for addon %s of cluster %s", addonName, clusterName)
+		}
+		time.Sleep(c.waiterDelay)
+	}
+}
+
+// isInUse reports whether EKS rejected the creation of a resource because it already exists
+func isInUse(err error) bool {
+	var awsErr awserr.Error
+	return errors.As(err, &awsErr) && awsErr.Code() == eks.ErrCodeResourceInUseException
+}
@@ -0,0 +1,452 @@
+/*
+Copyright © 2022 - 2025 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ekscloud_test
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/eks/helper/ekscloud"
+)
+
+// awsStandIn is an in-memory stand-in of the EKS (REST-JSON) and CloudFormation (query) APIs;
+// resources are ready as soon as they are created and updates succeed on their second poll
+type awsStandIn struct {
+	mu             sync.Mutex
+	stacks         map[string]*stack
+	clusters       map[string]map[string]interface{}
+	nodegroups     map[string]map[string]map[string]interface{}
+	updates        map[string]int
+	addons         map[string]map[string]interface{}
+	accessEntries  map[string]string
+	failUpdates    bool
+	failCreation   bool
+	failNodegroups bool
+}
+
+type stack struct {
+	Name       string
+	Template   string
+	Parameters map[string]string
+	Tags       map[string]string
+}
+
+func newAWSStandIn() *awsStandIn {
+	return &awsStandIn{
+		stacks:        map[string]*stack{},
+		clusters:      map[string]map[string]interface{}{},
+		nodegroups:    map[string]map[string]map[string]interface{}{},
+		updates:       map[string]int{},
+		addons:        map[string]map[string]interface{}{},
+		accessEntries: map[string]string{},
+	}
+}
+
+func (a *awsStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if r.Method == http.MethodPost && r.URL.Path == "/" {
+		a.serveCloudFormation(w, r)
+		return
+	}
+	a.serveEKS(w, r)
+}
+
+// addCluster registers a cluster which was not created through the client
+func (a *awsStandIn) addCluster(name, version string, nodegroups ...string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.clusters[name] = newCluster(name, version)
+	a.nodegroups[name] = map[string]map[string]interface{}{}
+	for _, ng := range nodegroups {
+		a.nodegroups[name][ng] = newNodegroup(name, ng, version, map[string]interface{}{
+			"nodeRole":      "arn:aws:iam::123456789012:role/existing-node-role",
+			"subnets":       []interface{}{"subnet-existing"},
+			"scalingConfig": map[string]interface{}{"desiredSize": 1, "minSize": 1, "maxSize": 1},
+		})
+	}
+}
+
+func (a *awsStandIn) serveCloudFormation(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	name := r.Form.Get("StackName")
+	switch r.Form.Get("Action") {
+	case "CreateStack":
+		s := &stack{Name: name, Template: r.Form.Get("TemplateBody"), Parameters: map[string]string{}, Tags: map[string]string{}}
+		for i := 1; r.Form.Has(fmt.Sprintf("Parameters.member.%d.ParameterKey", i)); i++ {
+			s.Parameters[r.Form.Get(fmt.Sprintf("Parameters.member.%d.ParameterKey", i))] = r.Form.Get(fmt.Sprintf("Parameters.member.%d.ParameterValue", i))
+		}
+		for i := 1; r.Form.Has(fmt.Sprintf("Tags.member.%d.Key", i)); i++ {
+			s.Tags[r.Form.Get(fmt.Sprintf("Tags.member.%d.Key", i))] = r.Form.Get(fmt.Sprintf("Tags.member.%d.Value", i))
+		}
+		a.stacks[name] = s
+		writeXML(w, struct {
+			XMLName xml.Name `xml:"CreateStackResponse"`
+			StackID string   `xml:"CreateStackResult>StackId"`
+		}{StackID: "arn:aws:cloudformation:us-east-2:123456789012:stack/" + name})
+	case "DescribeStacks":
+		s, ok := a.stacks[name]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			writeXML(w, struct {
+				XMLName xml.Name `xml:"ErrorResponse"`
+				Code    string   `xml:"Error>Code"`
+				Message string   `xml:"Error>Message"`
+			}{Code: "ValidationError", Message: fmt.Sprintf("Stack with id %s does not exist", name)})
+			return
+		}
+		type output struct {
+			OutputKey   string
+			OutputValue string
+		}
+		var outputs []output
+		if strings.Contains(s.Template, "ClusterRoleArn") {
+			outputs = []output{
+				{"ClusterRoleArn", "arn:aws:iam::123456789012:role/cluster-role"},
+				{"NodeRoleArn", "arn:aws:iam::123456789012:role/node-role"},
+				{"NodeInstanceProfileArn", "arn:aws:iam::123456789012:instance-profile/node-profile"},
+				{"SubnetIds", "subnet-1,subnet-2"},
+				{"SecurityGroupIds", "sg-control-plane"},
+			}
+		}
+		writeXML(w, struct {
+			XMLName     xml.Name `xml:"DescribeStacksResponse"`
+			StackName   string   `xml:"DescribeStacksResult>Stacks>member>StackName"`
+			StackStatus string   `xml:"DescribeStacksResult>Stacks>member>StackStatus"`
+			Outputs     []output `xml:"DescribeStacksResult>Stacks>member>Outputs>member"`
+		}{StackName: name, StackStatus: "CREATE_COMPLETE", Outputs: outputs})
+	case "DeleteStack":
+		delete(a.stacks, name)
+		writeXML(w, struct {
+			XMLName xml.Name `xml:"DeleteStackResponse"`
+		}{})
+	default:
+		http.Error(w, "unsupported action", http.StatusBadRequest)
+	}
+}
+
+func (a *awsStandIn) serveEKS(w http.ResponseWriter, r *http.Request) {
+	var body map[string]interface{}
+	if r.Body != nil && r.ContentLength != 0 {
+		_ = json.NewDecoder(r.Body).Decode(&body)
+	}
+
+	if arn, ok := strings.CutPrefix(r.URL.Path, "/tags/"); ok {
+		for _, cluster := range a.clusters {
+			if cluster["arn"] != arn {
+				continue
+			}
+			tags := cluster["tags"].(map[string]interface{})
+			if r.Method == http.MethodPost {
+				maps.Copy(tags, body["tags"].(map[string]interface{}))
+			} else {
+				for _, key := range r.URL.Query()["tagKeys"] {
+					delete(tags, key)
+				}
+			}
+			writeJSON(w, map[string]interface{}{})
+			return
+		}
+		writeEKSError(w, http.StatusNotFound, "ResourceNotFoundException", "resource not found")
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if parts[0] != "clusters" {
+		http.Error(w, "unsupported path", http.StatusNotFound)
+		return
+	}
+	if len(parts) == 1 && r.Method == http.MethodPost {
+		if a.failCreation {
+			writeEKSError(w, http.StatusBadRequest, "InvalidParameterException", "cluster creation failed on purpose")
+			return
+		}
+		name := body["name"].(string)
+		version, _ := body["version"].(string)
+		if version == "" {
+			version = "1.31"
+		}
+		cluster := newCluster(name, version)
+		vpc := cluster["resourcesVpcConfig"].(map[string]interface{})
+		vpc["subnetIds"] = body["resourcesVpcConfig"].(map[string]interface{})["subnetIds"]
+		cluster["roleArn"] = body["roleArn"]
+		cluster["accessConfig"] = body["accessConfig"]
+		if tags, ok := body["tags"].(map[string]interface{}); ok {
+			cluster["tags"] = tags
+		}
+		a.clusters[name] = cluster
+		a.nodegroups[name] = map[string]map[string]interface{}{}
+		writeJSON(w, map[string]interface{}{"cluster": cluster})
+		return
+	}
+
+	clusterName := parts[1]
+	cluster, ok := a.clusters[clusterName]
+	if !ok {
+		writeEKSError(w, http.StatusNotFound, "ResourceNotFoundException", "No cluster found for name: "+clusterName)
+		return
+	}
+
+	switch {
+	case len(parts) == 2 && r.Method == http.MethodGet:
+		writeJSON(w, map[string]interface{}{"cluster": cluster})
+	case len(parts) == 2 && r.Method == http.MethodDelete:
+		delete(a.clusters, clusterName)
+		delete(a.nodegroups, clusterName)
+		writeJSON(w, map[string]interface{}{"cluster": cluster})
+	case len(parts) == 3 && parts[2] == "updates":
+		cluster["version"] = body["version"]
+		a.writeUpdate(w, "VersionUpdate")
+	case len(parts) == 3 && parts[2] == "update-config":
+		if logging, ok := body["logging"].(map[string]interface{}); ok {
+			if !a.updateLogging(cluster, logging) {
+				writeEKSError(w, http.StatusBadRequest, "InvalidParameterException", "No changes needed for the logging config provided")
+				return
+			}
+		}
+		if vpcConfig, ok := body["resourcesVpcConfig"].(map[string]interface{}); ok {
+			maps.Copy(cluster["resourcesVpcConfig"].(map[string]interface{}), vpcConfig)
+		}
+		a.writeUpdate(w, "ConfigUpdate")
+	case len(parts) == 4 && parts[2] == "updates":
+		a.updates[parts[3]]++
+		status := "InProgress"
+		var errs []interface{}
+		switch {
+		case a.updates[parts[3]] < 2:
+		case a.failUpdates:
+			status = "Failed"
+			errs = []interface{}{map[string]interface{}{"errorCode": "Unknown", "errorMessage": "update failed on purpose"}}
+		default:
+			status = "Successful"
+		}
+		writeJSON(w, map[string]interface{}{"update": map[string]interface{}{"id": parts[3], "status": status, "errors": errs}})
+	case len(parts) == 3 && parts[2] == "access-entries":
+		a.accessEntries[body["principalArn"].(string)] = body["type"].(string)
+		writeJSON(w, map[string]interface{}{"accessEntry": map[string]interface{}{"principalArn": body["principalArn"]}})
+	case len(parts) == 3 && parts[2] == "node-groups" && r.Method == http.MethodGet:
+		writeJSON(w, map[string]interface{}{"nodegroups": slices.Sorted(maps.Keys(a.nodegroups[clusterName]))})
+	case len(parts) == 3 && parts[2] == "node-groups" && r.Method == http.MethodPost:
+		if a.failNodegroups {
+			writeEKSError(w, http.StatusBadRequest, "InvalidParameterException", "nodegroup creation failed on purpose")
+			return
+		}
+		name := body["nodegroupName"].(string)
+		version, _ := body["version"].(string)
+		if version == "" {
+			version = cluster["version"].(string)
+		}
+		nodegroup := newNodegroup(clusterName, name, version, body)
+		a.nodegroups[clusterName][name] = nodegroup
+		writeJSON(w, map[string]interface{}{"nodegroup": nodegroup})
+	case len(parts) >= 3 && parts[2] == "addons":
+		a.serveAddon(w, r, clusterName, parts[3:], body)
+	default:
+		a.serveNodegroup(w, r, clusterName, parts[3:], body)
+	}
+}
+
+// serveAddon serves the addons of a cluster; like the updates, an addon becomes active, or failed, on its second poll
+func (a *awsStandIn) serveAddon(w http.ResponseWriter, r *http.Request, clusterName string, parts []string, body map[string]interface{}) {
+	if len(parts) == 0 {
+		name := body["addonName"].(string)
+		if _, ok := a.addons[clusterName+"/"+name]; ok {
+			writeEKSError(w, http.StatusConflict, "ResourceInUseException", "Addon already exists.")
+			return
+		}
+		addon := map[string]interface{}{"addonName": name, "clusterName": clusterName, "status": "CREATING", "configurationValues": body["configurationValues"]}
+		a.addons[clusterName+"/"+name] = addon
+		writeJSON(w, map[string]interface{}{"addon": addon})
+		return
+	}
+	addon, ok := a.addons[clusterName+"/"+parts[0]]
+	if !ok {
+		writeEKSError(w, http.StatusNotFound, "ResourceNotFoundException", "No addon: "+parts[0]+" found in cluster: "+clusterName)
+		return
+	}
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		writeJSON(w, map[string]interface{}{"addon": maps.Clone(addon)})
+		switch {
+		case addon["status"] == "ACTIVE" || strings.HasSuffix(addon["status"].(string), "FAILED"):
+		case a.failUpdates:
+			addon["status"] = map[interface{}]string{"CREATING": "CREATE_FAILED", "UPDATING": "UPDATE_FAILED"}[addon["status"]]
+			addon["health"] = map[string]interface{}{"issues": []interface{}{map[string]interface{}{"code": "Unknown", "message": "addon failed on purpose"}}}
+		default:
+			addon["status"] = "ACTIVE"
+		}
+	case len(parts) == 2 && parts[1] == "update":
+		addon["status"] = "UPDATING"
+		addon["configurationValues"] = body["configurationValues"]
+		a.writeUpdate(w, "AddonUpdate")
+	default:
+		http.Error(w, "unsupported path", http.StatusNotFound)
+	}
+}
+
+func (a *awsStandIn) serveNodegroup(w http.ResponseWriter, r *http.Request, clusterName string, parts []string, body map[string]interface{}) {
+	if len(parts) == 0 {
+		http.Error(w, "unsupported path", http.StatusNotFound)
+		return
+	}
+	nodegroup, ok := a.nodegroups[clusterName][parts[0]]
+	if !ok {
+		writeEKSError(w, http.StatusNotFound, "ResourceNotFoundException", "No node group found for name: "+parts[0])
+		return
+	}
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		writeJSON(w, map[string]interface{}{"nodegroup": nodegroup})
+	case len(parts) == 1 && r.Method == http.MethodDelete:
+		delete(a.nodegroups[clusterName], parts[0])
+		writeJSON(w, map[string]interface{}{"nodegroup": nodegroup})
+	case parts[1] == "update-config":
+		if scaling, ok := body["scalingConfig"]; ok {
+			nodegroup["scalingConfig"] = scaling
+		}
+		if labels, ok := body["labels"].(map[string]interface{}); ok {
+			current := nodegroup["labels"].(map[string]interface{})
+			if add, ok := labels["addOrUpdateLabels"].(map[string]interface{}); ok {
+				maps.Copy(current, add)
+			}
+			if remove, ok := labels["removeLabels"].([]interface{}); ok {
+				for _, key := range remove {
+					delete(current, key.(string))
+				}
+			}
+		}
+		a.writeUpdate(w, "ConfigUpdate")
+	case parts[1] == "update-version":
+		nodegroup["version"] = body["version"]
+		a.writeUpdate(w, "VersionUpdate")
+	default:
+		http.Error(w, "unsupported path", http.StatusNotFound)
+	}
+}
+
+// updateLogging applies the logging setups to the cluster and reports whether something changed
+func (a *awsStandIn) updateLogging(cluster, logging map[string]interface{}) bool {
+	enabled := map[string]bool{}
+	for _, setup := range cluster["logging"].(map[string]interface{})["clusterLogging"].([]interface{}) {
+		setup := setup.(map[string]interface{})
+		if setup["enabled"] == true {
+			for _, t := range setup["types"].([]interface{}) {
+				enabled[t.(string)] = true
+			}
+		}
+	}
+	changed := false
+	for _, setup := range logging["clusterLogging"].([]interface{}) {
+		setup := setup.(map[string]interface{})
+		for _, t := range setup["types"].([]interface{}) {
+			if enabled[t.(string)] != (setup["enabled"] == true) {
+				enabled[t.(string)] = setup["enabled"] == true
+				changed = true
+			}
+		}
+	}
+	var enabledTypes, disabledTypes []interface{}
+	for _, t := range ekscloud.LoggingTypes {
+		if enabled[t] {
+			enabledTypes = append(enabledTypes, t)
+		} else {
+			disabledTypes = append(disabledTypes, t)
+		}
+	}
+	cluster["logging"] = map[string]interface{}{"clusterLogging": []interface{}{
+		map[string]interface{}{"enabled": true, "types": enabledTypes},
+		map[string]interface{}{"enabled": false, "types": disabledTypes},
+	}}
+	return changed
+}
+
+func (a *awsStandIn) writeUpdate(w http.ResponseWriter, updateType string) {
+	id := fmt.Sprintf("update-%d", len(a.updates)+1)
+	a.updates[id] = 0
+	writeJSON(w, map[string]interface{}{"update": map[string]interface{}{"id": id, "type": updateType, "status": "InProgress"}})
+}
+
+func newCluster(name, version string) map[string]interface{} {
+	var disabledTypes []interface{}
+	for _, t := range ekscloud.LoggingTypes {
+		disabledTypes = append(disabledTypes, t)
+	}
+	return map[string]interface{}{
+		"name":                    name,
+		"arn":                     "arn:aws:eks:us-east-2:123456789012:cluster/" + name,
+		"version":                 version,
+		"status":                  "ACTIVE",
+		"endpoint":                "https://" + strings.ToUpper(name) + ".gr7.us-east-2.eks.amazonaws.com",
+		"certificateAuthority":    map[string]interface{}{"data": "Y2VydGlmaWNhdGU="},
+		"kubernetesNetworkConfig": map[string]interface{}{"serviceIpv4Cidr": "10.100.0.0/16"},
+		"resourcesVpcConfig": map[string]interface{}{
+			"subnetIds":              []interface{}{"subnet-existing"},
+			"clusterSecurityGroupId": "sg-cluster",
+			"endpointPublicAccess":   true,
+			"endpointPrivateAccess":  false,
+			"publicAccessCidrs":      []interface{}{"0.0.0.0/0"},
+		},
+		"logging": map[string]interface{}{"clusterLogging": []interface{}{
+			map[string]interface{}{"enabled": false, "types": disabledTypes},
+		}},
+		"tags": map[string]interface{}{},
+	}
+}
+
+func newNodegroup(clusterName, name, version string, spec map[string]interface{}) map[string]interface{} {
+	nodegroup := map[string]interface{}{
+		"nodegroupName": name,
+		"nodegroupArn":  fmt.Sprintf("arn:aws:eks:us-east-2:123456789012:nodegroup/%s/%s", clusterName, name),
+		"clusterName":   clusterName,
+		"version":       version,
+		"status":        "ACTIVE",
+		"amiType":       "AL2023_x86_64_STANDARD",
+		"instanceTypes": []interface{}{"m5.large"},
+		"labels":        map[string]interface{}{},
+		"tags":          map[string]interface{}{},
+	}
+	for _, key := range []string{"nodeRole", "subnets", "scalingConfig", "instanceTypes", "labels", "tags"} {
+		if value, ok := spec[key]; ok && value != nil {
+			nodegroup[key] = value
+		}
+	}
+	return nodegroup
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeXML(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "text/xml")
+	_ = xml.NewEncoder(w).Encode(v)
+}
+
+func writeEKSError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("X-Amzn-Errortype", code)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"message": message})
+}
@@ -0,0 +1,191 @@
+// Package ekscloud manages EKS clusters and nodegroups directly through the AWS API,
+// it is used to create and modify clusters outside of Rancher
+package ekscloud
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/cloudformation/cloudformationiface"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/aws/aws-sdk-go/service/eks/eksiface"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultNodegroupName is the nodegroup created along with a cluster
+	DefaultNodegroupName = "ranchernodes"
+	// DefaultInstanceType is used by the nodegroups when no instance type is given
+	DefaultInstanceType = "m5.large"
+)
+
+// LoggingTypes are all the control plane logging types of an EKS cluster
+var LoggingTypes = []string{"api", "audit", "authenticator", "controllerManager", "scheduler"}
+
+// Client creates and modifies EKS clusters on AWS; every method waits for the change to be applied
+type Client interface {
+	CreateCluster(spec ClusterSpec) (*Cluster, error)
+	DescribeCluster(name string) (*Cluster, error)
+	DeleteCluster(name string) error
+	UpgradeCluster(name, version string) error
+	UpdateLogging(name string, enableTypes, disableTypes []string) error
+	UpdateVPCAccess(name string, access VPCAccess) error
+	TagResource(arn string, tags map[string]string) error
+	UntagResource(arn string, keys []string) error
+	// EnableNetworkPolicy enables the network policy agent of the VPC CNI, EKS does not enforce the NetworkPolicies otherwise
+	EnableNetworkPolicy(clusterName string) error
+	Token(clusterName string) (string, error)
+	Kubeconfig(clusterName string) ([]byte, error)
+
+	CreateNodegroup(clusterName string, spec NodegroupSpec) (*Nodegroup, error)
+	DescribeNodegroup(clusterName, name string) (*Nodegroup, error)
+	ListNodegroups(clusterName string) ([]Nodegroup, error)
+	DeleteNodegroup(clusterName, name string) error
+	ScaleNodegroup(clusterName, name string, scaling Scaling) error
+	UpdateNodegroupLabels(clusterName, name string, addOrUpdate map[string]string, remove []string) error
+	UpgradeNodegroup(clusterName, name, version string) error
+}
+
+// ClusterSpec describes a cluster to create, the VPC and IAM roles are created in a dedicated CloudFormation stack
+type ClusterSpec struct {
+	Name    string
+	Version string
+	Tags    map[string]string
+	// Nodes is the size of the default nodegroup
+	Nodes        int64
+	InstanceType string
+	// WithoutNodegroup only creates the control plane
+	WithoutNodegroup bool
+	// SelfManagedNodes creates an autoscaling group of self-managed nodes instead of the default managed nodegroup
+	SelfManagedNodes bool
+}
+
+// Cluster is the AWS view of an EKS cluster
+type Cluster struct {
+	Name                 string
+	Arn                  string
+	Version              string
+	Status               string
+	Endpoint             string
+	CertificateAuthority string
+	ServiceCIDR          string
+	SecurityGroupID      string
+	Tags                 map[string]string
+	// LoggingTypes are the enabled logging types
+	LoggingTypes []string
+	VPCAccess    VPCAccess
+	SubnetIDs    []string
+}
+
+// VPCAccess is the control plane endpoint access of a cluster
+type VPCAccess struct {
+	PublicAccess        bool
+	PrivateAccess       bool
+	PublicAccessSources []string
+}
+
+// NodegroupSpec describes a managed nodegroup to create
+type NodegroupSpec struct {
+	Name          string
+	Version       string
+	Scaling       Scaling
+	InstanceTypes []string
+	Labels        map[string]string
+	Tags          map[string]string
+	// Subnets and NodeRole default to the ones created with the cluster
+	Subnets  []string
+	NodeRole string
+}
+
+// Scaling is the size of a nodegroup
+type Scaling struct {
+	DesiredSize int64
+	MinSize     int64
+	MaxSize     int64
+}
+
+// Nodegroup is the AWS view of an EKS managed nodegroup
+type Nodegroup struct {
+	Name          string
+	Arn           string
+	Version       string
+	Status        string
+	AmiType       string
+	InstanceTypes []string
+	Scaling       Scaling
+	Labels        map[string]string
+	Tags          map[string]string
+}
+
+// Option customizes the client returned by New
+type Option func(*options)
+
+type options struct {
+	endpoint    string
+	credentials *credentials.Credentials
+	waiterDelay time.Duration
+}
+
+// WithEndpoint sends all the requests to endpoint, eg. a local stand-in of the AWS API
+func WithEndpoint(endpoint string) Option {
+	return func(o *options) { o.endpoint = endpoint }
+}
+
+// WithStaticCredentials uses the given access key instead of the default credential chain
+func WithStaticCredentials(accessKeyID, secretAccessKey string) Option {
+	return func(o *options) { o.credentials = credentials.NewStaticCredentials(accessKeyID, secretAccessKey, "") }
+}
+
+// WithWaiterDelay changes the delay between two checks while waiting for a change to be applied
+func WithWaiterDelay(delay time.Duration) Option {
+	return func(o *options) { o.waiterDelay = delay }
+}
+
+type client struct {
+	eks            eksiface.EKSAPI
+	cloudformation cloudformationiface.CloudFormationAPI
+	sts            stsiface.STSAPI
+	waiterOptions  []request.WaiterOption
+	waiterDelay    time.Duration
+}
+
+// New returns a Client for region using the default AWS credential chain unless overridden
+func New(region string, opts ...Option) (Client, error) {
+	o := options{waiterDelay: 30 * time.Second}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	config := aws.NewConfig().WithRegion(region)
+	if o.endpoint != "" {
+		config = config.WithEndpoint(o.endpoint)
+	}
+	if o.credentials != nil {
+		config = config.WithCredentials(o.credentials)
+	}
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            *config,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "creating AWS session")
+	}
+
+	return &client{
+		eks:            eks.New(sess),
+		cloudformation: cloudformation.New(sess),
+		sts:            sts.New(sess),
+		waiterOptions: []request.WaiterOption{
+			request.WithWaiterDelay(request.ConstantWaiterDelay(o.waiterDelay)),
+			// Clusters take up to 20 minutes to be created or upgraded
+			request.WithWaiterMaxAttempts(int((40 * time.Minute) / max(o.waiterDelay, time.Second))),
+		},
+		waiterDelay: o.waiterDelay,
+	}, nil
+}
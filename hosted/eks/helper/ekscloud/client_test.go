@@ -0,0 +1,275 @@
+/*
+Copyright © 2022 - 2025 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ekscloud_test
+
+import (
+	"encoding/base64"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/yaml"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/eks/helper/ekscloud"
+)
+
+var _ = Describe("Client", func() {
+	const clusterName = "hp-ci-eks"
+	var (
+		aws    *awsStandIn
+		client ekscloud.Client
+		tags   = map[string]string{"owner": "hosted-providers-qa"}
+	)
+
+	BeforeEach(func() {
+		aws = newAWSStandIn()
+		server := httptest.NewServer(aws)
+		DeferCleanup(server.Close)
+
+		var err error
+		client, err = ekscloud.New("us-east-2",
+			ekscloud.WithEndpoint(server.URL),
+			ekscloud.WithStaticCredentials("AKIDEXAMPLE", "secret"),
+			ekscloud.WithWaiterDelay(10*time.Millisecond),
+		)
+		Expect(err).To(BeNil())
+	})
+
+	It("creates a cluster with its default nodegroup and deletes everything", func() {
+		cluster, err := client.CreateCluster(ekscloud.ClusterSpec{Name: clusterName, Version: "1.30", Tags: tags})
+		Expect(err).To(BeNil())
+		Expect(cluster.Name).To(Equal(clusterName))
+		Expect(cluster.Version).To(Equal("1.30"))
+		Expect(cluster.Status).To(Equal("ACTIVE"))
+		Expect(cluster.Tags).To(Equal(tags))
+		Expect(cluster.SubnetIDs).To(Equal([]string{"subnet-1", "subnet-2"}))
+		Expect(cluster.LoggingTypes).To(BeEmpty())
+
+		Expect(aws.stacks).To(HaveKey(ekscloud.InfraStackName(clusterName)))
+		Expect(aws.stacks[ekscloud.InfraStackName(clusterName)].Tags).To(Equal(tags))
+		Expect(aws.clusters[clusterName]["roleArn"]).To(Equal("arn:aws:iam::123456789012:role/cluster-role"))
+		Expect(aws.clusters[clusterName]["accessConfig"]).To(HaveKeyWithValue("authenticationMode", "API_AND_CONFIG_MAP"))
+
+		nodegroups, err := client.ListNodegroups(clusterName)
+		Expect(err).To(BeNil())
+		Expect(nodegroups).To(HaveLen(1))
+		Expect(nodegroups[0].Name).To(Equal(ekscloud.DefaultNodegroupName))
+		Expect(nodegroups[0].Version).To(Equal("1.30"))
+		Expect(nodegroups[0].Scaling).To(Equal(ekscloud.Scaling{DesiredSize: 1, MinSize: 1, MaxSize: 1}))
+		Expect(nodegroups[0].InstanceTypes).To(Equal([]string{ekscloud.DefaultInstanceType}))
+		Expect(nodegroups[0].Tags).To(Equal(tags))
+		Expect(aws.nodegroups[clusterName][ekscloud.DefaultNodegroupName]["nodeRole"]).To(Equal("arn:aws:iam::123456789012:role/node-role"))
+
+		Expect(client.DeleteCluster(clusterName)).To(Succeed())
+		Expect(aws.clusters).To(BeEmpty())
+		Expect(aws.stacks).To(BeEmpty())
+
+		By("ignoring a cluster which is already deleted")
+		Expect(client.DeleteCluster(clusterName)).To(Succeed())
+		_, err = client.DescribeCluster(clusterName)
+		Expect(err).To(MatchError(ContainSubstring("ResourceNotFoundException")))
+	})
+
+	It("deletes the infra stack when the cluster cannot be created", func() {
+		aws.failCreation = true
+		_, err := client.CreateCluster(ekscloud.ClusterSpec{Name: clusterName, Tags: tags})
+		Expect(err).To(MatchError(ContainSubstring("cluster creation failed on purpose")))
+		Expect(aws.clusters).To(BeEmpty())
+		Expect(aws.stacks).To(BeEmpty())
+	})
+
+	It("deletes the cluster and its stacks when the nodegroup cannot be created", func() {
+		aws.failNodegroups = true
+		_, err := client.CreateCluster(ekscloud.ClusterSpec{Name: clusterName, Tags: tags})
+		Expect(err).To(MatchError(ContainSubstring("nodegroup creation failed on purpose")))
+		Expect(aws.clusters).To(BeEmpty())
+		Expect(aws.stacks).To(BeEmpty())
+	})
+
+	It("creates a cluster without nodegroup", func() {
+		_, err := client.CreateCluster(ekscloud.ClusterSpec{Name: clusterName, WithoutNodegroup: true})
+		Expect(err).To(BeNil())
+
+		nodegroups, err := client.ListNodegroups(clusterName)
+		Expect(err).To(BeNil())
+		Expect(nodegroups).To(BeEmpty())
+		Expect(aws.stacks).To(HaveLen(1))
+	})
+
+	It("creates a cluster with self-managed nodes", func() {
+		cluster, err := client.CreateCluster(ekscloud.ClusterSpec{Name: clusterName, Version: "1.31", Nodes: 2, SelfManagedNodes: true})
+		Expect(err).To(BeNil())
+
+		nodegroups, err := client.ListNodegroups(clusterName)
+		Expect(err).To(BeNil())
+		Expect(nodegroups).To(BeEmpty())
+		Expect(aws.accessEntries).To(HaveKeyWithValue("arn:aws:iam::123456789012:role/node-role", "EC2_LINUX"))
+
+		Expect(aws.stacks).To(HaveKey(ekscloud.NodesStackName(clusterName)))
+		parameters := aws.stacks[ekscloud.NodesStackName(clusterName)].Parameters
+		Expect(parameters).To(HaveKeyWithValue("ClusterEndpoint", cluster.Endpoint))
+		Expect(parameters).To(HaveKeyWithValue("ClusterSecurityGroupId", "sg-cluster"))
+		Expect(parameters).To(HaveKeyWithValue("ServiceCIDR", "10.100.0.0/16"))
+		Expect(parameters).To(HaveKeyWithValue("NodeImageId", "/aws/service/eks/optimized-ami/1.31/amazon-linux-2023/x86_64/standard/recommended/image_id"))
+		Expect(parameters).To(HaveKeyWithValue("NodeCount", "2"))
+
+		Expect(client.DeleteCluster(clusterName)).To(Succeed())
+		Expect(aws.stacks).To(BeEmpty())
+	})
+
+	Context("with an existing cluster", func() {
+		var cluster *ekscloud.Cluster
+
+		BeforeEach(func() {
+			var err error
+			cluster, err = client.CreateCluster(ekscloud.ClusterSpec{Name: clusterName, Version: "1.30", WithoutNodegroup: true})
+			Expect(err).To(BeNil())
+		})
+
+		It("upgrades the control plane", func() {
+			Expect(client.UpgradeCluster(clusterName, "1.31")).To(Succeed())
+			cluster, err := client.DescribeCluster(clusterName)
+			Expect(err).To(BeNil())
+			Expect(cluster.Version).To(Equal("1.31"))
+		})
+
+		It("updates the logging types", func() {
+			Expect(client.UpdateLogging(clusterName, []string{"all"}, nil)).To(Succeed())
+			cluster, err := client.DescribeCluster(clusterName)
+			Expect(err).To(BeNil())
+			Expect(cluster.LoggingTypes).To(ConsistOf(ekscloud.LoggingTypes))
+
+			By("ignoring an update without change")
+			Expect(client.UpdateLogging(clusterName, []string{"api"}, nil)).To(Succeed())
+
+			Expect(client.UpdateLogging(clusterName, nil, []string{"api", "audit"})).To(Succeed())
+			cluster, err = client.DescribeCluster(clusterName)
+			Expect(err).To(BeNil())
+			Expect(cluster.LoggingTypes).To(ConsistOf("authenticator", "controllerManager", "scheduler"))
+
+			Expect(client.UpdateLogging(clusterName, nil, nil)).ToNot(Succeed())
+		})
+
+		It("updates the VPC access", func() {
+			access := ekscloud.VPCAccess{PublicAccess: true, PrivateAccess: true, PublicAccessSources: []string{"10.0.0.0/8"}}
+			Expect(client.UpdateVPCAccess(clusterName, access)).To(Succeed())
+			cluster, err := client.DescribeCluster(clusterName)
+			Expect(err).To(BeNil())
+			Expect(cluster.VPCAccess).To(Equal(access))
+		})
+
+		It("tags and untags the cluster", func() {
+			Expect(client.TagResource(cluster.Arn, map[string]string{"foo": "bar", "empty": ""})).To(Succeed())
+			Expect(client.UntagResource(cluster.Arn, []string{"empty"})).To(Succeed())
+			cluster, err := client.DescribeCluster(clusterName)
+			Expect(err).To(BeNil())
+			Expect(cluster.Tags).To(Equal(map[string]string{"foo": "bar"}))
+
+			Expect(client.TagResource("arn:aws:eks:us-east-2:123456789012:cluster/unknown", map[string]string{"foo": "bar"})).ToNot(Succeed())
+		})
+
+		It("manages nodegroups", func() {
+			nodegroup, err := client.CreateNodegroup(clusterName, ekscloud.NodegroupSpec{Name: "ng1", Labels: map[string]string{"foo": "bar"}})
+			Expect(err).To(BeNil())
+			Expect(nodegroup.Version).To(Equal("1.30"))
+			Expect(nodegroup.Scaling).To(Equal(ekscloud.Scaling{DesiredSize: 2, MinSize: 2, MaxSize: 2}))
+			Expect(aws.nodegroups[clusterName]["ng1"]["nodeRole"]).To(Equal("arn:aws:iam::123456789012:role/node-role"))
+			Expect(aws.nodegroups[clusterName]["ng1"]["subnets"]).To(ConsistOf("subnet-1", "subnet-2"))
+
+			Expect(client.ScaleNodegroup(clusterName, "ng1", ekscloud.Scaling{DesiredSize: 3, MinSize: 1, MaxSize: 5})).To(Succeed())
+			Expect(client.UpdateNodegroupLabels(clusterName, "ng1", map[string]string{"baz": "qux"}, []string{"foo"})).To(Succeed())
+			Expect(client.UpdateNodegroupLabels(clusterName, "ng1", nil, nil)).ToNot(Succeed())
+			Expect(client.UpgradeNodegroup(clusterName, "ng1", "1.31")).To(Succeed())
+
+			nodegroup, err = client.DescribeNodegroup(clusterName, "ng1")
+			Expect(err).To(BeNil())
+			Expect(nodegroup.Scaling).To(Equal(ekscloud.Scaling{DesiredSize: 3, MinSize: 1, MaxSize: 5}))
+			Expect(nodegroup.Labels).To(Equal(map[string]string{"baz": "qux"}))
+			Expect(nodegroup.Version).To(Equal("1.31"))
+
+			Expect(client.DeleteNodegroup(clusterName, "ng1")).To(Succeed())
+			Expect(client.DeleteNodegroup(clusterName, "ng1")).To(Succeed())
+			nodegroups, err := client.ListNodegroups(clusterName)
+			Expect(err).To(BeNil())
+			Expect(nodegroups).To(BeEmpty())
+		})
+
+		It("reports a failed update", func() {
+			aws.failUpdates = true
+			Expect(client.UpgradeCluster(clusterName, "1.31")).To(MatchError(ContainSubstring("update failed on purpose")))
+		})
+
+		It("enables the network policy agent of the VPC CNI", func() {
+			Expect(client.EnableNetworkPolicy(clusterName)).To(Succeed())
+			addon := aws.addons[clusterName+"/"+ekscloud.VPCCNIAddon]
+			Expect(addon["status"]).To(Equal("ACTIVE"))
+			Expect(addon["configurationValues"]).To(Equal(`{"enableNetworkPolicy":"true"}`))
+
+			By("updating the configuration of the addon once it is installed")
+			addon["configurationValues"] = `{}`
+			Expect(client.EnableNetworkPolicy(clusterName)).To(Succeed())
+			Expect(addon["configurationValues"]).To(Equal(`{"enableNetworkPolicy":"true"}`))
+			Expect(addon["status"]).To(Equal("ACTIVE"))
+		})
+
+		It("returns a kubeconfig authenticated with a token bound to the cluster", func() {
+			content, err := client.Kubeconfig(clusterName)
+			Expect(err).To(BeNil())
+			var kubeconfig struct {
+				Clusters []struct {
+					Cluster map[string]string `json:"cluster"`
+				} `json:"clusters"`
+				Users []struct {
+					User map[string]string `json:"user"`
+				} `json:"users"`
+				CurrentContext string `json:"current-context"`
+			}
+			Expect(yaml.Unmarshal(content, &kubeconfig)).To(Succeed())
+			Expect(kubeconfig.CurrentContext).To(Equal(clusterName))
+			Expect(kubeconfig.Clusters[0].Cluster).To(HaveKeyWithValue("server", cluster.Endpoint))
+			Expect(kubeconfig.Clusters[0].Cluster).To(HaveKeyWithValue("certificate-authority-data", "Y2VydGlmaWNhdGU="))
+
+			token, found := strings.CutPrefix(kubeconfig.Users[0].User["token"], "k8s-aws-v1.")
+			Expect(found).To(BeTrue())
+			presigned, err := base64.RawURLEncoding.DecodeString(token)
+			Expect(err).To(BeNil())
+			query, err := url.ParseQuery(strings.SplitN(string(presigned), "?", 2)[1])
+			Expect(err).To(BeNil())
+			Expect(query.Get("Action")).To(Equal("GetCallerIdentity"))
+			Expect(query.Get("X-Amz-SignedHeaders")).To(ContainSubstring("x-k8s-aws-id"))
+		})
+
+		It("reports a failed addon", func() {
+			aws.failUpdates = true
+			Expect(client.EnableNetworkPolicy(clusterName)).To(MatchError(And(ContainSubstring("CREATE_FAILED"), ContainSubstring("addon failed on purpose"))))
+		})
+	})
+
+	It("adds a nodegroup to a cluster created outside of the client", func() {
+		aws.addCluster("imported", "1.29", "existing")
+
+		nodegroup, err := client.CreateNodegroup("imported", ekscloud.NodegroupSpec{Name: "ng2", Scaling: ekscloud.Scaling{DesiredSize: 1, MinSize: 1, MaxSize: 2}})
+		Expect(err).To(BeNil())
+		Expect(nodegroup.Version).To(Equal("1.29"))
+		Expect(aws.nodegroups["imported"]["ng2"]["nodeRole"]).To(Equal("arn:aws:iam::123456789012:role/existing-node-role"))
+		Expect(aws.nodegroups["imported"]["ng2"]["subnets"]).To(ConsistOf("subnet-existing"))
+
+		Expect(client.DeleteCluster("imported")).To(Succeed())
+		Expect(aws.clusters).To(BeEmpty())
+	})
+})
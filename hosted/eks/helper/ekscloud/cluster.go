@@ -0,0 +1,422 @@
+package ekscloud
+
+import (
+	_ "embed"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/pkg/errors"
+)
+
+// updateTimeout is the maximum time to wait for a cluster or nodegroup update
+const updateTimeout = 40 * time.Minute
+
+var (
+	//go:embed templates/cluster-infra.yaml
+	clusterInfraTemplate string
+	//go:embed templates/self-managed-nodes.yaml
+	selfManagedNodesTemplate string
+)
+
+// InfraStackName is the CloudFormation stack holding the VPC and IAM roles of a cluster
+func InfraStackName(clusterName string) string {
+	return fmt.Sprintf("hp-eks-%s-infra", clusterName)
+}
+
+// NodesStackName is the CloudFormation stack holding the self-managed nodes of a cluster
+func NodesStackName(clusterName string) string {
+	return fmt.Sprintf("hp-eks-%s-nodes", clusterName)
+}
+
+func (c *client) CreateCluster(spec ClusterSpec) (*Cluster, error) {
+	if spec.Nodes == 0 {
+		spec.Nodes = 1
+	}
+	if spec.InstanceType == "" {
+		spec.InstanceType = DefaultInstanceType
+	}
+
+	infra, err := c.createStack(InfraStackName(spec.Name), clusterInfraTemplate, nil, spec.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = c.eks.CreateCluster(&eks.CreateClusterInput{
+		Name:    aws.String(spec.Name),
+		Version: optionalString(spec.Version),
+		RoleArn: aws.String(infra["ClusterRoleArn"]),
+		ResourcesVpcConfig: &eks.VpcConfigRequest{
+			SubnetIds:             aws.StringSlice(strings.Split(infra["SubnetIds"], ",")),
+			SecurityGroupIds:      aws.StringSlice(strings.Split(infra["SecurityGroupIds"], ",")),
+			EndpointPublicAccess:  aws.Bool(true),
+			EndpointPrivateAccess: aws.Bool(false),
+		},
+		AccessConfig: &eks.CreateAccessConfigRequest{
+			AuthenticationMode:                      aws.String(eks.AuthenticationModeApiAndConfigMap),
+			BootstrapClusterCreatorAdminPermissions: aws.Bool(true),
+		},
+		Tags: optionalStringMap(spec.Tags),
+	})
+	if err != nil {
+		// the infra stack is only used by the cluster, it is not left behind
+		return nil, rollback(errors.Wrapf(err, "creating cluster %s", spec.Name), c.deleteStack(InfraStackName(spec.Name)))
+	}
+
+	cluster, err := c.createClusterNodes(spec, infra)
+	if err != nil {
+		// DeleteCluster deletes the nodegroups, the nodes stack, the cluster and the infra stack, whichever have been created
+		return nil, rollback(err, c.DeleteCluster(spec.Name))
+	}
+	return cluster, nil
+}
+
+// createClusterNodes waits for a created cluster to be active, then adds its nodegroup or self-managed nodes
+func (c *client) createClusterNodes(spec ClusterSpec, infra map[string]string) (*Cluster, error) {
+	err := c.eks.WaitUntilClusterActiveWithContext(aws.BackgroundContext(), &eks.DescribeClusterInput{Name: aws.String(spec.Name)}, c.waiterOptions...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "waiting for cluster %s to be active", spec.Name)
+	}
+
+	cluster, err := c.DescribeCluster(spec.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case spec.WithoutNodegroup:
+	case spec.SelfManagedNodes:
+		err = c.createSelfManagedNodes(spec, cluster, infra)
+	default:
+		_, err = c.CreateNodegroup(spec.Name, NodegroupSpec{
+			Name:          DefaultNodegroupName,
+			Version:       cluster.Version,
+			Scaling:       Scaling{DesiredSize: spec.Nodes, MinSize: spec.Nodes, MaxSize: spec.Nodes},
+			InstanceTypes: []string{spec.InstanceType},
+			Tags:          spec.Tags,
+			Subnets:       strings.Split(infra["SubnetIds"], ","),
+			NodeRole:      infra["NodeRoleArn"],
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return cluster, nil
+}
+
+// rollback returns the error of a failed creation, along with the error of the deletion of what had been created
+func rollback(err, deleteErr error) error {
+	if deleteErr != nil {
+		return fmt.Errorf("%w; %v", err, deleteErr)
+	}
+	return err
+}
+
+// createSelfManagedNodes creates an autoscaling group of EKS optimized AL2023 nodes joining the cluster through an access entry
+func (c *client) createSelfManagedNodes(spec ClusterSpec, cluster *Cluster, infra map[string]string) error {
+	_, err := c.eks.CreateAccessEntry(&eks.CreateAccessEntryInput{
+		ClusterName:  aws.String(spec.Name),
+		PrincipalArn: aws.String(infra["NodeRoleArn"]),
+		Type:         aws.String("EC2_LINUX"),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "creating access entry of the nodes of cluster %s", spec.Name)
+	}
+
+	_, err = c.createStack(NodesStackName(spec.Name), selfManagedNodesTemplate, map[string]string{
+		"ClusterName":                 spec.Name,
+		"ClusterEndpoint":             cluster.Endpoint,
+		"ClusterCertificateAuthority": cluster.CertificateAuthority,
+		"ServiceCIDR":                 cluster.ServiceCIDR,
+		"ClusterSecurityGroupId":      cluster.SecurityGroupID,
+		"NodeInstanceProfileArn":      infra["NodeInstanceProfileArn"],
+		"SubnetIds":                   infra["SubnetIds"],
+		"NodeImageId":                 fmt.Sprintf("/aws/service/eks/optimized-ami/%s/amazon-linux-2023/x86_64/standard/recommended/image_id", cluster.Version),
+		"InstanceType":                spec.InstanceType,
+		"NodeCount":                   strconv.FormatInt(spec.Nodes, 10),
+	}, spec.Tags)
+	return err
+}
+
+func (c *client) DescribeCluster(name string) (*Cluster, error) {
+	out, err := c.eks.DescribeCluster(&eks.DescribeClusterInput{Name: aws.String(name)})
+	if err != nil {
+		return nil, errors.Wrapf(err, "describing cluster %s", name)
+	}
+	return toCluster(out.Cluster), nil
+}
+
+func (c *client) DeleteCluster(name string) error {
+	nodegroups, err := c.ListNodegroups(name)
+	if err != nil && !isNotFound(err) {
+		return err
+	}
+	for _, nodegroup := range nodegroups {
+		if err = c.DeleteNodegroup(name, nodegroup.Name); err != nil {
+			return err
+		}
+	}
+	if err = c.deleteStack(NodesStackName(name)); err != nil {
+		return err
+	}
+
+	_, err = c.eks.DeleteCluster(&eks.DeleteClusterInput{Name: aws.String(name)})
+	switch {
+	case isNotFound(err):
+	case err != nil:
+		return errors.Wrapf(err, "deleting cluster %s", name)
+	default:
+		if err = c.eks.WaitUntilClusterDeletedWithContext(aws.BackgroundContext(), &eks.DescribeClusterInput{Name: aws.String(name)}, c.waiterOptions...); err != nil {
+			return errors.Wrapf(err, "waiting for cluster %s to be deleted", name)
+		}
+	}
+
+	return c.deleteStack(InfraStackName(name))
+}
+
+func (c *client) UpgradeCluster(name, version string) error {
+	out, err := c.eks.UpdateClusterVersion(&eks.UpdateClusterVersionInput{
+		Name:    aws.String(name),
+		Version: aws.String(version),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "upgrading cluster %s to %s", name, version)
+	}
+	return c.waitForUpdate(name, "", out.Update)
+}
+
+func (c *client) UpdateLogging(name string, enableTypes, disableTypes []string) error {
+	logging := &eks.Logging{}
+	for _, setup := range []struct {
+		enabled bool
+		types   []string
+	}{{true, enableTypes}, {false, disableTypes}} {
+		if len(setup.types) == 0 {
+			continue
+		}
+		types := setup.types
+		if slices.Contains(types, "all") {
+			types = LoggingTypes
+		}
+		logging.ClusterLogging = append(logging.ClusterLogging, &eks.LogSetup{
+			Enabled: aws.Bool(setup.enabled),
+			Types:   aws.StringSlice(types),
+		})
+	}
+	if len(logging.ClusterLogging) == 0 {
+		return errors.New("no logging type to enable or disable")
+	}
+
+	out, err := c.eks.UpdateClusterConfig(&eks.UpdateClusterConfigInput{
+		Name:    aws.String(name),
+		Logging: logging,
+	})
+	if isNoChange(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "updating logging of cluster %s", name)
+	}
+	return c.waitForUpdate(name, "", out.Update)
+}
+
+func (c *client) UpdateVPCAccess(name string, access VPCAccess) error {
+	vpcConfig := &eks.VpcConfigRequest{
+		EndpointPublicAccess:  aws.Bool(access.PublicAccess),
+		EndpointPrivateAccess: aws.Bool(access.PrivateAccess),
+	}
+	if len(access.PublicAccessSources) != 0 {
+		vpcConfig.PublicAccessCidrs = aws.StringSlice(access.PublicAccessSources)
+	}
+
+	out, err := c.eks.UpdateClusterConfig(&eks.UpdateClusterConfigInput{
+		Name:               aws.String(name),
+		ResourcesVpcConfig: vpcConfig,
+	})
+	if isNoChange(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "updating VPC access of cluster %s", name)
+	}
+	return c.waitForUpdate(name, "", out.Update)
+}
+
+func (c *client) TagResource(arn string, tags map[string]string) error {
+	_, err := c.eks.TagResource(&eks.TagResourceInput{
+		ResourceArn: aws.String(arn),
+		Tags:        aws.StringMap(tags),
+	})
+	return errors.Wrapf(err, "tagging %s", arn)
+}
+
+func (c *client) UntagResource(arn string, keys []string) error {
+	_, err := c.eks.UntagResource(&eks.UntagResourceInput{
+		ResourceArn: aws.String(arn),
+		TagKeys:     aws.StringSlice(keys),
+	})
+	return errors.Wrapf(err, "untagging %s", arn)
+}
+
+// waitForUpdate polls an update of a cluster, or of one of its nodegroups, until it is successful
+func (c *client) waitForUpdate(clusterName, nodegroupName string, update *eks.Update) error {
+	input := &eks.DescribeUpdateInput{
+		Name:          aws.String(clusterName),
+		NodegroupName: optionalString(nodegroupName),
+		UpdateId:      update.Id,
+	}
+	deadline := time.Now().Add(updateTimeout)
+	for {
+		out, err := c.eks.DescribeUpdate(input)
+		if err != nil {
+			return errors.Wrapf(err, "describing update %s of cluster %s", aws.StringValue(update.Id), clusterName)
+		}
+		switch status := aws.StringValue(out.Update.Status); status {
+		case eks.UpdateStatusSuccessful:
+			return nil
+		case eks.UpdateStatusFailed, eks.UpdateStatusCancelled:
+			var details []string
+			for _, detail := range out.Update.Errors {
+				details = append(details, aws.StringValue(detail.ErrorMessage))
+			}
+			return fmt.Errorf("update %s of cluster %s is %s: %s", aws.StringValue(update.Id), clusterName, status, strings.Join(details, "; "))
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for update %s of cluster %s", aws.StringValue(update.Id), clusterName)
+		}
+		time.Sleep(c.waiterDelay)
+	}
+}
+
+// createStack creates a CloudFormation stack and returns its outputs once it is created
+func (c *client) createStack(name, template string, parameters, tags map[string]string) (map[string]string, error) {
+	input := &cloudformation.CreateStackInput{
+		StackName:    aws.String(name),
+		TemplateBody: aws.String(template),
+		Capabilities: aws.StringSlice([]string{cloudformation.CapabilityCapabilityIam}),
+		OnFailure:    aws.String(cloudformation.OnFailureDelete),
+	}
+	for _, key := range slices.Sorted(maps.Keys(parameters)) {
+		input.Parameters = append(input.Parameters, &cloudformation.Parameter{
+			ParameterKey:   aws.String(key),
+			ParameterValue: aws.String(parameters[key]),
+		})
+	}
+	for _, key := range slices.Sorted(maps.Keys(tags)) {
+		input.Tags = append(input.Tags, &cloudformation.Tag{
+			Key:   aws.String(key),
+			Value: aws.String(tags[key]),
+		})
+	}
+
+	if _, err := c.cloudformation.CreateStack(input); err != nil {
+		return nil, errors.Wrapf(err, "creating stack %s", name)
+	}
+	if err := c.cloudformation.WaitUntilStackCreateCompleteWithContext(aws.BackgroundContext(), &cloudformation.DescribeStacksInput{StackName: aws.String(name)}, c.waiterOptions...); err != nil {
+		return nil, errors.Wrapf(err, "waiting for stack %s to be created", name)
+	}
+	return c.stackOutputs(name)
+}
+
+func (c *client) stackOutputs(name string) (map[string]string, error) {
+	out, err := c.cloudformation.DescribeStacks(&cloudformation.DescribeStacksInput{StackName: aws.String(name)})
+	if err != nil {
+		return nil, errors.Wrapf(err, "describing stack %s", name)
+	}
+	outputs := map[string]string{}
+	for _, stack := range out.Stacks {
+		for _, output := range stack.Outputs {
+			outputs[aws.StringValue(output.OutputKey)] = aws.StringValue(output.OutputValue)
+		}
+	}
+	return outputs, nil
+}
+
+// deleteStack deletes a CloudFormation stack, it is a no-op if the stack does not exist
+func (c *client) deleteStack(name string) error {
+	input := &cloudformation.DescribeStacksInput{StackName: aws.String(name)}
+	if _, err := c.cloudformation.DescribeStacks(input); err != nil {
+		if isStackNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "describing stack %s", name)
+	}
+	if _, err := c.cloudformation.DeleteStack(&cloudformation.DeleteStackInput{StackName: aws.String(name)}); err != nil {
+		return errors.Wrapf(err, "deleting stack %s", name)
+	}
+	if err := c.cloudformation.WaitUntilStackDeleteCompleteWithContext(aws.BackgroundContext(), input, c.waiterOptions...); err != nil {
+		return errors.Wrapf(err, "waiting for stack %s to be deleted", name)
+	}
+	return nil
+}
+
+func toCluster(cluster *eks.Cluster) *Cluster {
+	c := &Cluster{
+		Name:     aws.StringValue(cluster.Name),
+		Arn:      aws.StringValue(cluster.Arn),
+		Version:  aws.StringValue(cluster.Version),
+		Status:   aws.StringValue(cluster.Status),
+		Endpoint: aws.StringValue(cluster.Endpoint),
+		Tags:     aws.StringValueMap(cluster.Tags),
+	}
+	if cluster.CertificateAuthority != nil {
+		c.CertificateAuthority = aws.StringValue(cluster.CertificateAuthority.Data)
+	}
+	if cluster.KubernetesNetworkConfig != nil {
+		c.ServiceCIDR = aws.StringValue(cluster.KubernetesNetworkConfig.ServiceIpv4Cidr)
+	}
+	if vpc := cluster.ResourcesVpcConfig; vpc != nil {
+		c.SecurityGroupID = aws.StringValue(vpc.ClusterSecurityGroupId)
+		c.SubnetIDs = aws.StringValueSlice(vpc.SubnetIds)
+		c.VPCAccess = VPCAccess{
+			PublicAccess:        aws.BoolValue(vpc.EndpointPublicAccess),
+			PrivateAccess:       aws.BoolValue(vpc.EndpointPrivateAccess),
+			PublicAccessSources: aws.StringValueSlice(vpc.PublicAccessCidrs),
+		}
+	}
+	if cluster.Logging != nil {
+		for _, setup := range cluster.Logging.ClusterLogging {
+			if aws.BoolValue(setup.Enabled) {
+				c.LoggingTypes = append(c.LoggingTypes, aws.StringValueSlice(setup.Types)...)
+			}
+		}
+	}
+	return c
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return aws.String(s)
+}
+
+func optionalStringMap(m map[string]string) map[string]*string {
+	if len(m) == 0 {
+		return nil
+	}
+	return aws.StringMap(m)
+}
+
+func isNotFound(err error) bool {
+	var awsErr awserr.Error
+	return errors.As(err, &awsErr) && awsErr.Code() == eks.ErrCodeResourceNotFoundException
+}
+
+// isNoChange reports whether EKS rejected an update because the cluster already has the requested configuration
+func isNoChange(err error) bool {
+	var awsErr awserr.Error
+	return errors.As(err, &awsErr) && awsErr.Code() == eks.ErrCodeInvalidParameterException && strings.Contains(awsErr.Message(), "No changes needed")
+}
+
+func isStackNotFound(err error) bool {
+	var awsErr awserr.Error
+	return errors.As(err, &awsErr) && awsErr.Code() == "ValidationError" && strings.Contains(awsErr.Message(), "does not exist")
+}
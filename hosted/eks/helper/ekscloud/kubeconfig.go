@@ -0,0 +1,59 @@
+package ekscloud
+
+import (
+	"encoding/base64"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// tokenPrefix is the prefix of the bearer tokens the AWS IAM authenticator of EKS accepts
+const tokenPrefix = "k8s-aws-v1."
+
+// Token returns a bearer token for the API of a cluster, as `aws eks get-token` does: the presigned STS GetCallerIdentity request
+// of the credentials of the client, bound to the cluster. EKS accepts it for 15 minutes.
+func (c *client) Token(clusterName string) (string, error) {
+	req, _ := c.sts.GetCallerIdentityRequest(&sts.GetCallerIdentityInput{})
+	req.HTTPRequest.Header.Add("x-k8s-aws-id", clusterName)
+	presigned, err := req.Presign(time.Minute)
+	if err != nil {
+		return "", errors.Wrapf(err, "presigning the token of cluster %s", clusterName)
+	}
+	return tokenPrefix + base64.RawURLEncoding.EncodeToString([]byte(presigned)), nil
+}
+
+// Kubeconfig returns a kubeconfig for the API endpoint of a cluster, authenticated with a Token of the credentials of the client;
+// they need an access entry of the cluster, e.g. the cluster creator's one
+func (c *client) Kubeconfig(clusterName string) ([]byte, error) {
+	cluster, err := c.DescribeCluster(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	token, err := c.Token(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	kubeconfig := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Config",
+		"clusters": []interface{}{map[string]interface{}{
+			"name": clusterName,
+			"cluster": map[string]interface{}{
+				"server":                     cluster.Endpoint,
+				"certificate-authority-data": cluster.CertificateAuthority,
+			},
+		}},
+		"users": []interface{}{map[string]interface{}{
+			"name": clusterName,
+			"user": map[string]interface{}{"token": token},
+		}},
+		"contexts": []interface{}{map[string]interface{}{
+			"name":    clusterName,
+			"context": map[string]interface{}{"cluster": clusterName, "user": clusterName},
+		}},
+		"current-context": clusterName,
+	}
+	return yaml.Marshal(kubeconfig)
+}
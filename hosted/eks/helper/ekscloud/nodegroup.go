@@ -0,0 +1,215 @@
+package ekscloud
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/pkg/errors"
+)
+
+func (c *client) CreateNodegroup(clusterName string, spec NodegroupSpec) (*Nodegroup, error) {
+	if spec.Scaling == (Scaling{}) {
+		spec.Scaling = Scaling{DesiredSize: 2, MinSize: 2, MaxSize: 2}
+	}
+	if len(spec.InstanceTypes) == 0 {
+		spec.InstanceTypes = []string{DefaultInstanceType}
+	}
+	if len(spec.Subnets) == 0 || spec.NodeRole == "" {
+		subnets, nodeRole, err := c.nodegroupDefaults(clusterName)
+		if err != nil {
+			return nil, err
+		}
+		if len(spec.Subnets) == 0 {
+			spec.Subnets = subnets
+		}
+		if spec.NodeRole == "" {
+			spec.NodeRole = nodeRole
+		}
+	}
+
+	_, err := c.eks.CreateNodegroup(&eks.CreateNodegroupInput{
+		ClusterName:   aws.String(clusterName),
+		NodegroupName: aws.String(spec.Name),
+		Version:       optionalString(spec.Version),
+		NodeRole:      aws.String(spec.NodeRole),
+		Subnets:       aws.StringSlice(spec.Subnets),
+		InstanceTypes: aws.StringSlice(spec.InstanceTypes),
+		ScalingConfig: toScalingConfig(spec.Scaling),
+		Labels:        optionalStringMap(spec.Labels),
+		Tags:          optionalStringMap(spec.Tags),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "creating nodegroup %s of cluster %s", spec.Name, clusterName)
+	}
+	input := &eks.DescribeNodegroupInput{ClusterName: aws.String(clusterName), NodegroupName: aws.String(spec.Name)}
+	if err = c.eks.WaitUntilNodegroupActiveWithContext(aws.BackgroundContext(), input, c.waiterOptions...); err != nil {
+		return nil, errors.Wrapf(err, "waiting for nodegroup %s of cluster %s to be active", spec.Name, clusterName)
+	}
+	return c.DescribeNodegroup(clusterName, spec.Name)
+}
+
+// nodegroupDefaults returns the subnets and node role of the cluster infra stack,
+// or the ones of an existing nodegroup for clusters which were not created by this package
+func (c *client) nodegroupDefaults(clusterName string) ([]string, string, error) {
+	infra, err := c.stackOutputs(InfraStackName(clusterName))
+	if err == nil && infra["NodeRoleArn"] != "" {
+		return strings.Split(infra["SubnetIds"], ","), infra["NodeRoleArn"], nil
+	}
+	if err != nil && !isStackNotFound(err) {
+		return nil, "", err
+	}
+
+	cluster, err := c.DescribeCluster(clusterName)
+	if err != nil {
+		return nil, "", err
+	}
+	names, err := c.listNodegroupNames(clusterName)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(names) == 0 {
+		return nil, "", errors.Errorf("no node role found for cluster %s, it must be given", clusterName)
+	}
+	out, err := c.eks.DescribeNodegroup(&eks.DescribeNodegroupInput{ClusterName: aws.String(clusterName), NodegroupName: aws.String(names[0])})
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "describing nodegroup %s of cluster %s", names[0], clusterName)
+	}
+	return cluster.SubnetIDs, aws.StringValue(out.Nodegroup.NodeRole), nil
+}
+
+func (c *client) DescribeNodegroup(clusterName, name string) (*Nodegroup, error) {
+	out, err := c.eks.DescribeNodegroup(&eks.DescribeNodegroupInput{
+		ClusterName:   aws.String(clusterName),
+		NodegroupName: aws.String(name),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "describing nodegroup %s of cluster %s", name, clusterName)
+	}
+	return toNodegroup(out.Nodegroup), nil
+}
+
+func (c *client) ListNodegroups(clusterName string) ([]Nodegroup, error) {
+	names, err := c.listNodegroupNames(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	var nodegroups []Nodegroup
+	for _, name := range names {
+		nodegroup, err := c.DescribeNodegroup(clusterName, name)
+		if err != nil {
+			return nil, err
+		}
+		nodegroups = append(nodegroups, *nodegroup)
+	}
+	return nodegroups, nil
+}
+
+func (c *client) listNodegroupNames(clusterName string) ([]string, error) {
+	var names []string
+	err := c.eks.ListNodegroupsPages(&eks.ListNodegroupsInput{ClusterName: aws.String(clusterName)}, func(page *eks.ListNodegroupsOutput, _ bool) bool {
+		names = append(names, aws.StringValueSlice(page.Nodegroups)...)
+		return true
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "listing nodegroups of cluster %s", clusterName)
+	}
+	return names, nil
+}
+
+func (c *client) DeleteNodegroup(clusterName, name string) error {
+	input := &eks.DeleteNodegroupInput{ClusterName: aws.String(clusterName), NodegroupName: aws.String(name)}
+	if _, err := c.eks.DeleteNodegroup(input); err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "deleting nodegroup %s of cluster %s", name, clusterName)
+	}
+	describeInput := &eks.DescribeNodegroupInput{ClusterName: input.ClusterName, NodegroupName: input.NodegroupName}
+	if err := c.eks.WaitUntilNodegroupDeletedWithContext(aws.BackgroundContext(), describeInput, c.waiterOptions...); err != nil {
+		return errors.Wrapf(err, "waiting for nodegroup %s of cluster %s to be deleted", name, clusterName)
+	}
+	return nil
+}
+
+func (c *client) ScaleNodegroup(clusterName, name string, scaling Scaling) error {
+	out, err := c.eks.UpdateNodegroupConfig(&eks.UpdateNodegroupConfigInput{
+		ClusterName:   aws.String(clusterName),
+		NodegroupName: aws.String(name),
+		ScalingConfig: toScalingConfig(scaling),
+	})
+	if isNoChange(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "scaling nodegroup %s of cluster %s", name, clusterName)
+	}
+	return c.waitForUpdate(clusterName, name, out.Update)
+}
+
+func (c *client) UpdateNodegroupLabels(clusterName, name string, addOrUpdate map[string]string, remove []string) error {
+	if len(addOrUpdate) == 0 && len(remove) == 0 {
+		return errors.New("no labels provided to remove or update/add")
+	}
+	labels := &eks.UpdateLabelsPayload{}
+	if len(addOrUpdate) != 0 {
+		labels.AddOrUpdateLabels = aws.StringMap(addOrUpdate)
+	}
+	if len(remove) != 0 {
+		labels.RemoveLabels = aws.StringSlice(remove)
+	}
+
+	out, err := c.eks.UpdateNodegroupConfig(&eks.UpdateNodegroupConfigInput{
+		ClusterName:   aws.String(clusterName),
+		NodegroupName: aws.String(name),
+		Labels:        labels,
+	})
+	if isNoChange(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "updating labels of nodegroup %s of cluster %s", name, clusterName)
+	}
+	return c.waitForUpdate(clusterName, name, out.Update)
+}
+
+func (c *client) UpgradeNodegroup(clusterName, name, version string) error {
+	out, err := c.eks.UpdateNodegroupVersion(&eks.UpdateNodegroupVersionInput{
+		ClusterName:   aws.String(clusterName),
+		NodegroupName: aws.String(name),
+		Version:       aws.String(version),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "upgrading nodegroup %s of cluster %s to %s", name, clusterName, version)
+	}
+	return c.waitForUpdate(clusterName, name, out.Update)
+}
+
+func toScalingConfig(scaling Scaling) *eks.NodegroupScalingConfig {
+	return &eks.NodegroupScalingConfig{
+		DesiredSize: aws.Int64(scaling.DesiredSize),
+		MinSize:     aws.Int64(scaling.MinSize),
+		MaxSize:     aws.Int64(scaling.MaxSize),
+	}
+}
+
+func toNodegroup(nodegroup *eks.Nodegroup) *Nodegroup {
+	n := &Nodegroup{
+		Name:          aws.StringValue(nodegroup.NodegroupName),
+		Arn:           aws.StringValue(nodegroup.NodegroupArn),
+		Version:       aws.StringValue(nodegroup.Version),
+		Status:        aws.StringValue(nodegroup.Status),
+		AmiType:       aws.StringValue(nodegroup.AmiType),
+		InstanceTypes: aws.StringValueSlice(nodegroup.InstanceTypes),
+		Labels:        aws.StringValueMap(nodegroup.Labels),
+		Tags:          aws.StringValueMap(nodegroup.Tags),
+	}
+	if scaling := nodegroup.ScalingConfig; scaling != nil {
+		n.Scaling = Scaling{
+			DesiredSize: aws.Int64Value(scaling.DesiredSize),
+			MinSize:     aws.Int64Value(scaling.MinSize),
+			MaxSize:     aws.Int64Value(scaling.MaxSize),
+		}
+	}
+	return n
+}
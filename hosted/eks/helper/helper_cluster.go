@@ -3,7 +3,6 @@ package helper
 import (
 	"fmt"
 	"maps"
-	"os"
 	"sort"
 	"strings"
 	"time"
@@ -13,9 +12,9 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/rancher-sandbox/ele-testhelpers/tools"
 
+	"github.com/rancher/hosted-providers-e2e/hosted/eks/helper/ekscloud"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
 
-	"github.com/epinio/epinio/acceptance/helpers/proc"
 	"github.com/pkg/errors"
 	"github.com/rancher/shepherd/clients/rancher"
 	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
@@ -23,7 +22,6 @@ import (
 	"github.com/rancher/shepherd/extensions/clusters/eks"
 	"github.com/rancher/shepherd/pkg/config"
 	namegen "github.com/rancher/shepherd/pkg/namegenerator"
-	k8slabels "k8s.io/apimachinery/pkg/labels"
 	"k8s.io/utils/pointer"
 )
 
@@ -104,11 +102,11 @@ func UpgradeClusterKubernetesVersion(cluster *management.Cluster, upgradeToVersi
 // UpgradeNodeKubernetesVersion upgrades the k8s version of nodegroup to the value defined by upgradeToVersion.
 // if wait is set to true, it will wait until the cluster finishes upgrading;
 // if checkClusterConfig is set to true, it will validate that nodegroup has been upgraded successfully
-// if useEksctl is set to true, nodegroup will be upgraded using eksctl utility instead of updating it from Rancher
-func UpgradeNodeKubernetesVersion(cluster *management.Cluster, upgradeToVersion string, client *rancher.Client, wait, checkClusterConfig, useEksctl bool) (*management.Cluster, error) {
+// if upgradeOnAWS is set to true, nodegroup will be upgraded through the AWS API instead of updating it from Rancher
+func UpgradeNodeKubernetesVersion(cluster *management.Cluster, upgradeToVersion string, client *rancher.Client, wait, checkClusterConfig, upgradeOnAWS bool) (*management.Cluster, error) {
 	var err error
 
-	if !useEksctl {
+	if !upgradeOnAWS {
 		upgradedCluster := cluster
 		configNodeGroups := *upgradedCluster.EKSConfig.NodeGroups
 		for i := range configNodeGroups {
@@ -123,7 +121,7 @@ func UpgradeNodeKubernetesVersion(cluster *management.Cluster, upgradeToVersion
 			Expect(err).To(BeNil())
 		}
 	} else {
-		// Upgrade Nodegroup using eksctl due to custom Launch template
+		// Upgrade Nodegroup through the AWS API due to custom Launch template
 		for _, ng := range *cluster.EKSConfig.NodeGroups {
 			err = UpgradeEKSNodegroupOnAWS(helpers.GetEKSRegion(), cluster.EKSConfig.DisplayName, *ng.NodegroupName, upgradeToVersion)
 			Expect(err).To(BeNil())
@@ -520,318 +518,231 @@ func ListEKSAllVersions(client *rancher.Client) (allVersions []string, err error
 	return helpers.FilterUIUnsupportedVersions(allVersions, client), nil
 }
 
-// <==============================EKS CLI==============================>
+// <==============================EKS on AWS==============================>
 
-// Create AWS EKS cluster using EKS CLI
-func CreateEKSClusterOnAWS(region string, clusterName string, k8sVersion string, nodes string, tags map[string]string, extraArgs ...string) error {
-	currentKubeconfig := os.Getenv("KUBECONFIG")
-	defer os.Setenv("KUBECONFIG", currentKubeconfig)
+// cloudClient returns an EKS client for region authenticated with the default AWS credential chain
+func cloudClient(region string) ekscloud.Client {
+	client, err := ekscloud.New(region)
+	Expect(err).To(BeNil())
+	return client
+}
 
-	helpers.SetTempKubeConfig(clusterName)
+// CreateEKSClusterOnAWS creates an EKS cluster on AWS along with its VPC and IAM roles;
+// updateFunc can be used to create the cluster without nodegroup or with self-managed nodes
+func CreateEKSClusterOnAWS(region string, clusterName string, k8sVersion string, nodes int64, tags map[string]string, updateFunc func(spec *ekscloud.ClusterSpec)) error {
+	spec := ekscloud.ClusterSpec{
+		Name:    clusterName,
+		Version: k8sVersion,
+		Tags:    tags,
+		Nodes:   nodes,
+	}
+	if updateFunc != nil {
+		updateFunc(&spec)
+	}
 
-	formattedTags := k8slabels.SelectorFromSet(tags).String()
 	fmt.Println("Creating EKS cluster ...")
-	args := []string{"create", "cluster", "--region=" + region, "--name=" + clusterName, "--version=" + k8sVersion, "--nodegroup-name", "ranchernodes", "--nodes", nodes, "--tags", formattedTags}
-	if len(extraArgs) != 0 {
-		args = append(args, extraArgs...)
-	}
-	fmt.Printf("Running command: eksctl %v\n", args)
-	out, err := proc.RunW("eksctl", args...)
-	if err != nil {
-		return errors.Wrap(err, "Failed to create cluster: "+out)
+	if _, err := cloudClient(region).CreateCluster(spec); err != nil {
+		return errors.Wrap(err, "Failed to create cluster")
 	}
 	fmt.Println("Created EKS cluster: ", clusterName)
-
 	return nil
 }
 
-// Upgrade EKS cluster using EKS CLI
+// UpgradeEKSClusterOnAWS upgrades the control plane of an EKS cluster
 func UpgradeEKSClusterOnAWS(region string, clusterName string, upgradeToVersion string) error {
-
 	fmt.Println("Upgrading EKS cluster controlplane ...")
-	args := []string{"upgrade", "cluster", "--region=" + region, "--name=" + clusterName, "--version=" + upgradeToVersion, "--approve"}
-	fmt.Printf("Running command: eksctl %v\n", args)
-	out, err := proc.RunW("eksctl", args...)
-	if err != nil {
-		return errors.Wrap(err, "Failed to upgrade cluster: "+out)
+	if err := cloudClient(region).UpgradeCluster(clusterName, upgradeToVersion); err != nil {
+		return errors.Wrap(err, "Failed to upgrade cluster")
 	}
-
 	fmt.Println("Upgraded EKS cluster controlplane: ", clusterName)
 	return nil
 }
 
-// AddNodeGroupOnAWS adds nodegroup ot a cluster using EKS CLI
-func AddNodeGroupOnAWS(nodeName, clusterName, region string, extraArgs ...string) error {
+// AddNodeGroupOnAWS adds a managed nodegroup of 2 nodes to a cluster
+func AddNodeGroupOnAWS(nodeName, clusterName, region string) error {
 	fmt.Println("Adding nodegroup to EKS cluster ...")
-	args := []string{"create", "nodegroup", "--region=" + region, "--cluster", clusterName, "--name", nodeName}
-	if len(extraArgs) != 0 {
-		args = append(args, extraArgs...)
-	}
-	fmt.Printf("Running command: eksctl %v\n", args)
-	out, err := proc.RunW("eksctl", args...)
-	if err != nil {
-		return errors.Wrap(err, "Failed to add nodegroup: "+out)
+	if _, err := cloudClient(region).CreateNodegroup(clusterName, ekscloud.NodegroupSpec{Name: nodeName}); err != nil {
+		return errors.Wrap(err, "Failed to add nodegroup")
 	}
 	fmt.Println("Added nodegroup: ", nodeName)
 	return nil
-
 }
 
-// ScaleNodeGroupOnAWS scales nodegroup of a cluster using EKS CLI
-func ScaleNodeGroupOnAWS(ngName, clusterName, region string, numOfNodes, maxCount, minCount int64, extraArgs ...string) error {
+// ScaleNodeGroupOnAWS scales nodegroup of a cluster
+func ScaleNodeGroupOnAWS(ngName, clusterName, region string, numOfNodes, maxCount, minCount int64) error {
 	fmt.Println("Scaling nodegroup of EKS cluster ...")
-	args := []string{"scale", "nodegroup", "--region", region, "--name", ngName, "--cluster", clusterName, "--nodes", fmt.Sprintf("%d", numOfNodes), "--nodes-max", fmt.Sprintf("%d", maxCount), "--nodes-min", fmt.Sprintf("%d", minCount), "--wait"}
-	if len(extraArgs) != 0 {
-		args = append(args, extraArgs...)
-	}
-	fmt.Printf("Running command: eksctl %v\n", args)
-	out, err := proc.RunW("eksctl", args...)
-	if err != nil {
-		return errors.Wrap(err, "Failed to scale nodegroup: "+out)
+	scaling := ekscloud.Scaling{DesiredSize: numOfNodes, MinSize: minCount, MaxSize: maxCount}
+	if err := cloudClient(region).ScaleNodegroup(clusterName, ngName, scaling); err != nil {
+		return errors.Wrap(err, "Failed to scale nodegroup")
 	}
 	fmt.Println("Scaled nodegroup: ", ngName)
 	return nil
 }
 
 // UpdateNodeGroupLabelsOnAWS deletes or add/updates labels on nodegroup of a cluster;
-func UpdateNodeGroupLabelsOnAWS(clusterName, nodegroupName, region string, addOrUpdatelabels map[string]string, removeLabels []string, extraArgs ...string) error {
-	if len(addOrUpdatelabels) == 0 && len(removeLabels) == 0 {
-		return fmt.Errorf("no labels provided to remove or update/add")
-	}
+func UpdateNodeGroupLabelsOnAWS(clusterName, nodegroupName, region string, addOrUpdatelabels map[string]string, removeLabels []string) error {
 	fmt.Println("Updating labels of nodegroup on EKS cluster ...")
-	args := []string{"eks", "update-nodegroup-config", "--cluster-name", clusterName, "--nodegroup-name", nodegroupName, "--region", region}
-	var addLabel, removeLabel string
-
-	if len(addOrUpdatelabels) > 0 {
-		formattedLabels := k8slabels.FormatLabels(addOrUpdatelabels)
-		addLabel = fmt.Sprintf("addOrUpdateLabels={%s}", formattedLabels)
+	if err := cloudClient(region).UpdateNodegroupLabels(clusterName, nodegroupName, addOrUpdatelabels, removeLabels); err != nil {
+		return errors.Wrap(err, "Failed to update labels to nodegroup")
 	}
-	if len(removeLabels) > 0 {
-		removeLabel = fmt.Sprintf("removeLabels=%s", strings.Join(removeLabels, ","))
-	}
-
-	var label string
-	if addLabel != "" && removeLabel != "" {
-		label = fmt.Sprintf("%s,%s", addLabel, removeLabel)
-	} else if addLabel != "" {
-		label = fmt.Sprintf("%s", addLabel)
-	} else {
-		label = fmt.Sprintf("%s", removeLabel)
-	}
-
-	args = append(args, "--labels", label)
-	if len(extraArgs) != 0 {
-		args = append(args, extraArgs...)
-	}
-
-	fmt.Printf("Running command: aws %v\n", args)
-	out, err := proc.RunW("aws", args...)
-	if err != nil {
-		return errors.Wrap(err, "Failed to update labels to nodegroup: "+out)
-	}
-	fmt.Println("Updated labels to nodegroup: ", nodegroupName, "\n", out)
+	fmt.Println("Updated labels to nodegroup: ", nodegroupName)
 	return nil
 }
 
-// AddClusterTagsOnAWS adds label to cluster using AWS cli
-func AddClusterTagsOnAWS(clusterName, region string, tags map[string]string, extraArgs ...string) error {
-	arn, err := GetFromEKS(region, clusterName, "cluster", ".[].Arn")
+// AddClusterTagsOnAWS adds tags to a cluster
+func AddClusterTagsOnAWS(clusterName, region string, tags map[string]string) error {
+	cluster, err := DescribeEKSClusterOnAWS(region, clusterName)
 	if err != nil {
 		return fmt.Errorf("failed to get ARN for cluster %s: %v", clusterName, err)
 	}
-	return UpdateResoureTagsOnAWS(arn, clusterName, region, tags, extraArgs...)
+	return UpdateResoureTagsOnAWS(cluster.Arn, clusterName, region, tags)
 }
 
-// RemoveClusterTagsOnAWS removes label from cluster using AWS cli
-func RemoveClusterTagsOnAWS(clusterName, region string, tags []string, extraArgs ...string) error {
-	arn, err := GetFromEKS(region, clusterName, "cluster", ".[].Arn")
+// RemoveClusterTagsOnAWS removes tags from a cluster
+func RemoveClusterTagsOnAWS(clusterName, region string, tags []string) error {
+	cluster, err := DescribeEKSClusterOnAWS(region, clusterName)
 	if err != nil {
 		return fmt.Errorf("failed to get ARN for cluster %s: %v", clusterName, err)
 	}
-	return RemoveResourceTagsOnAWS(arn, clusterName, region, tags, extraArgs...)
+	return RemoveResourceTagsOnAWS(cluster.Arn, clusterName, region, tags)
 }
 
-// UpdateClusterTagOnAWS tags resource using AWS CLI
-func UpdateResoureTagsOnAWS(resourceArn, clusterName, region string, tags map[string]string, extraArgs ...string) error {
-	formattedTags := k8slabels.SelectorFromSet(tags).String()
+// UpdateResoureTagsOnAWS tags an EKS resource
+func UpdateResoureTagsOnAWS(resourceArn, clusterName, region string, tags map[string]string) error {
 	fmt.Println("Updating tag on EKS cluster ...")
-	args := []string{"eks", "tag-resource", "--resource-arn", resourceArn, "--tags", formattedTags, "--region", region}
-	if len(extraArgs) != 0 {
-		args = append(args, extraArgs...)
+	if err := cloudClient(region).TagResource(resourceArn, tags); err != nil {
+		return errors.Wrap(err, "Failed to update tag")
 	}
-	fmt.Printf("Running command: aws %v\n", args)
-	out, err := proc.RunW("aws", args...)
-	if err != nil {
-		return errors.Wrap(err, "Failed to update tag: "+out)
-	}
-	fmt.Println("Updated tag on EKS cluster: ", clusterName, "\n", out)
+	fmt.Println("Updated tag on EKS cluster: ", clusterName)
 	return nil
 }
 
-// RemoveResourceTagsOnAWS untags the resources using AWS CLI
-func RemoveResourceTagsOnAWS(resourceArn, clusterName, region string, tags []string, extraArgs ...string) error {
+// RemoveResourceTagsOnAWS untags an EKS resource
+func RemoveResourceTagsOnAWS(resourceArn, clusterName, region string, tags []string) error {
 	fmt.Println("Removing tag on EKS cluster ...")
-	args := []string{"eks", "untag-resource", "--resource-arn", resourceArn, "--region", region}
-	args = append(args, "--tag-keys")
-	for _, tag := range tags {
-		args = append(args, tag)
-	}
-	if len(extraArgs) != 0 {
-		args = append(args, extraArgs...)
+	if err := cloudClient(region).UntagResource(resourceArn, tags); err != nil {
+		return errors.Wrap(err, "Failed to remove tag")
 	}
-	fmt.Printf("Running command: aws %v\n", args)
-	out, err := proc.RunW("aws", args...)
-	if err != nil {
-		return errors.Wrap(err, "Failed to remove tag: "+out)
-	}
-	fmt.Println("Removed tag on EKS cluster: ", clusterName, "\n", out)
+	fmt.Println("Removed tag on EKS cluster: ", clusterName)
 	return nil
 }
 
 // UpdateLoggingOnAWS enabled and disabled the logging of a cluster
 // types: all, api, audit, authenticator, controllerManager, scheduler
-func UpdateLoggingOnAWS(clusterName, region string, enableLoggingTypes, disableLoggingTypes []string, extraArgs ...string) error {
+func UpdateLoggingOnAWS(clusterName, region string, enableLoggingTypes, disableLoggingTypes []string) error {
 	fmt.Println("Updating Logging of EKS cluster ...")
-	args := []string{"utils", "update-cluster-logging", "--region", region, "--cluster", clusterName, "--approve"}
-	if len(enableLoggingTypes) != 0 {
-		args = append(args, "--enable-types")
-		args = append(args, strings.Join(enableLoggingTypes, ","))
-	}
-	if len(disableLoggingTypes) != 0 {
-		args = append(args, "--disable-types")
-		args = append(args, strings.Join(disableLoggingTypes, ","))
+	if err := cloudClient(region).UpdateLogging(clusterName, enableLoggingTypes, disableLoggingTypes); err != nil {
+		return errors.Wrap(err, "Failed to update logging")
 	}
-	if len(extraArgs) != 0 {
-		args = append(args, extraArgs...)
+	fmt.Println("Updated logging of EKS cluster: ", clusterName)
+	return nil
+}
+
+// UpdateVPCAccess updates the public and private access of the control plane endpoint
+func UpdateVPCAccess(clusterName, region string, enablePublic, enablePrivate bool, publicAccessCIDR []string) error {
+	fmt.Println("Updating VPC access of control plane ...")
+	access := ekscloud.VPCAccess{
+		PublicAccess:        enablePublic,
+		PrivateAccess:       enablePrivate,
+		PublicAccessSources: publicAccessCIDR,
 	}
-	fmt.Printf("Running command: eksctl %v\n", args)
-	out, err := proc.RunW("eksctl", args...)
-	if err != nil {
-		return errors.Wrap(err, "Failed to update logging: "+out)
+	if err := cloudClient(region).UpdateVPCAccess(clusterName, access); err != nil {
+		return errors.Wrap(err, "Failed to update VPC access")
 	}
-	fmt.Println("Updated logging of EKS cluster: ", clusterName, "\n", out)
+	fmt.Println("Updated VPC access: ", clusterName)
 	return nil
 }
 
-func UpdateVPCAccess(clusterName, region string, enablePublic, enablePrivate bool, publicAccessCIDR []string, extraArgs ...string) error {
-	fmt.Println("Updating VPC access of control plane ...")
-	args := []string{"utils", "update-cluster-vpc-config", "--region", region, "--cluster", clusterName, "--approve"}
-	if enablePublic {
-		args = append(args, "--public-access")
+// EnableNetworkPolicyOnAWS enables the network policy agent of the VPC CNI addon, EKS does not enforce the NetworkPolicies otherwise
+func EnableNetworkPolicyOnAWS(clusterName, region string) error {
+	fmt.Println("Enabling network policy on EKS cluster ...")
+	if err := cloudClient(region).EnableNetworkPolicy(clusterName); err != nil {
+		return errors.Wrap(err, "Failed to enable network policy")
 	}
-	if enablePrivate {
-		args = append(args, "--private-access")
+	fmt.Println("Enabled network policy on EKS cluster: ", clusterName)
+	return nil
+}
+
+// RunCommandOnPrivateCluster runs a shell command against a cluster whose API endpoint is private, with a kubeconfig of the AWS credentials
+// and returns its output. EKS has no command invoke: the public endpoint is opened to the Rancher host only for the time of the command, then closed again.
+func RunCommandOnPrivateCluster(clusterName, region, command string) (out string, err error) {
+	client := cloudClient(region)
+	fmt.Println("Opening the public endpoint of EKS cluster to the Rancher host ...")
+	window := ekscloud.VPCAccess{PublicAccess: true, PrivateAccess: true, PublicAccessSources: []string{helpers.GetRancherIP() + "/32"}}
+	if err = client.UpdateVPCAccess(clusterName, window); err != nil {
+		return "", errors.Wrap(err, "Failed to open the public endpoint")
 	}
-	if len(publicAccessCIDR) != 0 {
-		args = append(args, "--public-access-cidrs", strings.Join(publicAccessCIDR, ","))
+	defer func() {
+		fmt.Println("Closing the public endpoint of EKS cluster ...")
+		if closeErr := client.UpdateVPCAccess(clusterName, ekscloud.VPCAccess{PrivateAccess: true}); closeErr != nil && err == nil {
+			err = errors.Wrap(closeErr, "Failed to close the public endpoint")
+		}
+	}()
+
+	content, err := client.Kubeconfig(clusterName)
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to get kubeconfig")
 	}
-	if len(extraArgs) != 0 {
-		args = append(args, extraArgs...)
+	kubeconfig, err := helpers.Kubeconfigs.Write(clusterName, content)
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to write kubeconfig")
 	}
-	fmt.Printf("Running command: eksctl %v\n", args)
-	out, err := proc.RunW("eksctl", args...)
+	defer func() {
+		_ = helpers.Kubeconfigs.Release(clusterName) // clean up
+	}()
+
+	fmt.Printf("Running command against the cluster: %s\n", command)
+	out, err = kubeconfig.Run("sh", "-c", command)
 	if err != nil {
-		return errors.Wrap(err, "Failed to update VPC access: "+out)
+		return out, errors.Wrap(err, "Failed to run command: "+out)
 	}
-	fmt.Println("Updated VPC access: ", clusterName)
-	return nil
+	return out, nil
 }
 
-// Upgrade EKS cluster nodegroup using EKS CLI
+// UpgradeEKSNodegroupOnAWS upgrades a nodegroup of an EKS cluster
 func UpgradeEKSNodegroupOnAWS(region string, clusterName string, ngName string, upgradeToVersion string) error {
 	fmt.Println("Upgrading EKS cluster nodegroup ...")
-	args := []string{"upgrade", "nodegroup", "--region=" + region, "--name=" + ngName, "--cluster=" + clusterName, "--kubernetes-version=" + upgradeToVersion}
-	fmt.Printf("Running command: eksctl %v\n", args)
-	out, err := proc.RunW("eksctl", args...)
-	if err != nil {
-		return errors.Wrap(err, "Failed to upgrade nodegroup: "+out)
+	if err := cloudClient(region).UpgradeNodegroup(clusterName, ngName, upgradeToVersion); err != nil {
+		return errors.Wrap(err, "Failed to upgrade nodegroup")
 	}
-
 	fmt.Println("Upgraded EKS cluster nodegroup: ", clusterName)
 	return nil
 }
 
-func GetFromEKS(region string, clusterName string, cmd string, query string, extraArgs ...string) (out string, err error) {
-	clusterArgs := []string{"eksctl", "get", "cluster", "--region=" + region, "--name=" + clusterName, "-ojson"}
-	ngArgs := []string{"eksctl", "get", "nodegroup", "--region=" + region, "--cluster=" + clusterName, "-ojson"}
-	queryArgs := []string{"|", "jq", "-r", query}
+// DescribeEKSClusterOnAWS returns the cluster as seen by AWS
+func DescribeEKSClusterOnAWS(region string, clusterName string) (*ekscloud.Cluster, error) {
+	return cloudClient(region).DescribeCluster(clusterName)
+}
 
-	if cmd == "cluster" {
-		// extraArgs must be appended before queryArgs
-		if len(extraArgs) != 0 {
-			clusterArgs = append(clusterArgs, extraArgs...)
-		}
-		clusterArgs = append(clusterArgs, queryArgs...)
-		cmd = strings.Join(clusterArgs, " ")
-	} else {
-		// extraArgs must be appended before queryArgs
-		if len(extraArgs) != 0 {
-			ngArgs = append(ngArgs, extraArgs...)
-		}
-		ngArgs = append(ngArgs, queryArgs...)
-		cmd = strings.Join(ngArgs, " ")
-	}
+// ListEKSNodeGroupsOnAWS returns the managed nodegroups of a cluster as seen by AWS
+func ListEKSNodeGroupsOnAWS(region string, clusterName string) ([]ekscloud.Nodegroup, error) {
+	return cloudClient(region).ListNodegroups(clusterName)
+}
 
-	fmt.Printf("Running command: %s\n", cmd)
-	out, err = proc.RunW("bash", "-c", cmd)
-	return strings.TrimSpace(out), err
+// DescribeEKSNodeGroupOnAWS returns a managed nodegroup as seen by AWS
+func DescribeEKSNodeGroupOnAWS(region string, clusterName string, ngName string) (*ekscloud.Nodegroup, error) {
+	return cloudClient(region).DescribeNodegroup(clusterName, ngName)
 }
 
-// Creates/Deletes EKS cluster nodegroup using EKS CLI
-func ModifyEKSNodegroupOnAWS(region string, clusterName string, ngName string, operation string, extraArgs ...string) error {
-	args := []string{operation, "nodegroup", "--region=" + region, "--name=" + ngName, "--cluster=" + clusterName}
-	if operation == "delete" {
-		args = append(args, "--disable-eviction")
-	}
-	args = append(args, extraArgs...)
-	fmt.Printf("Running command: eksctl %v\n", args)
-	out, err := proc.RunW("eksctl", args...)
-	if err != nil {
-		return errors.Wrap(err, "Failed to modify nodegroup: "+out)
+// DeleteNodeGroupOnAWS deletes a managed nodegroup and waits for its deletion
+func DeleteNodeGroupOnAWS(region string, clusterName string, ngName string) error {
+	if err := cloudClient(region).DeleteNodegroup(clusterName, ngName); err != nil {
+		return errors.Wrap(err, "Failed to delete nodegroup")
 	}
 	return nil
 }
 
-// Complete cleanup steps for Amazon EKS
+// DeleteEKSClusterOnAWS deletes the nodegroups, the cluster and the resources created along with it
 func DeleteEKSClusterOnAWS(region string, clusterName string) error {
-	currentKubeconfig := os.Getenv("KUBECONFIG")
-	downstreamKubeconfig := os.Getenv(helpers.DownstreamKubeconfig(clusterName))
-	defer func() {
-		_ = os.Setenv("KUBECONFIG", currentKubeconfig)
-		_ = os.Remove(downstreamKubeconfig) // clean up
-	}()
-	_ = os.Setenv("KUBECONFIG", downstreamKubeconfig)
-
-	fmt.Println("Deleting all nodegroups ...")
-	ngNames, err := GetFromEKS(region, clusterName, "nodegroup", ".[].Name")
-	if err != nil {
-		return errors.Wrap(err, "Failed to list nodegroup for deletion")
-	}
-
-	if ngNames != "" {
-		for _, ngName := range strings.Split(ngNames, "\n") {
-			err = ModifyEKSNodegroupOnAWS(region, clusterName, ngName, "delete", "--wait")
-			if err != nil {
-				return errors.Wrap(err, "Failed to delete nodegroup")
-			}
-		}
-	}
-
 	fmt.Println("Deleting EKS cluster ...")
-
-	args := []string{"delete", "cluster", "--region=" + region, "--name=" + clusterName}
-	fmt.Printf("Running command: eksctl %v\n", args)
-	out, err := proc.RunW("eksctl", args...)
-	if err != nil {
-		return errors.Wrap(err, "Failed to delete cluster: "+out)
+	if err := cloudClient(region).DeleteCluster(clusterName); err != nil {
+		return errors.Wrap(err, "Failed to delete cluster")
 	}
-
 	fmt.Println("Deleted EKS cluster: ", clusterName)
-
 	return nil
 }
 
-// <==============================EKS CLI(end)==============================>
+// <==============================EKS on AWS(end)==============================>
 
 // GetK8sVersion returns the k8s version to be used by the test;
 // this value can either be a variant of envvar DOWNSTREAM_K8S_MINOR_VERSION or the highest available version
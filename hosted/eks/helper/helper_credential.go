@@ -0,0 +1,11 @@
+package helper
+
+import (
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+// CredentialOperations returns the operations of the cloud credential lifecycle checks on EKS clusters;
+// the cloud credentials of Amazon hold long-term access keys, whose expiry cannot be simulated
+func CredentialOperations() helpers.CredentialOperations {
+	return helpers.CredentialOperations{Trigger: toggleNodeCount}
+}
@@ -0,0 +1,53 @@
+package helper
+
+import (
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/fault"
+)
+
+// FaultOperations returns the operations of the operator fault checks on EKS clusters
+func FaultOperations() helpers.FaultOperations {
+	return helpers.FaultOperations{
+		Converged: func(cluster *management.Cluster) bool {
+			if cluster.EKSStatus == nil || cluster.EKSStatus.UpstreamSpec == nil {
+				return false
+			}
+			config, upstream := cluster.EKSConfig, cluster.EKSStatus.UpstreamSpec
+			if config.KubernetesVersion == nil || upstream.KubernetesVersion == nil || *config.KubernetesVersion != *upstream.KubernetesVersion {
+				return false
+			}
+			missing, extra := fault.Compare(nodeGroupNames(config.NodeGroups), nodeGroupNames(upstream.NodeGroups))
+			return len(missing) == 0 && len(extra) == 0
+		},
+		UpstreamNodePools: func(cluster *management.Cluster) []string {
+			return nodeGroupNames(cluster.EKSStatus.UpstreamSpec.NodeGroups)
+		},
+		CloudNodePools: func(cluster *management.Cluster) ([]string, error) {
+			nodeGroups, err := ListEKSNodeGroupsOnAWS(cluster.EKSConfig.Region, cluster.EKSConfig.DisplayName)
+			if err != nil {
+				return nil, err
+			}
+			var names []string
+			for _, ng := range nodeGroups {
+				names = append(names, ng.Name)
+			}
+			return names, nil
+		},
+	}
+}
+
+// nodeGroupNames returns the names of the node groups
+func nodeGroupNames(nodeGroups *[]management.NodeGroup) []string {
+	if nodeGroups == nil {
+		return nil
+	}
+	var names []string
+	for _, ng := range *nodeGroups {
+		if ng.NodegroupName != nil {
+			names = append(names, *ng.NodegroupName)
+		}
+	}
+	return names
+}
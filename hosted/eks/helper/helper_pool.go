@@ -0,0 +1,79 @@
+package helper
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/pool"
+)
+
+// PoolProvisioner returns the provisioner of a cluster pool of EKS clusters created from the default config
+func PoolProvisioner(region string) helpers.ClusterProvisioner {
+	return helpers.ClusterProvisioner{
+		Shape: "default",
+		Create: func(client *rancher.Client, cloudCredID, clusterName string) (*management.Cluster, error) {
+			k8sVersion, err := GetK8sVersion(client, false)
+			if err != nil {
+				return nil, err
+			}
+			return CreateEKSHostedCluster(client, clusterName, cloudCredID, k8sVersion, region, nil)
+		},
+		Baseline: poolBaseline,
+		Reset:    resetToBaseline,
+		Delete:   DeleteEKSHostCluster,
+	}
+}
+
+// poolBaseline returns the k8s version, node groups and tags of a cluster
+func poolBaseline(cluster *management.Cluster) pool.Baseline {
+	nodeGroups := *cluster.EKSConfig.NodeGroups
+	baseline := pool.Baseline{
+		KubernetesVersion: *cluster.EKSConfig.KubernetesVersion,
+		NodePools:         len(nodeGroups),
+	}
+	if len(nodeGroups) > 0 && nodeGroups[0].DesiredSize != nil {
+		baseline.NodeCount = *nodeGroups[0].DesiredSize
+	}
+	if cluster.EKSConfig.Tags != nil {
+		baseline.Tags = *cluster.EKSConfig.Tags
+	}
+	return baseline
+}
+
+// resetToBaseline deletes the node groups added to a cluster, scales it back and restores its tags;
+// an upgraded k8s version or a deleted node group cannot be restored
+func resetToBaseline(cluster *management.Cluster, client *rancher.Client, baseline pool.Baseline) (*management.Cluster, error) {
+	current := poolBaseline(cluster)
+	if current.KubernetesVersion != baseline.KubernetesVersion {
+		return cluster, fmt.Errorf("k8s version %s has been changed to %s", baseline.KubernetesVersion, current.KubernetesVersion)
+	}
+	// DeleteNodeGroup keeps the first node group only
+	if current.NodePools < baseline.NodePools || (current.NodePools > baseline.NodePools && baseline.NodePools != 1) {
+		return cluster, fmt.Errorf("%d node groups cannot be restored to %d", current.NodePools, baseline.NodePools)
+	}
+
+	var err error
+	if current.NodePools > baseline.NodePools {
+		cluster, err = DeleteNodeGroup(cluster, client, true, false)
+		if err != nil {
+			return cluster, err
+		}
+	}
+	if current.NodeCount != baseline.NodeCount {
+		cluster, err = ScaleNodeGroup(cluster, client, baseline.NodeCount, true, false)
+		if err != nil {
+			return cluster, err
+		}
+	}
+	if !maps.Equal(current.Tags, baseline.Tags) {
+		cluster, err = UpdateClusterTags(cluster, client, baseline.Tags, false)
+		if err != nil {
+			return cluster, err
+		}
+	}
+	return cluster, nil
+}
@@ -0,0 +1,57 @@
+package helper
+
+import (
+	"maps"
+
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	namegen "github.com/rancher/shepherd/pkg/namegenerator"
+	"k8s.io/utils/pointer"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+// RBACOperations returns the operations of the RBAC matrix on EKS clusters: the created clusters run k8sVersion, the cluster under test is upgraded to upgradeToVersion
+func RBACOperations(k8sVersion, upgradeToVersion, region string) helpers.RBACOperations {
+	return helpers.RBACOperations{
+		Create: func(client *rancher.Client, clusterName, cloudCredID string) (*management.Cluster, error) {
+			return CreateEKSHostedCluster(client, clusterName, cloudCredID, k8sVersion, region, nil)
+		},
+		Import: func(client *rancher.Client, clusterName, cloudCredID string) (*management.Cluster, error) {
+			return ImportEKSHostedCluster(client, clusterName, cloudCredID, region)
+		},
+		Edit: func(client *rancher.Client, cluster *management.Cluster) error {
+			_, err := UpdateCluster(cluster, client, func(upgradedCluster *management.Cluster) {
+				tags := map[string]string{}
+				if upgradedCluster.EKSConfig.Tags != nil {
+					tags = maps.Clone(*upgradedCluster.EKSConfig.Tags)
+				}
+				tags["rbac-edit"] = namegen.RandStringLower(5)
+				upgradedCluster.EKSConfig.Tags = &tags
+			})
+			return err
+		},
+		Scale: toggleNodeCount,
+		Upgrade: func(client *rancher.Client, cluster *management.Cluster) error {
+			_, err := UpdateCluster(cluster, client, func(upgradedCluster *management.Cluster) {
+				upgradedCluster.EKSConfig.KubernetesVersion = &upgradeToVersion
+			})
+			return err
+		},
+	}
+}
+
+// toggleNodeCount scales the node groups of the cluster to their toggled node count, see helpers.ToggledNodeCount
+func toggleNodeCount(client *rancher.Client, cluster *management.Cluster) error {
+	_, err := UpdateCluster(cluster, client, func(upgradedCluster *management.Cluster) {
+		nodeGroups := *upgradedCluster.EKSConfig.NodeGroups
+		for i := range nodeGroups {
+			nodeCount := helpers.ToggledNodeCount(*nodeGroups[i].DesiredSize)
+			nodeGroups[i].DesiredSize = pointer.Int64(nodeCount)
+			// the desired size must stay within the bounds of the node group
+			nodeGroups[i].MinSize = pointer.Int64(min(*nodeGroups[i].MinSize, nodeCount))
+			nodeGroups[i].MaxSize = pointer.Int64(max(*nodeGroups[i].MaxSize, nodeCount))
+		}
+	})
+	return err
+}
@@ -0,0 +1,10 @@
+package helper
+
+import (
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/workload"
+)
+
+// WorkloadOptions returns the options of the workload checks on EKS clusters: the default gp2 StorageClass and a classic ELB
+func WorkloadOptions() workload.Options {
+	return workload.Options{}
+}
@@ -14,7 +14,7 @@ import (
 var _ = Describe("K8sChartSupportImport", func() {
 	var cluster *management.Cluster
 	BeforeEach(func() {
-		err := helper.CreateEKSClusterOnAWS(region, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
+		err := helper.CreateEKSClusterOnAWS(region, clusterName, k8sVersion, 1, helpers.GetCommonMetadataLabels(), nil)
 		Expect(err).To(BeNil())
 
 		cluster, err = helper.ImportEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, region)
@@ -0,0 +1,39 @@
+package k8s_chart_support_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/eks/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var _ = Describe("K8sChartSupportMatrix", func() {
+	var cluster *management.Cluster
+	BeforeEach(func() {
+		if helpers.ChartMatrixWindow == "" {
+			Skip("Skipping the chart matrix, CHART_MATRIX_WINDOW is not set")
+		}
+
+		var err error
+		cluster, err = helper.CreateEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, region, nil)
+		Expect(err).To(BeNil())
+		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+		Expect(err).To(BeNil())
+	})
+	AfterEach(func() {
+		if ctx.ClusterCleanup && cluster != nil {
+			err := helper.DeleteEKSHostCluster(cluster, ctx.RancherAdminClient)
+			Expect(err).To(BeNil())
+		} else {
+			fmt.Println("Skipping downstream cluster deletion: ", clusterName)
+		}
+	})
+
+	It("should reconcile the cluster after every upgrade and downgrade of the operator chart", func() {
+		cluster = helpers.ChartMatrixChecks(cluster, ctx.RancherAdminClient, helper.ScaleNodeGroup)
+	})
+})
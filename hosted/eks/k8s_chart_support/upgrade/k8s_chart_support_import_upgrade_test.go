@@ -5,37 +5,34 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
 
 	"github.com/rancher/hosted-providers-e2e/hosted/eks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
 )
 
 var _ = Describe("K8sChartSupportUpgradeImport", func() {
-	var cluster *management.Cluster
+	var f *helpers.Fixture
 	BeforeEach(func() {
-		err := helper.CreateEKSClusterOnAWS(region, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
+		f = newFixture()
+		f.GuardCost()
+		err := helper.CreateEKSClusterOnAWS(region, f.ClusterName, f.K8sVersion, 1, helpers.GetCommonMetadataLabels(), nil)
 		Expect(err).To(BeNil())
 
-		cluster, err = helper.ImportEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, region)
+		f.Cluster, err = helper.ImportEKSHostedCluster(f.Client, f.ClusterName, ctx.CloudCredID, region)
 		Expect(err).To(BeNil())
-		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
-		Expect(err).To(BeNil())
-	})
-	AfterEach(func() {
-		if ctx.ClusterCleanup && cluster != nil {
-			err := helper.DeleteEKSHostCluster(cluster, ctx.RancherAdminClient)
+		f.AddClusterCleanup(func() {
+			err := helper.DeleteEKSHostCluster(f.Cluster, f.Client)
 			Expect(err).To(BeNil())
-			err = helper.DeleteEKSClusterOnAWS(region, clusterName)
+			err = helper.DeleteEKSClusterOnAWS(region, f.ClusterName)
 			Expect(err).To(BeNil())
-		} else {
-			fmt.Println("Skipping downstream cluster deletion: ", clusterName)
-		}
+		})
+		f.Cluster, err = helpers.WaitUntilClusterIsReady(f.Cluster, f.Client)
+		Expect(err).To(BeNil())
 	})
 	It("should successfully test k8s chart support import in an upgrade scenario", func() {
 		GinkgoLogr.Info(fmt.Sprintf("Testing K8s %s chart support for import on Rancher upgraded from %s to %s", helpers.K8sUpgradedMinorVersion, helpers.RancherFullVersion, helpers.RancherUpgradeFullVersion))
-		testCaseID = 167 // Report to Qase
+		f.SetQaseID(167) // Report to Qase
 
-		commonchecks(&ctx, cluster, clusterName, helpers.RancherUpgradeFullVersion, helpers.K8sUpgradedMinorVersion)
+		commonchecks(f, helpers.RancherUpgradeFullVersion, helpers.K8sUpgradedMinorVersion)
 	})
 })
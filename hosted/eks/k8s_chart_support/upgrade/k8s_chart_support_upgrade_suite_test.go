@@ -16,18 +16,16 @@ import (
 	nodestat "github.com/rancher/shepherd/extensions/nodes"
 	"github.com/rancher/shepherd/extensions/workloads/pods"
 	"github.com/rancher/shepherd/pkg/config"
-	namegen "github.com/rancher/shepherd/pkg/namegenerator"
 
 	"github.com/rancher/hosted-providers-e2e/hosted/eks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
 )
 
 var (
-	ctx                     helpers.RancherContext
-	clusterName, k8sVersion string
-	region                  = helpers.GetEKSRegion()
-	testCaseID              int64
-	k                       = kubectl.New()
+	ctx         helpers.RancherContext
+	region      = helpers.GetEKSRegion()
+	k           = kubectl.New()
+	environment helpers.EnvironmentProfile
 )
 
 func TestK8sChartSupportUpgrade(t *testing.T) {
@@ -41,14 +39,30 @@ var _ = BeforeEach(func() {
 	Expect(helpers.RancherUpgradeFullVersion).ToNot(BeEmpty())
 	Expect(helpers.K8sUpgradedMinorVersion).ToNot(BeEmpty())
 	Expect(helpers.Kubeconfig).ToNot(BeEmpty())
+	helpers.ExpectNoOperatorOverride()
+	environment = helpers.StockEnvironmentProfile()
+
+	// Registered before the fixture of the spec, so that it runs once the downstream cluster has been deleted
+	DeferCleanup(func() {
+		// The test must restore the env to its original state, so we install rancher back to its original version and uninstall the operator charts
+		// Restoring rancher back to its original state is necessary because in case DOWNSTREAM_CLUSTER_CLEANUP is set to false; in which case clusters will be retained for the next test.
+		// Once the operator is uninstalled, it might be reinstalled since the cluster exists, and installing rancher back to its original state ensures that the version is not the one we want to test.
+		By(fmt.Sprintf("Installing Rancher back to its original version %s", helpers.RancherFullVersion), func() {
+			helpers.InstallRancherManager(k, environment)
+			helpers.CheckRancherDeployments(k)
+		})
+
+		By("Uninstalling the existing operator charts", func() {
+			helpers.UninstallOperatorCharts()
+		})
+	})
 
 	By("Adding the necessary chart repos", func() {
 		helpers.AddRancherCharts()
 	})
 
 	By(fmt.Sprintf("Installing Rancher Manager %s", helpers.RancherFullVersion), func() {
-		rancherChannel, rancherVersion, rancherHeadVersion := helpers.GetRancherVersions(helpers.RancherFullVersion)
-		helpers.InstallRancherManager(k, helpers.RancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, "", "")
+		helpers.InstallRancherManager(k, environment)
 		helpers.CheckRancherDeployments(k)
 	})
 
@@ -67,44 +81,27 @@ var _ = BeforeEach(func() {
 		Expect(err).To(BeNil())
 		ctx.RancherAdminClient = rancherAdminClient
 	})
+})
 
+// newFixture returns the fixture of the current spec with the k8s version its cluster is created with
+func newFixture() *helpers.Fixture {
+	f := helpers.NewFixture(&ctx)
 	var err error
-	clusterName = namegen.AppendRandomString(helpers.ClusterNamePrefix)
-	k8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, false)
+	f.K8sVersion, err = helper.GetK8sVersion(f.Client, false)
 	Expect(err).To(BeNil())
-	Expect(k8sVersion).ToNot(BeEmpty())
-	GinkgoLogr.Info(fmt.Sprintf("Using EKS version %s for cluster %s", k8sVersion, clusterName))
-
-})
-
-var _ = AfterEach(func() {
-	// The test must restore the env to its original state, so we install rancher back to its original version and uninstall the operator charts
-	// Restoring rancher back to its original state is necessary because in case DOWNSTREAM_CLUSTER_CLEANUP is set to false; in which case clusters will be retained for the next test.
-	// Once the operator is uninstalled, it might be reinstalled since the cluster exists, and installing rancher back to its original state ensures that the version is not the one we want to test.
-	By(fmt.Sprintf("Installing Rancher back to its original version %s", helpers.RancherFullVersion), func() {
-		rancherChannel, rancherVersion, rancherHeadVersion := helpers.GetRancherVersions(helpers.RancherFullVersion)
-		helpers.InstallRancherManager(k, helpers.RancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, "", "")
-		helpers.CheckRancherDeployments(k)
-	})
-
-	By("Uninstalling the existing operator charts", func() {
-		helpers.UninstallOperatorCharts()
-	})
-})
-
-var _ = ReportBeforeEach(func(report SpecReport) {
-	// Reset case ID
-	testCaseID = -1
-})
+	Expect(f.K8sVersion).ToNot(BeEmpty())
+	GinkgoLogr.Info(fmt.Sprintf("Using EKS version %s for cluster %s", f.K8sVersion, f.ClusterName))
+	return f
+}
 
 var _ = ReportAfterEach(func(report SpecReport) {
 	// Add result in Qase if asked
-	Qase(testCaseID, report)
+	Qase(helpers.QaseID(report), report)
 })
 
-func commonchecks(ctx *helpers.RancherContext, cluster *management.Cluster, clusterName, rancherUpgradedVersion, k8sUpgradedVersion string) {
+func commonchecks(f *helpers.Fixture, rancherUpgradedVersion, k8sUpgradedVersion string) {
 
-	helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
+	helpers.ClusterIsReadyChecks(f.Cluster, f.Client, f.ClusterName)
 
 	var originalChartVersion string
 	By("checking the chart version", func() {
@@ -114,8 +111,7 @@ func commonchecks(ctx *helpers.RancherContext, cluster *management.Cluster, clus
 	})
 
 	By(fmt.Sprintf("upgrading rancher to %v", rancherUpgradedVersion), func() {
-		rancherChannel, rancherVersion, rancherHeadVersion := helpers.GetRancherVersions(rancherUpgradedVersion)
-		helpers.InstallRancherManager(k, helpers.RancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, "none", "none")
+		helpers.InstallRancherManager(k, environment.WithRancherVersion(rancherUpgradedVersion))
 		helpers.CheckRancherDeployments(k)
 
 		By("ensuring operator pods are also up", func() {
@@ -125,7 +121,7 @@ func commonchecks(ctx *helpers.RancherContext, cluster *management.Cluster, clus
 		})
 
 		By("ensuring the rancher client is connected", func() {
-			isConnected, err := ctx.RancherAdminClient.IsConnected()
+			isConnected, err := f.Client.IsConnected()
 			Expect(err).To(BeNil())
 			Expect(isConnected).To(BeTrue())
 		})
@@ -134,12 +130,12 @@ func commonchecks(ctx *helpers.RancherContext, cluster *management.Cluster, clus
 	By("making sure the local cluster is ready", func() {
 		const localClusterID = "local"
 		By("checking all management nodes are ready", func() {
-			err := nodestat.AllManagementNodeReady(ctx.RancherAdminClient, localClusterID, helpers.Timeout)
+			err := nodestat.AllManagementNodeReady(f.Client, localClusterID, helpers.Timeout)
 			Expect(err).To(BeNil())
 		})
 
 		By("checking all pods are ready", func() {
-			podErrors := pods.StatusPods(ctx.RancherAdminClient, localClusterID)
+			podErrors := pods.StatusPods(f.Client, localClusterID)
 			Expect(podErrors).To(BeEmpty())
 		})
 	})
@@ -153,32 +149,32 @@ func commonchecks(ctx *helpers.RancherContext, cluster *management.Cluster, clus
 
 	By("making sure the downstream cluster is ready", func() {
 		var err error
-		cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
+		f.Cluster, err = f.Client.Management.Cluster.ByID(f.Cluster.ID)
 		Expect(err).To(BeNil())
-		helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
+		helpers.ClusterIsReadyChecks(f.Cluster, f.Client, f.ClusterName)
 
 		// since no changes have been made to the cluster so far, we need reinstantiate EKSConfig after fetching the cluster
 		if helpers.IsImport {
-			cluster.EKSConfig = cluster.EKSStatus.UpstreamSpec
+			f.Cluster.EKSConfig = f.Cluster.EKSStatus.UpstreamSpec
 		}
 	})
 
 	var latestVersion *string
 	By(fmt.Sprintf("fetching a list of available k8s versions and ensure the v%s is present in the list and upgrading the cluster to it", k8sUpgradedVersion), func() {
-		versions, err := helper.ListEKSAvailableVersions(ctx.RancherAdminClient, cluster)
+		versions, err := helper.ListEKSAvailableVersions(f.Client, f.Cluster)
 		Expect(err).To(BeNil())
 		Expect(versions).ToNot(BeEmpty())
 		GinkgoLogr.Info(fmt.Sprintf("Available EKS versions: %v", versions))
 
 		latestVersion = &versions[0]
 		Expect(*latestVersion).To(ContainSubstring(k8sUpgradedVersion))
-		Expect(helpers.VersionCompare(*latestVersion, cluster.Version.GitVersion)).To(BeNumerically("==", 1))
+		Expect(helpers.VersionCompare(*latestVersion, f.Cluster.Version.GitVersion)).To(BeNumerically("==", 1))
 
-		cluster, err = helper.UpgradeClusterKubernetesVersion(cluster, *latestVersion, ctx.RancherAdminClient, true)
+		f.Cluster, err = helper.UpgradeClusterKubernetesVersion(f.Cluster, *latestVersion, f.Client, true)
 		Expect(err).To(BeNil())
 
 		By("upgrading the NodeGroups", func() {
-			cluster, err = helper.UpgradeNodeKubernetesVersion(cluster, *latestVersion, ctx.RancherAdminClient, true, true, helpers.IsImport)
+			f.Cluster, err = helper.UpgradeNodeKubernetesVersion(f.Cluster, *latestVersion, f.Client, true, true, helpers.IsImport)
 			Expect(err).To(BeNil())
 		})
 	})
@@ -194,9 +190,9 @@ func commonchecks(ctx *helpers.RancherContext, cluster *management.Cluster, clus
 
 	By("making a change to the cluster (scaling the node up) to validate functionality after chart downgrade", func() {
 		var err error
-		configNodeGroups := *cluster.EKSConfig.NodeGroups
+		configNodeGroups := *f.Cluster.EKSConfig.NodeGroups
 		initialNodeCount := *configNodeGroups[0].DesiredSize
-		cluster, err = helper.ScaleNodeGroup(cluster, ctx.RancherAdminClient, initialNodeCount+1, true, true)
+		f.Cluster, err = helper.ScaleNodeGroup(f.Cluster, f.Client, initialNodeCount+1, true, true)
 		Expect(err).To(BeNil())
 	})
 
@@ -205,22 +201,22 @@ func commonchecks(ctx *helpers.RancherContext, cluster *management.Cluster, clus
 	})
 
 	By("making a change(adding a nodepool) to the cluster to re-install the operator and validating it is re-installed to the latest/upgraded version", func() {
-		currentNodeGroupNumber := len(*cluster.EKSConfig.NodeGroups)
+		currentNodeGroupNumber := len(*f.Cluster.EKSConfig.NodeGroups)
 		var err error
-		cluster, err = helper.AddNodeGroup(cluster, 1, ctx.RancherAdminClient, false, false)
+		f.Cluster, err = helper.AddNodeGroup(f.Cluster, 1, f.Client, false, false)
 		Expect(err).To(BeNil())
 
 		By("ensuring that the chart is re-installed to the latest/upgraded version", func() {
 			helpers.WaitUntilOperatorChartInstallation(upgradedChartVersion, "", 0)
 		})
 
-		err = clusters.WaitClusterToBeUpgraded(ctx.RancherAdminClient, cluster.ID)
+		err = clusters.WaitClusterToBeUpgraded(f.Client, f.Cluster.ID)
 		Expect(err).To(BeNil())
 		// Check if the desired config has been applied in Rancher
 		Eventually(func() int {
-			cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
+			f.Cluster, err = f.Client.Management.Cluster.ByID(f.Cluster.ID)
 			Expect(err).To(BeNil())
-			return len(*cluster.EKSStatus.UpstreamSpec.NodeGroups)
+			return len(*f.Cluster.EKSStatus.UpstreamSpec.NodeGroups)
 		}, tools.SetTimeout(20*time.Minute), 10*time.Second).Should(BeNumerically("==", currentNodeGroupNumber+1))
 	})
 
@@ -20,9 +20,6 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
-	"github.com/rancher/shepherd/clients/rancher"
-	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
-
 	"github.com/rancher/hosted-providers-e2e/hosted/eks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
 )
@@ -31,7 +28,7 @@ var _ = Describe("P0Import", func() {
 	for _, testData := range []struct {
 		qaseID    int64
 		isUpgrade bool
-		testBody  func(cluster *management.Cluster, client *rancher.Client, clusterName string)
+		testBody  func(f *helpers.Fixture)
 		testTitle string
 	}{
 		{
@@ -49,39 +46,40 @@ var _ = Describe("P0Import", func() {
 	} {
 		testData := testData
 		When("a cluster is created", func() {
+			var f *helpers.Fixture
+
 			BeforeEach(func() {
 				if testData.isUpgrade && helpers.SkipUpgradeTests {
 					Skip(helpers.SkipUpgradeTestsLog)
 				}
 
-				k8sVersion, err := helper.GetK8sVersion(ctx.RancherAdminClient, testData.isUpgrade)
+				f = helpers.NewFixture(&ctx)
+				f.AddClusterCleanup(func() {
+					if f.Cluster != nil && f.Cluster.ID != "" {
+						GinkgoLogr.Info(fmt.Sprintf("Cleaning up resource cluster: %s %s", f.Cluster.Name, f.Cluster.ID))
+						err := helper.DeleteEKSHostCluster(f.Cluster, f.Client)
+						Expect(err).To(BeNil())
+					}
+					err := helper.DeleteEKSClusterOnAWS(region, f.ClusterName)
+					Expect(err).To(BeNil())
+				})
+
+				var err error
+				f.K8sVersion, err = helper.GetK8sVersion(f.Client, testData.isUpgrade)
 				Expect(err).To(BeNil())
-				GinkgoLogr.Info(fmt.Sprintf("Using K8s version %s for cluster %s", k8sVersion, clusterName))
-				err = helper.CreateEKSClusterOnAWS(region, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
+				GinkgoLogr.Info(fmt.Sprintf("Using K8s version %s for cluster %s", f.K8sVersion, f.ClusterName))
+				err = helper.CreateEKSClusterOnAWS(region, f.ClusterName, f.K8sVersion, 1, helpers.GetCommonMetadataLabels(), nil)
 				Expect(err).To(BeNil())
 
-				cluster, err = helper.ImportEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, region)
+				f.Cluster, err = helper.ImportEKSHostedCluster(f.Client, f.ClusterName, ctx.CloudCredID, region)
 				Expect(err).To(BeNil())
-				cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+				f.Cluster, err = helpers.WaitUntilClusterIsReady(f.Cluster, f.Client)
 				Expect(err).To(BeNil())
 			})
-			AfterEach(func() {
-				if ctx.ClusterCleanup {
-					if cluster != nil && cluster.ID != "" {
-						GinkgoLogr.Info(fmt.Sprintf("Cleaning up resource cluster: %s %s", cluster.Name, cluster.ID))
-						err := helper.DeleteEKSHostCluster(cluster, ctx.RancherAdminClient)
-						Expect(err).To(BeNil())
-					}
-					err := helper.DeleteEKSClusterOnAWS(region, clusterName)
-					Expect(err).To(BeNil())
-				} else {
-					fmt.Println("Skipping downstream cluster deletion: ", clusterName)
-				}
-			})
 
 			It(testData.testTitle, func() {
-				testCaseID = testData.qaseID
-				testData.testBody(cluster, ctx.RancherAdminClient, clusterName)
+				f.SetQaseID(testData.qaseID)
+				testData.testBody(f)
 			})
 		})
 	}
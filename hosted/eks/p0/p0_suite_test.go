@@ -22,10 +22,6 @@ import (
 	. "github.com/onsi/gomega"
 	. "github.com/rancher-sandbox/qase-ginkgo"
 
-	"github.com/rancher/shepherd/clients/rancher"
-	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
-	namegen "github.com/rancher/shepherd/pkg/namegenerator"
-
 	"github.com/rancher/hosted-providers-e2e/hosted/eks/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
 )
@@ -35,11 +31,8 @@ const (
 )
 
 var (
-	ctx         helpers.RancherContext
-	cluster     *management.Cluster
-	clusterName string
-	testCaseID  int64
-	region      = helpers.GetEKSRegion()
+	ctx    helpers.RancherContext
+	region = helpers.GetEKSRegion()
 )
 
 func TestP0(t *testing.T) {
@@ -54,66 +47,63 @@ var _ = SynchronizedBeforeSuite(func() []byte {
 	ctx = helpers.CommonBeforeSuite()
 })
 
-var _ = BeforeEach(func() {
-	// Setting this to nil ensures we do not use the `cluster` variable value from another test running in parallel with this one.
-	cluster = nil
-	clusterName = namegen.AppendRandomString(helpers.ClusterNamePrefix)
-})
-
-var _ = ReportBeforeEach(func(report SpecReport) {
-	// Reset case ID
-	testCaseID = -1
-})
-
 var _ = ReportAfterEach(func(report SpecReport) {
 	// Add result in Qase if asked
-	Qase(testCaseID, report)
+	Qase(helpers.QaseID(report), report)
 })
 
-func p0upgradeK8sVersionChecks(cluster *management.Cluster, client *rancher.Client, clusterName string) {
-	helpers.ClusterIsReadyChecks(cluster, client, clusterName)
+func p0upgradeK8sVersionChecks(f *helpers.Fixture) {
+	helpers.ClusterIsReadyChecks(f.Cluster, f.Client, f.ClusterName)
 
 	// Default version is highest supported version
-	upgradeToVersion, err := helper.GetK8sVersion(client, false)
+	var err error
+	f.UpgradeToVersion, err = helper.GetK8sVersion(f.Client, false)
 	Expect(err).To(BeNil())
-	GinkgoLogr.Info(fmt.Sprintf("Upgrading cluster to EKS version %s", upgradeToVersion))
+	GinkgoLogr.Info(fmt.Sprintf("Upgrading cluster to EKS version %s", f.UpgradeToVersion))
 
 	By("upgrading the ControlPlane", func() {
-		cluster, err = helper.UpgradeClusterKubernetesVersion(cluster, upgradeToVersion, client, true)
+		f.Cluster, err = helper.UpgradeClusterKubernetesVersion(f.Cluster, f.UpgradeToVersion, f.Client, true)
 		Expect(err).To(BeNil())
 	})
 
 	By("upgrading the NodeGroups", func() {
-		cluster, err = helper.UpgradeNodeKubernetesVersion(cluster, upgradeToVersion, client, true, true, helpers.IsImport)
+		f.Cluster, err = helper.UpgradeNodeKubernetesVersion(f.Cluster, f.UpgradeToVersion, f.Client, true, true, helpers.IsImport)
 		Expect(err).To(BeNil())
 	})
 }
 
-func p0NodesChecks(cluster *management.Cluster, client *rancher.Client, clusterName string) {
-	helpers.ClusterIsReadyChecks(cluster, client, clusterName)
-	configNodeGroups := *cluster.EKSConfig.NodeGroups
+func p0NodesChecks(f *helpers.Fixture) {
+	helpers.ClusterIsReadyChecks(f.Cluster, f.Client, f.ClusterName)
+	helpers.WorkloadChecks(f.Cluster, f.Client, helper.WorkloadOptions())
+	configNodeGroups := *f.Cluster.EKSConfig.NodeGroups
 	initialNodeCount := *configNodeGroups[0].DesiredSize
 
 	By("scaling up the NodeGroup", func() {
 		var err error
-		cluster, err = helper.ScaleNodeGroup(cluster, client, initialNodeCount+increaseBy, true, true)
+		f.Cluster, err = helper.ScaleNodeGroup(f.Cluster, f.Client, initialNodeCount+increaseBy, true, true)
 		Expect(err).To(BeNil())
 	})
 
 	By("scaling down the NodeGroup", func() {
 		var err error
-		cluster, err = helper.ScaleNodeGroup(cluster, client, initialNodeCount, true, true)
+		f.Cluster, err = helper.ScaleNodeGroup(f.Cluster, f.Client, initialNodeCount, true, true)
 		Expect(err).To(BeNil())
 	})
 
 	By("adding a NodeGroup", func() {
 		var err error
-		cluster, err = helper.AddNodeGroup(cluster, increaseBy, client, true, true)
+		f.Cluster, err = helper.AddNodeGroup(f.Cluster, increaseBy, f.Client, true, true)
 		Expect(err).To(BeNil())
 	})
 	By("deleting the NodeGroup", func() {
 		var err error
-		cluster, err = helper.DeleteNodeGroup(cluster, client, true, true)
+		f.Cluster, err = helper.DeleteNodeGroup(f.Cluster, f.Client, true, true)
 		Expect(err).To(BeNil())
 	})
+
+	if helpers.GetEnvironmentProfile().Proxy.Enabled {
+		By("checking that the operator routes through the proxy", func() {
+			helpers.ProxyRoutingChecks()
+		})
+	}
 }
@@ -8,6 +8,7 @@ import (
 	namegen "github.com/rancher/shepherd/pkg/namegenerator"
 
 	"github.com/rancher/hosted-providers-e2e/hosted/eks/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/eks/helper/ekscloud"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
 )
 
@@ -57,7 +58,7 @@ var _ = Describe("P1Import", func() {
 		When("a cluster is imported", func() {
 
 			BeforeEach(func() {
-				err := helper.CreateEKSClusterOnAWS(region, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
+				err := helper.CreateEKSClusterOnAWS(region, clusterName, k8sVersion, 1, helpers.GetCommonMetadataLabels(), nil)
 				Expect(err).To(BeNil())
 				cluster, err = helper.ImportEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, region)
 				Expect(err).To(BeNil())
@@ -85,7 +86,9 @@ var _ = Describe("P1Import", func() {
 
 	It("should successfully Import cluster with ONLY control plane", func() {
 		testCaseID = 94
-		err := helper.CreateEKSClusterOnAWS(region, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels(), "--without-nodegroup")
+		err := helper.CreateEKSClusterOnAWS(region, clusterName, k8sVersion, 1, helpers.GetCommonMetadataLabels(), func(spec *ekscloud.ClusterSpec) {
+			spec.WithoutNodegroup = true
+		})
 		Expect(err).To(BeNil())
 		cluster, err = helper.ImportEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, region)
 		Expect(err).To(BeNil())
@@ -105,7 +108,9 @@ var _ = Describe("P1Import", func() {
 
 	It("successfully import EKS cluster with self-managed nodes", func() {
 		testCaseID = 107
-		err := helper.CreateEKSClusterOnAWS(region, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels(), "--managed=false")
+		err := helper.CreateEKSClusterOnAWS(region, clusterName, k8sVersion, 1, helpers.GetCommonMetadataLabels(), func(spec *ekscloud.ClusterSpec) {
+			spec.SelfManagedNodes = true
+		})
 		Expect(err).To(BeNil())
 		cluster, err = helper.ImportEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, region)
 		Expect(err).To(BeNil())
@@ -115,7 +120,7 @@ var _ = Describe("P1Import", func() {
 
 	When("a cluster with multiple nodegroups is imported", func() {
 		BeforeEach(func() {
-			err := helper.CreateEKSClusterOnAWS(region, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
+			err := helper.CreateEKSClusterOnAWS(region, clusterName, k8sVersion, 1, helpers.GetCommonMetadataLabels(), nil)
 			Expect(err).To(BeNil())
 			for i := 0; i < 2; i++ {
 				err = helper.AddNodeGroupOnAWS(namegen.AppendRandomString("ng"), clusterName, region)
@@ -136,7 +141,7 @@ var _ = Describe("P1Import", func() {
 	When("a cluster is imported", func() {
 
 		var _ = BeforeEach(func() {
-			err := helper.CreateEKSClusterOnAWS(region, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
+			err := helper.CreateEKSClusterOnAWS(region, clusterName, k8sVersion, 1, helpers.GetCommonMetadataLabels(), nil)
 			Expect(err).To(BeNil())
 			cluster, err = helper.ImportEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, region)
 			Expect(err).To(BeNil())
@@ -210,7 +215,7 @@ var _ = Describe("P1Import", func() {
 				var err error
 				err = helper.DeleteEKSHostCluster(cluster, ctx.RancherAdminClient)
 				Expect(err).To(BeNil())
-				err = helper.ModifyEKSNodegroupOnAWS(region, clusterName, "ranchernodes", "delete", "--wait")
+				err = helper.DeleteNodeGroupOnAWS(region, clusterName, ekscloud.DefaultNodegroupName)
 				Expect(err).To(BeNil())
 
 				cluster, err = helper.ImportEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, region)
@@ -194,11 +194,27 @@ var _ = Describe("P1Provisioning", func() {
 		Expect(err).To(BeNil())
 
 		helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
-		var amiID string
-		amiID, err = helper.GetFromEKS(region, clusterName, "nodegroup", ".[].ImageID", "--name", gpuNodeName)
+		gpuNodeGroup, err := helper.DescribeEKSNodeGroupOnAWS(region, clusterName, gpuNodeName)
 		Expect(err).To(BeNil())
-		GinkgoLogr.Info(fmt.Sprintf("Used AMI for GPU enabled nodegroup in EKS cluster: %s", amiID))
-		Expect(amiID).To(Or(Equal("AL2_x86_64_GPU"), Equal("AL2023_x86_64_NVIDIA")))
+		GinkgoLogr.Info(fmt.Sprintf("Used AMI for GPU enabled nodegroup in EKS cluster: %s", gpuNodeGroup.AmiType))
+		Expect(gpuNodeGroup.AmiType).To(Or(Equal("AL2_x86_64_GPU"), Equal("AL2023_x86_64_NVIDIA")))
+	})
+
+	It("should enforce the network policies once the network policy agent of the VPC CNI is enabled", func() {
+		var err error
+		cluster, err = helper.CreateEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, region, nil)
+		Expect(err).To(BeNil())
+		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+		Expect(err).To(BeNil())
+
+		By("checking the network policies are not enforced by default", func() {
+			helpers.NetworkPolicyChecks(cluster, ctx.RancherAdminClient, helper.WorkloadOptions(), false)
+		})
+
+		By("enabling the network policy agent", func() {
+			Expect(helper.EnableNetworkPolicyOnAWS(clusterName, region)).To(Succeed())
+		})
+		helpers.NetworkPolicyChecks(cluster, ctx.RancherAdminClient, helper.WorkloadOptions(), true)
 	})
 
 	XIt("Deploy a cluster with Public/Priv access then disable Public access", func() {
@@ -312,34 +328,41 @@ var _ = Describe("P1Provisioning", func() {
 		})
 	})
 
-	When("a cluster is created", func() {
+	When("a cluster is leased from the pool", func() {
+		var (
+			f     *helpers.Fixture
+			lease *helpers.Lease
+		)
 
 		BeforeEach(func() {
-			var err error
-			cluster, err = helper.CreateEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, region, nil)
-			Expect(err).To(BeNil())
-			cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
-			Expect(err).To(BeNil())
+			f = helpers.NewFixture(&ctx)
+			lease = clusterPool.Lease(f)
 		})
 
 		It("Update cluster logging types", func() {
 			testCaseID = 128
-			updateLoggingCheck(cluster, ctx.RancherAdminClient)
+			// the logging types are not part of the baseline of the pool clusters
+			lease.Retire("the logging types have been updated")
+			updateLoggingCheck(f.Cluster, f.Client)
 		})
 
 		It("Update Tags and Labels", func() {
 			testCaseID = 131
-			updateTagsAndLabels(cluster, ctx.RancherAdminClient)
+			updateTagsAndLabels(f.Cluster, f.Client)
 		})
 
 		It("Update the cloud creds", func() {
 			testCaseID = 109
-			updateCloudCredentialsCheck(cluster, ctx.RancherAdminClient)
+			updateCloudCredentialsCheck(f.Cluster, f.Client)
+		})
+
+		It("should report the failures of its cloud credential and recover once it is valid again", func() {
+			f.Cluster = helpers.CloudCredentialLifecycleChecks(f.Cluster, f.Client, helper.CredentialOperations())
 		})
 
 		It("should fail to Delete all Node groups", func() {
 			testCaseID = 134
-			deleteAllNodeGroupsCheck(cluster, ctx.RancherAdminClient)
+			deleteAllNodeGroupsCheck(f.Cluster, f.Client)
 		})
 	})
 })
@@ -17,7 +17,6 @@ package p1_test
 import (
 	"fmt"
 	"maps"
-	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -44,6 +43,8 @@ var (
 	clusterName string
 	testCaseID  int64
 	region      = helpers.GetEKSRegion()
+	// clusterPool leases the default shaped clusters of the specs which do not need a cluster of their own
+	clusterPool *helpers.ClusterPool
 )
 
 func TestP1(t *testing.T) {
@@ -53,9 +54,17 @@ func TestP1(t *testing.T) {
 
 var _ = SynchronizedBeforeSuite(func() []byte {
 	helpers.CommonSynchronizedBeforeSuite()
-	return nil
-}, func() {
+	return helpers.CreateClusterPool(helper.PoolProvisioner(region))
+}, func(data []byte) {
 	ctx = helpers.CommonBeforeSuite()
+	clusterPool = helpers.OpenClusterPool(data, &ctx, helper.PoolProvisioner(region))
+})
+
+var _ = SynchronizedAfterSuite(func() {}, func() {
+	// the pool is not created when the BeforeSuite failed
+	if clusterPool != nil {
+		clusterPool.Drain()
+	}
 })
 
 var _ = BeforeEach(func() {
@@ -255,9 +264,8 @@ func syncAWSToRancherCheck(cluster *management.Cluster, client *rancher.Client,
 	var nodeName = namegen.AppendRandomString("ng")
 	ngCount := len(*cluster.EKSStatus.UpstreamSpec.NodeGroups)
 	if helpers.IsImport {
-		// The following error is encountered when adding nodegroup to a non-eksctl managed i.e. rancher provisioned cluster; so we skip it for now
-		// Error: loading VPC spec for cluster "auto-eks-hp-ci-atiia": VPC configuration required for creating nodegroups on clusters not owned by eksctl: vpc.subnets, vpc.id, vpc.securityGroup
-		// If this is implemented for rancher-provisioned cluster; make sure to check for Config spec.
+		// The nodegroup is only added on AWS to an imported cluster, it reuses the subnets and node role of the existing nodegroups;
+		// if this is implemented for rancher-provisioned cluster; make sure to check for Config spec.
 		By("adding a NodeGroup", func() {
 			err := helper.AddNodeGroupOnAWS(nodeName, clusterName, region)
 			Expect(err).To(BeNil())
@@ -288,7 +296,7 @@ func syncAWSToRancherCheck(cluster *management.Cluster, client *rancher.Client,
 			configNodeGroups := *cluster.EKSConfig.NodeGroups
 			nodeName = *configNodeGroups[1].NodegroupName
 		}
-		err := helper.ModifyEKSNodegroupOnAWS(region, clusterName, nodeName, "delete", "--wait")
+		err := helper.DeleteNodeGroupOnAWS(region, clusterName, nodeName)
 		Expect(err).To(BeNil())
 		Eventually(func() bool {
 			cluster, err = client.Management.Cluster.ByID(cluster.ID)
@@ -486,18 +494,14 @@ func syncRancherToAWSCheck(cluster *management.Cluster, client *rancher.Client,
 		}
 
 		// Verify the new edits reflect in AWS and existing details do NOT change
-		var out string
-		out, err = helper.GetFromEKS(region, clusterName, "cluster", "'.[]|.Version'")
+		awsCluster, err := helper.DescribeEKSClusterOnAWS(region, clusterName)
 		Expect(err).To(BeNil())
-		Expect(out).To(Equal(upgradeToVersion))
+		Expect(awsCluster.Version).To(Equal(upgradeToVersion))
 
-		out, err = helper.GetFromEKS(region, clusterName, "nodegroup", "'.|length'")
+		awsNodeGroups, err := helper.ListEKSNodeGroupsOnAWS(region, clusterName)
 		Expect(err).To(BeNil())
-		Expect(strconv.Atoi(out)).To(Equal(currentNodeGroupNumber))
-
-		out, err = helper.GetFromEKS(region, clusterName, "nodegroup", "'.[]|.DesiredCapacity'")
-		Expect(err).To(BeNil())
-		Expect(strconv.ParseInt(out, 10, 64)).To(Equal(initialNodeCount + 1))
+		Expect(awsNodeGroups).To(HaveLen(currentNodeGroupNumber))
+		Expect(awsNodeGroups[0].Scaling.DesiredSize).To(Equal(initialNodeCount + 1))
 	})
 
 	By("adding a NodeGroup", func() {
@@ -509,14 +513,13 @@ func syncRancherToAWSCheck(cluster *management.Cluster, client *rancher.Client,
 		Expect(*cluster.EKSConfig.LoggingTypes).ShouldNot(HaveExactElements(loggingTypes))
 
 		// Verify the new edits reflect in AWS console and existing details do NOT change
-		var out string
-		out, err = helper.GetFromEKS(region, clusterName, "cluster", "'.[]|.Version'")
+		awsCluster, err := helper.DescribeEKSClusterOnAWS(region, clusterName)
 		Expect(err).To(BeNil())
-		Expect(out).To(Equal(upgradeToVersion))
+		Expect(awsCluster.Version).To(Equal(upgradeToVersion))
 
-		out, err = helper.GetFromEKS(region, clusterName, "nodegroup", "'.|length'")
+		awsNodeGroups, err := helper.ListEKSNodeGroupsOnAWS(region, clusterName)
 		Expect(err).To(BeNil())
-		Expect(strconv.Atoi(out)).To(Equal(currentNodeGroupNumber + 1))
+		Expect(awsNodeGroups).To(HaveLen(currentNodeGroupNumber + 1))
 	})
 
 	By("Adding the LoggingTypes", func() {
@@ -528,14 +531,13 @@ func syncRancherToAWSCheck(cluster *management.Cluster, client *rancher.Client,
 		Expect(len(*cluster.EKSConfig.NodeGroups)).To(Equal(currentNodeGroupNumber + 1))
 
 		// Verify the new edits reflect in AWS console and existing details do NOT change
-		var out string
-		out, err = helper.GetFromEKS(region, clusterName, "nodegroup", "'.|length'")
+		awsNodeGroups, err := helper.ListEKSNodeGroupsOnAWS(region, clusterName)
 		Expect(err).To(BeNil())
-		Expect(strconv.Atoi(out)).To(Equal(currentNodeGroupNumber + 1))
+		Expect(awsNodeGroups).To(HaveLen(currentNodeGroupNumber + 1))
 
-		out, err = helper.GetFromEKS(region, clusterName, "cluster", "'.[]|.Logging|.[]|.[]|.Types'")
+		awsCluster, err := helper.DescribeEKSClusterOnAWS(region, clusterName)
 		Expect(err).To(BeNil())
-		Expect(out).ShouldNot(HaveExactElements(loggingTypes))
+		Expect(awsCluster.LoggingTypes).ShouldNot(HaveExactElements(loggingTypes))
 	})
 
 }
@@ -702,21 +704,9 @@ func updateLoggingCheck(cluster *management.Cluster, client *rancher.Client) {
 
 // Automates Qase: 109 and 155
 func updateCloudCredentialsCheck(cluster *management.Cluster, client *rancher.Client) {
-	newCCID, err := helpers.CreateCloudCredentials(client)
-	Expect(err).To(BeNil())
-	updateFunc := func(cluster *management.Cluster) {
-		cluster.EKSConfig.AmazonCredentialSecret = newCCID
-	}
-	cluster, err = helper.UpdateCluster(cluster, client, updateFunc)
-	Expect(err).To(BeNil())
-	Expect(cluster.EKSConfig.AmazonCredentialSecret).To(Equal(newCCID))
-	Eventually(func() bool {
-		cluster, err = client.Management.Cluster.ByID(cluster.ID)
-		Expect(err).NotTo(HaveOccurred())
-		return cluster.EKSStatus.UpstreamSpec.AmazonCredentialSecret == newCCID
-	}, "5m", "5s").Should(BeTrue(), "Failed while upstream cloud credentials update")
+	cluster, _ = helpers.RotateCloudCredential(cluster, client)
 
-	cluster, err = helper.ScaleNodeGroup(cluster, client, 3, true, true)
+	cluster, err := helper.ScaleNodeGroup(cluster, client, 3, true, true)
 	Expect(err).To(BeNil())
 }
 
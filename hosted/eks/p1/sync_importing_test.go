@@ -40,7 +40,7 @@ var _ = Describe("SyncImport", func() {
 			upgradeToVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, false)
 			Expect(err).To(BeNil())
 			GinkgoLogr.Info(fmt.Sprintf("Using kubernetes version %s for cluster %s", k8sVersion, clusterName))
-			err = helper.CreateEKSClusterOnAWS(region, clusterName, k8sVersion, "1", helpers.GetCommonMetadataLabels())
+			err = helper.CreateEKSClusterOnAWS(region, clusterName, k8sVersion, 1, helpers.GetCommonMetadataLabels(), nil)
 			Expect(err).To(BeNil())
 
 			cluster, err = helper.ImportEKSHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, region)
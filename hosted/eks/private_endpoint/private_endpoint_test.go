@@ -0,0 +1,68 @@
+/*
+Copyright © 2022 - 2025 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package private_endpoint_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher/shepherd/extensions/clusters/eks"
+	"k8s.io/utils/pointer"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/eks/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var _ = Describe("PrivateEndpoint", func() {
+	var f *helpers.Fixture
+
+	BeforeEach(func() {
+		helpers.SkipUnsupportedPrivateClusters(ctx.RancherAdminClient)
+
+		f = helpers.NewFixture(&ctx)
+		f.AddClusterCleanup(func() {
+			if f.Cluster != nil && f.Cluster.ID != "" {
+				GinkgoLogr.Info(fmt.Sprintf("Cleaning up resource cluster: %s %s", f.Cluster.Name, f.Cluster.ID))
+				err := helper.DeleteEKSHostCluster(f.Cluster, f.Client)
+				Expect(err).To(BeNil())
+			}
+		})
+		var err error
+		f.K8sVersion, err = helper.GetK8sVersion(f.Client, false)
+		Expect(err).To(BeNil())
+		GinkgoLogr.Info(fmt.Sprintf("While provisioning, using K8s version %s for cluster %s", f.K8sVersion, f.ClusterName))
+
+		createFunc := func(clusterConfig *eks.ClusterConfig) {
+			clusterConfig.PublicAccess = pointer.Bool(false)
+			clusterConfig.PrivateAccess = pointer.Bool(true)
+		}
+		f.Cluster, err = helper.CreateEKSHostedCluster(f.Client, f.ClusterName, ctx.CloudCredID, f.K8sVersion, region, createFunc)
+		Expect(err).To(BeNil())
+	})
+
+	It("should register a cluster with a private endpoint only and reach it through the agent tunnel only", func() {
+		f.Cluster = helpers.RegisterPrivateCluster(f.Cluster, f.Client, func(command string) (string, error) {
+			return helper.RunCommandOnPrivateCluster(f.ClusterName, region, command)
+		})
+		helpers.ClusterIsReadyChecks(f.Cluster, f.Client, f.ClusterName)
+
+		// the endpoint of a cluster without public access resolves to the private addresses of the API server
+		awsCluster, err := helper.DescribeEKSClusterOnAWS(region, f.ClusterName)
+		Expect(err).To(BeNil())
+		Expect(awsCluster.VPCAccess.PublicAccess).To(BeFalse(), "the public endpoint opened for the registration is closed again")
+		helpers.PrivateEndpointChecks(f.Cluster, f.Client, awsCluster.Endpoint)
+	})
+})
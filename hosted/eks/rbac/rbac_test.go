@@ -0,0 +1,64 @@
+/*
+Copyright © 2022 - 2025 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rbac_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/eks/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/rbac"
+)
+
+var _ = Describe("RBACMatrix", func() {
+	var f *helpers.Fixture
+
+	BeforeEach(func() {
+		if helpers.SkipUpgradeTests {
+			Skip(helpers.SkipUpgradeTestsLog)
+		}
+
+		f = helpers.NewFixture(&ctx)
+		f.AddClusterCleanup(func() {
+			if f.Cluster != nil && f.Cluster.ID != "" {
+				GinkgoLogr.Info(fmt.Sprintf("Cleaning up resource cluster: %s %s", f.Cluster.Name, f.Cluster.ID))
+				err := helper.DeleteEKSHostCluster(f.Cluster, f.Client)
+				Expect(err).To(BeNil())
+			}
+		})
+		var err error
+		f.K8sVersion, err = helper.GetK8sVersion(f.Client, true)
+		Expect(err).To(BeNil())
+		GinkgoLogr.Info(fmt.Sprintf("While provisioning, using K8s version %s for cluster %s", f.K8sVersion, f.ClusterName))
+
+		f.Cluster, err = helper.CreateEKSHostedCluster(f.Client, f.ClusterName, ctx.CloudCredID, f.K8sVersion, region, nil)
+		Expect(err).To(BeNil())
+		f.Cluster, err = helpers.WaitUntilClusterIsReady(f.Cluster, f.Client)
+		Expect(err).To(BeNil())
+	})
+
+	It("should allow or deny the cluster operations as expected for each Rancher role", func() {
+		// Default version is highest supported version
+		var err error
+		f.UpgradeToVersion, err = helper.GetK8sVersion(f.Client, false)
+		Expect(err).To(BeNil())
+
+		helpers.RBACMatrixChecks(f, helper.RBACOperations(f.K8sVersion, f.UpgradeToVersion, region), rbac.Matrix)
+		helpers.ClusterIsReadyChecks(f.Cluster, f.Client, f.ClusterName)
+	})
+})
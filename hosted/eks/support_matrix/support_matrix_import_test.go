@@ -39,8 +39,9 @@ var _ = Describe("SupportMatrixImport", func() {
 			)
 			BeforeEach(func() {
 				clusterName = namegen.AppendRandomString(helpers.ClusterNamePrefix)
+				helpers.GuardClusterCost(clusterName)
 				var err error
-				err = helper.CreateEKSClusterOnAWS(region, clusterName, version, "1", helpers.GetCommonMetadataLabels())
+				err = helper.CreateEKSClusterOnAWS(region, clusterName, version, 1, helpers.GetCommonMetadataLabels(), nil)
 				Expect(err).To(BeNil())
 				cluster, err = helper.ImportEKSHostedCluster(ctx.StdUserClient, clusterName, ctx.CloudCredID, region)
 				Expect(err).To(BeNil())
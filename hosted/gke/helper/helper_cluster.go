@@ -2,7 +2,6 @@ package helper
 
 import (
 	"fmt"
-	"os"
 	"strings"
 	"time"
 
@@ -487,18 +486,15 @@ func CreateGKEClusterOnGCloud(zone string, clusterName string, project string, k
 	labels := helpers.GetCommonMetadataLabels()
 	labelsAsString := k8slabels.SelectorFromSet(labels).String()
 
-	// creating GKE using gcloud changes the kubeconfig to use GKE; this can be problematic for test cases that need to use local cluster;
-	// this workaround helps to keep the original kubeconfig
-	currentKubeconfig := os.Getenv("KUBECONFIG")
-	defer os.Setenv("KUBECONFIG", currentKubeconfig)
-
-	helpers.SetTempKubeConfig(clusterName)
+	// creating GKE using gcloud writes the cluster credentials to KUBECONFIG; this can be problematic for test cases that need to use local cluster,
+	// so gcloud is given a kubeconfig file of its own
+	kubeconfig := helpers.DownstreamKubeconfig(clusterName)
 
 	fmt.Println("Creating GKE cluster ...")
 	args := []string{"container", "clusters", "create", clusterName, "--project", project, "--zone", zone, "--cluster-version", k8sVersion, "--labels", labelsAsString, "--network", "default", "--release-channel", "None", "--machine-type", "n2-standard-2", "--disk-size", "100", "--num-nodes", "1", "--no-enable-master-authorized-networks"}
 	args = append(args, extraArgs...)
 	fmt.Printf("Running command: gcloud %v\n", args)
-	out, err := proc.RunW("gcloud", args...)
+	out, err := kubeconfig.Run("gcloud", args...)
 	if err != nil {
 		return errors.Wrap(err, "Failed to create cluster: "+out)
 	}
@@ -557,6 +553,18 @@ func DeleteNodePoolOnGCloud(zone, project, clusterName, poolName string) error {
 	return nil
 }
 
+// ListNodePoolsOnGCloud lists the names of the node pools of a GKE cluster using gcloud cli
+func ListNodePoolsOnGCloud(zone, project, clusterName string) ([]string, error) {
+	fmt.Println("Listing node pools of GKE cluster ...")
+	args := []string{"container", "node-pools", "list", "--cluster", clusterName, "--project", project, "--zone", zone, "--format", "value(name)"}
+	fmt.Printf("Running command: gcloud %v\n", args)
+	out, err := proc.RunW("gcloud", args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list node pools: "+out)
+	}
+	return strings.Fields(out), nil
+}
+
 // UpgradeGKEClusterOnGCloud upgrades the k8s version of a given GKE cluster; if upgradeNodePool is true, it only upgrades the nodepool version
 func UpgradeGKEClusterOnGCloud(zone, clusterName, project, k8sVersion string, upgradeNodePool bool, nodePoolName string, exrtaArgs ...string) error {
 	args := []string{"container", "clusters", "upgrade", clusterName, "--cluster-version", k8sVersion, "--project", project, "--zone", zone, "--quiet"}
@@ -593,18 +601,16 @@ func UpgradeGKEClusterOnGCloud(zone, clusterName, project, k8sVersion string, up
 
 // Complete cleanup steps for Google GKE
 func DeleteGKEClusterOnGCloud(zone, project, clusterName string) error {
-	currentKubeconfig := os.Getenv("KUBECONFIG")
-	downstreamKubeconfig := os.Getenv(helpers.DownstreamKubeconfig(clusterName))
+	// gcloud removes the cluster credentials from KUBECONFIG, it is given the kubeconfig file the cluster was created with
+	kubeconfig := helpers.DownstreamKubeconfig(clusterName)
 	defer func() {
-		_ = os.Setenv("KUBECONFIG", currentKubeconfig)
-		_ = os.Remove(downstreamKubeconfig) // clean up
+		_ = helpers.Kubeconfigs.Release(clusterName) // clean up
 	}()
-	_ = os.Setenv("KUBECONFIG", downstreamKubeconfig)
 
 	fmt.Println("Deleting GKE cluster ...")
 	args := []string{"container", "clusters", "delete", clusterName, "--zone", zone, "--quiet", "--project", project, "--async"}
 	fmt.Printf("Running command: gcloud %v\n", args)
-	out, err := proc.RunW("gcloud", args...)
+	out, err := kubeconfig.Run("gcloud", args...)
 	if err != nil {
 		return errors.Wrap(err, "Failed to delete cluster: "+out)
 	}
@@ -614,6 +620,47 @@ func DeleteGKEClusterOnGCloud(zone, project, clusterName string) error {
 	return nil
 }
 
+// RunCommandThroughConnectGateway runs a shell command against a cluster whose API endpoint is private and returns its output; GKE has no command invoke,
+// so the cluster is registered to the fleet of project and the command is given the credentials of the Connect gateway, which reaches the API from Google's network
+func RunCommandThroughConnectGateway(clusterName, zone, project, command string) (string, error) {
+	kubeconfig := helpers.DownstreamKubeconfig(clusterName)
+	defer func() {
+		_ = helpers.Kubeconfigs.Release(clusterName) // clean up
+	}()
+
+	fmt.Println("Registering GKE cluster to the fleet ...")
+	args := []string{"container", "clusters", "update", clusterName, "--zone", zone, "--project", project, "--fleet-project", project}
+	fmt.Printf("Running command: gcloud %v\n", args)
+	out, err := kubeconfig.Run("gcloud", args...)
+	if err != nil && !strings.Contains(out, "already registered") {
+		return out, errors.Wrap(err, "Failed to register cluster to the fleet: "+out)
+	}
+
+	args = []string{"container", "fleet", "memberships", "get-credentials", clusterName, "--project", project}
+	fmt.Printf("Running command: gcloud %v\n", args)
+	if out, err = kubeconfig.Run("gcloud", args...); err != nil {
+		return out, errors.Wrap(err, "Failed to get the Connect gateway credentials: "+out)
+	}
+
+	fmt.Printf("Running command against the cluster: %s\n", command)
+	out, err = kubeconfig.Run("sh", "-c", command)
+	if err != nil {
+		return out, errors.Wrap(err, "Failed to run command: "+out)
+	}
+	return out, nil
+}
+
+// APIServerEndpointsOnGCloud returns the public and private endpoints of the control plane of a private cluster
+func APIServerEndpointsOnGCloud(clusterName, zone, project string) ([]string, error) {
+	args := []string{"container", "clusters", "describe", clusterName, "--zone", zone, "--project", project, "--format", "value(privateClusterConfig.publicEndpoint,privateClusterConfig.privateEndpoint)"}
+	fmt.Printf("Running command: gcloud %v\n", args)
+	out, err := proc.RunW("gcloud", args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to describe cluster: "+out)
+	}
+	return strings.Fields(out), nil
+}
+
 // EnableDisableServiceAccountOnGCloud can enable/disable a service account via gcloud cli
 func EnableDisableServiceAccountOnGCloud(clientID, project, op string) error {
 	if !(op == "enable" || op == "disable") {
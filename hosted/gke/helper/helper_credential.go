@@ -0,0 +1,43 @@
+package helper
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rancher/shepherd/clients/rancher"
+	"github.com/rancher/shepherd/extensions/cloudcredentials"
+	"github.com/rancher/shepherd/extensions/cloudcredentials/google"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+// ExpiringServiceAccount is the service account of SECONDARY_GCP_CREDENTIALS, it is disabled to simulate the expiry of its key
+const ExpiringServiceAccount = "hosted-providers-ci-creds-test"
+
+// CredentialOperations returns the operations of the cloud credential lifecycle checks on GKE clusters of project
+func CredentialOperations(project string) helpers.CredentialOperations {
+	return helpers.CredentialOperations{
+		Trigger: toggleNodeCount,
+		Expiry:  CredentialExpiry(project),
+	}
+}
+
+// CredentialExpiry simulates the expiry of the cloud credential of SECONDARY_GCP_CREDENTIALS by disabling its service account in project
+func CredentialExpiry(project string) *helpers.CredentialExpiry {
+	return &helpers.CredentialExpiry{
+		Create: func(client *rancher.Client) (string, error) {
+			cloudCredentialConfig := cloudcredentials.CloudCredential{GoogleCredentialConfig: &cloudcredentials.GoogleCredentialConfig{AuthEncodedJSON: os.Getenv("SECONDARY_GCP_CREDENTIALS")}}
+			cloudCredential, err := google.CreateGoogleCloudCredentials(client, cloudCredentialConfig)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%s:%s", cloudCredential.Namespace, cloudCredential.Name), nil
+		},
+		Expire: func() error {
+			return EnableDisableServiceAccountOnGCloud(ExpiringServiceAccount, project, "disable")
+		},
+		Renew: func() error {
+			return EnableDisableServiceAccountOnGCloud(ExpiringServiceAccount, project, "enable")
+		},
+	}
+}
@@ -0,0 +1,85 @@
+package helper
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"github.com/rancher/shepherd/extensions/clusters"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/pool"
+)
+
+// PoolProvisioner returns the provisioner of a cluster pool of GKE clusters created from the default config
+func PoolProvisioner(zone, region, project string) helpers.ClusterProvisioner {
+	return helpers.ClusterProvisioner{
+		Shape: "default",
+		Create: func(client *rancher.Client, cloudCredID, clusterName string) (*management.Cluster, error) {
+			k8sVersion, err := GetK8sVersion(client, project, cloudCredID, zone, region, false)
+			if err != nil {
+				return nil, err
+			}
+			return CreateGKEHostedCluster(client, clusterName, cloudCredID, k8sVersion, zone, region, project, nil)
+		},
+		Baseline: poolBaseline,
+		Reset:    resetToBaseline,
+		Delete:   DeleteGKEHostCluster,
+	}
+}
+
+// poolBaseline returns the k8s version, node pools and labels of a cluster
+func poolBaseline(cluster *management.Cluster) pool.Baseline {
+	nodePools := *cluster.GKEConfig.NodePools
+	baseline := pool.Baseline{
+		KubernetesVersion: *cluster.GKEConfig.KubernetesVersion,
+		NodePools:         len(nodePools),
+	}
+	if len(nodePools) > 0 && nodePools[0].InitialNodeCount != nil {
+		baseline.NodeCount = *nodePools[0].InitialNodeCount
+	}
+	if cluster.GKEConfig.Labels != nil {
+		baseline.Tags = maps.Clone(*cluster.GKEConfig.Labels)
+	}
+	return baseline
+}
+
+// resetToBaseline deletes the node pools added to a cluster, scales it back and restores its labels;
+// an upgraded k8s version or a deleted node pool cannot be restored
+func resetToBaseline(cluster *management.Cluster, client *rancher.Client, baseline pool.Baseline) (*management.Cluster, error) {
+	current := poolBaseline(cluster)
+	if current.KubernetesVersion != baseline.KubernetesVersion {
+		return cluster, fmt.Errorf("k8s version %s has been changed to %s", baseline.KubernetesVersion, current.KubernetesVersion)
+	}
+	if current.NodePools < baseline.NodePools {
+		return cluster, fmt.Errorf("%d node pools cannot be restored to %d", current.NodePools, baseline.NodePools)
+	}
+
+	var err error
+	if current.NodePools > baseline.NodePools || !maps.Equal(current.Tags, baseline.Tags) {
+		// AddNodePool appends the new node pools, the ones of the baseline are kept; DeleteNodePool would delete the first one
+		cluster, err = UpdateCluster(cluster, client, func(cluster *management.Cluster) {
+			nodePools := (*cluster.GKEConfig.NodePools)[:baseline.NodePools]
+			cluster.GKEConfig.NodePools = &nodePools
+			labels := maps.Clone(baseline.Tags)
+			if labels == nil {
+				labels = map[string]string{}
+			}
+			cluster.GKEConfig.Labels = &labels
+		})
+		if err != nil {
+			return cluster, err
+		}
+		if err = clusters.WaitClusterToBeUpgraded(client, cluster.ID); err != nil {
+			return cluster, err
+		}
+	}
+	if current.NodeCount != baseline.NodeCount {
+		cluster, err = ScaleNodePool(cluster, client, baseline.NodeCount, true, false)
+		if err != nil {
+			return cluster, err
+		}
+	}
+	return cluster, nil
+}
@@ -0,0 +1,53 @@
+package helper
+
+import (
+	"maps"
+
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	namegen "github.com/rancher/shepherd/pkg/namegenerator"
+	"k8s.io/utils/pointer"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+// RBACOperations returns the operations of the RBAC matrix on GKE clusters: the created clusters run k8sVersion, the cluster under test is upgraded to upgradeToVersion
+func RBACOperations(k8sVersion, upgradeToVersion, zone, region, project string) helpers.RBACOperations {
+	return helpers.RBACOperations{
+		Create: func(client *rancher.Client, clusterName, cloudCredID string) (*management.Cluster, error) {
+			return CreateGKEHostedCluster(client, clusterName, cloudCredID, k8sVersion, zone, region, project, nil)
+		},
+		Import: func(client *rancher.Client, clusterName, cloudCredID string) (*management.Cluster, error) {
+			return ImportGKEHostedCluster(client, clusterName, cloudCredID, zone, project)
+		},
+		Edit: func(client *rancher.Client, cluster *management.Cluster) error {
+			_, err := UpdateCluster(cluster, client, func(upgradedCluster *management.Cluster) {
+				labels := map[string]string{}
+				if upgradedCluster.GKEConfig.Labels != nil {
+					maps.Copy(labels, *upgradedCluster.GKEConfig.Labels)
+				}
+				labels["rbac-edit"] = namegen.RandStringLower(5)
+				upgradedCluster.GKEConfig.Labels = &labels
+			})
+			return err
+		},
+		Scale: toggleNodeCount,
+		Upgrade: func(client *rancher.Client, cluster *management.Cluster) error {
+			_, err := UpdateCluster(cluster, client, func(upgradedCluster *management.Cluster) {
+				upgradedCluster.GKEConfig.KubernetesVersion = &upgradeToVersion
+			})
+			return err
+		},
+	}
+}
+
+// toggleNodeCount scales the node pools of the cluster to their toggled node count, see helpers.ToggledNodeCount
+func toggleNodeCount(client *rancher.Client, cluster *management.Cluster) error {
+	_, err := UpdateCluster(cluster, client, func(upgradedCluster *management.Cluster) {
+		nodePools := *upgradedCluster.GKEConfig.NodePools
+		for i := range nodePools {
+			nodePools[i].InitialNodeCount = pointer.Int64(helpers.ToggledNodeCount(*nodePools[i].InitialNodeCount))
+		}
+	})
+	return err
+}
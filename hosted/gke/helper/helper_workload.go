@@ -0,0 +1,10 @@
+package helper
+
+import (
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/workload"
+)
+
+// WorkloadOptions returns the options of the workload checks on GKE clusters: the default StorageClass and a network load balancer
+func WorkloadOptions() workload.Options {
+	return workload.Options{}
+}
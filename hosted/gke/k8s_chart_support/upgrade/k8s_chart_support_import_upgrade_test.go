@@ -5,7 +5,6 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
 
 	"github.com/rancher/hosted-providers-e2e/hosted/gke/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
@@ -13,35 +12,32 @@ import (
 
 var _ = Describe("K8sChartSupportUpgradeImport", func() {
 	var (
-		cluster *management.Cluster
+		f *helpers.Fixture
 	)
 
 	BeforeEach(func() {
-		err := helper.CreateGKEClusterOnGCloud(zone, clusterName, project, k8sVersion)
+		f = newFixture()
+		f.GuardCost()
+		err := helper.CreateGKEClusterOnGCloud(zone, f.ClusterName, project, f.K8sVersion)
 		Expect(err).To(BeNil())
 
-		cluster, err = helper.ImportGKEHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, zone, project)
+		f.Cluster, err = helper.ImportGKEHostedCluster(f.Client, f.ClusterName, ctx.CloudCredID, zone, project)
 		Expect(err).To(BeNil())
-		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
-		Expect(err).To(BeNil())
-	})
-
-	AfterEach(func() {
-		if ctx.ClusterCleanup && cluster != nil {
-			err := helper.DeleteGKEHostCluster(cluster, ctx.RancherAdminClient)
+		f.AddClusterCleanup(func() {
+			err := helper.DeleteGKEHostCluster(f.Cluster, f.Client)
 			Expect(err).To(BeNil())
-			err = helper.DeleteGKEClusterOnGCloud(zone, project, clusterName)
+			err = helper.DeleteGKEClusterOnGCloud(zone, project, f.ClusterName)
 			Expect(err).To(BeNil())
-		} else {
-			fmt.Println("Skipping downstream cluster deletion: ", clusterName)
-		}
+		})
+		f.Cluster, err = helpers.WaitUntilClusterIsReady(f.Cluster, f.Client)
+		Expect(err).To(BeNil())
 	})
 
 	It("should successfully test k8s chart support import in an upgrade scenario", func() {
 		GinkgoLogr.Info(fmt.Sprintf("Testing K8s %s chart support for import on Rancher upgraded from %s to %s", helpers.K8sUpgradedMinorVersion, helpers.RancherFullVersion, helpers.RancherUpgradeFullVersion))
 
-		testCaseID = 64 // Report to Qase
-		commonChartSupportUpgrade(&ctx, cluster, clusterName, helpers.RancherUpgradeFullVersion, helpers.K8sUpgradedMinorVersion)
+		f.SetQaseID(64) // Report to Qase
+		commonChartSupportUpgrade(f, helpers.RancherUpgradeFullVersion, helpers.K8sUpgradedMinorVersion)
 	})
 
 })
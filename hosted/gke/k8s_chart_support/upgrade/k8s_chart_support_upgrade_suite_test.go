@@ -17,19 +17,17 @@ import (
 	nodestat "github.com/rancher/shepherd/extensions/nodes"
 	"github.com/rancher/shepherd/extensions/workloads/pods"
 	"github.com/rancher/shepherd/pkg/config"
-	namegen "github.com/rancher/shepherd/pkg/namegenerator"
 
 	"github.com/rancher/hosted-providers-e2e/hosted/gke/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
 )
 
 var (
-	ctx                     helpers.RancherContext
-	clusterName, k8sVersion string
-	testCaseID              int64
-	zone                    = helpers.GetGKEZone()
-	project                 = helpers.GetGKEProjectID()
-	k                       = kubectl.New()
+	ctx         helpers.RancherContext
+	zone        = helpers.GetGKEZone()
+	project     = helpers.GetGKEProjectID()
+	k           = kubectl.New()
+	environment helpers.EnvironmentProfile
 )
 
 func TestK8sChartSupportUpgrade(t *testing.T) {
@@ -44,14 +42,28 @@ var _ = BeforeEach(func() {
 	Expect(helpers.RancherUpgradeFullVersion).ToNot(BeEmpty())
 	Expect(helpers.K8sUpgradedMinorVersion).ToNot(BeEmpty())
 	Expect(helpers.Kubeconfig).ToNot(BeEmpty())
+	helpers.ExpectNoOperatorOverride()
+	environment = helpers.StockEnvironmentProfile()
+
+	// Registered before the fixture of the spec, so that it runs once the downstream cluster has been deleted
+	DeferCleanup(func() {
+		// The test must restore the env to its original state, so we install rancher back to its original version and uninstall the operator charts
+		By(fmt.Sprintf("Installing Rancher back to its original version %s", helpers.RancherFullVersion), func() {
+			helpers.InstallRancherManager(k, environment)
+			helpers.CheckRancherDeployments(k)
+		})
+
+		By("Uninstalling the existing operator charts", func() {
+			helpers.UninstallOperatorCharts()
+		})
+	})
 
 	By("Adding the necessary chart repos", func() {
 		helpers.AddRancherCharts()
 	})
 
 	By(fmt.Sprintf("Installing Rancher Manager %s", helpers.RancherFullVersion), func() {
-		rancherChannel, rancherVersion, rancherHeadVersion := helpers.GetRancherVersions(helpers.RancherFullVersion)
-		helpers.InstallRancherManager(k, helpers.RancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, "", "")
+		helpers.InstallRancherManager(k, environment)
 		helpers.CheckRancherDeployments(k)
 	})
 
@@ -70,42 +82,27 @@ var _ = BeforeEach(func() {
 		Expect(err).To(BeNil())
 		ctx.RancherAdminClient = rancherAdminClient
 	})
+})
 
-	clusterName = namegen.AppendRandomString(helpers.ClusterNamePrefix)
-
+// newFixture returns the fixture of the current spec with the k8s version its cluster is created with
+func newFixture() *helpers.Fixture {
+	f := helpers.NewFixture(&ctx)
 	var err error
 	// For k8s chart support upgrade we want to begin with the default k8s version; we will upgrade rancher and then upgrade k8s to the default available there.
-	k8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, project, ctx.CloudCredID, zone, "", false)
+	f.K8sVersion, err = helper.GetK8sVersion(f.Client, project, ctx.CloudCredID, zone, "", false)
 	Expect(err).To(BeNil())
-	GinkgoLogr.Info(fmt.Sprintf("Using GKE version %s for cluster %s", k8sVersion, clusterName))
-})
-
-var _ = AfterEach(func() {
-	// The test must restore the env to its original state, so we install rancher back to its original version and uninstall the operator charts
-	By(fmt.Sprintf("Installing Rancher back to its original version %s", helpers.RancherFullVersion), func() {
-		rancherChannel, rancherVersion, rancherHeadVersion := helpers.GetRancherVersions(helpers.RancherFullVersion)
-		helpers.InstallRancherManager(k, helpers.RancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, "", "")
-		helpers.CheckRancherDeployments(k)
-	})
-
-	By("Uninstalling the existing operator charts", func() {
-		helpers.UninstallOperatorCharts()
-	})
-})
-
-var _ = ReportBeforeEach(func(report SpecReport) {
-	// Reset case ID
-	testCaseID = -1
-})
+	GinkgoLogr.Info(fmt.Sprintf("Using GKE version %s for cluster %s", f.K8sVersion, f.ClusterName))
+	return f
+}
 
 var _ = ReportAfterEach(func(report SpecReport) {
 	// Add result in Qase if asked
-	Qase(testCaseID, report)
+	Qase(helpers.QaseID(report), report)
 })
 
 // commonChartSupportUpgrade runs the common checks required for testing chart support
-func commonChartSupportUpgrade(ctx *helpers.RancherContext, cluster *management.Cluster, clusterName, rancherUpgradedVersion, k8sUpgradedVersion string) {
-	helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
+func commonChartSupportUpgrade(f *helpers.Fixture, rancherUpgradedVersion, k8sUpgradedVersion string) {
+	helpers.ClusterIsReadyChecks(f.Cluster, f.Client, f.ClusterName)
 
 	var originalChartVersion string
 	By("checking the chart version", func() {
@@ -115,8 +112,7 @@ func commonChartSupportUpgrade(ctx *helpers.RancherContext, cluster *management.
 	})
 
 	By("upgrading rancher", func() {
-		rancherChannel, rancherVersion, rancherHeadVersion := helpers.GetRancherVersions(rancherUpgradedVersion)
-		helpers.InstallRancherManager(k, helpers.RancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, "", "")
+		helpers.InstallRancherManager(k, environment.WithRancherVersion(rancherUpgradedVersion))
 		helpers.CheckRancherDeployments(k)
 
 		By("ensuring operator pods are also up", func() {
@@ -126,7 +122,7 @@ func commonChartSupportUpgrade(ctx *helpers.RancherContext, cluster *management.
 		})
 
 		By("ensuring the rancher client is connected", func() {
-			isConnected, err := ctx.RancherAdminClient.IsConnected()
+			isConnected, err := f.Client.IsConnected()
 			Expect(err).To(BeNil())
 			Expect(isConnected).To(BeTrue())
 		})
@@ -135,12 +131,12 @@ func commonChartSupportUpgrade(ctx *helpers.RancherContext, cluster *management.
 	By("making sure the local cluster is ready", func() {
 		const localClusterID = "local"
 		By("checking all management nodes are ready", func() {
-			err := nodestat.AllManagementNodeReady(ctx.RancherAdminClient, localClusterID, helpers.Timeout)
+			err := nodestat.AllManagementNodeReady(f.Client, localClusterID, helpers.Timeout)
 			Expect(err).To(BeNil())
 		})
 
 		By("checking all pods are ready", func() {
-			podErrors := pods.StatusPods(ctx.RancherAdminClient, localClusterID)
+			podErrors := pods.StatusPods(f.Client, localClusterID)
 			Expect(podErrors).To(BeEmpty())
 		})
 	})
@@ -156,23 +152,23 @@ func commonChartSupportUpgrade(ctx *helpers.RancherContext, cluster *management.
 
 	By("making sure the downstream cluster is ready", func() {
 		var err error
-		cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
+		f.Cluster, err = f.Client.Management.Cluster.ByID(f.Cluster.ID)
 		Expect(err).To(BeNil())
-		helpers.ClusterIsReadyChecks(cluster, ctx.RancherAdminClient, clusterName)
+		helpers.ClusterIsReadyChecks(f.Cluster, f.Client, f.ClusterName)
 
 		// since no changes have been made to the cluster so far, we need reinstantiate GKEConfig after fetching the cluster
 		if helpers.IsImport {
-			cluster.GKEConfig = cluster.GKEStatus.UpstreamSpec
+			f.Cluster.GKEConfig = f.Cluster.GKEStatus.UpstreamSpec
 		}
 	})
 
 	By(fmt.Sprintf("fetching a list of available k8s versions and ensuring v%s is present in the list and upgrading the cluster to it", k8sUpgradedVersion), func() {
-		versions, err := helper.ListGKEAvailableVersions(ctx.RancherAdminClient, cluster.ID)
+		versions, err := helper.ListGKEAvailableVersions(f.Client, f.Cluster.ID)
 		Expect(err).To(BeNil())
 		Expect(versions).ToNot(BeEmpty())
 		GinkgoLogr.Info(fmt.Sprintf("Available GKE versions: %v", versions))
 
-		highestSupportedVersionByUI := helpers.HighestK8sMinorVersionSupportedByUI(ctx.RancherAdminClient)
+		highestSupportedVersionByUI := helpers.HighestK8sMinorVersionSupportedByUI(f.Client)
 		var latestVersion string
 		for _, v := range versions {
 			if strings.Contains(v, highestSupportedVersionByUI) {
@@ -180,9 +176,9 @@ func commonChartSupportUpgrade(ctx *helpers.RancherContext, cluster *management.
 			}
 		}
 		Expect(latestVersion).To(ContainSubstring(k8sUpgradedVersion))
-		Expect(helpers.VersionCompare(latestVersion, cluster.Version.GitVersion)).To(BeNumerically("==", 1))
+		Expect(helpers.VersionCompare(latestVersion, f.Cluster.Version.GitVersion)).To(BeNumerically("==", 1))
 
-		cluster, err = helper.UpgradeKubernetesVersion(cluster, latestVersion, ctx.RancherAdminClient, true, true, true)
+		f.Cluster, err = helper.UpgradeKubernetesVersion(f.Cluster, latestVersion, f.Client, true, true, true)
 		Expect(err).To(BeNil())
 	})
 
@@ -196,10 +192,10 @@ func commonChartSupportUpgrade(ctx *helpers.RancherContext, cluster *management.
 	})
 
 	By("making a change to the cluster (scaling nodepool up) to validate functionality after chart downgrade", func() {
-		configNodePools := *cluster.GKEConfig.NodePools
+		configNodePools := *f.Cluster.GKEConfig.NodePools
 		initialNodeCount := *configNodePools[0].InitialNodeCount
 		var err error
-		cluster, err = helper.ScaleNodePool(cluster, ctx.RancherAdminClient, initialNodeCount+1, true, true)
+		f.Cluster, err = helper.ScaleNodePool(f.Cluster, f.Client, initialNodeCount+1, true, true)
 		Expect(err).To(BeNil())
 	})
 
@@ -208,25 +204,25 @@ func commonChartSupportUpgrade(ctx *helpers.RancherContext, cluster *management.
 	})
 
 	By("making a change(adding a nodepool) to the cluster to re-install the operator and validating it is re-installed to the latest/upgraded version", func() {
-		currentNodePoolNumber := len(*cluster.GKEConfig.NodePools)
+		currentNodePoolNumber := len(*f.Cluster.GKEConfig.NodePools)
 		var err error
-		cluster, err = helper.AddNodePool(cluster, ctx.RancherAdminClient, 1, "", false, false)
+		f.Cluster, err = helper.AddNodePool(f.Cluster, f.Client, 1, "", false, false)
 		Expect(err).To(BeNil())
 
-		Expect(len(*cluster.GKEConfig.NodePools)).To(BeNumerically("==", currentNodePoolNumber+1))
+		Expect(len(*f.Cluster.GKEConfig.NodePools)).To(BeNumerically("==", currentNodePoolNumber+1))
 
 		By("ensuring that the chart is re-installed to the latest/upgraded version", func() {
 			helpers.WaitUntilOperatorChartInstallation(upgradedChartVersion, "", 0)
 		})
 
-		err = clusters.WaitClusterToBeUpgraded(ctx.RancherAdminClient, cluster.ID)
+		err = clusters.WaitClusterToBeUpgraded(f.Client, f.Cluster.ID)
 		Expect(err).To(BeNil())
 
 		Eventually(func() int {
 			GinkgoLogr.Info("Waiting for the total nodepool count to increase in GKEStatus.UpstreamSpec ...")
-			cluster, err = ctx.RancherAdminClient.Management.Cluster.ByID(cluster.ID)
+			f.Cluster, err = f.Client.Management.Cluster.ByID(f.Cluster.ID)
 			Expect(err).To(BeNil())
-			return len(*cluster.GKEStatus.UpstreamSpec.NodePools)
+			return len(*f.Cluster.GKEStatus.UpstreamSpec.NodePools)
 		}, tools.SetTimeout(12*time.Minute), 10*time.Second).Should(BeNumerically("==", currentNodePoolNumber+1))
 
 	})
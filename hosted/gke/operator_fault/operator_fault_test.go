@@ -0,0 +1,97 @@
+/*
+Copyright © 2022 - 2025 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator_fault_test
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/gke/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/fault"
+)
+
+var _ = Describe("OperatorFault", func() {
+	var f *helpers.Fixture
+
+	BeforeEach(func() {
+		f = helpers.NewFixture(&ctx)
+		f.AddClusterCleanup(func() {
+			if f.Cluster != nil && f.Cluster.ID != "" {
+				GinkgoLogr.Info(fmt.Sprintf("Cleaning up resource cluster: %s %s", f.Cluster.Name, f.Cluster.ID))
+				err := helper.DeleteGKEHostCluster(f.Cluster, f.Client)
+				Expect(err).To(BeNil())
+			}
+		})
+	})
+
+	createCluster := func(forUpgrade bool) {
+		var err error
+		f.K8sVersion, err = helper.GetK8sVersion(f.Client, project, ctx.CloudCredID, zone, region, forUpgrade)
+		Expect(err).NotTo(HaveOccurred())
+		GinkgoLogr.Info(fmt.Sprintf("Using K8s version %s for cluster %s", f.K8sVersion, f.ClusterName))
+
+		f.Cluster, err = helper.CreateGKEHostedCluster(f.Client, f.ClusterName, ctx.CloudCredID, f.K8sVersion, zone, region, project, nil)
+		Expect(err).To(BeNil())
+		f.Cluster, err = helpers.WaitUntilClusterIsReady(f.Cluster, f.Client)
+		Expect(err).To(BeNil())
+	}
+
+	When("a cluster is created", func() {
+		BeforeEach(func() {
+			createCluster(false)
+		})
+
+		It("should converge when the operator is disrupted while adding a nodepool", func() {
+			for _, injection := range []fault.Injection{
+				{Action: fault.Kill, Point: fault.InProgress},
+				{Action: fault.ScaleDown, Point: fault.BeforeSubmit, Outage: 2 * time.Minute},
+			} {
+				By(fmt.Sprintf("adding a nodepool with the operator fault: %s", injection), func() {
+					f.Cluster = helpers.OperatorFaultChecks(f.Cluster, f.Client, injection, helper.FaultOperations(), func(cluster *management.Cluster) (*management.Cluster, error) {
+						return helper.AddNodePool(cluster, f.Client, 1, "", false, false)
+					})
+				})
+			}
+			helpers.ClusterIsReadyChecks(f.Cluster, f.Client, f.ClusterName)
+		})
+	})
+
+	When("a cluster is created for upgrade", func() {
+		BeforeEach(func() {
+			if helpers.SkipUpgradeTests {
+				Skip(helpers.SkipUpgradeTestsLog)
+			}
+			createCluster(true)
+		})
+
+		It("should converge when the operator is killed while upgrading the k8s version", func() {
+			versions, err := helper.ListGKEAvailableVersions(f.Client, f.Cluster.ID)
+			Expect(err).To(BeNil())
+			Expect(versions).ToNot(BeEmpty())
+			f.UpgradeToVersion = versions[0]
+
+			injection := fault.Injection{Action: fault.Kill, Point: fault.InProgress}
+			f.Cluster = helpers.OperatorFaultChecks(f.Cluster, f.Client, injection, helper.FaultOperations(), func(cluster *management.Cluster) (*management.Cluster, error) {
+				return helper.UpgradeKubernetesVersion(cluster, f.UpgradeToVersion, f.Client, false, false, false)
+			})
+			helpers.ClusterIsReadyChecks(f.Cluster, f.Client, f.ClusterName)
+		})
+	})
+})
@@ -21,10 +21,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	. "github.com/rancher-sandbox/qase-ginkgo"
-	"github.com/rancher/shepherd/clients/rancher"
-	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
 	"github.com/rancher/shepherd/extensions/clusters/gke"
-	namegen "github.com/rancher/shepherd/pkg/namegenerator"
 
 	"github.com/rancher/hosted-providers-e2e/hosted/gke/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
@@ -35,11 +32,9 @@ const (
 )
 
 var (
-	ctx                                helpers.RancherContext
-	cluster                            *management.Cluster
-	clusterName, zone, region, project string
-	testCaseID                         int64
-	updateFunc                         func(clusterConfig *gke.ClusterConfig)
+	ctx                   helpers.RancherContext
+	zone, region, project string
+	updateFunc            func(clusterConfig *gke.ClusterConfig)
 )
 
 func TestP0(t *testing.T) {
@@ -55,71 +50,70 @@ var _ = SynchronizedBeforeSuite(func() []byte {
 })
 
 var _ = BeforeEach(func() {
-	// Setting this to nil ensures we do not use the `cluster` variable value from another test running in parallel with this one.
-	cluster = nil
-	clusterName = namegen.AppendRandomString(helpers.ClusterNamePrefix)
 	zone = helpers.GetGKEZone()
 	region = helpers.GetGKERegion()
 	project = helpers.GetGKEProjectID()
 })
 
-var _ = ReportBeforeEach(func(report SpecReport) {
-	// Reset case ID
-	testCaseID = -1
-})
-
 var _ = ReportAfterEach(func(report SpecReport) {
 	// Add result in Qase if asked
-	Qase(testCaseID, report)
+	Qase(helpers.QaseID(report), report)
 })
 
-func p0upgradeK8sVersionChecks(cluster *management.Cluster, client *rancher.Client, clusterName string) {
-	helpers.ClusterIsReadyChecks(cluster, client, clusterName)
+func p0upgradeK8sVersionChecks(f *helpers.Fixture) {
+	helpers.ClusterIsReadyChecks(f.Cluster, f.Client, f.ClusterName)
 
-	versions, err := helper.ListGKEAvailableVersions(client, cluster.ID)
+	versions, err := helper.ListGKEAvailableVersions(f.Client, f.Cluster.ID)
 	Expect(err).To(BeNil())
 	Expect(versions).ToNot(BeEmpty())
-	upgradeToVersion := versions[0]
-	GinkgoLogr.Info(fmt.Sprintf("Upgrading cluster to GKE version %s", upgradeToVersion))
+	f.UpgradeToVersion = versions[0]
+	GinkgoLogr.Info(fmt.Sprintf("Upgrading cluster to GKE version %s", f.UpgradeToVersion))
 
 	// Upgrading controlplane and nodepool sequentially
 	By("upgrading the ControlPlane", func() {
-		cluster, err = helper.UpgradeKubernetesVersion(cluster, upgradeToVersion, client, false, true, true)
+		f.Cluster, err = helper.UpgradeKubernetesVersion(f.Cluster, f.UpgradeToVersion, f.Client, false, true, true)
 		Expect(err).To(BeNil())
 	})
 
 	By("upgrading the Nodepools", func() {
-		cluster, err = helper.UpgradeNodeKubernetesVersion(cluster, upgradeToVersion, client, true, true)
+		f.Cluster, err = helper.UpgradeNodeKubernetesVersion(f.Cluster, f.UpgradeToVersion, f.Client, true, true)
 		Expect(err).To(BeNil())
 	})
 }
 
-func p0NodesChecks(cluster *management.Cluster, client *rancher.Client, clusterName string) {
-	helpers.ClusterIsReadyChecks(cluster, client, clusterName)
-	configNodePools := *cluster.GKEConfig.NodePools
+func p0NodesChecks(f *helpers.Fixture) {
+	helpers.ClusterIsReadyChecks(f.Cluster, f.Client, f.ClusterName)
+	helpers.WorkloadChecks(f.Cluster, f.Client, helper.WorkloadOptions())
+	configNodePools := *f.Cluster.GKEConfig.NodePools
 	initialNodeCount := *configNodePools[0].InitialNodeCount
 
 	By("scaling up the nodepool", func() {
 		var err error
-		cluster, err = helper.ScaleNodePool(cluster, client, initialNodeCount+1, true, true)
+		f.Cluster, err = helper.ScaleNodePool(f.Cluster, f.Client, initialNodeCount+1, true, true)
 		Expect(err).To(BeNil())
 	})
 
 	By("scaling down the nodepool", func() {
 		var err error
-		cluster, err = helper.ScaleNodePool(cluster, client, initialNodeCount, true, true)
+		f.Cluster, err = helper.ScaleNodePool(f.Cluster, f.Client, initialNodeCount, true, true)
 		Expect(err).To(BeNil())
 	})
 
 	By("adding a nodepool", func() {
 		var err error
-		cluster, err = helper.AddNodePool(cluster, client, increaseBy, "", true, true)
+		f.Cluster, err = helper.AddNodePool(f.Cluster, f.Client, increaseBy, "", true, true)
 		Expect(err).To(BeNil())
 	})
 
 	By("deleting the nodepool", func() {
 		var err error
-		cluster, err = helper.DeleteNodePool(cluster, client, true, true)
+		f.Cluster, err = helper.DeleteNodePool(f.Cluster, f.Client, true, true)
 		Expect(err).To(BeNil())
 	})
+
+	if helpers.GetEnvironmentProfile().Proxy.Enabled {
+		By("checking that the operator routes through the proxy", func() {
+			helpers.ProxyRoutingChecks()
+		})
+	}
 }
@@ -188,6 +188,25 @@ var _ = Describe("P1Provisioning", func() {
 		}, "5m", "5s").Should(BeTrue(), "Failed while waiting for k8s upgrade.")
 	})
 
+	It("should enforce the network policies of a cluster created with network policy enabled", func() {
+		updateFunc := func(clusterConfig *gke.ClusterConfig) {
+			clusterConfig.NetworkPolicyEnabled = pointer.Bool(true)
+			if clusterConfig.ClusterAddons == nil {
+				clusterConfig.ClusterAddons = &gke.ClusterAddons{}
+			}
+			clusterConfig.ClusterAddons.NetworkPolicyConfig = true
+		}
+
+		var err error
+		cluster, err = helper.CreateGKEHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, k8sVersion, zone, "", project, updateFunc)
+		Expect(err).To(BeNil())
+		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+		Expect(err).To(BeNil())
+		Expect(*cluster.GKEConfig.NetworkPolicyEnabled).To(BeTrue())
+
+		helpers.NetworkPolicyChecks(cluster, ctx.RancherAdminClient, helper.WorkloadOptions(), true)
+	})
+
 	When("a cluster is created", func() {
 
 		BeforeEach(func() {
@@ -224,24 +243,38 @@ var _ = Describe("P1Provisioning", func() {
 			cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
 			Expect(err).To(BeNil())
 		})
+	})
+
+	When("a cluster is leased from the pool", func() {
+		var (
+			f     *helpers.Fixture
+			lease *helpers.Lease
+		)
+
+		BeforeEach(func() {
+			f = helpers.NewFixture(&ctx)
+			lease = clusterPool.Lease(f)
+		})
 
 		It("should be able to update mutable parameter loggingService and monitoringService", func() {
 			testCaseID = 28
+			// the logging and monitoring services are not part of the baseline of the pool clusters
+			lease.Retire("the logging and monitoring services have been updated")
 			By("disabling the services", func() {
-				updateLoggingAndMonitoringServiceCheck(cluster, ctx.RancherAdminClient, "none", "none")
+				updateLoggingAndMonitoringServiceCheck(f.Cluster, f.Client, "none", "none")
 			})
 			By("enabling the services", func() {
-				updateLoggingAndMonitoringServiceCheck(cluster, ctx.RancherAdminClient, "monitoring.googleapis.com/kubernetes", "logging.googleapis.com/kubernetes")
+				updateLoggingAndMonitoringServiceCheck(f.Cluster, f.Client, "monitoring.googleapis.com/kubernetes", "logging.googleapis.com/kubernetes")
 			})
 		})
 
 		It("should be able to update autoscaling", func() {
 			testCaseID = 29
 			By("enabling autoscaling", func() {
-				updateAutoScaling(cluster, ctx.RancherAdminClient, true)
+				updateAutoScaling(f.Cluster, f.Client, true)
 			})
 			By("disabling autoscaling", func() {
-				updateAutoScaling(cluster, ctx.RancherAdminClient, false)
+				updateAutoScaling(f.Cluster, f.Client, false)
 			})
 		})
 
@@ -252,15 +285,15 @@ var _ = Describe("P1Provisioning", func() {
 				Skip(helpers.SkipUpgradeTestsLog)
 			}
 
-			_, err = helper.AddNodePool(cluster, ctx.RancherAdminClient, 1, "WINDOWS_LTSC_CONTAINERD", true, true)
+			_, err = helper.AddNodePool(f.Cluster, f.Client, 1, "WINDOWS_LTSC_CONTAINERD", true, true)
 			Expect(err).To(BeNil())
 		})
 
 		It("updating a cluster to all windows nodepool should fail", func() {
 			testCaseID = 263
 
-			_, err := helper.UpdateCluster(cluster, ctx.RancherAdminClient, func(upgradedCluster *management.Cluster) {
-				updateNodePoolsList := *cluster.GKEConfig.NodePools
+			_, err := helper.UpdateCluster(f.Cluster, f.Client, func(upgradedCluster *management.Cluster) {
+				updateNodePoolsList := *f.Cluster.GKEConfig.NodePools
 				for i := 0; i < len(updateNodePoolsList); i++ {
 					updateNodePoolsList[i].Config.ImageType = "WINDOWS_LTSC_CONTAINERD"
 				}
@@ -273,12 +306,18 @@ var _ = Describe("P1Provisioning", func() {
 
 		It("should be able to update combination mutable parameter", func() {
 			testCaseID = 31
-			combinationMutableParameterUpdate(cluster, ctx.RancherAdminClient)
+			// the autoscaling, logging and monitoring services are left updated
+			lease.Retire("the autoscaling, logging and monitoring services have been updated")
+			combinationMutableParameterUpdate(f.Cluster, f.Client)
 		})
 
 		It("should successfully update with new cloud credentials", func() {
 			testCaseID = 5
-			updateCloudCredentialsCheck(cluster, ctx.RancherAdminClient)
+			updateCloudCredentialsCheck(f.Cluster, f.Client)
+		})
+
+		It("should report the failures of its cloud credential and recover once it is valid again", func() {
+			f.Cluster = helpers.CloudCredentialLifecycleChecks(f.Cluster, f.Client, helper.CredentialOperations(project))
 		})
 	})
 
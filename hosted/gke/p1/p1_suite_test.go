@@ -16,7 +16,6 @@ package p1_test
 
 import (
 	"fmt"
-	"os"
 	"strings"
 	"testing"
 	"time"
@@ -35,6 +34,7 @@ import (
 
 	"github.com/rancher/hosted-providers-e2e/hosted/gke/helper"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/credential"
 )
 
 var (
@@ -44,6 +44,8 @@ var (
 	testCaseID              int64
 	zone                    = helpers.GetGKEZone()
 	project                 = helpers.GetGKEProjectID()
+	// clusterPool leases the default shaped clusters of the specs which do not need a cluster of their own
+	clusterPool *helpers.ClusterPool
 )
 
 func TestP1(t *testing.T) {
@@ -53,9 +55,17 @@ func TestP1(t *testing.T) {
 
 var _ = SynchronizedBeforeSuite(func() []byte {
 	helpers.CommonSynchronizedBeforeSuite()
-	return nil
-}, func() {
+	return helpers.CreateClusterPool(helper.PoolProvisioner(zone, "", project))
+}, func(data []byte) {
 	ctx = helpers.CommonBeforeSuite()
+	clusterPool = helpers.OpenClusterPool(data, &ctx, helper.PoolProvisioner(zone, "", project))
+})
+
+var _ = SynchronizedAfterSuite(func() {}, func() {
+	// the pool is not created when the BeforeSuite failed
+	if clusterPool != nil {
+		clusterPool.Drain()
+	}
 })
 
 var _ = BeforeEach(func() {
@@ -342,21 +352,9 @@ func combinationMutableParameterUpdate(cluster *management.Cluster, client *ranc
 }
 
 func updateCloudCredentialsCheck(cluster *management.Cluster, client *rancher.Client) {
-	newCCID, err := helpers.CreateCloudCredentials(client)
-	Expect(err).To(BeNil())
-	updateFunc := func(cluster *management.Cluster) {
-		cluster.GKEConfig.GoogleCredentialSecret = newCCID
-	}
-	cluster, err = helper.UpdateCluster(cluster, client, updateFunc)
-	Expect(err).To(BeNil())
-	Expect(cluster.GKEConfig.GoogleCredentialSecret).To(Equal(newCCID))
-	Eventually(func() bool {
-		cluster, err = client.Management.Cluster.ByID(cluster.ID)
-		Expect(err).NotTo(HaveOccurred())
-		return cluster.GKEStatus.UpstreamSpec.GoogleCredentialSecret == newCCID
-	}, "5m", "5s").Should(BeTrue(), "Failed while upstream cloud credentials update")
+	cluster, _ = helpers.RotateCloudCredential(cluster, client)
 
-	cluster, err = helper.AddNodePool(cluster, client, 1, "", false, false)
+	cluster, err := helper.AddNodePool(cluster, client, 1, "", false, false)
 	Expect(err).To(BeNil())
 }
 
@@ -400,19 +398,18 @@ func invalidCredCheck(cluster *management.Cluster, client *rancher.Client) {
 
 // Automates Qase 6 and 305
 func expiredCredCheck(cluster *management.Cluster, client *rancher.Client) {
+	expiry := helper.CredentialExpiry(project)
+
 	By("adding the creds")
-	cloudCredentialConfig := cloudcredentials.CloudCredential{GoogleCredentialConfig: &cloudcredentials.GoogleCredentialConfig{AuthEncodedJSON: os.Getenv("SECONDARY_GCP_CREDENTIALS")}}
-	cloudCredential, err := google.CreateGoogleCloudCredentials(client, cloudCredentialConfig)
+	cloudCredentialID, err := expiry.Create(client)
 	Expect(err).To(BeNil())
-	cloudCredentialID := fmt.Sprintf("%s:%s", cloudCredential.Namespace, cloudCredential.Name)
 
 	By("disabling the service account")
-	const clientID = "hosted-providers-ci-creds-test"
-	err = helper.EnableDisableServiceAccountOnGCloud(clientID, project, "disable")
+	err = expiry.Expire()
 	Expect(err).To(BeNil())
 	defer func() {
 		By("cleanup: enabling the service account")
-		err = helper.EnableDisableServiceAccountOnGCloud(clientID, project, "enable")
+		err = expiry.Renew()
 		Expect(err).To(BeNil())
 	}()
 
@@ -429,6 +426,6 @@ func expiredCredCheck(cluster *management.Cluster, client *rancher.Client) {
 	Eventually(func() bool {
 		cluster, err = client.Management.Cluster.ByID(cluster.ID)
 		Expect(err).To(BeNil())
-		return cluster.Transitioning == "error" && strings.Contains(cluster.TransitioningMessage, "cannot fetch token") || strings.Contains(cluster.TransitioningMessage, "unexpected end of JSON input")
+		return cluster.Transitioning == "error" && credential.Matches(helpers.Provider, credential.Expired, cluster.TransitioningMessage)
 	}, "2m", "3s").Should(BeTrue())
 }
@@ -0,0 +1,75 @@
+/*
+Copyright © 2022 - 2025 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package private_endpoint_test
+
+import (
+	"fmt"
+	"math/rand"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher/shepherd/extensions/clusters/gke"
+	"k8s.io/utils/pointer"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/gke/helper"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var _ = Describe("PrivateEndpoint", func() {
+	var f *helpers.Fixture
+
+	BeforeEach(func() {
+		helpers.SkipUnsupportedPrivateClusters(ctx.RancherAdminClient)
+
+		f = helpers.NewFixture(&ctx)
+		f.AddClusterCleanup(func() {
+			if f.Cluster != nil && f.Cluster.ID != "" {
+				GinkgoLogr.Info(fmt.Sprintf("Cleaning up resource cluster: %s %s", f.Cluster.Name, f.Cluster.ID))
+				err := helper.DeleteGKEHostCluster(f.Cluster, f.Client)
+				Expect(err).To(BeNil())
+			}
+		})
+		var err error
+		f.K8sVersion, err = helper.GetK8sVersion(f.Client, project, ctx.CloudCredID, zone, "", false)
+		Expect(err).To(BeNil())
+		GinkgoLogr.Info(fmt.Sprintf("While provisioning, using kubernetes version %s for cluster %s", f.K8sVersion, f.ClusterName))
+
+		createFunc := func(clusterConfig *gke.ClusterConfig) {
+			network := pointer.String("hosted-providers-ci-private")
+			clusterConfig.Network = network
+			clusterConfig.Subnetwork = network
+			clusterConfig.PrivateClusterConfig.EnablePrivateNodes = true
+			clusterConfig.PrivateClusterConfig.EnablePrivateEndpoint = true
+			// the P1 private clusters use 172.16.[0-9].0/28
+			clusterConfig.PrivateClusterConfig.MasterIpv4CidrBlock = fmt.Sprintf("172.16.%d.0/28", 10+rand.Intn(10))
+			// a private endpoint requires the authorized networks, none is authorized
+			clusterConfig.MasterAuthorizedNetworksConfig.Enabled = true
+		}
+		f.Cluster, err = helper.CreateGKEHostedCluster(f.Client, f.ClusterName, ctx.CloudCredID, f.K8sVersion, zone, "", project, createFunc)
+		Expect(err).To(BeNil())
+	})
+
+	It("should register a cluster with a private endpoint through the Connect gateway and reach it through the agent tunnel only", func() {
+		f.Cluster = helpers.RegisterPrivateCluster(f.Cluster, f.Client, func(command string) (string, error) {
+			return helper.RunCommandThroughConnectGateway(f.ClusterName, zone, project, command)
+		})
+		helpers.ClusterIsReadyChecks(f.Cluster, f.Client, f.ClusterName)
+		Expect(f.Cluster.GKEStatus.UpstreamSpec.PrivateClusterConfig.EnablePrivateEndpoint).To(BeTrue())
+
+		endpoints, err := helper.APIServerEndpointsOnGCloud(f.ClusterName, zone, project)
+		Expect(err).To(BeNil())
+		helpers.PrivateEndpointChecks(f.Cluster, f.Client, endpoints...)
+	})
+})
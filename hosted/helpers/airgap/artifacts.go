@@ -0,0 +1,164 @@
+package airgap
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	K3sBinary        = "k3s"
+	K3sInstallScript = "install.sh"
+	ChartsDir        = "charts"
+)
+
+var (
+	// K3sReleaseURL and K3sInstallScriptURL are variables so they can be pointed at a local mirror
+	K3sReleaseURL       = "https://github.com/k3s-io/k3s/releases/download"
+	K3sInstallScriptURL = "https://get.k3s.io"
+	// RancherReleaseURL hosts the rancher-images.txt of every Rancher release
+	RancherReleaseURL = "https://github.com/rancher/rancher/releases/download"
+
+	chartImageRegexp = regexp.MustCompile(`(?m)^\s*-?\s*image:\s*["']?([^"'\s]+)["']?\s*$`)
+)
+
+// K3sImagesTarball returns the name of the k3s airgap images tarball for the current architecture
+func K3sImagesTarball() string {
+	return fmt.Sprintf("k3s-airgap-images-%s.tar.zst", runtime.GOARCH)
+}
+
+// DownloadK3sArtifacts downloads the k3s binary, airgap images tarball and install script of k3sVersion into dir;
+// files already present are kept
+func DownloadK3sArtifacts(k3sVersion, dir string) error {
+	binary := K3sBinary
+	if runtime.GOARCH != "amd64" {
+		binary = K3sBinary + "-" + runtime.GOARCH
+	}
+	// The k3s version contains a '+' which must be escaped in the release URL
+	releaseURL := fmt.Sprintf("%s/%s", K3sReleaseURL, strings.ReplaceAll(k3sVersion, "+", "%2B"))
+
+	for file, url := range map[string]string{
+		K3sBinary:          releaseURL + "/" + binary,
+		K3sImagesTarball(): releaseURL + "/" + K3sImagesTarball(),
+		K3sInstallScript:   K3sInstallScriptURL,
+	} {
+		if err := download(url, filepath.Join(dir, file)); err != nil {
+			return err
+		}
+	}
+	return os.Chmod(filepath.Join(dir, K3sBinary), 0755)
+}
+
+// DownloadRancherImageList downloads the rancher-images.txt of a Rancher release [eg. 2.10.1] into dir and returns its path
+func DownloadRancherImageList(rancherVersion, dir string) (string, error) {
+	file := filepath.Join(dir, fmt.Sprintf("rancher-images-%s.txt", rancherVersion))
+	url := fmt.Sprintf("%s/v%s/rancher-images.txt", RancherReleaseURL, strings.TrimPrefix(rancherVersion, "v"))
+	return file, download(url, file)
+}
+
+// PullChart downloads a chart archive into dir and returns its path; version can be empty for the latest one
+func PullChart(chart, version, dir string, extraArgs ...string) (string, error) {
+	name := filepath.Base(chart)
+	if path, err := chartArchive(dir, name, version); err == nil {
+		return path, nil
+	}
+
+	args := append([]string{"pull", chart, "--destination", dir}, extraArgs...)
+	if version != "" {
+		args = append(args, "--version", version)
+	}
+	if out, err := exec.Command("helm", args...).CombinedOutput(); err != nil {
+		return "", errors.Wrapf(err, "helm pull %s: %s", chart, out)
+	}
+	return chartArchive(dir, name, version)
+}
+
+// chartArchive finds a chart archive pulled in dir, the most recent one is returned if version is empty
+func chartArchive(dir, name, version string) (string, error) {
+	if version != "" {
+		path := filepath.Join(dir, fmt.Sprintf("%s-%s.tgz", name, strings.TrimPrefix(version, "v")))
+		if _, err := os.Stat(path); err != nil {
+			return "", err
+		}
+		return path, nil
+	}
+
+	matches, err := filepath.Glob(filepath.Join(dir, name+"-*.tgz"))
+	if err != nil {
+		return "", err
+	}
+	if len(matches) == 0 {
+		return "", fmt.Errorf("no %s chart archive found in %s", name, dir)
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		fi, _ := os.Stat(matches[i])
+		fj, _ := os.Stat(matches[j])
+		return fi.ModTime().After(fj.ModTime())
+	})
+	return matches[0], nil
+}
+
+// ChartImages renders a chart with the given helm template arguments and returns the images it references
+func ChartImages(chartPath string, templateArgs ...string) ([]string, error) {
+	args := append([]string{"template", "airgap", chartPath}, templateArgs...)
+	out, err := exec.Command("helm", args...).Output()
+	if err != nil {
+		return nil, errors.Wrapf(err, "helm template %s", chartPath)
+	}
+	return ManifestImages(string(out)), nil
+}
+
+// ManifestImages returns the images referenced in rendered manifests, without duplicates
+func ManifestImages(manifests string) []string {
+	var images []string
+	seen := map[string]bool{}
+	for _, match := range chartImageRegexp.FindAllStringSubmatch(manifests, -1) {
+		if image := match[1]; !seen[image] {
+			seen[image] = true
+			images = append(images, image)
+		}
+	}
+	return images
+}
+
+func download(url, file string) error {
+	if _, err := os.Stat(file); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+
+	resp, err := http.Get(url)
+	if err != nil {
+		return errors.Wrapf(err, "downloading %s", url)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("downloading %s: %s", url, resp.Status)
+	}
+
+	// Write to a temporary file so that an interrupted download is not kept
+	tmp := file + ".part"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(f, resp.Body); err != nil {
+		f.Close()
+		return errors.Wrapf(err, "downloading %s", url)
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, file)
+}
@@ -0,0 +1,110 @@
+/*
+Copyright © 2022 - 2025 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package airgap_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/airgap"
+)
+
+var _ = Describe("Artifacts", func() {
+	It("extracts the images of rendered manifests", func() {
+		manifests := `
+apiVersion: apps/v1
+kind: Deployment
+spec:
+  template:
+    spec:
+      initContainers:
+        - image: "quay.io/jetstack/cert-manager-startupapicheck:v1.16.2"
+      containers:
+        - name: controller
+          image: quay.io/jetstack/cert-manager-controller:v1.16.2
+          imagePullPolicy: IfNotPresent
+        - name: sidecar
+          image: 'quay.io/jetstack/cert-manager-controller:v1.16.2'
+`
+		Expect(airgap.ManifestImages(manifests)).To(Equal([]string{
+			"quay.io/jetstack/cert-manager-startupapicheck:v1.16.2",
+			"quay.io/jetstack/cert-manager-controller:v1.16.2",
+		}))
+	})
+
+	Context("with a release server stand-in", func() {
+		var requests []string
+
+		BeforeEach(func() {
+			requests = nil
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.URL.EscapedPath())
+				switch r.URL.EscapedPath() {
+				case "/k3s/v1.31.4%2Bk3s1/k3s", "/k3s/v1.31.4%2Bk3s1/k3s-arm64", "/k3s/v1.31.4%2Bk3s1/" + airgap.K3sImagesTarball():
+					fmt.Fprint(w, "binary")
+				case "/install.sh":
+					fmt.Fprint(w, "#!/bin/sh")
+				case "/rancher/v2.10.1/rancher-images.txt":
+					fmt.Fprint(w, "rancher/rancher:v2.10.1\n")
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			DeferCleanup(server.Close)
+
+			releaseURL, installScriptURL, rancherReleaseURL := airgap.K3sReleaseURL, airgap.K3sInstallScriptURL, airgap.RancherReleaseURL
+			airgap.K3sReleaseURL, airgap.K3sInstallScriptURL, airgap.RancherReleaseURL = server.URL+"/k3s", server.URL+"/install.sh", server.URL+"/rancher"
+			DeferCleanup(func() {
+				airgap.K3sReleaseURL, airgap.K3sInstallScriptURL, airgap.RancherReleaseURL = releaseURL, installScriptURL, rancherReleaseURL
+			})
+		})
+
+		It("downloads the k3s artifacts once", func() {
+			dir := GinkgoT().TempDir()
+			Expect(airgap.DownloadK3sArtifacts("v1.31.4+k3s1", dir)).To(Succeed())
+
+			for _, file := range []string{airgap.K3sBinary, airgap.K3sImagesTarball(), airgap.K3sInstallScript} {
+				Expect(filepath.Join(dir, file)).To(BeARegularFile())
+			}
+			info, err := os.Stat(filepath.Join(dir, airgap.K3sBinary))
+			Expect(err).To(BeNil())
+			Expect(info.Mode().Perm() & 0100).ToNot(BeZero())
+
+			count := len(requests)
+			Expect(airgap.DownloadK3sArtifacts("v1.31.4+k3s1", dir)).To(Succeed())
+			Expect(requests).To(HaveLen(count))
+		})
+
+		It("does not keep failed downloads", func() {
+			dir := GinkgoT().TempDir()
+			Expect(airgap.DownloadK3sArtifacts("v0.0.0+k3s1", dir)).To(MatchError(ContainSubstring("404")))
+			Expect(filepath.Join(dir, airgap.K3sBinary)).ToNot(BeAnExistingFile())
+		})
+
+		It("downloads the Rancher image list", func() {
+			file, err := airgap.DownloadRancherImageList("2.10.1", GinkgoT().TempDir())
+			Expect(err).To(BeNil())
+			content, err := os.ReadFile(file)
+			Expect(err).To(BeNil())
+			Expect(string(content)).To(Equal("rancher/rancher:v2.10.1\n"))
+		})
+	})
+})
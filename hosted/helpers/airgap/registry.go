@@ -0,0 +1,262 @@
+// Package airgap mirrors the images, charts and k3s artifacts needed to run Rancher in a disconnected environment
+package airgap
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// RegistryImage is the image used to run the local registry
+	RegistryImage = "registry:2"
+	// RegistryContainerName is the name of the local registry container
+	RegistryContainerName = "hp_airgap_registry"
+)
+
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// StartRegistry runs a registry:2 container listening on port, it is a no-op if the container is already running;
+// it returns the registry address as seen from the host
+func StartRegistry(name string, port int) (string, error) {
+	registry := fmt.Sprintf("localhost:%d", port)
+	if out, err := exec.Command("docker", "inspect", "--format", "{{.State.Running}}", name).Output(); err == nil && strings.TrimSpace(string(out)) == "true" {
+		return registry, nil
+	}
+	out, err := exec.Command("docker", "run", "-d", "--restart=always", "--name", name,
+		"-p", fmt.Sprintf("%d:5000", port), RegistryImage).CombinedOutput()
+	if err != nil {
+		return "", errors.Wrapf(err, "starting registry %s: %s", name, out)
+	}
+	return registry, WaitForRegistry(registry, time.Minute)
+}
+
+// StopRegistry removes the registry container and its content
+func StopRegistry(name string) error {
+	out, err := exec.Command("docker", "rm", "-f", "-v", name).CombinedOutput()
+	if err != nil {
+		return errors.Wrapf(err, "removing registry %s: %s", name, out)
+	}
+	return nil
+}
+
+// WaitForRegistry waits until the registry API answers
+func WaitForRegistry(registry string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		resp, err := httpClient.Get(registryURL(registry, "/v2/"))
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return nil
+			}
+			err = fmt.Errorf("unexpected status %s", resp.Status)
+		}
+		if time.Now().After(deadline) {
+			return errors.Wrapf(err, "registry %s is not ready", registry)
+		}
+		time.Sleep(2 * time.Second)
+	}
+}
+
+// MirrorReference returns the reference of image in registry; the source registry is dropped so that the
+// image path matches what containerd requests from a mirror [eg. quay.io/jetstack/cert-manager-controller:v1.16.2 -> localhost:5000/jetstack/cert-manager-controller:v1.16.2]
+func MirrorReference(image, registry string) (string, error) {
+	image = strings.TrimSpace(image)
+	if image == "" {
+		return "", errors.New("empty image reference")
+	}
+
+	path := image
+	if parts := strings.SplitN(image, "/", 2); len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		path = parts[1]
+	}
+
+	name, suffix := path, ""
+	if i := strings.Index(path, "@"); i >= 0 {
+		name, suffix = path[:i], path[i:]
+	} else if i := strings.LastIndex(path, ":"); i > strings.LastIndex(path, "/") {
+		name, suffix = path[:i], path[i:]
+	} else {
+		suffix = ":latest"
+	}
+	if name == "" {
+		return "", fmt.Errorf("invalid image reference %q", image)
+	}
+	if !strings.Contains(name, "/") {
+		// Official images, eg. registry:2 is docker.io/library/registry:2
+		name = "library/" + name
+	}
+	return fmt.Sprintf("%s/%s%s", strings.TrimSuffix(registry, "/"), name, suffix), nil
+}
+
+// MirrorImages pulls every image and pushes it to registry, all the images are processed even if one fails
+func MirrorImages(registry string, images []string, out io.Writer) error {
+	var failed []string
+	for _, image := range images {
+		target, err := MirrorReference(image, registry)
+		if err == nil {
+			err = mirrorImage(image, target, out)
+		}
+		if err != nil {
+			fmt.Fprintf(out, "Unable to mirror %s: %v\n", image, err)
+			failed = append(failed, image)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("unable to mirror %d/%d images: %s", len(failed), len(images), strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func mirrorImage(image, target string, out io.Writer) error {
+	for _, args := range [][]string{
+		{"pull", image},
+		{"tag", image, target},
+		{"push", target},
+	} {
+		cmd := exec.Command("docker", args...)
+		cmd.Stdout, cmd.Stderr = out, out
+		if err := cmd.Run(); err != nil {
+			return errors.Wrapf(err, "docker %s", args[0])
+		}
+	}
+	return nil
+}
+
+// Catalog lists the repositories stored in registry
+func Catalog(registry string) ([]string, error) {
+	var catalog struct {
+		Repositories []string `json:"repositories"`
+	}
+	if err := getJSON(registryURL(registry, "/v2/_catalog?n=10000"), &catalog); err != nil {
+		return nil, err
+	}
+	return catalog.Repositories, nil
+}
+
+// Tags lists the tags of a repository stored in registry
+func Tags(registry, repository string) ([]string, error) {
+	var tags struct {
+		Tags []string `json:"tags"`
+	}
+	if err := getJSON(registryURL(registry, "/v2/"+repository+"/tags/list"), &tags); err != nil {
+		return nil, err
+	}
+	return tags.Tags, nil
+}
+
+// MissingImages returns the images of the list which are not available in registry
+func MissingImages(registry string, images []string) ([]string, error) {
+	var missing []string
+	for _, image := range images {
+		ref, err := MirrorReference(image, registry)
+		if err != nil {
+			return nil, err
+		}
+		repository, reference := splitReference(strings.TrimPrefix(ref, strings.TrimSuffix(registry, "/")+"/"))
+		req, err := http.NewRequest(http.MethodHead, registryURL(registry, "/v2/"+repository+"/manifests/"+reference), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			missing = append(missing, image)
+		}
+	}
+	return missing, nil
+}
+
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// splitReference splits a repository[:tag|@digest] reference
+func splitReference(ref string) (string, string) {
+	if i := strings.Index(ref, "@"); i >= 0 {
+		return ref[:i], ref[i+1:]
+	}
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		return ref[:i], ref[i+1:]
+	}
+	return ref, "latest"
+}
+
+// ReadImageList reads an image list in the rancher-images.txt format; empty lines and comments are ignored and duplicates are removed
+func ReadImageList(r io.Reader) ([]string, error) {
+	var images []string
+	seen := map[string]bool{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		image := strings.TrimSpace(scanner.Text())
+		if image == "" || strings.HasPrefix(image, "#") || seen[image] {
+			continue
+		}
+		seen[image] = true
+		images = append(images, image)
+	}
+	return images, scanner.Err()
+}
+
+// FilterImages keeps the images matching at least one of the regular expressions; all the images are kept if no filter is given
+func FilterImages(images, filters []string) ([]string, error) {
+	if len(filters) == 0 {
+		return images, nil
+	}
+	var regexps []*regexp.Regexp
+	for _, filter := range filters {
+		re, err := regexp.Compile(filter)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid image filter %q", filter)
+		}
+		regexps = append(regexps, re)
+	}
+
+	var filtered []string
+	for _, image := range images {
+		for _, re := range regexps {
+			if re.MatchString(image) {
+				filtered = append(filtered, image)
+				break
+			}
+		}
+	}
+	return filtered, nil
+}
+
+func registryURL(registry, path string) string {
+	registry = strings.TrimSuffix(registry, "/")
+	if !strings.HasPrefix(registry, "http://") && !strings.HasPrefix(registry, "https://") {
+		// The local registry is plain HTTP
+		registry = "http://" + registry
+	}
+	return registry + path
+}
+
+func getJSON(url string, v interface{}) error {
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,197 @@
+/*
+Copyright © 2022 - 2025 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package airgap_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/airgap"
+)
+
+var _ = Describe("Registry", func() {
+	DescribeTable("MirrorReference",
+		func(image, expected string) {
+			ref, err := airgap.MirrorReference(image, "localhost:5000")
+			Expect(err).To(BeNil())
+			Expect(ref).To(Equal(expected))
+		},
+		Entry("docker.io namespaced image", "rancher/rancher:v2.10.1", "localhost:5000/rancher/rancher:v2.10.1"),
+		Entry("docker.io official image", "registry:2", "localhost:5000/library/registry:2"),
+		Entry("untagged image", "rancher/shell", "localhost:5000/rancher/shell:latest"),
+		Entry("other registry", "quay.io/jetstack/cert-manager-controller:v1.16.2", "localhost:5000/jetstack/cert-manager-controller:v1.16.2"),
+		Entry("registry with port", "registry.local:5000/rancher/fleet:v0.11.2", "localhost:5000/rancher/fleet:v0.11.2"),
+		Entry("digest", "rancher/aks-operator@sha256:0123", "localhost:5000/rancher/aks-operator@sha256:0123"),
+	)
+
+	It("rejects an empty reference", func() {
+		_, err := airgap.MirrorReference(" ", "localhost:5000")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("reads and filters an image list", func() {
+		images, err := airgap.ReadImageList(strings.NewReader("rancher/rancher:v2.10.1\n\n# comment\nrancher/aks-operator:v1.10.1\nrancher/rancher:v2.10.1\nrancher/mirrored-pause:3.6\n"))
+		Expect(err).To(BeNil())
+		Expect(images).To(Equal([]string{"rancher/rancher:v2.10.1", "rancher/aks-operator:v1.10.1", "rancher/mirrored-pause:3.6"}))
+
+		filtered, err := airgap.FilterImages(images, []string{`^rancher/rancher:`, `-operator:`})
+		Expect(err).To(BeNil())
+		Expect(filtered).To(Equal([]string{"rancher/rancher:v2.10.1", "rancher/aks-operator:v1.10.1"}))
+
+		filtered, err = airgap.FilterImages(images, nil)
+		Expect(err).To(BeNil())
+		Expect(filtered).To(Equal(images))
+
+		_, err = airgap.FilterImages(images, []string{"("})
+		Expect(err).To(HaveOccurred())
+	})
+
+	Context("with a registry API stand-in", func() {
+		// request is a manifest request received by the stand-in, the handler runs outside of the spec goroutine and must not assert
+		type request struct {
+			Method, Path, Accept string
+		}
+		var (
+			registry  string
+			mu        sync.Mutex
+			manifests []request
+		)
+
+		BeforeEach(func() {
+			manifests = nil
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if strings.Contains(r.URL.Path, "/manifests/") {
+					mu.Lock()
+					manifests = append(manifests, request{Method: r.Method, Path: r.URL.Path, Accept: r.Header.Get("Accept")})
+					mu.Unlock()
+				}
+				switch r.URL.Path {
+				case "/v2/":
+					w.WriteHeader(http.StatusOK)
+				case "/v2/_catalog":
+					fmt.Fprint(w, `{"repositories":["library/registry","rancher/rancher"]}`)
+				case "/v2/rancher/rancher/tags/list":
+					fmt.Fprint(w, `{"name":"rancher/rancher","tags":["v2.10.1"]}`)
+				case "/v2/rancher/rancher/manifests/v2.10.1", "/v2/library/registry/manifests/sha256:0123":
+					if r.Method != http.MethodHead {
+						w.WriteHeader(http.StatusMethodNotAllowed)
+						return
+					}
+					w.WriteHeader(http.StatusOK)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			DeferCleanup(server.Close)
+			registry = strings.TrimPrefix(server.URL, "http://")
+		})
+
+		It("lists repositories and tags", func() {
+			Expect(airgap.WaitForRegistry("127.0.0.1:1", 0)).ToNot(Succeed())
+			Expect(airgap.WaitForRegistry(registry, 5*time.Second)).To(Succeed())
+
+			repositories, err := airgap.Catalog(registry)
+			Expect(err).To(BeNil())
+			Expect(repositories).To(ConsistOf("library/registry", "rancher/rancher"))
+
+			tags, err := airgap.Tags(registry, "rancher/rancher")
+			Expect(err).To(BeNil())
+			Expect(tags).To(ConsistOf("v2.10.1"))
+
+			_, err = airgap.Tags(registry, "rancher/shell")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("reports the images missing from the registry", func() {
+			missing, err := airgap.MissingImages(registry, []string{"rancher/rancher:v2.10.1", "docker.io/library/registry@sha256:0123", "rancher/rancher:v2.9.0", "rancher/shell:v0.3.0"})
+			Expect(err).To(BeNil())
+			Expect(missing).To(Equal([]string{"rancher/rancher:v2.9.0", "rancher/shell:v0.3.0"}))
+
+			mu.Lock()
+			defer mu.Unlock()
+			Expect(manifests).To(HaveLen(4))
+			for _, manifest := range manifests {
+				Expect(manifest.Method).To(Equal(http.MethodHead), "manifest request %s", manifest.Path)
+				Expect(manifest.Accept).To(ContainSubstring("application/vnd.oci.image.index.v1+json"), "manifest request %s", manifest.Path)
+			}
+			Expect(manifests).To(ContainElements(
+				HaveField("Path", "/v2/rancher/rancher/manifests/v2.10.1"),
+				HaveField("Path", "/v2/library/registry/manifests/sha256:0123"),
+			))
+		})
+	})
+
+	Context("with a local registry:2 container", Ordered, func() {
+		const port = 5055
+		var registry string
+
+		BeforeAll(func() {
+			if _, err := exec.LookPath("docker"); err != nil {
+				Skip("docker is not available")
+			}
+			if err := exec.Command("docker", "info").Run(); err != nil {
+				Skip("docker daemon is not available")
+			}
+
+			var err error
+			registry, err = airgap.StartRegistry("hp_airgap_registry_test", port)
+			Expect(err).To(BeNil())
+			DeferCleanup(airgap.StopRegistry, "hp_airgap_registry_test")
+		})
+
+		It("is idempotent", func() {
+			again, err := airgap.StartRegistry("hp_airgap_registry_test", port)
+			Expect(err).To(BeNil())
+			Expect(again).To(Equal(registry))
+		})
+
+		It("mirrors images", func() {
+			images := []string{airgap.RegistryImage}
+			missing, err := airgap.MissingImages(registry, images)
+			Expect(err).To(BeNil())
+			Expect(missing).To(Equal(images))
+
+			Expect(airgap.MirrorImages(registry, images, GinkgoWriter)).To(Succeed())
+
+			missing, err = airgap.MissingImages(registry, images)
+			Expect(err).To(BeNil())
+			Expect(missing).To(BeEmpty())
+
+			repositories, err := airgap.Catalog(registry)
+			Expect(err).To(BeNil())
+			Expect(repositories).To(ContainElement("library/registry"))
+
+			tags, err := airgap.Tags(registry, "library/registry")
+			Expect(err).To(BeNil())
+			Expect(tags).To(ContainElement("2"))
+
+			_, err = airgap.Tags(registry, "rancher/does-not-exist")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("reports the images it cannot mirror", func() {
+			err := airgap.MirrorImages(registry, []string{"rancher/does-not-exist:v0.0.0"}, GinkgoWriter)
+			Expect(err).To(MatchError(ContainSubstring("unable to mirror 1/1 images")))
+		})
+	})
+})
@@ -0,0 +1,121 @@
+package backupinspector
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	clustersGroupResource = "clusters.management.cattle.io"
+	settingsGroupResource = "settings.management.cattle.io"
+	secretsGroupResource  = "secrets"
+	// ClusterConfigNamespace is the namespace holding the <provider>ClusterConfig CRs and cloud credentials
+	ClusterConfigNamespace = "cattle-global-data"
+)
+
+// providerGroups maps a hosted provider to the API group of its <provider>ClusterConfig CR
+var providerGroups = map[string]string{
+	"aks": "aks.cattle.io",
+	"eks": "eks.cattle.io",
+	"gke": "gke.cattle.io",
+	// PANDARIA:
+	"cce": "cce.pandaria.io",
+	"ack": "ack.pandaria.io",
+	"tke": "tke.pandaria.io",
+}
+
+// ClusterConfigGroupResource returns the group resource of the <provider>ClusterConfig CR, e.g. aksclusterconfigs.aks.cattle.io
+func ClusterConfigGroupResource(provider string) (string, error) {
+	group, ok := providerGroups[provider]
+	if !ok {
+		return "", fmt.Errorf("unknown hosted provider %q", provider)
+	}
+	return provider + "clusterconfigs." + group, nil
+}
+
+// HostedCluster is the backup content related to a single hosted cluster
+type HostedCluster struct {
+	Cluster          Resource
+	ClusterConfig    Resource
+	CloudCredential  Resource
+	OperatorSettings []Resource
+}
+
+// HostedCluster finds the management cluster, the <provider>ClusterConfig CR and the cloud credential secret of a hosted cluster;
+// it returns an error listing everything that is missing from the backup
+func (i *Index) HostedCluster(provider, clusterID string) (HostedCluster, error) {
+	var (
+		hosted HostedCluster
+		errs   []string
+	)
+
+	configGroupResource, err := ClusterConfigGroupResource(provider)
+	if err != nil {
+		return hosted, err
+	}
+
+	cluster, found := i.Get(clustersGroupResource, "", clusterID)
+	if !found {
+		return hosted, fmt.Errorf("cluster %s not found in %s", clusterID, clustersGroupResource)
+	}
+	hosted.Cluster = cluster
+
+	clusterConfig, _ := nestedMap(cluster.Object, "spec", provider+"Config")
+	if clusterConfig == nil {
+		errs = append(errs, fmt.Sprintf("cluster %s has no spec.%sConfig", clusterID, provider))
+	}
+
+	if hosted.ClusterConfig, found = i.Get(configGroupResource, ClusterConfigNamespace, clusterID); !found {
+		errs = append(errs, fmt.Sprintf("%s %s/%s not found", configGroupResource, ClusterConfigNamespace, clusterID))
+	}
+
+	if credential := credentialSecret(clusterConfig); credential == "" {
+		errs = append(errs, fmt.Sprintf("cluster %s has no cloud credential in spec.%sConfig", clusterID, provider))
+	} else {
+		namespace, name, _ := strings.Cut(credential, ":")
+		if hosted.CloudCredential, found = i.Get(secretsGroupResource, namespace, name); !found {
+			errs = append(errs, fmt.Sprintf("cloud credential secret %s not found", credential))
+		}
+	}
+
+	hosted.OperatorSettings = i.OperatorSettings(provider)
+
+	if len(errs) > 0 {
+		return hosted, fmt.Errorf("backup content is incomplete: %s", strings.Join(errs, "; "))
+	}
+	return hosted, nil
+}
+
+// OperatorSettings returns the management settings used by a provider operator, e.g. aks-refresh
+func (i *Index) OperatorSettings(provider string) (settings []Resource) {
+	for _, setting := range i.List(settingsGroupResource) {
+		if strings.HasPrefix(setting.Name, provider+"-") {
+			settings = append(settings, setting)
+		}
+	}
+	return
+}
+
+// credentialSecret returns the <namespace>:<name> cloud credential referenced by a cluster config;
+// the field name differs per provider (azureCredentialSecret, amazonCredentialSecret, aliyun_credential_secret, ...)
+func credentialSecret(clusterConfig map[string]interface{}) string {
+	for key, value := range clusterConfig {
+		normalizedKey := strings.ToLower(strings.ReplaceAll(key, "_", ""))
+		if credential, ok := value.(string); ok && strings.HasSuffix(normalizedKey, "credentialsecret") {
+			return credential
+		}
+	}
+	return ""
+}
+
+func nestedMap(object map[string]interface{}, fields ...string) (map[string]interface{}, bool) {
+	current := object
+	for _, field := range fields {
+		next, ok := current[field].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current = next
+	}
+	return current, true
+}
@@ -0,0 +1,184 @@
+// Package backupinspector indexes the content of a rancher-backup tarball so that tests can
+// assert on the hosted-provider resources it contains without restoring it.
+package backupinspector
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+const (
+	// encryptedPrefix is the prefix added by the k8s storage transformers used by rancher-backup
+	encryptedPrefix = "k8s:enc:"
+	// filtersDir holds the resource set filters, not actual resources
+	filtersDir = "filters"
+)
+
+// Resource is a single object stored in a backup
+type Resource struct {
+	Resource  string
+	Group     string
+	Version   string
+	Namespace string
+	Name      string
+	// Object is nil when the resource is encrypted
+	Object    map[string]interface{}
+	Encrypted bool
+}
+
+// GroupResource returns the resource in the resource.group format used by the backup directories, e.g. clusters.management.cattle.io
+func (r Resource) GroupResource() string {
+	if r.Group == "" {
+		return r.Resource
+	}
+	return r.Resource + "." + r.Group
+}
+
+// Index contains all the resources of a backup, indexed by group resource
+type Index struct {
+	resources map[string][]Resource
+}
+
+// Open reads and indexes a rancher-backup tar.gz file
+func Open(backupFile string) (*Index, error) {
+	file, err := os.Open(backupFile)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return Read(file)
+}
+
+// Read indexes a rancher-backup tar.gz stream
+func Read(r io.Reader) (*Index, error) {
+	gzr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("backup is not a gzip archive: %w", err)
+	}
+	defer gzr.Close()
+
+	index := &Index{resources: map[string][]Resource{}}
+	tr := tar.NewReader(gzr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read backup archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		resource, ok := parsePath(header.Name)
+		if !ok {
+			continue
+		}
+
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", header.Name, err)
+		}
+		if bytes.HasPrefix(data, []byte(encryptedPrefix)) {
+			resource.Encrypted = true
+		} else if err = json.Unmarshal(data, &resource.Object); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", header.Name, err)
+		}
+
+		gr := resource.GroupResource()
+		index.resources[gr] = append(index.resources[gr], resource)
+	}
+	return index, nil
+}
+
+// parsePath converts a backup file path to a Resource;
+// paths have the form <resource>[.<group>]#<version>/[<namespace>/]<name>.json
+func parsePath(name string) (Resource, bool) {
+	name = strings.TrimPrefix(path.Clean(name), "./")
+	if !strings.HasSuffix(name, ".json") {
+		return Resource{}, false
+	}
+	parts := strings.Split(strings.TrimSuffix(name, ".json"), "/")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == filtersDir {
+		return Resource{}, false
+	}
+
+	gvr, version, found := strings.Cut(parts[0], "#")
+	if !found {
+		return Resource{}, false
+	}
+	resourceName, group, _ := strings.Cut(gvr, ".")
+
+	resource := Resource{
+		Resource: resourceName,
+		Group:    group,
+		Version:  version,
+		Name:     parts[len(parts)-1],
+	}
+	if len(parts) == 3 {
+		resource.Namespace = parts[1]
+	}
+	return resource, true
+}
+
+// List returns all the resources of a group resource, e.g. clusters.management.cattle.io
+func (i *Index) List(groupResource string) []Resource {
+	return i.resources[groupResource]
+}
+
+// Get returns a resource by group resource, namespace and name; namespace is empty for cluster scoped resources
+func (i *Index) Get(groupResource, namespace, name string) (Resource, bool) {
+	for _, resource := range i.resources[groupResource] {
+		if resource.Namespace == namespace && resource.Name == name {
+			return resource, true
+		}
+	}
+	return Resource{}, false
+}
+
+// Summary returns the number of resources, and encrypted resources, per group resource
+func (i *Index) Summary() Summary {
+	summary := Summary{}
+	for gr, resources := range i.resources {
+		entry := SummaryEntry{GroupResource: gr, Count: len(resources)}
+		for _, resource := range resources {
+			if resource.Encrypted {
+				entry.Encrypted++
+			}
+		}
+		summary = append(summary, entry)
+	}
+	sort.Slice(summary, func(a, b int) bool { return summary[a].GroupResource < summary[b].GroupResource })
+	return summary
+}
+
+// SummaryEntry is the resource count of a single group resource
+type SummaryEntry struct {
+	GroupResource string
+	Count         int
+	Encrypted     int
+}
+
+// Summary is the resource count of a backup, sorted by group resource
+type Summary []SummaryEntry
+
+func (s Summary) String() string {
+	var builder strings.Builder
+	for _, entry := range s {
+		fmt.Fprintf(&builder, "%s: %d", entry.GroupResource, entry.Count)
+		if entry.Encrypted > 0 {
+			fmt.Fprintf(&builder, " (%d encrypted)", entry.Encrypted)
+		}
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
@@ -0,0 +1,141 @@
+/*
+Copyright © 2022 - 2025 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupinspector_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/backupinspector"
+)
+
+// writeBackup creates a synthetic rancher-backup tarball from a map of path to content
+func writeBackup(files map[string]string) string {
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gzw)
+	for name, content := range files {
+		Expect(tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(content)), Typeflag: tar.TypeReg})).To(Succeed())
+		_, err := tw.Write([]byte(content))
+		Expect(err).To(BeNil())
+	}
+	Expect(tw.Close()).To(Succeed())
+	Expect(gzw.Close()).To(Succeed())
+
+	backupFile := filepath.Join(GinkgoT().TempDir(), "hp-backup.tar.gz")
+	Expect(os.WriteFile(backupFile, buf.Bytes(), 0600)).To(Succeed())
+	return backupFile
+}
+
+var _ = Describe("BackupInspector", func() {
+	const clusterID = "c-abcde"
+
+	var files map[string]string
+	BeforeEach(func() {
+		files = map[string]string{
+			"filters/filters.json":                                               `[{"apiVersion":"v1"}]`,
+			"clusters.management.cattle.io#v3/c-abcde.json":                      `{"metadata":{"name":"c-abcde"},"spec":{"aksConfig":{"azureCredentialSecret":"cattle-global-data:cc-xyz","clusterName":"hp-ci"}}}`,
+			"aksclusterconfigs.aks.cattle.io#v1/cattle-global-data/c-abcde.json": `{"metadata":{"name":"c-abcde","namespace":"cattle-global-data"}}`,
+			"secrets#v1/cattle-global-data/cc-xyz.json":                          `{"metadata":{"name":"cc-xyz"}}`,
+			"settings.management.cattle.io#v3/aks-refresh.json":                  `{"metadata":{"name":"aks-refresh"},"value":"300"}`,
+			"settings.management.cattle.io#v3/server-url.json":                   `{"metadata":{"name":"server-url"}}`,
+		}
+	})
+
+	It("should index resources by group resource, namespace and name", func() {
+		index, err := backupinspector.Open(writeBackup(files))
+		Expect(err).To(BeNil())
+
+		Expect(index.List("clusters.management.cattle.io")).To(HaveLen(1))
+		Expect(index.List("settings.management.cattle.io")).To(HaveLen(2))
+		Expect(index.List("filters")).To(BeEmpty())
+
+		secret, found := index.Get("secrets", "cattle-global-data", "cc-xyz")
+		Expect(found).To(BeTrue())
+		Expect(secret.Version).To(Equal("v1"))
+		Expect(secret.Group).To(BeEmpty())
+
+		_, found = index.Get("secrets", "", "cc-xyz")
+		Expect(found).To(BeFalse())
+	})
+
+	It("should find the hosted-provider content of a cluster", func() {
+		index, err := backupinspector.Open(writeBackup(files))
+		Expect(err).To(BeNil())
+
+		hosted, err := index.HostedCluster("aks", clusterID)
+		Expect(err).To(BeNil())
+		Expect(hosted.ClusterConfig.Namespace).To(Equal(backupinspector.ClusterConfigNamespace))
+		Expect(hosted.CloudCredential.Name).To(Equal("cc-xyz"))
+		Expect(hosted.OperatorSettings).To(HaveLen(1))
+	})
+
+	It("should report every missing hosted-provider resource", func() {
+		delete(files, "aksclusterconfigs.aks.cattle.io#v1/cattle-global-data/c-abcde.json")
+		delete(files, "secrets#v1/cattle-global-data/cc-xyz.json")
+		index, err := backupinspector.Open(writeBackup(files))
+		Expect(err).To(BeNil())
+
+		_, err = index.HostedCluster("aks", clusterID)
+		Expect(err).To(MatchError(SatisfyAll(
+			ContainSubstring("aksclusterconfigs.aks.cattle.io cattle-global-data/c-abcde not found"),
+			ContainSubstring("cloud credential secret cattle-global-data:cc-xyz not found"),
+		)))
+
+		_, err = index.HostedCluster("eks", clusterID)
+		Expect(err).To(MatchError(ContainSubstring("has no spec.eksConfig")))
+
+		_, err = index.HostedCluster("aks", "c-missing")
+		Expect(err).To(MatchError(ContainSubstring("cluster c-missing not found")))
+	})
+
+	It("should index encrypted resources without decoding them", func() {
+		files["secrets#v1/cattle-global-data/cc-xyz.json"] = "k8s:enc:aescbc:v1:key1:\x01\x02\x03"
+		index, err := backupinspector.Open(writeBackup(files))
+		Expect(err).To(BeNil())
+
+		secret, found := index.Get("secrets", "cattle-global-data", "cc-xyz")
+		Expect(found).To(BeTrue())
+		Expect(secret.Encrypted).To(BeTrue())
+		Expect(secret.Object).To(BeNil())
+
+		_, err = index.HostedCluster("aks", clusterID)
+		Expect(err).To(BeNil())
+		Expect(index.Summary().String()).To(ContainSubstring("secrets: 1 (1 encrypted)"))
+	})
+
+	It("should summarize the backup content", func() {
+		index, err := backupinspector.Open(writeBackup(files))
+		Expect(err).To(BeNil())
+
+		summary := index.Summary()
+		Expect(summary).To(HaveLen(4))
+		Expect(summary[0].GroupResource).To(Equal("aksclusterconfigs.aks.cattle.io"))
+		Expect(summary.String()).To(ContainSubstring("settings.management.cattle.io: 2\n"))
+	})
+
+	It("should fail on files which are not a gzip archive", func() {
+		backupFile := filepath.Join(GinkgoT().TempDir(), "hp-backup.tar.gz")
+		Expect(os.WriteFile(backupFile, []byte("not a backup"), 0600)).To(Succeed())
+		_, err := backupinspector.Open(backupFile)
+		Expect(err).To(MatchError(ContainSubstring("not a gzip archive")))
+	})
+})
@@ -0,0 +1,108 @@
+// Package chaos describes the restarts of the Rancher server injected while a hosted cluster is provisioning, upgrading or being deleted,
+// and measures how long Rancher took to serve its API and to resume the reconciliation of the cluster afterwards.
+package chaos
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Target is what gets restarted
+type Target string
+
+const (
+	// RancherDeployment restarts the pods of the rancher deployment; the leader election moves to a new pod
+	RancherDeployment Target = "rancher-deployment"
+	// K3sService restarts the k3s service of the local node, taking down the API server of the local cluster along with Rancher
+	K3sService Target = "k3s-service"
+	// RancherLeader deletes the rancher pod holding LeaderLease only; the other replicas keep running and one of them takes the
+	// leadership over
+	RancherLeader Target = "rancher-leader"
+)
+
+const (
+	// LeaderLease is the lease held by the rancher pod running the controllers
+	LeaderLease = "cattle-controllers"
+	// LeaderLeaseNamespace is the namespace of LeaderLease
+	LeaderLeaseNamespace = "kube-system"
+)
+
+// LeaderPod returns the name of the pod holding a lease from its holder identity, the hostname of the pod optionally followed by
+// an underscore and a unique ID
+func LeaderPod(holderIdentity string) string {
+	pod, _, _ := strings.Cut(strings.TrimSpace(holderIdentity), "_")
+	return pod
+}
+
+// Phase is the lifecycle phase of the cluster Rancher is restarted in
+type Phase string
+
+const (
+	Provisioning Phase = "provisioning"
+	Upgrading    Phase = "upgrading"
+	Deleting     Phase = "deleting"
+)
+
+// State returns the state of the Rancher cluster during the phase
+func (p Phase) State() string {
+	switch p {
+	case Provisioning:
+		return "provisioning"
+	case Upgrading:
+		return "updating"
+	case Deleting:
+		return "removing"
+	}
+	return ""
+}
+
+// Recovery is the timeline of a restart of Rancher
+type Recovery struct {
+	Target Target
+	Phase  Phase
+	// Disrupted is when the restart was triggered
+	Disrupted time.Time
+	// APIBack is when the Rancher API served the cluster again
+	APIBack time.Time
+	// Resumed is when Rancher updated the cluster again, i.e. resumed its reconciliation
+	Resumed time.Time
+}
+
+// APIDowntime is how long the Rancher API was unavailable
+func (r Recovery) APIDowntime() time.Duration {
+	return since(r.Disrupted, r.APIBack)
+}
+
+// ReconciliationDelay is how long Rancher took to resume the reconciliation of the cluster
+func (r Recovery) ReconciliationDelay() time.Duration {
+	return since(r.Disrupted, r.Resumed)
+}
+
+func (r Recovery) String() string {
+	return fmt.Sprintf("%s restarted while %s: API back after %s, reconciliation resumed after %s",
+		r.Target, r.Phase, r.APIDowntime().Round(time.Second), r.ReconciliationDelay().Round(time.Second))
+}
+
+func since(from, to time.Time) time.Duration {
+	if from.IsZero() || to.Before(from) {
+		return 0
+	}
+	return to.Sub(from)
+}
+
+// LatestUpdate returns the latest of the timestamps of the conditions of a cluster, in RFC 3339; the invalid timestamps are ignored
+func LatestUpdate(timestamps []string) (time.Time, bool) {
+	var latest time.Time
+	found := false
+	for _, timestamp := range timestamps {
+		t, err := time.Parse(time.RFC3339, timestamp)
+		if err != nil {
+			continue
+		}
+		if !found || t.After(latest) {
+			latest, found = t, true
+		}
+	}
+	return latest, found
+}
@@ -0,0 +1,74 @@
+/*
+Copyright © 2022 - 2025 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chaos_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/chaos"
+)
+
+var _ = Describe("Recovery", func() {
+	disrupted := time.Date(2025, 1, 2, 15, 0, 0, 0, time.UTC)
+
+	It("measures the API downtime and the reconciliation delay", func() {
+		recovery := chaos.Recovery{
+			Target:    chaos.K3sService,
+			Phase:     chaos.Upgrading,
+			Disrupted: disrupted,
+			APIBack:   disrupted.Add(95 * time.Second),
+			Resumed:   disrupted.Add(4*time.Minute + 300*time.Millisecond),
+		}
+		Expect(recovery.APIDowntime()).To(Equal(95 * time.Second))
+		Expect(recovery.ReconciliationDelay()).To(Equal(4*time.Minute + 300*time.Millisecond))
+		Expect(recovery.String()).To(Equal("k3s-service restarted while upgrading: API back after 1m35s, reconciliation resumed after 4m0s"))
+	})
+
+	It("reports no delay until it is measured", func() {
+		recovery := chaos.Recovery{Target: chaos.RancherDeployment, Phase: chaos.Deleting, Disrupted: disrupted}
+		Expect(recovery.APIDowntime()).To(BeZero())
+		Expect(recovery.ReconciliationDelay()).To(BeZero())
+	})
+
+	It("maps the phases to the states of the cluster", func() {
+		Expect(chaos.Provisioning.State()).To(Equal("provisioning"))
+		Expect(chaos.Upgrading.State()).To(Equal("updating"))
+		Expect(chaos.Deleting.State()).To(Equal("removing"))
+	})
+})
+
+var _ = Describe("LatestUpdate", func() {
+	It("returns the latest valid timestamp", func() {
+		latest, ok := chaos.LatestUpdate([]string{"2025-01-02T15:00:00Z", "", "not a time", "2025-01-02T15:04:05Z", "2025-01-02T14:59:00Z"})
+		Expect(ok).To(BeTrue())
+		Expect(latest).To(Equal(time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)))
+	})
+
+	It("returns nothing without a valid timestamp", func() {
+		_, ok := chaos.LatestUpdate([]string{"", "yesterday"})
+		Expect(ok).To(BeFalse())
+	})
+})
+
+var _ = Describe("LeaderPod", func() {
+	It("returns the pod of the holder identity", func() {
+		Expect(chaos.LeaderPod("rancher-7d9c6b5f4d-x2kzq")).To(Equal("rancher-7d9c6b5f4d-x2kzq"))
+		Expect(chaos.LeaderPod("rancher-7d9c6b5f4d-x2kzq_6f1c9a0e-5b7d-4c2e-9a8f-3d2b1c0e4f5a")).To(Equal("rancher-7d9c6b5f4d-x2kzq"))
+		Expect(chaos.LeaderPod(" \n")).To(BeEmpty())
+	})
+})
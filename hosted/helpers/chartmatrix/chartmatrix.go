@@ -0,0 +1,212 @@
+// Package chartmatrix plans the upgrades and downgrades between the versions of an operator chart: it selects the versions within
+// a window, orders the transitions so that every ordered pair of versions is walked once, and renders the compatibility table of
+// the transitions which broke the reconciliation of a cluster.
+package chartmatrix
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// Window selects the chart versions of the matrix
+type Window struct {
+	// Size is the number of latest versions, 0 for all of them
+	Size int
+	// Constraint is a semver constraint of the versions, e.g. ">=105.0.0 <106.0.0"
+	Constraint string
+}
+
+// ParseWindow parses a window given as a number of latest versions, e.g. "4", or as a semver constraint, e.g. ">=105.0.0 <106.0.0"
+func ParseWindow(window string) (Window, error) {
+	window = strings.TrimSpace(window)
+	if window == "" {
+		return Window{}, fmt.Errorf("empty chart window")
+	}
+	if size, err := strconv.Atoi(window); err == nil {
+		if size < 2 {
+			return Window{}, fmt.Errorf("the chart window needs at least 2 versions, got %d", size)
+		}
+		return Window{Size: size}, nil
+	}
+	if _, err := semver.NewConstraint(window); err != nil {
+		return Window{}, fmt.Errorf("invalid chart window %q: %w", window, err)
+	}
+	return Window{Constraint: window}, nil
+}
+
+// Select returns the versions in the window, latest first; current is always selected, the window ends at it when it is a size
+// so that the matrix covers the versions before the current one. The versions which are not semver are ignored.
+func (w Window) Select(versions []string, current string) ([]string, error) {
+	currentVersion, err := semver.NewVersion(current)
+	if err != nil {
+		return nil, fmt.Errorf("invalid current chart version %q: %w", current, err)
+	}
+	var constraint *semver.Constraints
+	if w.Constraint != "" {
+		if constraint, err = semver.NewConstraint(w.Constraint); err != nil {
+			return nil, fmt.Errorf("invalid chart window %q: %w", w.Constraint, err)
+		}
+	}
+
+	seen := map[string]bool{current: true}
+	selected := []*semver.Version{currentVersion}
+	for _, version := range versions {
+		v, err := semver.NewVersion(version)
+		if err != nil || seen[v.Original()] {
+			continue
+		}
+		seen[v.Original()] = true
+		if constraint != nil && !constraint.Check(v) {
+			continue
+		}
+		if w.Size > 0 && v.GreaterThan(currentVersion) {
+			continue
+		}
+		selected = append(selected, v)
+	}
+	sort.SliceStable(selected, func(i, j int) bool { return selected[i].GreaterThan(selected[j]) })
+	if w.Size > 0 && len(selected) > w.Size {
+		selected = selected[:w.Size]
+	}
+
+	var result []string
+	for _, v := range selected {
+		result = append(result, v.Original())
+	}
+	return result, nil
+}
+
+// Hop is a transition of the chart from a version to another
+type Hop struct {
+	From string
+	To   string
+}
+
+// Upgrade reports whether the hop upgrades the chart, it downgrades it otherwise
+func (h Hop) Upgrade() bool {
+	from, errFrom := semver.NewVersion(h.From)
+	to, errTo := semver.NewVersion(h.To)
+	if errFrom != nil || errTo != nil {
+		return false
+	}
+	return to.GreaterThan(from)
+}
+
+func (h Hop) String() string {
+	direction := "downgrade"
+	if h.Upgrade() {
+		direction = "upgrade"
+	}
+	return fmt.Sprintf("%s -> %s (%s)", h.From, h.To, direction)
+}
+
+// Walk returns the hops from start through every ordered pair of versions, each exactly once, back to start; it is an eulerian
+// circuit of the complete directed graph of the versions, so that a single chart installation walks all the transitions
+func Walk(start string, versions []string) []Hop {
+	vertices := []string{start}
+	for _, version := range versions {
+		if version != start {
+			vertices = append(vertices, version)
+		}
+	}
+	next := map[string][]string{}
+	for _, from := range vertices {
+		for _, to := range vertices {
+			if from != to {
+				next[from] = append(next[from], to)
+			}
+		}
+	}
+
+	// Hierholzer's algorithm
+	var circuit []string
+	stack := []string{start}
+	for len(stack) > 0 {
+		v := stack[len(stack)-1]
+		if len(next[v]) > 0 {
+			stack = append(stack, next[v][0])
+			next[v] = next[v][1:]
+			continue
+		}
+		stack = stack[:len(stack)-1]
+		circuit = append(circuit, v)
+	}
+
+	var hops []Hop
+	for i := len(circuit) - 1; i > 0; i-- {
+		hops = append(hops, Hop{From: circuit[i], To: circuit[i-1]})
+	}
+	return hops
+}
+
+// Result is the outcome of a hop
+type Result struct {
+	Hop Hop
+	// Err tells how the hop broke the reconciliation of the cluster, it is empty when it did not
+	Err      string
+	Duration time.Duration
+}
+
+// Passed reports whether the cluster was reconciled after the hop
+func (r Result) Passed() bool {
+	return r.Err == ""
+}
+
+func (r Result) String() string {
+	if r.Passed() {
+		return fmt.Sprintf("%s: passed in %s", r.Hop, r.Duration.Round(time.Second))
+	}
+	return fmt.Sprintf("%s: broken after %s: %s", r.Hop, r.Duration.Round(time.Second), r.Err)
+}
+
+// Broken returns the results of the hops which broke the reconciliation
+func Broken(results []Result) []Result {
+	var broken []Result
+	for _, result := range results {
+		if !result.Passed() {
+			broken = append(broken, result)
+		}
+	}
+	return broken
+}
+
+// Table renders the results as a markdown table of the transitions, from a version in a row to a version in a column:
+// "ok" when the cluster was reconciled, "broken" when it was not, "-" when the transition was not walked
+func Table(versions []string, results []Result) string {
+	outcomes := map[Hop]string{}
+	for _, result := range results {
+		outcome := "ok"
+		if !result.Passed() {
+			outcome = "broken"
+		}
+		outcomes[result.Hop] = outcome
+	}
+
+	var b strings.Builder
+	b.WriteString("| from \\ to |")
+	for _, to := range versions {
+		fmt.Fprintf(&b, " %s |", to)
+	}
+	b.WriteString("\n|---|")
+	for range versions {
+		b.WriteString("---|")
+	}
+	b.WriteString("\n")
+	for _, from := range versions {
+		fmt.Fprintf(&b, "| %s |", from)
+		for _, to := range versions {
+			outcome, ok := outcomes[Hop{From: from, To: to}]
+			if !ok {
+				outcome = "-"
+			}
+			fmt.Fprintf(&b, " %s |", outcome)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,112 @@
+/*
+Copyright © 2022 - 2025 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chartmatrix_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/chartmatrix"
+)
+
+var available = []string{"106.0.0", "105.1.1", "105.1.0", "105.0.2-rc.1", "105.0.0", "104.2.0", "not-semver", "105.1.0"}
+
+var _ = Describe("ParseWindow", func() {
+	It("parses a number of versions", func() {
+		Expect(chartmatrix.ParseWindow(" 4 ")).To(Equal(chartmatrix.Window{Size: 4}))
+	})
+
+	It("parses a semver constraint", func() {
+		Expect(chartmatrix.ParseWindow(">=105.0.0 <106.0.0")).To(Equal(chartmatrix.Window{Constraint: ">=105.0.0 <106.0.0"}))
+	})
+
+	It("rejects the invalid windows", func() {
+		for _, window := range []string{"", "1", "0", "latest"} {
+			_, err := chartmatrix.ParseWindow(window)
+			Expect(err).To(HaveOccurred(), window)
+		}
+	})
+})
+
+var _ = Describe("Select", func() {
+	It("selects the latest versions up to the current one", func() {
+		Expect(chartmatrix.Window{Size: 3}.Select(available, "105.1.0")).To(Equal([]string{"105.1.0", "105.0.2-rc.1", "105.0.0"}))
+	})
+
+	It("selects the versions of the constraint and the current one", func() {
+		Expect(chartmatrix.Window{Constraint: ">=105.0.0"}.Select(available, "105.1.0")).To(Equal([]string{"106.0.0", "105.1.1", "105.1.0", "105.0.0"}))
+		Expect(chartmatrix.Window{Constraint: "~104"}.Select(available, "105.1.0")).To(Equal([]string{"105.1.0", "104.2.0"}))
+	})
+
+	It("rejects an invalid current version", func() {
+		_, err := chartmatrix.Window{Size: 2}.Select(available, "")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Walk", func() {
+	It("walks every ordered pair of versions once, back to the start", func() {
+		versions := []string{"106.0.0", "105.1.0", "105.0.0", "104.2.0"}
+		hops := chartmatrix.Walk("105.1.0", versions)
+		Expect(hops).To(HaveLen(12))
+		Expect(hops[0].From).To(Equal("105.1.0"))
+		Expect(hops[len(hops)-1].To).To(Equal("105.1.0"))
+
+		walked := map[chartmatrix.Hop]bool{}
+		for i, hop := range hops {
+			Expect(hop.From).ToNot(Equal(hop.To))
+			Expect(walked).ToNot(HaveKey(hop))
+			walked[hop] = true
+			if i > 0 {
+				Expect(hop.From).To(Equal(hops[i-1].To))
+			}
+		}
+	})
+
+	It("adds the start to the versions", func() {
+		Expect(chartmatrix.Walk("105.1.0", []string{"105.0.0"})).To(Equal([]chartmatrix.Hop{
+			{From: "105.1.0", To: "105.0.0"},
+			{From: "105.0.0", To: "105.1.0"},
+		}))
+		Expect(chartmatrix.Walk("105.1.0", []string{"105.1.0"})).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Hop", func() {
+	It("tells the upgrades from the downgrades", func() {
+		Expect(chartmatrix.Hop{From: "105.0.0", To: "105.1.0"}.String()).To(Equal("105.0.0 -> 105.1.0 (upgrade)"))
+		Expect(chartmatrix.Hop{From: "105.1.0", To: "105.0.2-rc.1"}.String()).To(Equal("105.1.0 -> 105.0.2-rc.1 (downgrade)"))
+	})
+})
+
+var _ = Describe("Table", func() {
+	It("renders the outcome of the transitions", func() {
+		results := []chartmatrix.Result{
+			{Hop: chartmatrix.Hop{From: "105.1.0", To: "105.0.0"}, Duration: 5 * time.Minute},
+			{Hop: chartmatrix.Hop{From: "105.0.0", To: "105.1.0"}, Err: "cluster is not ready", Duration: 30 * time.Minute},
+		}
+		Expect(chartmatrix.Table([]string{"105.1.0", "105.0.0", "104.2.0"}, results)).To(Equal(`| from \ to | 105.1.0 | 105.0.0 | 104.2.0 |
+|---|---|---|---|
+| 105.1.0 | - | ok | - |
+| 105.0.0 | broken | - | - |
+| 104.2.0 | - | - | - |
+`))
+		Expect(chartmatrix.Broken(results)).To(Equal(results[1:]))
+		Expect(results[0].String()).To(Equal("105.1.0 -> 105.0.0 (downgrade): passed in 5m0s"))
+		Expect(results[1].String()).To(Equal("105.0.0 -> 105.1.0 (upgrade): broken after 30m0s: cluster is not ready"))
+	})
+})
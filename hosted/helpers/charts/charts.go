@@ -0,0 +1,279 @@
+// Package charts manages the rancher-<provider>-operator and rancher-<provider>-operator-crd releases in process with the Helm SDK:
+// it lists, upgrades, downgrades, rolls back and uninstalls them, and exposes their history and the changes of their values
+// between revisions. The charts are fetched from a Repository, either a chart repository configured in helm or a local directory.
+package charts
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// OperatorChart returns the name of the operator chart of provider, its release has the same name
+func OperatorChart(provider string) string {
+	return fmt.Sprintf("rancher-%s-operator", provider)
+}
+
+// CRDChart returns the name of the CRD chart of the operator of provider, its release has the same name
+func CRDChart(provider string) string {
+	return OperatorChart(provider) + "-crd"
+}
+
+// Release is a revision of a release of an operator chart
+type Release struct {
+	Name       string
+	Namespace  string
+	Chart      string
+	Version    string
+	AppVersion string
+	Revision   int
+	Status     string
+	// Updated is when the revision was deployed
+	Updated     time.Time
+	Description string
+}
+
+func (r Release) String() string {
+	return fmt.Sprintf("%s revision %d: %s %s (app %s), %s", r.Name, r.Revision, r.Chart, r.Version, r.AppVersion, r.Status)
+}
+
+func newRelease(rel *release.Release) Release {
+	r := Release{Name: rel.Name, Namespace: rel.Namespace, Revision: rel.Version}
+	if rel.Chart != nil && rel.Chart.Metadata != nil {
+		r.Chart = rel.Chart.Metadata.Name
+		r.Version = rel.Chart.Metadata.Version
+		r.AppVersion = rel.Chart.Metadata.AppVersion
+	}
+	if rel.Info != nil {
+		r.Status = rel.Info.Status.String()
+		r.Updated = rel.Info.LastDeployed.Time
+		r.Description = rel.Info.Description
+	}
+	return r
+}
+
+// NewConfiguration initializes the Helm SDK for the releases of namespace, in the cluster of getter; the releases are stored
+// by the driver of HELM_DRIVER, in secrets by default
+func NewConfiguration(getter genericclioptions.RESTClientGetter, namespace string, log action.DebugLog) (*action.Configuration, error) {
+	cfg := new(action.Configuration)
+	if err := cfg.Init(getter, namespace, os.Getenv("HELM_DRIVER"), log); err != nil {
+		return nil, fmt.Errorf("failed to initialize helm for namespace %s: %w", namespace, err)
+	}
+	return cfg, nil
+}
+
+// Manager manages the operator and CRD releases of a provider
+type Manager struct {
+	Provider   string
+	Namespace  string
+	Repository Repository
+	// Wait for the resources of the releases to be ready after an upgrade, a rollback or an uninstallation
+	Wait    bool
+	Timeout time.Duration
+
+	cfg *action.Configuration
+}
+
+// NewManager returns the Manager of the releases of provider in namespace, waiting up to 5 minutes for them to be ready
+func NewManager(cfg *action.Configuration, provider, namespace string, repository Repository) *Manager {
+	return &Manager{
+		Provider:   provider,
+		Namespace:  namespace,
+		Repository: repository,
+		Wait:       true,
+		Timeout:    5 * time.Minute,
+		cfg:        cfg,
+	}
+}
+
+// releases returns the names of the releases in their upgrade order: the CRDs come before the operator using them
+func (m *Manager) releases() []string {
+	return []string{CRDChart(m.Provider), OperatorChart(m.Provider)}
+}
+
+// List returns the last revision of the installed releases, in any state
+func (m *Manager) List() ([]Release, error) {
+	list := action.NewList(m.cfg)
+	list.All = true
+	list.Filter = fmt.Sprintf("^%s(-crd)?$", regexp.QuoteMeta(OperatorChart(m.Provider)))
+	rels, err := list.Run()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the %s operator releases: %w", m.Provider, err)
+	}
+	var releases []Release
+	for _, rel := range rels {
+		releases = append(releases, newRelease(rel))
+	}
+	sort.Slice(releases, func(i, j int) bool { return releases[i].Name < releases[j].Name })
+	return releases, nil
+}
+
+// CurrentVersion returns the chart version of the operator release, empty when it is not installed
+func (m *Manager) CurrentVersion() (string, error) {
+	history, err := m.History(OperatorChart(m.Provider))
+	if err != nil || len(history) == 0 {
+		return "", err
+	}
+	return history[len(history)-1].Version, nil
+}
+
+// Versions returns the versions of the operator chart available in the repository, latest first
+func (m *Manager) Versions() ([]string, error) {
+	return m.Repository.Versions(OperatorChart(m.Provider))
+}
+
+// Upgrade upgrades the CRD and operator releases to the chart version, installing the missing ones; the defaults are the values of
+// the new chart, the values set on the current revision and values are merged over them
+func (m *Manager) Upgrade(version string, values map[string]interface{}) error {
+	for _, name := range m.releases() {
+		chrt, err := m.Repository.Load(name, version)
+		if err != nil {
+			return err
+		}
+		installed, err := m.installed(name)
+		if err != nil {
+			return err
+		}
+		if !installed {
+			install := action.NewInstall(m.cfg)
+			install.ReleaseName = name
+			install.Namespace = m.Namespace
+			install.Wait = m.Wait
+			install.Timeout = m.Timeout
+			if _, err = install.Run(chrt, values); err != nil {
+				return fmt.Errorf("failed to install %s %s: %w", name, version, err)
+			}
+			continue
+		}
+		upgrade := action.NewUpgrade(m.cfg)
+		upgrade.Namespace = m.Namespace
+		upgrade.Wait = m.Wait
+		upgrade.Timeout = m.Timeout
+		upgrade.ResetThenReuseValues = true
+		if _, err = upgrade.Run(name, chrt, values); err != nil {
+			return fmt.Errorf("failed to upgrade %s to %s: %w", name, version, err)
+		}
+	}
+	return nil
+}
+
+// Downgrade downgrades the CRD and operator releases to the chart version, which must be lower than the current one
+func (m *Manager) Downgrade(version string) error {
+	current, err := m.CurrentVersion()
+	if err != nil {
+		return err
+	}
+	if current == "" {
+		return fmt.Errorf("%s is not installed", OperatorChart(m.Provider))
+	}
+	lower, err := lessThan(version, current)
+	if err != nil {
+		return err
+	}
+	if !lower {
+		return fmt.Errorf("cannot downgrade %s from %s to %s", OperatorChart(m.Provider), current, version)
+	}
+	return m.Upgrade(version, nil)
+}
+
+// Rollback rolls the release back to the revision, to the previous one when revision is 0
+func (m *Manager) Rollback(name string, revision int) error {
+	rollback := action.NewRollback(m.cfg)
+	rollback.Version = revision
+	rollback.Wait = m.Wait
+	rollback.Timeout = m.Timeout
+	if err := rollback.Run(name); err != nil {
+		return fmt.Errorf("failed to roll %s back to revision %d: %w", name, revision, err)
+	}
+	return nil
+}
+
+// Uninstall uninstalls the operator release, then the CRD one; the releases which are not installed are skipped
+func (m *Manager) Uninstall() error {
+	for _, name := range []string{OperatorChart(m.Provider), CRDChart(m.Provider)} {
+		installed, err := m.installed(name)
+		if err != nil {
+			return err
+		}
+		if !installed {
+			continue
+		}
+		uninstall := action.NewUninstall(m.cfg)
+		uninstall.Wait = m.Wait
+		uninstall.Timeout = m.Timeout
+		if _, err = uninstall.Run(name); err != nil {
+			return fmt.Errorf("failed to uninstall %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// History returns the revisions of the release, oldest first; it is empty when the release is not installed
+func (m *Manager) History(name string) ([]Release, error) {
+	history := action.NewHistory(m.cfg)
+	history.Max = 256
+	rels, err := history.Run(name)
+	if errors.Is(err, driver.ErrReleaseNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the history of %s: %w", name, err)
+	}
+	var releases []Release
+	for _, rel := range rels {
+		releases = append(releases, newRelease(rel))
+	}
+	sort.Slice(releases, func(i, j int) bool { return releases[i].Revision < releases[j].Revision })
+	return releases, nil
+}
+
+// Values returns the values of the revision of the release rendered with the defaults of its chart
+func (m *Manager) Values(name string, revision int) (map[string]interface{}, error) {
+	get := action.NewGetValues(m.cfg)
+	get.Version = revision
+	get.AllValues = true
+	values, err := get.Run(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the values of %s revision %d: %w", name, revision, err)
+	}
+	return values, nil
+}
+
+// ValuesDiff returns the changes of the rendered values of the release from a revision to another
+func (m *Manager) ValuesDiff(name string, from, to int) ([]Change, error) {
+	fromValues, err := m.Values(name, from)
+	if err != nil {
+		return nil, err
+	}
+	toValues, err := m.Values(name, to)
+	if err != nil {
+		return nil, err
+	}
+	return DiffValues(fromValues, toValues), nil
+}
+
+func (m *Manager) installed(name string) (bool, error) {
+	history, err := m.History(name)
+	return len(history) > 0, err
+}
+
+func lessThan(version, other string) (bool, error) {
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return false, fmt.Errorf("invalid chart version %q: %w", version, err)
+	}
+	o, err := semver.NewVersion(other)
+	if err != nil {
+		return false, fmt.Errorf("invalid chart version %q: %w", other, err)
+	}
+	return v.LessThan(o), nil
+}
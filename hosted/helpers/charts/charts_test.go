@@ -0,0 +1,181 @@
+/*
+Copyright © 2022 - 2025 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package charts_test
+
+import (
+	"io"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	kubefake "helm.sh/helm/v3/pkg/kube/fake"
+	"helm.sh/helm/v3/pkg/repo"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/charts"
+)
+
+const namespace = "cattle-system"
+
+// newRepository writes a local chart repository with the versions of the operator and CRD charts of provider
+func newRepository(provider string, versions ...string) charts.LocalRepository {
+	dir := GinkgoT().TempDir()
+	for _, version := range versions {
+		for _, name := range []string{charts.OperatorChart(provider), charts.CRDChart(provider)} {
+			_, err := chartutil.Save(&chart.Chart{
+				Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: name, Version: version, AppVersion: "v" + version},
+				// the values.yaml of the package is written from the raw files
+				Raw: []*chart.File{{Name: "values.yaml", Data: []byte("replicas: 1\nimage:\n  tag: v" + version + "\n")}},
+				Templates: []*chart.File{{
+					Name: "templates/configmap.yaml",
+					Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ .Release.Name }}\ndata:\n  tag: {{ .Values.image.tag | quote }}\n"),
+				}},
+			}, dir)
+			Expect(err).To(BeNil())
+		}
+	}
+	index, err := repo.IndexDirectory(dir, "")
+	Expect(err).To(BeNil())
+	Expect(index.WriteFile(filepath.Join(dir, "index.yaml"), 0o644)).To(Succeed())
+	return charts.LocalRepository{Dir: dir}
+}
+
+// newConfiguration stores the releases in memory and prints the resources instead of applying them
+func newConfiguration() *action.Configuration {
+	memory := driver.NewMemory()
+	memory.SetNamespace(namespace)
+	return &action.Configuration{
+		Releases:     storage.Init(memory),
+		KubeClient:   &kubefake.PrintingKubeClient{Out: io.Discard},
+		Capabilities: chartutil.DefaultCapabilities,
+		Log:          func(string, ...interface{}) {},
+	}
+}
+
+var _ = Describe("Manager", func() {
+	var manager *charts.Manager
+
+	BeforeEach(func() {
+		manager = charts.NewManager(newConfiguration(), "aks", namespace, newRepository("aks", "105.0.0", "105.1.0", "106.0.0"))
+		manager.Wait = false
+	})
+
+	It("lists the versions of the repository, latest first", func() {
+		Expect(manager.Versions()).To(Equal([]string{"106.0.0", "105.1.0", "105.0.0"}))
+	})
+
+	It("installs the missing releases", func() {
+		Expect(manager.CurrentVersion()).To(BeEmpty())
+		Expect(manager.Upgrade("105.0.0", nil)).To(Succeed())
+
+		releases, err := manager.List()
+		Expect(err).To(BeNil())
+		Expect(releases).To(HaveLen(2))
+		Expect(releases[0].Name).To(Equal("rancher-aks-operator"))
+		Expect(releases[1].Name).To(Equal("rancher-aks-operator-crd"))
+		for _, release := range releases {
+			Expect(release.Version).To(Equal("105.0.0"))
+			Expect(release.AppVersion).To(Equal("v105.0.0"))
+			Expect(release.Revision).To(Equal(1))
+			Expect(release.Status).To(Equal("deployed"))
+		}
+		Expect(manager.CurrentVersion()).To(Equal("105.0.0"))
+	})
+
+	It("upgrades, downgrades and rolls back the releases", func() {
+		operator := charts.OperatorChart("aks")
+		Expect(manager.Upgrade("105.0.0", map[string]interface{}{"debug": true})).To(Succeed())
+		Expect(manager.Upgrade("106.0.0", map[string]interface{}{"replicas": float64(2)})).To(Succeed())
+		Expect(manager.CurrentVersion()).To(Equal("106.0.0"))
+
+		By("keeping the values set on the previous revision and taking the defaults of the new chart")
+		changes, err := manager.ValuesDiff(operator, 1, 2)
+		Expect(err).To(BeNil())
+		Expect(changes).To(Equal([]charts.Change{
+			{Type: charts.Modified, Key: "image.tag", From: "v105.0.0", To: "v106.0.0"},
+			{Type: charts.Modified, Key: "replicas", From: float64(1), To: float64(2)},
+		}))
+
+		By("refusing to downgrade to a later version")
+		Expect(manager.Downgrade("106.0.0")).ToNot(Succeed())
+		Expect(manager.Downgrade("105.1.0")).To(Succeed())
+		Expect(manager.CurrentVersion()).To(Equal("105.1.0"))
+
+		By("rolling back to the previous revision")
+		Expect(manager.Rollback(operator, 0)).To(Succeed())
+		Expect(manager.CurrentVersion()).To(Equal("106.0.0"))
+
+		history, err := manager.History(operator)
+		Expect(err).To(BeNil())
+		var versions []string
+		for _, release := range history {
+			versions = append(versions, release.Version)
+		}
+		Expect(versions).To(Equal([]string{"105.0.0", "106.0.0", "105.1.0", "106.0.0"}))
+		Expect(history[len(history)-1].Status).To(Equal("deployed"))
+		Expect(history[len(history)-2].Status).To(Equal("superseded"))
+	})
+
+	It("uninstalls the releases", func() {
+		Expect(manager.Uninstall()).To(Succeed())
+		Expect(manager.Upgrade("105.0.0", nil)).To(Succeed())
+		Expect(manager.Uninstall()).To(Succeed())
+		Expect(manager.List()).To(BeEmpty())
+		Expect(manager.History(charts.CRDChart("aks"))).To(BeEmpty())
+	})
+
+	It("fails on a version missing from the repository", func() {
+		Expect(manager.Upgrade("104.0.0", nil)).ToNot(Succeed())
+		Expect(manager.List()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("DiffValues", func() {
+	It("compares the nested values", func() {
+		changes := charts.DiffValues(map[string]interface{}{
+			"image":    map[string]interface{}{"repository": "rancher/aks-operator", "tag": "v1.9.0"},
+			"replicas": 1,
+			"debug":    false,
+			"args":     []interface{}{"--v=2"},
+		}, map[string]interface{}{
+			"image":       map[string]interface{}{"repository": "rancher/aks-operator", "tag": "v1.10.0"},
+			"replicas":    1,
+			"args":        []interface{}{"--v=2", "--metrics"},
+			"httpProxy":   "http://172.17.0.1:3128",
+			"tolerations": map[string]interface{}{},
+		})
+		var lines []string
+		for _, change := range changes {
+			lines = append(lines, change.String())
+		}
+		Expect(lines).To(Equal([]string{
+			"~ args: [--v=2] -> [--v=2 --metrics]",
+			"- debug: false",
+			"+ httpProxy: http://172.17.0.1:3128",
+			"~ image.tag: v1.9.0 -> v1.10.0",
+			"+ tolerations: map[]",
+		}))
+	})
+
+	It("finds no change in the same values", func() {
+		values := map[string]interface{}{"image": map[string]interface{}{"tag": "v1.9.0"}}
+		Expect(charts.DiffValues(values, values)).To(BeEmpty())
+		Expect(charts.DiffValues(nil, nil)).To(BeEmpty())
+	})
+})
@@ -0,0 +1,106 @@
+package charts
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/helmpath"
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+// Repository provides the versions of the operator charts
+type Repository interface {
+	// Versions returns the versions of the chart, latest first
+	Versions(name string) ([]string, error)
+	// Load returns the version of the chart
+	Load(name, version string) (*chart.Chart, error)
+}
+
+// LocalRepository is a chart repository in a local directory: an index.yaml and the packaged charts, as written by `helm repo index`
+type LocalRepository struct {
+	Dir string
+}
+
+func (r LocalRepository) Versions(name string) ([]string, error) {
+	index, err := repo.LoadIndexFile(filepath.Join(r.Dir, "index.yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the index of %s: %w", r.Dir, err)
+	}
+	return versions(index, name)
+}
+
+func (r LocalRepository) Load(name, version string) (*chart.Chart, error) {
+	index, err := repo.LoadIndexFile(filepath.Join(r.Dir, "index.yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the index of %s: %w", r.Dir, err)
+	}
+	chartVersion, err := index.Get(name, version)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s is not in %s: %w", name, version, r.Dir, err)
+	}
+	if len(chartVersion.URLs) == 0 {
+		return nil, fmt.Errorf("%s %s has no URL in %s", name, version, r.Dir)
+	}
+	path := chartVersion.URLs[0]
+	if u, err := url.Parse(path); err == nil && u.Scheme == "file" {
+		path = u.Path
+	} else if !filepath.IsAbs(path) {
+		path = filepath.Join(r.Dir, path)
+	}
+	return load(path, name, version)
+}
+
+// HelmRepository is a chart repository added to helm, e.g. with AddRancherCharts; its index is read from the helm cache,
+// run `helm repo update` to refresh it
+type HelmRepository struct {
+	Name     string
+	Settings *cli.EnvSettings
+}
+
+// NewHelmRepository returns the chart repository added to helm as name, with the settings of the helm environment variables
+func NewHelmRepository(name string) HelmRepository {
+	return HelmRepository{Name: name, Settings: cli.New()}
+}
+
+func (r HelmRepository) Versions(name string) ([]string, error) {
+	index, err := repo.LoadIndexFile(filepath.Join(r.Settings.RepositoryCache, helmpath.CacheIndexFile(r.Name)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the index of the %s repository: %w", r.Name, err)
+	}
+	return versions(index, name)
+}
+
+func (r HelmRepository) Load(name, version string) (*chart.Chart, error) {
+	options := action.ChartPathOptions{Version: version}
+	path, err := options.LocateChart(fmt.Sprintf("%s/%s", r.Name, name), r.Settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s %s from the %s repository: %w", name, version, r.Name, err)
+	}
+	return load(path, name, version)
+}
+
+func versions(index *repo.IndexFile, name string) ([]string, error) {
+	index.SortEntries()
+	chartVersions, ok := index.Entries[name]
+	if !ok {
+		return nil, fmt.Errorf("no chart %s in the repository", name)
+	}
+	var result []string
+	for _, chartVersion := range chartVersions {
+		result = append(result, chartVersion.Version)
+	}
+	return result, nil
+}
+
+func load(path, name, version string) (*chart.Chart, error) {
+	chrt, err := loader.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s %s: %w", name, version, err)
+	}
+	return chrt, nil
+}
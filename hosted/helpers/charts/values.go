@@ -0,0 +1,84 @@
+package charts
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// ChangeType is how a value changed between two revisions
+type ChangeType string
+
+const (
+	Added    ChangeType = "+"
+	Removed  ChangeType = "-"
+	Modified ChangeType = "~"
+)
+
+// Change is a change of a value, Key is the dotted path of the value, e.g. image.tag
+type Change struct {
+	Type ChangeType
+	Key  string
+	From interface{}
+	To   interface{}
+}
+
+func (c Change) String() string {
+	switch c.Type {
+	case Added:
+		return fmt.Sprintf("%s %s: %v", c.Type, c.Key, c.To)
+	case Removed:
+		return fmt.Sprintf("%s %s: %v", c.Type, c.Key, c.From)
+	}
+	return fmt.Sprintf("%s %s: %v -> %v", c.Type, c.Key, c.From, c.To)
+}
+
+// DiffValues returns the changes of the values from a revision to another, sorted by key; nested maps are compared value by value,
+// lists as a whole
+func DiffValues(from, to map[string]interface{}) []Change {
+	fromValues, toValues := map[string]interface{}{}, map[string]interface{}{}
+	flatten("", from, fromValues)
+	flatten("", to, toValues)
+
+	var changes []Change
+	for key, value := range fromValues {
+		other, ok := toValues[key]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Type: Removed, Key: key, From: value})
+		case !reflect.DeepEqual(value, other):
+			changes = append(changes, Change{Type: Modified, Key: key, From: value, To: other})
+		}
+	}
+	for key, value := range toValues {
+		if _, ok := fromValues[key]; !ok {
+			changes = append(changes, Change{Type: Added, Key: key, To: value})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes
+}
+
+// flatten stores the leaves of values by their dotted path; an empty map is a leaf
+func flatten(prefix string, values map[string]interface{}, leaves map[string]interface{}) {
+	for key, value := range values {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if nested, ok := asMap(value); ok && len(nested) > 0 {
+			flatten(key, nested, leaves)
+			continue
+		}
+		leaves[key] = value
+	}
+}
+
+// asMap returns value as a map of values, including the named map types such as chartutil.Values
+func asMap(value interface{}) (map[string]interface{}, bool) {
+	mapType := reflect.TypeOf(map[string]interface{}{})
+	v := reflect.ValueOf(value)
+	if !v.IsValid() || !v.Type().ConvertibleTo(mapType) || v.Kind() != reflect.Map {
+		return nil, false
+	}
+	return v.Convert(mapType).Interface().(map[string]interface{}), true
+}
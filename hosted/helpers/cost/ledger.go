@@ -0,0 +1,210 @@
+package cost
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	ledgerFile = "ledger.json"
+	lockFile   = "ledger.lock"
+	reportFile = "report.txt"
+)
+
+var (
+	// ErrOverBudget is returned by Reserve when the estimate of a spec does not fit in the budget of the run
+	ErrOverBudget = errors.New("the estimated cost exceeds the budget of the run")
+	// ErrNotReserved is returned by Complete when the cluster has not been reserved in the ledger, e.g. it was adopted from a previous run
+	ErrNotReserved = errors.New("the cluster is not in the cost ledger")
+)
+
+// Record is the cluster of a spec: its estimated cost when the spec starts, and the cluster-hours it actually used once it is done
+type Record struct {
+	Suite          string    `json:"suite"`
+	Spec           string    `json:"spec"`
+	ClusterName    string    `json:"clusterName"`
+	Shape          Shape     `json:"shape"`
+	HourlyRate     float64   `json:"hourlyRate"`
+	Unpriced       []string  `json:"unpriced,omitempty"`
+	EstimatedHours float64   `json:"estimatedHours"`
+	Start          time.Time `json:"start"`
+	End            time.Time `json:"end,omitempty"`
+}
+
+// Done returns whether the spec of the record is done
+func (r Record) Done() bool {
+	return !r.End.IsZero()
+}
+
+// EstimatedCost returns the cost estimated when the spec started
+func (r Record) EstimatedCost() float64 {
+	return r.HourlyRate * r.EstimatedHours
+}
+
+// ActualHours returns the cluster-hours used by the spec, up to now if it is not done
+func (r Record) ActualHours(now time.Time) float64 {
+	end := r.End
+	if !r.Done() {
+		end = now
+	}
+	return end.Sub(r.Start).Hours()
+}
+
+// ActualCost returns the cost of the cluster-hours used by the spec, up to now if it is not done
+func (r Record) ActualCost(now time.Time) float64 {
+	return r.HourlyRate * r.ActualHours(now)
+}
+
+// committedCost is what the record counts against the budget: the actual cost of a spec which is done,
+// the estimate of a running spec unless it has already been exceeded
+func (r Record) committedCost(now time.Time) float64 {
+	if r.Done() {
+		return r.ActualCost(now)
+	}
+	return max(r.EstimatedCost(), r.ActualCost(now))
+}
+
+// Ledger records the cost of the specs of a run, it is a JSON file guarded by a file lock in a directory shared by all the processes of the run
+type Ledger struct {
+	dir string
+}
+
+// OpenLedger opens the ledger in dir, creating it if needed
+func OpenLedger(dir string) (*Ledger, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.Wrap(err, "creating the cost ledger directory")
+	}
+	return &Ledger{dir: dir}, nil
+}
+
+// Dir returns the directory of the ledger
+func (l *Ledger) Dir() string {
+	return l.dir
+}
+
+// Reserve records the start of the spec of r, unless budget is positive and the committed cost of the run plus the estimate of r exceeds it;
+// ErrOverBudget is returned in that case
+func (l *Ledger) Reserve(r Record, budget float64) error {
+	return l.update(func(records []Record) ([]Record, error) {
+		committed := 0.0
+		for _, record := range records {
+			if record.ClusterName == r.ClusterName {
+				return nil, errors.Errorf("cluster %s is already in the cost ledger", r.ClusterName)
+			}
+			committed += record.committedCost(r.Start)
+		}
+		if budget > 0 && committed+r.EstimatedCost() > budget {
+			return nil, errors.Wrapf(ErrOverBudget, "%.2f committed and %.2f estimated for cluster %s, budget %.2f", committed, r.EstimatedCost(), r.ClusterName, budget)
+		}
+		return append(records, r), nil
+	})
+}
+
+// Complete records that the cluster reserved as clusterName lived from start, when it was created, to end, when it was deleted;
+// the start of the reservation is kept when start is zero. ErrNotReserved is returned when the cluster is not in the ledger.
+func (l *Ledger) Complete(clusterName string, start, end time.Time) error {
+	return l.update(func(records []Record) ([]Record, error) {
+		for i := range records {
+			if records[i].ClusterName == clusterName {
+				if !start.IsZero() {
+					records[i].Start = start
+				}
+				records[i].End = end
+				return records, nil
+			}
+		}
+		return nil, errors.Wrapf(ErrNotReserved, "cluster %s", clusterName)
+	})
+}
+
+// Records returns the records of the run in the order they were reserved
+func (l *Ledger) Records() ([]Record, error) {
+	var records []Record
+	err := l.locked(func() error {
+		var err error
+		records, err = l.read()
+		return err
+	})
+	return records, err
+}
+
+// WriteReport writes the summary of the run, counted up to now, next to the ledger and returns its path
+func (l *Ledger) WriteReport(now time.Time) (string, error) {
+	path := filepath.Join(l.dir, reportFile)
+	err := l.locked(func() error {
+		records, err := l.read()
+		if err != nil {
+			return err
+		}
+		var report bytes.Buffer
+		if err = Summarize(records, now).Write(&report); err != nil {
+			return err
+		}
+		return errors.Wrap(os.WriteFile(path, report.Bytes(), 0600), "writing the cost report")
+	})
+	return path, err
+}
+
+func (l *Ledger) update(update func(records []Record) ([]Record, error)) error {
+	return l.locked(func() error {
+		records, err := l.read()
+		if err != nil {
+			return err
+		}
+		if records, err = update(records); err != nil {
+			return err
+		}
+		return l.write(records)
+	})
+}
+
+// locked runs fn holding the lock of the ledger, which is shared by all the processes using it
+func (l *Ledger) locked(fn func() error) error {
+	lock, err := os.OpenFile(filepath.Join(l.dir, lockFile), os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return errors.Wrap(err, "opening the cost ledger lock")
+	}
+	// closing the file releases the lock
+	defer lock.Close()
+	if err = syscall.Flock(int(lock.Fd()), syscall.LOCK_EX); err != nil {
+		return errors.Wrap(err, "locking the cost ledger")
+	}
+	return fn()
+}
+
+func (l *Ledger) read() ([]Record, error) {
+	content, err := os.ReadFile(filepath.Join(l.dir, ledgerFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "reading the cost ledger")
+	}
+	var records []Record
+	if err = json.Unmarshal(content, &records); err != nil {
+		return nil, errors.Wrap(err, "decoding the cost ledger")
+	}
+	return records, nil
+}
+
+func (l *Ledger) write(records []Record) error {
+	content, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "encoding the cost ledger")
+	}
+	// the ledger is replaced atomically so that a crashed process never leaves it half written
+	tmp := filepath.Join(l.dir, ledgerFile+".tmp")
+	if err = os.WriteFile(tmp, content, 0600); err != nil {
+		return errors.Wrap(err, "writing the cost ledger")
+	}
+	if err = os.Rename(tmp, filepath.Join(l.dir, ledgerFile)); err != nil {
+		return errors.Wrap(err, "writing the cost ledger")
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+/*
+Copyright © 2022 - 2025 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cost_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/cost"
+)
+
+var _ = Describe("Ledger", func() {
+	var (
+		ledger *cost.Ledger
+		start  time.Time
+	)
+
+	record := func(suite, spec, clusterName string, hourlyRate float64, offset time.Duration) cost.Record {
+		return cost.Record{
+			Suite:          suite,
+			Spec:           spec,
+			ClusterName:    clusterName,
+			Shape:          cost.Shape{Provider: "eks", SKU: "standard"},
+			HourlyRate:     hourlyRate,
+			EstimatedHours: 1,
+			Start:          start.Add(offset),
+		}
+	}
+
+	BeforeEach(func() {
+		var err error
+		ledger, err = cost.OpenLedger(GinkgoT().TempDir())
+		Expect(err).To(BeNil())
+		start = time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC)
+	})
+
+	It("refuses the specs exceeding the budget", func() {
+		Expect(ledger.Reserve(record("eks/p0", "provisions", "eks-hp-ci-a", 1, 0), 2.5)).To(Succeed())
+		Expect(ledger.Reserve(record("eks/p0", "provisions", "eks-hp-ci-a", 1, 0), 2.5)).ToNot(Succeed(), "a cluster is reserved once")
+		Expect(ledger.Reserve(record("eks/p0", "imports", "eks-hp-ci-b", 1, 0), 2.5)).To(Succeed())
+		Expect(ledger.Reserve(record("eks/p0", "upgrades", "eks-hp-ci-c", 1, 0), 2.5)).To(MatchError(cost.ErrOverBudget))
+
+		// a spec done earlier than estimated frees its remaining budget
+		Expect(ledger.Complete("eks-hp-ci-a", time.Time{}, start.Add(30*time.Minute))).To(Succeed())
+		Expect(ledger.Reserve(record("eks/p0", "upgrades", "eks-hp-ci-c", 1, 90*time.Minute), 2.5)).To(MatchError(cost.ErrOverBudget))
+		Expect(ledger.Reserve(record("eks/p0", "upgrades", "eks-hp-ci-c", 0.5, 40*time.Minute), 2.5)).To(Succeed())
+
+		// no budget
+		Expect(ledger.Reserve(record("eks/p0", "deletes", "eks-hp-ci-d", 100, 0), 0)).To(Succeed())
+		Expect(ledger.Complete("eks-hp-ci-e", time.Time{}, start)).To(MatchError(cost.ErrNotReserved))
+	})
+
+	It("counts the cluster-hours from the creation of the cluster to its deletion", func() {
+		Expect(ledger.Reserve(record("eks/p0", "provisions", "eks-hp-ci-a", 1, 0), 0)).To(Succeed())
+		Expect(ledger.Complete("eks-hp-ci-a", start.Add(10*time.Minute), start.Add(70*time.Minute))).To(Succeed())
+
+		records, err := ledger.Records()
+		Expect(err).To(BeNil())
+		Expect(records[0].Start).To(Equal(start.Add(10 * time.Minute)))
+		Expect(records[0].ActualHours(start.Add(3 * time.Hour))).To(BeNumerically("~", 1, 1e-9))
+	})
+
+	It("counts the running specs exceeding their estimate", func() {
+		Expect(ledger.Reserve(record("eks/p1", "scales", "eks-hp-ci-a", 1, 0), 3)).To(Succeed())
+		// eks-hp-ci-a has been running for 2h30 instead of 1h
+		Expect(ledger.Reserve(record("eks/p1", "upgrades", "eks-hp-ci-b", 1, 150*time.Minute), 3)).To(MatchError(cost.ErrOverBudget))
+		Expect(ledger.Reserve(record("eks/p1", "upgrades", "eks-hp-ci-b", 1, 90*time.Minute), 3)).To(Succeed())
+	})
+
+	It("summarizes the estimated and actual cluster-hours", func() {
+		Expect(ledger.Reserve(record("eks/p0", "provisions", "eks-hp-ci-a", 2, 0), 0)).To(Succeed())
+		Expect(ledger.Reserve(record("eks/p0", "provisions", "eks-hp-ci-b", 2, 0), 0)).To(Succeed())
+		Expect(ledger.Reserve(record("eks/support_matrix", "provisions 1.31", "eks-hp-ci-c", 1, 0), 0)).To(Succeed())
+		Expect(ledger.Complete("eks-hp-ci-a", time.Time{}, start.Add(90*time.Minute))).To(Succeed())
+		Expect(ledger.Complete("eks-hp-ci-b", time.Time{}, start.Add(30*time.Minute))).To(Succeed())
+
+		records, err := ledger.Records()
+		Expect(err).To(BeNil())
+		Expect(records).To(HaveLen(3))
+		Expect(records[0].Done()).To(BeTrue())
+		Expect(records[2].Done()).To(BeFalse())
+
+		// eks-hp-ci-c is still running, it is counted up to now
+		summary := cost.Summarize(records, start.Add(3*time.Hour))
+		Expect(summary.Run.Clusters).To(Equal(3))
+		Expect(summary.Run.EstimatedHours).To(BeNumerically("~", 3, 1e-9))
+		Expect(summary.Run.ActualHours).To(BeNumerically("~", 5, 1e-9))
+		Expect(summary.Run.EstimatedCost).To(BeNumerically("~", 5, 1e-9))
+		Expect(summary.Run.ActualCost).To(BeNumerically("~", 7, 1e-9))
+		Expect(summary.Suites["eks/p0"].ActualHours).To(BeNumerically("~", 2, 1e-9))
+		Expect(summary.Specs["eks/p0"]["provisions"].Clusters).To(Equal(2))
+		Expect(summary.Specs["eks/support_matrix"]["provisions 1.31"].ActualCost).To(BeNumerically("~", 3, 1e-9))
+
+		var out bytes.Buffer
+		Expect(summary.Write(&out)).To(Succeed())
+		Expect(out.String()).To(MatchRegexp(`eks/p0\s+2\s+2\.00\s+2\.00\s+\$4\.00\s+\$4\.00`))
+		Expect(out.String()).To(MatchRegexp(`TOTAL\s+3\s+3\.00\s+5\.00\s+\$5\.00\s+\$7\.00`))
+
+		report, err := ledger.WriteReport(start.Add(3 * time.Hour))
+		Expect(err).To(BeNil())
+		Expect(os.ReadFile(report)).To(BeEquivalentTo(out.String()))
+	})
+
+	It("is shared by the processes opening it", func() {
+		other, err := cost.OpenLedger(ledger.Dir())
+		Expect(err).To(BeNil())
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				defer GinkgoRecover()
+				l := ledger
+				if i%2 == 0 {
+					l = other
+				}
+				Expect(l.Reserve(record("eks/p0", "provisions", fmt.Sprintf("eks-hp-ci-%d", i), 1, 0), 4)).To(Or(Succeed(), MatchError(cost.ErrOverBudget)))
+			}(i)
+		}
+		wg.Wait()
+
+		records, err := ledger.Records()
+		Expect(err).To(BeNil())
+		Expect(records).To(HaveLen(4))
+	})
+})
@@ -0,0 +1,134 @@
+// Package cost estimates what the downstream clusters of a run cost from an offline price table;
+// the estimates of the specs are recorded in a run ledger shared by the suites and Ginkgo processes of a run, which enforces the budget of the run.
+package cost
+
+import (
+	"os"
+	"sort"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// PriceTable holds the hourly prices of the clusters and nodes of each provider, in USD
+type PriceTable map[string]ProviderPrices
+
+// ProviderPrices are the hourly prices of a provider
+type ProviderPrices struct {
+	// Clusters is the fee of a cluster by SKU, e.g. the EKS control plane, the CCE flavor or the TKE cluster level
+	Clusters map[string]float64 `json:"clusters,omitempty"`
+	// Instances is the price of a node by instance type (vmSize, instanceType, machineType, flavor)
+	Instances map[string]float64 `json:"instances,omitempty"`
+}
+
+// DefaultPriceTable returns the built-in prices: approximate on-demand list prices, meant for budgeting rather than billing
+func DefaultPriceTable() PriceTable {
+	return PriceTable{
+		"aks": {
+			Clusters: map[string]float64{"free": 0, "standard": 0.10, "premium": 0.60},
+			Instances: map[string]float64{
+				"Standard_B2s":    0.0416,
+				"Standard_D2s_v3": 0.096,
+				"Standard_D4s_v3": 0.192,
+				"Standard_DS2_v2": 0.146,
+				"Standard_DS3_v2": 0.293,
+			},
+		},
+		"eks": {
+			Clusters: map[string]float64{"standard": 0.10},
+			Instances: map[string]float64{
+				"t3.medium":   0.0416,
+				"t3.large":    0.0832,
+				"t3.xlarge":   0.1664,
+				"m5.large":    0.096,
+				"m5.xlarge":   0.192,
+				"g4dn.xlarge": 0.526,
+			},
+		},
+		"gke": {
+			Clusters: map[string]float64{"standard": 0.10, "autopilot": 0.10},
+			Instances: map[string]float64{
+				"e2-medium":     0.0335,
+				"e2-standard-2": 0.067,
+				"n1-standard-2": 0.095,
+				"n1-standard-4": 0.19,
+				"n2-standard-2": 0.097,
+			},
+		},
+		"cce": {
+			Clusters: map[string]float64{"cce.s1.small": 0.10, "cce.s1.medium": 0.21, "cce.s2.small": 0.21},
+			Instances: map[string]float64{
+				"kc1.xlarge.2": 0.16,
+				"s6.large.2":   0.07,
+				"s6.xlarge.2":  0.14,
+			},
+		},
+		"ack": {
+			Clusters: map[string]float64{"ack.standard": 0, "ack.pro.small": 0.09},
+			Instances: map[string]float64{
+				"ecs.c8y.xlarge": 0.13,
+				"ecs.g7.xlarge":  0.20,
+				"ecs.c7.xlarge":  0.17,
+			},
+		},
+		"tke": {
+			Clusters: map[string]float64{"L5": 0.02, "L20": 0.05, "L50": 0.09},
+			Instances: map[string]float64{
+				"SA2.MEDIUM2": 0.025,
+				"SA2.MEDIUM4": 0.035,
+				"S5.MEDIUM4":  0.06,
+			},
+		},
+	}
+}
+
+// LoadPriceTable returns the default prices overridden by the prices of a YAML or JSON file
+func LoadPriceTable(file string) (PriceTable, error) {
+	table := DefaultPriceTable()
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading the price table")
+	}
+	var overrides PriceTable
+	if err = yaml.Unmarshal(data, &overrides); err != nil {
+		return nil, errors.Wrapf(err, "decoding the price table %s", file)
+	}
+	for provider, prices := range overrides {
+		merged := table[provider]
+		merged.Clusters = mergePrices(merged.Clusters, prices.Clusters)
+		merged.Instances = mergePrices(merged.Instances, prices.Instances)
+		table[provider] = merged
+	}
+	return table, nil
+}
+
+func mergePrices(prices, overrides map[string]float64) map[string]float64 {
+	if prices == nil {
+		prices = map[string]float64{}
+	}
+	for key, price := range overrides {
+		prices[key] = price
+	}
+	return prices
+}
+
+// HourlyRate returns what a cluster of shape costs per hour; the SKU and the instance types missing from the table are not counted but returned as unpriced
+func (t PriceTable) HourlyRate(shape Shape) (rate float64, unpriced []string) {
+	prices := t[shape.Provider]
+	if shape.SKU != "" {
+		if price, ok := prices.Clusters[shape.SKU]; ok {
+			rate += price
+		} else {
+			unpriced = append(unpriced, shape.SKU)
+		}
+	}
+	for _, np := range shape.NodePools {
+		if price, ok := prices.Instances[np.InstanceType]; ok {
+			rate += price * float64(np.Count)
+		} else {
+			unpriced = append(unpriced, np.InstanceType)
+		}
+	}
+	sort.Strings(unpriced)
+	return rate, unpriced
+}
@@ -0,0 +1,105 @@
+/*
+Copyright © 2022 - 2025 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cost_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/cost"
+)
+
+var _ = Describe("PriceTable", func() {
+	It("prices the cluster SKU and every node", func() {
+		table := cost.DefaultPriceTable()
+		rate, unpriced := table.HourlyRate(cost.Shape{
+			Provider:  "eks",
+			SKU:       "standard",
+			NodePools: []cost.NodePool{{InstanceType: "t3.large", Count: 2}, {InstanceType: "t3.medium", Count: 1}},
+		})
+		Expect(rate).To(BeNumerically("~", 0.10+2*0.0832+0.0416, 1e-9))
+		Expect(unpriced).To(BeEmpty())
+
+		rate, unpriced = table.HourlyRate(cost.Shape{
+			Provider:  "aks",
+			SKU:       "free",
+			NodePools: []cost.NodePool{{InstanceType: "Standard_XYZ", Count: 3}, {InstanceType: "Standard_DS2_v2", Count: 1}},
+		})
+		Expect(rate).To(BeNumerically("~", 0.146, 1e-9))
+		Expect(unpriced).To(Equal([]string{"Standard_XYZ"}))
+	})
+
+	It("overrides the default prices with a file", func() {
+		file := filepath.Join(GinkgoT().TempDir(), "prices.yaml")
+		Expect(os.WriteFile(file, []byte("eks:\n  instances:\n    t3.large: 0.5\ncce:\n  clusters:\n    cce.s2.medium: 0.4\n"), 0600)).To(Succeed())
+
+		table, err := cost.LoadPriceTable(file)
+		Expect(err).To(BeNil())
+		Expect(table["eks"].Instances).To(HaveKeyWithValue("t3.large", 0.5))
+		Expect(table["eks"].Instances).To(HaveKeyWithValue("t3.medium", 0.0416))
+		Expect(table["eks"].Clusters).To(HaveKeyWithValue("standard", 0.10))
+		Expect(table["cce"].Clusters).To(HaveKeyWithValue("cce.s2.medium", 0.4))
+		Expect(table["cce"].Clusters).To(HaveKeyWithValue("cce.s1.small", 0.10))
+
+		_, err = cost.LoadPriceTable(filepath.Join(GinkgoT().TempDir(), "missing.yaml"))
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("ShapeFromConfig", func() {
+	var config []byte
+
+	BeforeEach(func() {
+		var err error
+		config, err = os.ReadFile("../../../cattle-config-provisioning.example.yaml")
+		Expect(err).To(BeNil())
+	})
+
+	DescribeTable("reads the node pools of the example config",
+		func(provider string, expected cost.Shape) {
+			shape, err := cost.ShapeFromConfig(provider, config)
+			Expect(err).To(BeNil())
+			expected.Provider = provider
+			Expect(shape).To(Equal(expected))
+
+			// the example config only uses priced SKUs and instance types
+			_, unpriced := cost.DefaultPriceTable().HourlyRate(shape)
+			Expect(unpriced).To(BeEmpty())
+		},
+		Entry("aks", "aks", cost.Shape{SKU: "free", NodePools: []cost.NodePool{{InstanceType: "Standard_DS2_v2", Count: 1}}}),
+		Entry("eks", "eks", cost.Shape{SKU: "standard", NodePools: []cost.NodePool{{InstanceType: "t3.large", Count: 1}}}),
+		Entry("gke", "gke", cost.Shape{SKU: "standard", NodePools: []cost.NodePool{{InstanceType: "n1-standard-2", Count: 1}}}),
+		Entry("cce", "cce", cost.Shape{SKU: "cce.s1.small", NodePools: []cost.NodePool{{InstanceType: "kc1.xlarge.2", Count: 2}}}),
+		Entry("ack", "ack", cost.Shape{SKU: "ack.standard", NodePools: []cost.NodePool{{InstanceType: "ecs.c8y.xlarge", Count: 3}}}),
+		Entry("tke", "tke", cost.Shape{SKU: "L5", NodePools: []cost.NodePool{{InstanceType: "SA2.MEDIUM2", Count: 3}}}),
+	)
+
+	It("fails without the cluster config of the provider", func() {
+		_, err := cost.ShapeFromConfig("eks", []byte("rancher:\n  cleanup: false\n"))
+		Expect(err).To(MatchError(ContainSubstring("no eks cluster config")))
+		_, err = cost.ShapeFromConfig("rke2", config)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("has no node pools for GKE autopilot", func() {
+		shape, err := cost.ShapeFromConfig("gke", []byte("gkeClusterConfig:\n  autopilotConfig:\n    enabled: true\n"))
+		Expect(err).To(BeNil())
+		Expect(shape.SKU).To(Equal("autopilot"))
+		Expect(shape.Nodes()).To(BeZero())
+	})
+})
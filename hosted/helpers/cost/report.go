@@ -0,0 +1,78 @@
+package cost
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Totals are the estimated and actual cluster-hours and costs of a group of records
+type Totals struct {
+	Clusters       int
+	EstimatedHours float64
+	ActualHours    float64
+	EstimatedCost  float64
+	ActualCost     float64
+}
+
+func (t *Totals) add(r Record, now time.Time) {
+	t.Clusters++
+	t.EstimatedHours += r.EstimatedHours
+	t.ActualHours += r.ActualHours(now)
+	t.EstimatedCost += r.EstimatedCost()
+	t.ActualCost += r.ActualCost(now)
+}
+
+// Summary groups the records of a run by suite and by spec
+type Summary struct {
+	Run    Totals
+	Suites map[string]*Totals
+	// Specs are keyed by suite then spec
+	Specs map[string]map[string]*Totals
+	// suites and specs keep the order of the records
+	suites []string
+	specs  map[string][]string
+}
+
+// Summarize returns the summary of records, the specs which are not done are counted up to now
+func Summarize(records []Record, now time.Time) Summary {
+	s := Summary{
+		Suites: map[string]*Totals{},
+		Specs:  map[string]map[string]*Totals{},
+		specs:  map[string][]string{},
+	}
+	for _, r := range records {
+		if _, ok := s.Suites[r.Suite]; !ok {
+			s.Suites[r.Suite] = &Totals{}
+			s.Specs[r.Suite] = map[string]*Totals{}
+			s.suites = append(s.suites, r.Suite)
+		}
+		if _, ok := s.Specs[r.Suite][r.Spec]; !ok {
+			s.Specs[r.Suite][r.Spec] = &Totals{}
+			s.specs[r.Suite] = append(s.specs[r.Suite], r.Spec)
+		}
+		s.Run.add(r, now)
+		s.Suites[r.Suite].add(r, now)
+		s.Specs[r.Suite][r.Spec].add(r, now)
+	}
+	return s
+}
+
+// Write writes the summary as a table of estimated versus actual cluster-hours and costs
+func (s Summary) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SUITE / SPEC\tCLUSTERS\tEST. HOURS\tACTUAL HOURS\tEST. COST\tACTUAL COST")
+	row := func(name string, t *Totals) {
+		fmt.Fprintf(tw, "%s\t%d\t%.2f\t%.2f\t$%.2f\t$%.2f\n", name, t.Clusters, t.EstimatedHours, t.ActualHours, t.EstimatedCost, t.ActualCost)
+	}
+	for _, suite := range s.suites {
+		row(suite, s.Suites[suite])
+		for _, spec := range s.specs[suite] {
+			row("  "+strings.TrimSpace(spec), s.Specs[suite][spec])
+		}
+	}
+	row("TOTAL", &s.Run)
+	return tw.Flush()
+}
@@ -0,0 +1,159 @@
+package cost
+
+import (
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// NodePool is a group of nodes of the same instance type
+type NodePool struct {
+	InstanceType string `json:"instanceType"`
+	Count        int64  `json:"count"`
+}
+
+// Shape is what a cluster is billed for: the cluster SKU and its node pools
+type Shape struct {
+	Provider  string     `json:"provider"`
+	SKU       string     `json:"sku,omitempty"`
+	NodePools []NodePool `json:"nodePools,omitempty"`
+}
+
+// Nodes returns the number of nodes of the shape
+func (s Shape) Nodes() int64 {
+	var nodes int64
+	for _, np := range s.NodePools {
+		nodes += np.Count
+	}
+	return nodes
+}
+
+// clusterConfigs holds the fields of the cluster configs of CATTLE_TEST_CONFIG which define the shape of the clusters
+type clusterConfigs struct {
+	AKS *struct {
+		NodePools []struct {
+			VMSize    string `json:"vmSize"`
+			NodeCount int64  `json:"nodeCount"`
+		} `json:"nodePools"`
+	} `json:"aksClusterConfig"`
+	EKS *struct {
+		NodeGroups []struct {
+			InstanceType string `json:"instanceType"`
+			DesiredSize  int64  `json:"desiredSize"`
+		} `json:"nodeGroups"`
+	} `json:"eksClusterConfig"`
+	GKE *struct {
+		AutopilotConfig struct {
+			Enabled bool `json:"enabled"`
+		} `json:"autopilotConfig"`
+		NodePools []struct {
+			Config struct {
+				MachineType string `json:"machineType"`
+			} `json:"config"`
+			InitialNodeCount int64 `json:"initialNodeCount"`
+		} `json:"nodePools"`
+	} `json:"gkeClusterConfig"`
+	CCE *struct {
+		Flavor    string `json:"flavor"`
+		NodePools []struct {
+			NodeTemplate struct {
+				Flavor string `json:"flavor"`
+			} `json:"nodeTemplate"`
+			InitialNodeCount int64 `json:"initialNodeCount"`
+		} `json:"nodePools"`
+	} `json:"cceClusterConfig"`
+	ACK *struct {
+		ClusterSpec  string `json:"clusterSpec"`
+		NodePoolList []struct {
+			InstanceTypes []string `json:"instance_types"`
+			InstancesNum  int64    `json:"instances_num"`
+		} `json:"node_pool_list"`
+	} `json:"ackClusterConfig"`
+	TKE *struct {
+		ClusterBasicSettings struct {
+			ClusterLevel string `json:"clusterLevel"`
+		} `json:"clusterBasicSettings"`
+		NodePoolList []struct {
+			AutoScalingGroupPara struct {
+				DesiredCapacity int64 `json:"desiredCapacity"`
+			} `json:"autoScalingGroupPara"`
+			LaunchConfigurePara struct {
+				InstanceType string `json:"instanceType"`
+			} `json:"launchConfigurePara"`
+		} `json:"nodePoolList"`
+	} `json:"tkeClusterConfig"`
+}
+
+// ShapeFromConfig returns the shape of the clusters created from the cluster config of provider in a CATTLE_TEST_CONFIG file
+func ShapeFromConfig(provider string, data []byte) (Shape, error) {
+	var configs clusterConfigs
+	if err := yaml.Unmarshal(data, &configs); err != nil {
+		return Shape{}, errors.Wrap(err, "decoding the cluster configs")
+	}
+
+	shape := Shape{Provider: provider}
+	missing := errors.Errorf("the config has no %s cluster config", provider)
+	switch provider {
+	case "aks":
+		if configs.AKS == nil {
+			return shape, missing
+		}
+		// Rancher creates AKS clusters on the free tier
+		shape.SKU = "free"
+		for _, np := range configs.AKS.NodePools {
+			shape.NodePools = append(shape.NodePools, NodePool{InstanceType: np.VMSize, Count: np.NodeCount})
+		}
+	case "eks":
+		if configs.EKS == nil {
+			return shape, missing
+		}
+		shape.SKU = "standard"
+		for _, ng := range configs.EKS.NodeGroups {
+			shape.NodePools = append(shape.NodePools, NodePool{InstanceType: ng.InstanceType, Count: ng.DesiredSize})
+		}
+	case "gke":
+		if configs.GKE == nil {
+			return shape, missing
+		}
+		shape.SKU = "standard"
+		if configs.GKE.AutopilotConfig.Enabled {
+			// autopilot clusters have no node pools, the pods are billed instead
+			shape.SKU = "autopilot"
+			return shape, nil
+		}
+		for _, np := range configs.GKE.NodePools {
+			shape.NodePools = append(shape.NodePools, NodePool{InstanceType: np.Config.MachineType, Count: np.InitialNodeCount})
+		}
+	case "cce":
+		if configs.CCE == nil {
+			return shape, missing
+		}
+		shape.SKU = configs.CCE.Flavor
+		for _, np := range configs.CCE.NodePools {
+			shape.NodePools = append(shape.NodePools, NodePool{InstanceType: np.NodeTemplate.Flavor, Count: np.InitialNodeCount})
+		}
+	case "ack":
+		if configs.ACK == nil {
+			return shape, missing
+		}
+		shape.SKU = configs.ACK.ClusterSpec
+		for _, np := range configs.ACK.NodePoolList {
+			// the nodes are created from the first instance type which is available
+			var instanceType string
+			if len(np.InstanceTypes) > 0 {
+				instanceType = np.InstanceTypes[0]
+			}
+			shape.NodePools = append(shape.NodePools, NodePool{InstanceType: instanceType, Count: np.InstancesNum})
+		}
+	case "tke":
+		if configs.TKE == nil {
+			return shape, missing
+		}
+		shape.SKU = configs.TKE.ClusterBasicSettings.ClusterLevel
+		for _, np := range configs.TKE.NodePoolList {
+			shape.NodePools = append(shape.NodePools, NodePool{InstanceType: np.LaunchConfigurePara.InstanceType, Count: np.AutoScalingGroupPara.DesiredCapacity})
+		}
+	default:
+		return shape, errors.Errorf("unknown provider %q", provider)
+	}
+	return shape, nil
+}
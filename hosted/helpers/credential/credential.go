@@ -0,0 +1,90 @@
+// Package credential describes how the cloud credentials of the hosted providers fail: how to corrupt the secret of a cloud credential,
+// and which errors the operators report on the cluster once its cloud credential is deleted, corrupted or expired.
+package credential
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Failure is the way a cloud credential fails
+type Failure string
+
+const (
+	// Deleted cloud credentials no longer exist
+	Deleted Failure = "deleted"
+	// Corrupted cloud credentials hold an invalid secret
+	Corrupted Failure = "corrupted"
+	// Expired cloud credentials are valid, but the provider refuses them
+	Expired Failure = "expired"
+)
+
+// CorruptedValue replaces the secret part of a corrupted cloud credential
+const CorruptedValue = "hosted-providers-e2e-corrupted"
+
+// secretFields are the fields of the cloud credentials holding their secret part, per provider;
+// the keys of the cloud credential secrets are the fields prefixed by the credential config, e.g. azurecredentialConfig-clientSecret
+var secretFields = map[string]string{
+	"aks": "clientSecret",
+	"eks": "secretKey",
+	"gke": "authEncodedJson",
+	"cce": "secretKey",
+	"ack": "accessKeySecret",
+	"tke": "accessKeySecret",
+}
+
+// errorFragments are fragments of the errors the operators report for a failed cloud credential, per failure and provider
+var errorFragments = map[Failure]map[string][]string{
+	Deleted: {
+		"": {"not found", "does not exist"},
+	},
+	Corrupted: {
+		"":    {"unauthorized", "forbidden", "401", "403"},
+		"aks": {"aadsts", "invalid_client", "invalid client secret"},
+		"eks": {"signaturedoesnotmatch", "invalidclienttokenid", "unrecognizedclientexception"},
+		"gke": {"invalid character", "unexpected end of json input", "cannot fetch token", "invalid_grant"},
+		"cce": {"apigw.0301", "incorrect iam authentication", "verify aksk signature fail"},
+		"ack": {"signaturedoesnotmatch", "specified signature is not matched", "invalidaccesskeyid"},
+		"tke": {"authfailure"},
+	},
+	Expired: {
+		"":    {"unauthorized", "401", "expired"},
+		"gke": {"cannot fetch token", "invalid_grant", "unexpected end of json input", "disabled"},
+	},
+}
+
+// Corrupt returns a copy of the data of a cloud credential secret of provider, with its secret part replaced by CorruptedValue
+func Corrupt(provider string, data map[string][]byte) (map[string][]byte, error) {
+	field, ok := secretFields[provider]
+	if !ok {
+		return nil, fmt.Errorf("unknown provider %q", provider)
+	}
+	corrupted := make(map[string][]byte, len(data))
+	found := false
+	for key, value := range data {
+		if strings.HasSuffix(key, "-"+field) {
+			value, found = []byte(CorruptedValue), true
+		}
+		corrupted[key] = value
+	}
+	if !found {
+		return nil, fmt.Errorf("the cloud credential has no %s field", field)
+	}
+	return corrupted, nil
+}
+
+// Matches reports whether the error message of a cluster tells that its cloud credential failed the given way
+func Matches(provider string, failure Failure, message string) bool {
+	message = strings.ToLower(message)
+	for _, fragment := range Fragments(provider, failure) {
+		if strings.Contains(message, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// Fragments returns the fragments of the error messages matching a failure of the cloud credentials of provider
+func Fragments(provider string, failure Failure) []string {
+	return append(append([]string{}, errorFragments[failure][provider]...), errorFragments[failure][""]...)
+}
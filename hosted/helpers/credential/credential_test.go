@@ -0,0 +1,65 @@
+/*
+Copyright © 2022 - 2025 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credential_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/credential"
+)
+
+var _ = Describe("Corrupt", func() {
+	It("only replaces the secret part of the cloud credential", func() {
+		data := map[string][]byte{
+			"azurecredentialConfig-clientId":       []byte("client"),
+			"azurecredentialConfig-clientSecret":   []byte("secret"),
+			"azurecredentialConfig-subscriptionId": []byte("subscription"),
+		}
+		corrupted, err := credential.Corrupt("aks", data)
+		Expect(err).To(BeNil())
+		Expect(corrupted).To(Equal(map[string][]byte{
+			"azurecredentialConfig-clientId":       []byte("client"),
+			"azurecredentialConfig-clientSecret":   []byte(credential.CorruptedValue),
+			"azurecredentialConfig-subscriptionId": []byte("subscription"),
+		}))
+		// the data of the secret is left as is, to restore it
+		Expect(data["azurecredentialConfig-clientSecret"]).To(Equal([]byte("secret")))
+	})
+
+	It("fails without the secret part", func() {
+		_, err := credential.Corrupt("eks", map[string][]byte{"amazonec2credentialConfig-accessKey": []byte("key")})
+		Expect(err).To(MatchError(ContainSubstring("no secretKey field")))
+
+		_, err = credential.Corrupt("rke2", map[string][]byte{})
+		Expect(err).To(MatchError(ContainSubstring("unknown provider")))
+	})
+})
+
+var _ = Describe("Matches", func() {
+	DescribeTable("tells the failure of the cloud credential from the error of the cluster",
+		func(provider string, failure credential.Failure, message string, matches bool) {
+			Expect(credential.Matches(provider, failure, message)).To(Equal(matches))
+		},
+		Entry("deleted", "aks", credential.Deleted, `error getting credentials: secrets "cc-abcde" not found`, true),
+		Entry("corrupted on AKS", "aks", credential.Corrupted, "AADSTS7000215: Invalid client secret provided.", true),
+		Entry("corrupted on EKS", "eks", credential.Corrupted, "SignatureDoesNotMatch: The request signature we calculated does not match", true),
+		Entry("corrupted on GKE", "gke", credential.Corrupted, "invalid character 'h' looking for beginning of value", true),
+		Entry("corrupted on TKE", "tke", credential.Corrupted, "[TencentCloudSDKError] Code=AuthFailure.SignatureFailure", true),
+		Entry("expired on GKE", "gke", credential.Expired, `oauth2: cannot fetch token: 400 Bad Request "invalid_grant"`, true),
+		Entry("another provider's error", "aks", credential.Corrupted, "SignatureDoesNotMatch", false),
+		Entry("an unrelated error", "eks", credential.Corrupted, "the nodegroup is being updated", false),
+	)
+})
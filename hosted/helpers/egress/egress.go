@@ -0,0 +1,183 @@
+// Package egress builds the faults injected into the egress of Rancher and its operators through the squid proxy of the proxy mode:
+// the requests to given cloud API hosts are blocked, throttled, answered with an HTTP error or reset. The faults are rendered as a squid
+// configuration, included by the squid.conf of the proxy.
+package egress
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Action is what the proxy does to the requests of a rule
+type Action string
+
+const (
+	// Block denies the requests, the clients get a 403 from the proxy
+	Block Action = "block"
+	// Error denies the requests with the Status of the rule, e.g. 503 or 429 for throttling
+	Error Action = "error"
+	// Reset resets the TCP connections of the requests
+	Reset Action = "reset"
+	// Delay throttles the responses to Rate bytes per second, delaying the requests
+	Delay Action = "delay"
+)
+
+// CloudAPIHosts are the hosts of the cloud APIs called by the operators, per provider; '*' matches a single label of a host
+var CloudAPIHosts = map[string][]string{
+	"aks": {"management.azure.com"},
+	"eks": {"eks.*.amazonaws.com"},
+	"gke": {"container.googleapis.com"},
+	// PANDARIA:
+	"cce": {"cce.*.myhuaweicloud.com"},
+	"ack": {"cs.*.aliyuncs.com"},
+	"tke": {"tke.tencentcloudapi.com", "tke.*.tencentcloudapi.com"},
+}
+
+// CloudAPIFaults returns the faults of the cloud API of provider run by the egress fault checks: an outage, throttling, an unavailable
+// service, reset connections and a slow network
+func CloudAPIFaults(provider string) []Rule {
+	hosts := CloudAPIHosts[provider]
+	return []Rule{
+		{Name: "outage", Hosts: hosts, Action: Block},
+		{Name: "throttling", Hosts: hosts, Action: Error, Status: http.StatusTooManyRequests},
+		{Name: "unavailable", Hosts: hosts, Action: Error, Status: http.StatusServiceUnavailable},
+		{Name: "reset", Hosts: hosts, Action: Reset},
+		{Name: "slow", Hosts: hosts, Action: Delay, Rate: 2048},
+	}
+}
+
+// Rule is a fault of the requests to the hosts
+type Rule struct {
+	// Name identifies the rule in the squid configuration, it must be lowercase alphanumeric or '-'
+	Name   string
+	Hosts  []string
+	Action Action
+	// Status is the HTTP status of the Error action
+	Status int
+	// Rate is the number of bytes per second of the Delay action
+	Rate int
+}
+
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// Validate returns an error when the rule cannot be rendered
+func (r Rule) Validate() error {
+	if !namePattern.MatchString(r.Name) {
+		return fmt.Errorf("invalid rule name %q", r.Name)
+	}
+	if len(r.Hosts) == 0 {
+		return fmt.Errorf("rule %s has no host", r.Name)
+	}
+	for _, host := range r.Hosts {
+		if host == "" || strings.ContainsAny(host, " /:") {
+			return fmt.Errorf("rule %s has an invalid host %q", r.Name, host)
+		}
+	}
+	switch r.Action {
+	case Block, Reset:
+	case Error:
+		if r.Status < 400 || r.Status > 599 {
+			return fmt.Errorf("rule %s needs an HTTP error status, got %d", r.Name, r.Status)
+		}
+	case Delay:
+		if r.Rate <= 0 {
+			return fmt.Errorf("rule %s needs a positive rate", r.Name)
+		}
+	default:
+		return fmt.Errorf("rule %s has an unknown action %q", r.Name, r.Action)
+	}
+	return nil
+}
+
+// HostPattern returns the squid dstdom_regex matching a host, where '*' matches a single label
+func HostPattern(host string) string {
+	labels := strings.Split(host, ".")
+	for i, label := range labels {
+		if label == "*" {
+			labels[i] = "[^.]+"
+		} else {
+			labels[i] = regexp.QuoteMeta(label)
+		}
+	}
+	return "^" + strings.Join(labels, `\.`) + "$"
+}
+
+// Config renders the rules as a squid configuration; it is included before the http_access rules of the proxy, so that the rules
+// are applied before any request is allowed. No rule renders an empty configuration, which lets every request through.
+func Config(rules []Rule) (string, error) {
+	var (
+		b      strings.Builder
+		delays []Rule
+		names  = map[string]bool{}
+	)
+	b.WriteString("# Egress faults of hosted-providers-e2e, rendered by the egress package\n")
+	for _, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return "", err
+		}
+		if names[rule.Name] {
+			return "", fmt.Errorf("duplicated rule name %s", rule.Name)
+		}
+		names[rule.Name] = true
+
+		acl := aclName(rule)
+		for _, host := range rule.Hosts {
+			fmt.Fprintf(&b, "acl %s dstdom_regex -i %s\n", acl, HostPattern(host))
+		}
+		switch rule.Action {
+		case Block:
+			fmt.Fprintf(&b, "http_access deny %s\n", acl)
+		case Error:
+			fmt.Fprintf(&b, "deny_info %d:ERR_ACCESS_DENIED %s\n", rule.Status, acl)
+			fmt.Fprintf(&b, "http_access deny %s\n", acl)
+		case Reset:
+			fmt.Fprintf(&b, "deny_info TCP_RESET %s\n", acl)
+			fmt.Fprintf(&b, "http_access deny %s\n", acl)
+		case Delay:
+			delays = append(delays, rule)
+		}
+	}
+
+	if len(delays) > 0 {
+		fmt.Fprintf(&b, "delay_pools %d\n", len(delays))
+		for i, rule := range delays {
+			pool := i + 1
+			fmt.Fprintf(&b, "delay_class %d 1\n", pool)
+			fmt.Fprintf(&b, "delay_parameters %d %d/%d\n", pool, rule.Rate, rule.Rate)
+			fmt.Fprintf(&b, "delay_access %d allow %s\n", pool, aclName(rule))
+			fmt.Fprintf(&b, "delay_access %d deny all\n", pool)
+		}
+	}
+	return b.String(), nil
+}
+
+// Fragments returns fragments of the errors the clients report for the requests faulted by the rule, none for Delay which only slows them down
+func (r Rule) Fragments() []string {
+	switch r.Action {
+	case Block:
+		return []string{"forbidden", "403"}
+	case Error:
+		return []string{strings.ToLower(http.StatusText(r.Status)), strconv.Itoa(r.Status)}
+	case Reset:
+		return []string{"connection reset", "eof", "broken pipe"}
+	}
+	return nil
+}
+
+// Matches reports whether the error message of a cluster tells that its requests were faulted by the rule
+func (r Rule) Matches(message string) bool {
+	message = strings.ToLower(message)
+	for _, fragment := range r.Fragments() {
+		if fragment != "" && strings.Contains(message, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+func aclName(rule Rule) string {
+	return "egress_" + strings.ReplaceAll(rule.Name, "-", "_")
+}
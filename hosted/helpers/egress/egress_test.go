@@ -0,0 +1,118 @@
+/*
+Copyright © 2022 - 2025 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package egress_test
+
+import (
+	"regexp"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/egress"
+)
+
+var _ = Describe("HostPattern", func() {
+	It("matches a single label for each wildcard", func() {
+		pattern := regexp.MustCompile(egress.HostPattern("eks.*.amazonaws.com"))
+		Expect(pattern.MatchString("eks.us-west-2.amazonaws.com")).To(BeTrue())
+		Expect(pattern.MatchString("eks.amazonaws.com")).To(BeFalse())
+		Expect(pattern.MatchString("eks.us-west-2.example.amazonaws.com")).To(BeFalse())
+		Expect(pattern.MatchString("sts.us-west-2.amazonaws.com")).To(BeFalse())
+	})
+
+	It("escapes the dots of the host", func() {
+		pattern := regexp.MustCompile(egress.HostPattern("management.azure.com"))
+		Expect(pattern.MatchString("management.azure.com")).To(BeTrue())
+		Expect(pattern.MatchString("managementXazure.com")).To(BeFalse())
+		Expect(pattern.MatchString("login.management.azure.com")).To(BeFalse())
+	})
+
+	It("covers the cloud API hosts of every provider", func() {
+		for _, provider := range []string{"aks", "eks", "gke", "cce", "ack", "tke"} {
+			Expect(egress.CloudAPIHosts).To(HaveKey(provider))
+			_, err := egress.Config(egress.CloudAPIFaults(provider))
+			Expect(err).To(BeNil())
+		}
+	})
+})
+
+var _ = Describe("Config", func() {
+	It("renders the rules", func() {
+		config, err := egress.Config([]egress.Rule{
+			{Name: "aks-outage", Hosts: egress.CloudAPIHosts["aks"], Action: egress.Block},
+			{Name: "eks-throttling", Hosts: egress.CloudAPIHosts["eks"], Action: egress.Error, Status: 429},
+			{Name: "gke-reset", Hosts: egress.CloudAPIHosts["gke"], Action: egress.Reset},
+			{Name: "gke-slow", Hosts: []string{"oauth2.googleapis.com"}, Action: egress.Delay, Rate: 512},
+		})
+		Expect(err).To(BeNil())
+		Expect(config).To(Equal(`# Egress faults of hosted-providers-e2e, rendered by the egress package
+acl egress_aks_outage dstdom_regex -i ^management\.azure\.com$
+http_access deny egress_aks_outage
+acl egress_eks_throttling dstdom_regex -i ^eks\.[^.]+\.amazonaws\.com$
+deny_info 429:ERR_ACCESS_DENIED egress_eks_throttling
+http_access deny egress_eks_throttling
+acl egress_gke_reset dstdom_regex -i ^container\.googleapis\.com$
+deny_info TCP_RESET egress_gke_reset
+http_access deny egress_gke_reset
+acl egress_gke_slow dstdom_regex -i ^oauth2\.googleapis\.com$
+delay_pools 1
+delay_class 1 1
+delay_parameters 1 512/512
+delay_access 1 allow egress_gke_slow
+delay_access 1 deny all
+`))
+	})
+
+	It("renders no fault without rule", func() {
+		config, err := egress.Config(nil)
+		Expect(err).To(BeNil())
+		Expect(config).ToNot(ContainSubstring("http_access"))
+	})
+
+	DescribeTable("rejects the invalid rules",
+		func(rules []egress.Rule, message string) {
+			_, err := egress.Config(rules)
+			Expect(err).To(MatchError(ContainSubstring(message)))
+		},
+		Entry("invalid name", []egress.Rule{{Name: "AKS outage", Hosts: []string{"management.azure.com"}, Action: egress.Block}}, "invalid rule name"),
+		Entry("no host", []egress.Rule{{Name: "outage", Action: egress.Block}}, "has no host"),
+		Entry("URL as host", []egress.Rule{{Name: "outage", Hosts: []string{"https://management.azure.com"}, Action: egress.Block}}, "invalid host"),
+		Entry("error without status", []egress.Rule{{Name: "outage", Hosts: []string{"management.azure.com"}, Action: egress.Error}}, "HTTP error status"),
+		Entry("delay without rate", []egress.Rule{{Name: "slow", Hosts: []string{"management.azure.com"}, Action: egress.Delay}}, "positive rate"),
+		Entry("unknown action", []egress.Rule{{Name: "outage", Hosts: []string{"management.azure.com"}, Action: "drop"}}, "unknown action"),
+		Entry("duplicated name", []egress.Rule{
+			{Name: "outage", Hosts: []string{"management.azure.com"}, Action: egress.Block},
+			{Name: "outage", Hosts: []string{"container.googleapis.com"}, Action: egress.Block},
+		}, "duplicated rule name"),
+	)
+})
+
+var _ = Describe("Matches", func() {
+	It("matches the errors of the faulted requests", func() {
+		throttling := egress.Rule{Name: "throttling", Action: egress.Error, Status: 429}
+		Expect(throttling.Matches(`Post "https://eks.us-west-2.amazonaws.com/clusters": Too Many Requests`)).To(BeTrue())
+		Expect(throttling.Matches("AccessDeniedException")).To(BeFalse())
+
+		outage := egress.Rule{Name: "outage", Action: egress.Block}
+		Expect(outage.Matches(`Get "https://management.azure.com/subscriptions": Forbidden`)).To(BeTrue())
+
+		reset := egress.Rule{Name: "reset", Action: egress.Reset}
+		Expect(reset.Matches("read tcp 10.42.0.12:43210->172.17.0.1:3128: read: connection reset by peer")).To(BeTrue())
+	})
+
+	It("matches nothing for the delays", func() {
+		Expect(egress.Rule{Name: "slow", Action: egress.Delay, Rate: 512}.Matches("Forbidden")).To(BeFalse())
+	})
+})
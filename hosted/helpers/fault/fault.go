@@ -0,0 +1,155 @@
+// Package fault describes the faults injected into the operator of a hosted provider while it mutates a cluster: when the operator pods
+// are killed or scaled down, and for how long. It also tells whether the mutation converged despite the fault: the cloud resources
+// are neither duplicated nor missing, and no condition of the cluster is stuck.
+package fault
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// OperatorLabel selects the pods of the operators in cattle-system, its value is the provider
+const OperatorLabel = "ke.cattle.io/operator"
+
+// Action is the way the operator is disrupted
+type Action string
+
+const (
+	// Kill deletes the operator pods, their deployment recreates them at once
+	Kill Action = "kill"
+	// ScaleDown scales the operator deployment to zero for the outage of the injection, then back to its replicas
+	ScaleDown Action = "scale-down"
+)
+
+// Point is the moment of the mutation the fault is injected at
+type Point string
+
+const (
+	// BeforeSubmit disrupts the operator before the mutation is submitted to Rancher; the operator is only back after the outage
+	BeforeSubmit Point = "before-submit"
+	// Submitted disrupts the operator as soon as Rancher accepted the mutation
+	Submitted Point = "submitted"
+	// InProgress disrupts the operator once it reports the cluster as updating, i.e. while it calls the API of the provider
+	InProgress Point = "in-progress"
+)
+
+// Injection is a fault of the operator during a mutation of a cluster
+type Injection struct {
+	Action Action
+	Point  Point
+	// Outage is how long the operator stays scaled down, ScaleDown only
+	Outage time.Duration
+}
+
+func (i Injection) String() string {
+	if i.Action == ScaleDown {
+		return fmt.Sprintf("%s for %s %s", i.Action, i.Outage, i.Point)
+	}
+	return fmt.Sprintf("%s %s", i.Action, i.Point)
+}
+
+// Validate returns an error when the injection cannot be run: the operator cannot be killed before the mutation is submitted,
+// since the killed pods are recreated at once, and only ScaleDown has an outage
+func (i Injection) Validate() error {
+	switch i.Point {
+	case BeforeSubmit, Submitted, InProgress:
+	default:
+		return fmt.Errorf("unknown injection point %q", i.Point)
+	}
+	switch i.Action {
+	case Kill:
+		if i.Point == BeforeSubmit {
+			return fmt.Errorf("the operator cannot be killed %s, scale it down instead", i.Point)
+		}
+		if i.Outage != 0 {
+			return fmt.Errorf("a killed operator has no outage")
+		}
+	case ScaleDown:
+		if i.Outage <= 0 {
+			return fmt.Errorf("a scaled down operator needs an outage")
+		}
+	default:
+		return fmt.Errorf("unknown fault action %q", i.Action)
+	}
+	return nil
+}
+
+// OperatorSelector returns the label selector of the operator pods of provider
+func OperatorSelector(provider string) string {
+	return OperatorLabel + "=" + provider
+}
+
+// Duplicates returns the sorted names which appear more than once, e.g. a node pool the operator created twice
+func Duplicates(names []string) []string {
+	seen := map[string]int{}
+	for _, name := range names {
+		seen[name]++
+	}
+	var duplicates []string
+	for name, count := range seen {
+		if count > 1 {
+			duplicates = append(duplicates, name)
+		}
+	}
+	sort.Strings(duplicates)
+	return duplicates
+}
+
+// Compare returns the sorted names of expected which are missing from actual, and the ones of actual which are not expected
+func Compare(expected, actual []string) (missing, extra []string) {
+	diff := func(from, other []string) []string {
+		set := map[string]bool{}
+		for _, name := range other {
+			set[name] = true
+		}
+		var names []string
+		for _, name := range from {
+			if !set[name] {
+				names = append(names, name)
+				set[name] = true
+			}
+		}
+		sort.Strings(names)
+		return names
+	}
+	return diff(expected, actual), diff(actual, expected)
+}
+
+// Condition is a condition of the Rancher cluster
+type Condition struct {
+	Type    string
+	Status  string
+	Reason  string
+	Message string
+}
+
+func (c Condition) String() string {
+	s := fmt.Sprintf("%s=%s", c.Type, c.Status)
+	if c.Reason != "" {
+		s += " (" + c.Reason + ")"
+	}
+	if c.Message != "" {
+		s += ": " + c.Message
+	}
+	return s
+}
+
+// convergedTypes are the conditions which must be true once a mutation converged
+var convergedTypes = map[string]bool{
+	"Provisioned": true,
+	"Updated":     true,
+	"Ready":       true,
+}
+
+// Stuck returns the conditions of a cluster which should have converged: the ones still in progress, and the failed ones among
+// Provisioned, Updated and Ready
+func Stuck(conditions []Condition) []Condition {
+	var stuck []Condition
+	for _, condition := range conditions {
+		if condition.Status == "Unknown" || (condition.Status == "False" && convergedTypes[condition.Type]) {
+			stuck = append(stuck, condition)
+		}
+	}
+	return stuck
+}
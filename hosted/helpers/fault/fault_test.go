@@ -0,0 +1,89 @@
+/*
+Copyright © 2022 - 2025 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fault_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/fault"
+)
+
+var _ = Describe("Injection", func() {
+	DescribeTable("validates the action and point",
+		func(injection fault.Injection, message string) {
+			err := injection.Validate()
+			if message == "" {
+				Expect(err).To(BeNil())
+			} else {
+				Expect(err).To(MatchError(ContainSubstring(message)))
+			}
+		},
+		Entry("kill in progress", fault.Injection{Action: fault.Kill, Point: fault.InProgress}, ""),
+		Entry("scale down before submit", fault.Injection{Action: fault.ScaleDown, Point: fault.BeforeSubmit, Outage: time.Minute}, ""),
+		Entry("kill before submit", fault.Injection{Action: fault.Kill, Point: fault.BeforeSubmit}, "cannot be killed"),
+		Entry("kill with an outage", fault.Injection{Action: fault.Kill, Point: fault.Submitted, Outage: time.Minute}, "no outage"),
+		Entry("scale down without an outage", fault.Injection{Action: fault.ScaleDown, Point: fault.Submitted}, "needs an outage"),
+		Entry("unknown action", fault.Injection{Action: "pause", Point: fault.Submitted}, "unknown fault action"),
+		Entry("unknown point", fault.Injection{Action: fault.Kill, Point: "later"}, "unknown injection point"),
+	)
+
+	It("describes the fault", func() {
+		Expect(fault.Injection{Action: fault.Kill, Point: fault.InProgress}.String()).To(Equal("kill in-progress"))
+		Expect(fault.Injection{Action: fault.ScaleDown, Point: fault.Submitted, Outage: 2 * time.Minute}.String()).To(Equal("scale-down for 2m0s submitted"))
+	})
+
+	It("selects the operator pods of the provider", func() {
+		Expect(fault.OperatorSelector("aks")).To(Equal("ke.cattle.io/operator=aks"))
+	})
+})
+
+var _ = Describe("Cloud resources", func() {
+	It("finds the duplicated names", func() {
+		Expect(fault.Duplicates([]string{"np2", "np1", "np2", "np3", "np1"})).To(Equal([]string{"np1", "np2"}))
+		Expect(fault.Duplicates([]string{"np1", "np2"})).To(BeEmpty())
+	})
+
+	It("compares the expected and actual names", func() {
+		missing, extra := fault.Compare([]string{"np1", "np2", "np3"}, []string{"np4", "np1", "np2", "np4"})
+		Expect(missing).To(Equal([]string{"np3"}))
+		Expect(extra).To(Equal([]string{"np4"}))
+
+		missing, extra = fault.Compare([]string{"np1"}, []string{"np1"})
+		Expect(missing).To(BeEmpty())
+		Expect(extra).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Stuck", func() {
+	It("returns the conditions in progress and the failed converged ones", func() {
+		conditions := []fault.Condition{
+			{Type: "Provisioned", Status: "True"},
+			{Type: "Updated", Status: "Unknown", Message: "waiting for the node pools"},
+			{Type: "Ready", Status: "False", Reason: "Error", Message: "cluster agent disconnected"},
+			{Type: "Pending", Status: "False"},
+			{Type: "Waiting", Status: "True"},
+		}
+		stuck := fault.Stuck(conditions)
+		Expect(stuck).To(Equal([]fault.Condition{conditions[1], conditions[2]}))
+		Expect(stuck[1].String()).To(Equal("Ready=False (Error): cluster agent disconnected"))
+	})
+
+	It("returns nothing once the cluster converged", func() {
+		Expect(fault.Stuck([]fault.Condition{{Type: "Ready", Status: "True"}, {Type: "Updated", Status: "True"}})).To(BeEmpty())
+	})
+})
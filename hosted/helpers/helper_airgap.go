@@ -0,0 +1,177 @@
+package helpers
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/airgap"
+)
+
+const (
+	certManagerChart = "jetstack/cert-manager"
+	airgapImagesFile = "airgap-images.txt"
+)
+
+// rancherChartRepos are the Rancher chart repositories supported in airgap mode
+var rancherChartRepos = map[string]string{
+	"latest": "https://releases.rancher.com/server-charts/latest",
+	"stable": "https://releases.rancher.com/server-charts/stable",
+	"alpha":  "https://releases.rancher.com/server-charts/alpha",
+	"prime":  "https://charts.rancher.com/server-charts/prime",
+}
+
+/*
+*
+Prepare the airgap artifacts and mirror the images while still connected:
+start the local registry, download the k3s artifacts, pull the cert-manager and Rancher charts and mirror their images
+  - @param profile environment profile with airgap enabled
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func PrepareAirgap(profile EnvironmentProfile) {
+	Expect(profile.Airgap.Enabled).To(BeTrue(), "airgap is not enabled in the environment profile")
+	Expect(profile.NightlyOperatorCharts).To(BeFalse(), "nightly operator charts are not supported in airgap mode")
+	Expect(rancherChartRepos).To(HaveKey(profile.Rancher.Channel), "Rancher channel is not supported in airgap mode")
+
+	chartsDir := filepath.Join(profile.Airgap.ArtifactsDir, airgap.ChartsDir)
+
+	registry := profile.mirrorRegistry().URL
+	if profile.PrivateRegistry.URL == "" {
+		By(fmt.Sprintf("Starting local registry on port %d", profile.Airgap.RegistryPort), func() {
+			local, err := airgap.StartRegistry(airgap.RegistryContainerName, profile.Airgap.RegistryPort)
+			Expect(err).To(BeNil())
+			GinkgoLogr.Info("Local registry: " + local)
+		})
+	}
+
+	By(fmt.Sprintf("Downloading k3s %s artifacts", profile.K3sVersion), func() {
+		Expect(airgap.DownloadK3sArtifacts(profile.K3sVersion, profile.Airgap.ArtifactsDir)).To(Succeed())
+	})
+
+	var certManagerChartPath, rancherChartPath string
+	By("Pulling cert-manager and Rancher charts", func() {
+		RunHelmCmdWithRetry("repo", "add", "jetstack", "https://charts.jetstack.io")
+		RunHelmCmdWithRetry("repo", "add", "rancher-"+profile.Rancher.Channel, rancherChartRepos[profile.Rancher.Channel])
+		RunHelmCmdWithRetry("repo", "update")
+
+		var err error
+		certManagerChartPath, err = airgap.PullChart(certManagerChart, profile.CertManagerVersion, chartsDir)
+		Expect(err).To(BeNil())
+		rancherChartPath, err = airgap.PullChart(rancherChartRef(profile), rancherChartVersion(profile), chartsDir, "--devel")
+		Expect(err).To(BeNil())
+	})
+
+	var images []string
+	By("Listing the images to mirror", func() {
+		imagesFile := profile.Airgap.ImagesFile
+		if imagesFile == "" {
+			Expect(rancherChartVersion(profile)).ToNot(BeEmpty(), "airgap.imagesFile is required for unreleased Rancher versions")
+			var err error
+			imagesFile, err = airgap.DownloadRancherImageList(rancherChartVersion(profile), profile.Airgap.ArtifactsDir)
+			Expect(err).To(BeNil())
+		}
+		f, err := os.Open(imagesFile)
+		Expect(err).To(BeNil())
+		defer f.Close()
+		rancherImages, err := airgap.ReadImageList(f)
+		Expect(err).To(BeNil())
+		rancherImages, err = airgap.FilterImages(rancherImages, profile.Airgap.ImageFilters)
+		Expect(err).To(BeNil())
+
+		certManagerImages, err := airgap.ChartImages(certManagerChartPath, "--set", "crds.enabled=true")
+		Expect(err).To(BeNil())
+		rancherChartImages, err := airgap.ChartImages(rancherChartPath, "--set", "hostname="+profile.Rancher.Hostname)
+		Expect(err).To(BeNil())
+
+		for _, image := range slices.Concat(rancherImages, certManagerImages, rancherChartImages, profile.Airgap.Images) {
+			if !slices.Contains(images, image) {
+				images = append(images, image)
+			}
+		}
+		Expect(os.WriteFile(filepath.Join(profile.Airgap.ArtifactsDir, airgapImagesFile), []byte(strings.Join(images, "\n")+"\n"), 0644)).To(Succeed())
+		GinkgoWriter.Printf("Images to mirror:\n%s\n", strings.Join(images, "\n"))
+	})
+
+	By(fmt.Sprintf("Mirroring %d images to %s", len(images), registry), func() {
+		Expect(airgap.MirrorImages(registry, images, GinkgoWriter)).To(Succeed())
+
+		missing, err := airgap.MissingImages(registry, images)
+		Expect(err).To(BeNil())
+		Expect(missing).To(BeEmpty(), "images are missing from the registry")
+	})
+}
+
+// installK3sAirgapArtifacts puts the k3s binary and images tarball where the install script and k3s expect them
+func installK3sAirgapArtifacts(profile EnvironmentProfile) {
+	dir := profile.Airgap.ArtifactsDir
+	imagesDir := "/var/lib/rancher/k3s/agent/images"
+	out, err := exec.Command("sh", "-c", fmt.Sprintf("sudo install -m 0755 %s /usr/local/bin/k3s && sudo mkdir -p %s && sudo cp %s %s/",
+		filepath.Join(dir, airgap.K3sBinary), imagesDir, filepath.Join(dir, airgap.K3sImagesTarball()), imagesDir)).CombinedOutput()
+	GinkgoWriter.Println(string(out))
+	Expect(err).To(Not(HaveOccurred()))
+}
+
+// k3sInstallCommand returns the k3s install command, using the pre-downloaded install script in airgap mode
+func k3sInstallCommand(profile EnvironmentProfile) *exec.Cmd {
+	env := append(os.Environ(), "INSTALL_K3S_VERSION="+profile.K3sVersion, "INSTALL_K3S_EXEC=--write-kubeconfig-mode 644")
+	if !profile.Airgap.Enabled {
+		installCmd := exec.Command("sh", "-c", "curl -sfL https://get.k3s.io | sh -s - server --cluster-init")
+		installCmd.Env = env
+		return installCmd
+	}
+	installCmd := exec.Command("sh", filepath.Join(profile.Airgap.ArtifactsDir, airgap.K3sInstallScript), "server", "--cluster-init")
+	installCmd.Env = append(env, "INSTALL_K3S_SKIP_DOWNLOAD=true")
+	return installCmd
+}
+
+// certManagerChartRef returns the cert-manager chart to install, the pulled archive in airgap mode
+func certManagerChartRef(profile EnvironmentProfile) string {
+	if !profile.Airgap.Enabled {
+		return certManagerChart
+	}
+	chartPath, err := airgap.PullChart(certManagerChart, profile.CertManagerVersion, filepath.Join(profile.Airgap.ArtifactsDir, airgap.ChartsDir))
+	Expect(err).To(BeNil())
+	return chartPath
+}
+
+// deployRancherManagerAirgap installs Rancher from the pulled chart archive, mirroring the flags of rancher.DeployRancherManager
+func deployRancherManagerAirgap(profile EnvironmentProfile, extraFlags []string) {
+	chartPath, err := airgap.PullChart(rancherChartRef(profile), rancherChartVersion(profile), filepath.Join(profile.Airgap.ArtifactsDir, airgap.ChartsDir), "--devel")
+	Expect(err).To(BeNil())
+
+	password := RancherPassword
+	if password == "" {
+		password = "rancherpassword"
+	}
+	flags := []string{
+		"upgrade", "--install", "rancher", chartPath,
+		"--namespace", "cattle-system",
+		"--create-namespace",
+		"--set", "hostname=" + profile.Rancher.Hostname,
+		"--set", "bootstrapPassword=" + password,
+		"--set", "extraEnv[0].name=CATTLE_SERVER_URL",
+		"--set", "extraEnv[0].value=https://" + profile.Rancher.Hostname,
+		"--set", "replicas=1",
+		"--set", "useBundledSystemChart=true",
+		"--wait", "--wait-for-jobs",
+	}
+	RunHelmCmdWithRetry(append(flags, extraFlags...)...)
+}
+
+func rancherChartRef(profile EnvironmentProfile) string {
+	return fmt.Sprintf("rancher-%s/rancher", profile.Rancher.Channel)
+}
+
+// rancherChartVersion returns the Rancher chart version, empty for the latest one
+func rancherChartVersion(profile EnvironmentProfile) string {
+	if profile.Rancher.Version == "latest" || profile.Rancher.Version == "devel" {
+		return ""
+	}
+	return profile.Rancher.Version
+}
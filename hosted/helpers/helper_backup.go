@@ -1,6 +1,9 @@
 package helpers
 
 import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
 	"os"
 	"os/exec"
 	"strings"
@@ -11,6 +14,14 @@ import (
 	"github.com/rancher-sandbox/ele-testhelpers/kubectl"
 	"github.com/rancher-sandbox/ele-testhelpers/rancher"
 	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/backupinspector"
+)
+
+const (
+	EncryptionConfigSecretName = "encryptionconfig"
+	encryptionConfigKey        = "encryption-provider-config.yaml"
 )
 
 /*
@@ -106,12 +117,91 @@ func GetLocalPath() string {
 	return localPath
 }
 
+/*
+Generate Encryption Configuration
+  - @returns Path of a file containing an EncryptionConfiguration with a random aescbc key
+*/
+func GenerateEncryptionConfig() string {
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	Expect(err).To(Not(HaveOccurred()))
+
+	encryptionConfig := fmt.Sprintf(`apiVersion: apiserver.config.k8s.io/v1
+kind: EncryptionConfiguration
+resources:
+  - resources:
+      - secrets
+    providers:
+      - aescbc:
+          keys:
+            - name: key1
+              secret: %s
+`, base64.StdEncoding.EncodeToString(key))
+
+	file, err := os.CreateTemp("", "hp-encryption-config-*.yaml")
+	Expect(err).To(Not(HaveOccurred()))
+	defer file.Close()
+
+	_, err = file.WriteString(encryptionConfig)
+	Expect(err).To(Not(HaveOccurred()))
+	return file.Name()
+}
+
+/*
+Create Encryption Configuration Secret
+  - @param encryptionConfigFile, file generated by GenerateEncryptionConfig
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func CreateEncryptionConfigSecret(encryptionConfigFile string) {
+	// The secret is re-created so that the same function can be used before backup and before restore
+	_ = kubectl.DeleteSecret("cattle-resources-system", EncryptionConfigSecretName)
+	out, err := kubectl.Run("create", "secret", "generic", EncryptionConfigSecretName,
+		"--from-file="+encryptionConfigKey+"="+encryptionConfigFile,
+		"--namespace", "cattle-resources-system")
+	Expect(err).To(Not(HaveOccurred()), out)
+}
+
+/*
+Check failed Restore
+  - @param restoreResourceName, Restore name
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func CheckRestoreFailure(restoreResourceName string) {
+	// The operator reports reconcile errors through the conditions with an Error reason
+	Eventually(func() string {
+		out, _ := kubectl.RunWithoutErr("get", "restore", restoreResourceName,
+			"-o", "jsonpath={.status.conditions[?(@.reason==\"Error\")].message}")
+		return out
+	}, tools.SetTimeout(5*time.Minute), 10*time.Second).ShouldNot(BeEmpty())
+
+	out, _ := kubectl.RunWithoutErr("logs", "-l app.kubernetes.io/name=rancher-backup",
+		"--tail=-1", "--since=5m",
+		"--namespace", "cattle-resources-system")
+	Expect(out).ToNot(ContainSubstring("Done restoring"))
+}
+
 /*
 Execute Backup
   - @param k kubectl structure
   - @returns Backup file
 */
 func ExecuteBackup(k *kubectl.Kubectl, backupResourceName string) string {
+	return executeBackup(k, backupResourceName, "../../helpers/assets/backup.yaml", "")
+}
+
+/*
+Execute encrypted Backup
+  - @param k kubectl structure
+  - @param backupResourceName, Backup name
+  - @returns Backup file and the EncryptionConfiguration file required to restore it
+*/
+func ExecuteEncryptedBackup(k *kubectl.Kubectl, backupResourceName string) (string, string) {
+	encryptionConfigFile := GenerateEncryptionConfig()
+	backupFile := executeBackup(k, backupResourceName, "../../helpers/assets/backup-encrypted.yaml", encryptionConfigFile)
+	return backupFile, encryptionConfigFile
+}
+
+func executeBackup(k *kubectl.Kubectl, backupResourceName, backupAsset, encryptionConfigFile string) string {
 	var err error
 	var backupFile string
 
@@ -119,8 +209,14 @@ func ExecuteBackup(k *kubectl.Kubectl, backupResourceName string) string {
 		InstallBackupOperator(k)
 	})
 
+	if encryptionConfigFile != "" {
+		By("Adding the encryption configuration secret", func() {
+			CreateEncryptionConfigSecret(encryptionConfigFile)
+		})
+	}
+
 	By("Adding a backup resource", func() {
-		err = kubectl.Apply("fleet-default", "../../helpers/assets/backup.yaml")
+		err = kubectl.Apply("fleet-default", backupAsset)
 		Expect(err).To(Not(HaveOccurred()))
 	})
 
@@ -139,20 +235,94 @@ func ExecuteBackup(k *kubectl.Kubectl, backupResourceName string) string {
 		// Copy backup file
 		err = exec.Command("sudo", "cp", localPath+"/"+backupFile, ".").Run()
 		Expect(err).To(Not(HaveOccurred()))
+
+		// Make the copy readable so that its content can be inspected
+		err = exec.Command("sudo", "chmod", "a+r", backupFile).Run()
+		Expect(err).To(Not(HaveOccurred()))
 	})
 	return backupFile
 }
 
+/*
+Inspect Backup
+  - @param backupFile, file returned by ExecuteBackup or ExecuteEncryptedBackup
+  - @param cluster, hosted cluster that must be part of the backup
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func InspectBackup(backupFile string, cluster *management.Cluster) {
+	index, err := backupinspector.Open(backupFile)
+	Expect(err).To(Not(HaveOccurred()))
+	GinkgoWriter.Printf("Content of backup %s:\n%s", backupFile, index.Summary())
+
+	hosted, err := index.HostedCluster(Provider, cluster.ID)
+	Expect(err).To(Not(HaveOccurred()))
+	switch Provider {
+	case "aks", "eks", "gke":
+		// Only these operators are configured by management settings, e.g. aks-refresh
+		Expect(hosted.OperatorSettings).ToNot(BeEmpty(), "No %s operator settings found in the backup", Provider)
+	}
+}
+
 /*
 Execute Restore
   - @param k kubectl structure
   - @returns Nothing, the function will fail through Ginkgo in case of issue
 */
 func ExecuteRestore(k *kubectl.Kubectl, restoreResourceName, backupFile string) {
+	executeRestore(k, restoreResourceName, backupFile, "../../helpers/assets/restore.yaml", "")
+
+	By("Checking that the restore has been done", func() {
+		CheckOperation(restoreResourceName, "Done restoring")
+	})
+}
+
+/*
+Execute encrypted Restore
+  - @param k kubectl structure
+  - @param restoreResourceName, Restore name
+  - @param backupFile, file returned by ExecuteEncryptedBackup
+  - @param encryptionConfigFile, file returned by ExecuteEncryptedBackup
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func ExecuteEncryptedRestore(k *kubectl.Kubectl, restoreResourceName, backupFile, encryptionConfigFile string) {
+	executeRestore(k, restoreResourceName, backupFile, "../../helpers/assets/restore-encrypted.yaml", encryptionConfigFile)
+
+	By("Checking that the restore has been done", func() {
+		CheckOperation(restoreResourceName, "Done restoring")
+	})
+}
+
+/*
+Execute Restore of an encrypted backup without its encryption configuration
+  - @param k kubectl structure
+  - @param restoreResourceName, Restore name
+  - @param backupFile, file returned by ExecuteEncryptedBackup
+  - @returns Nothing, the function will fail through Ginkgo if the restore does not fail
+*/
+func ExecuteRestoreWithoutEncryptionConfig(k *kubectl.Kubectl, restoreResourceName, backupFile string) {
+	executeRestore(k, restoreResourceName, backupFile, "../../helpers/assets/restore.yaml", "")
+
+	By("Checking that the restore has failed", func() {
+		CheckRestoreFailure(restoreResourceName)
+	})
+
+	By("Deleting the failed restore resource", func() {
+		out, err := kubectl.Run("delete", "restore", restoreResourceName, "--ignore-not-found")
+		Expect(err).To(Not(HaveOccurred()), out)
+	})
+}
+
+func executeRestore(k *kubectl.Kubectl, restoreResourceName, backupFile, restoreAsset, encryptionConfigFile string) {
 	By("Installing rancher-backup-operator", func() {
 		InstallBackupOperator(k)
 	})
 
+	if encryptionConfigFile != "" {
+		By("Adding the encryption configuration secret", func() {
+			CreateEncryptionConfigSecret(encryptionConfigFile)
+		})
+	}
+
 	By("Copying backup file to restore", func() {
 		// Get local storage path
 		localPath := GetLocalPath()
@@ -164,21 +334,17 @@ func ExecuteRestore(k *kubectl.Kubectl, restoreResourceName, backupFile string)
 
 	By("Adding a restore resource", func() {
 		// Set the backup file in the restore resource
-		err := tools.Sed("BACKUP_FILE", backupFile, "../../helpers/assets/restore.yaml")
+		err := tools.Sed("BACKUP_FILE", backupFile, restoreAsset)
 		Expect(err).To(Not(HaveOccurred()))
 
 		// And apply
-		err = kubectl.Apply("fleet-default", "../../helpers/assets/restore.yaml")
+		err = kubectl.Apply("fleet-default", restoreAsset)
 		Expect(err).To(Not(HaveOccurred()))
 	})
 
-	By("Checking that the restore has been done", func() {
-		CheckOperation(restoreResourceName, "Done restoring")
-	})
-
 	By("Reset restore resource", func() {
 		// Reset the backup file in the restore resource
-		err := tools.Sed(backupFile, "BACKUP_FILE", "../../helpers/assets/restore.yaml")
+		err := tools.Sed(backupFile, "BACKUP_FILE", restoreAsset)
 		Expect(err).To(Not(HaveOccurred()))
 	})
 }
@@ -0,0 +1,156 @@
+package helpers
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/kubectl"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/chaos"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+)
+
+// RancherChaosChecks restarts the target once the cluster is in the given phase, then waits for the Rancher API to serve the cluster again
+// and for Rancher to resume its reconciliation; the caller checks the final state of the cluster afterwards. The recovery is logged
+// and added to the report of the spec.
+func RancherChaosChecks(cluster *management.Cluster, client *rancher.Client, target chaos.Target, phase chaos.Phase) chaos.Recovery {
+	recovery := chaos.Recovery{Target: target, Phase: phase}
+
+	ginkgo.By(fmt.Sprintf("waiting for cluster %s to be %s", cluster.Name, phase), func() {
+		Eventually(func() bool {
+			current, err := client.Management.Cluster.ByID(cluster.ID)
+			if isNotFound(err) {
+				// the cluster is already deleted, it went past the phase
+				return true
+			}
+			Expect(err).To(BeNil())
+			return current.State == phase.State()
+		}, tools.SetTimeout(10*time.Minute), 2*time.Second).Should(BeTrue(), "cluster %s did not reach the %s phase", cluster.Name, phase)
+	})
+
+	ginkgo.By(fmt.Sprintf("restarting the %s", target), func() {
+		recovery.Disrupted = time.Now()
+		RestartRancher(target)
+	})
+
+	ginkgo.By("waiting for the Rancher API to serve the cluster", func() {
+		Eventually(func() error {
+			_, err := client.Management.Cluster.ByID(cluster.ID)
+			if isNotFound(err) {
+				// the cluster was deleted while Rancher was restarting, its API is back nonetheless
+				return nil
+			}
+			return err
+		}, tools.SetTimeout(15*time.Minute), 5*time.Second).Should(Succeed())
+		recovery.APIBack = time.Now()
+		CheckRancherDeployments(kubectl.New())
+	})
+
+	ginkgo.By("waiting for Rancher to resume the reconciliation of the cluster", func() {
+		Eventually(func() bool {
+			current, err := client.Management.Cluster.ByID(cluster.ID)
+			if isNotFound(err) {
+				return true
+			}
+			Expect(err).To(BeNil())
+			var timestamps []string
+			for _, condition := range current.Conditions {
+				timestamps = append(timestamps, condition.LastUpdateTime)
+			}
+			latest, ok := chaos.LatestUpdate(timestamps)
+			return ok && latest.After(recovery.Disrupted)
+		}, tools.SetTimeout(30*time.Minute), 5*time.Second).Should(BeTrue(), "Rancher did not resume the reconciliation of cluster %s", cluster.Name)
+		recovery.Resumed = time.Now()
+	})
+
+	ginkgo.GinkgoLogr.Info(fmt.Sprintf("Cluster %s: %s", cluster.Name, recovery))
+	ginkgo.AddReportEntry("rancher-chaos", recovery.String())
+	return recovery
+}
+
+// RestartRancher restarts the target and waits for the rancher deployment to be rolled out again
+func RestartRancher(target chaos.Target) {
+	switch target {
+	case chaos.RancherDeployment:
+		out, err := kubectl.RunWithoutErr("rollout", "restart", "deployment/rancher", "--namespace", CattleSystemNS)
+		Expect(err).To(BeNil(), out)
+	case chaos.RancherLeader:
+		deleteRancherLeader()
+	case chaos.K3sService:
+		out, err := exec.Command("sudo", "systemctl", "restart", "k3s").CombinedOutput()
+		Expect(err).To(BeNil(), string(out))
+		// the API server of the local cluster is down until k3s is started
+		Eventually(func() error {
+			_, err := kubectl.RunWithoutErr("get", "nodes")
+			return err
+		}, tools.SetTimeout(5*time.Minute), 5*time.Second).Should(Succeed())
+	default:
+		ginkgo.Fail(fmt.Sprintf("unknown chaos target %q", target))
+	}
+	ginkgo.GinkgoLogr.Info(fmt.Sprintf("Restarted the %s", target))
+
+	Eventually(func() error {
+		out, err := kubectl.RunWithoutErr("rollout", "status", "deployment/rancher", "--namespace", CattleSystemNS, "--timeout=1m")
+		ginkgo.GinkgoWriter.Println(out)
+		return err
+	}, tools.SetTimeout(10*time.Minute), 5*time.Second).Should(Succeed(), "Rancher deployment is not rolled out")
+}
+
+// deleteRancherLeader deletes the rancher pod holding chaos.LeaderLease, then waits for another replica to take the leadership over;
+// the other replicas must keep running
+func deleteRancherLeader() {
+	leader := rancherLeader()
+	Expect(leader).ToNot(BeEmpty(), "the %s lease has no holder", chaos.LeaderLease)
+	var replicas []string
+	for _, pod := range rancherPods() {
+		if pod != leader {
+			replicas = append(replicas, pod)
+		}
+	}
+	Expect(replicas).ToNot(BeEmpty(), "the leader %s is the only rancher replica, the leadership cannot change", leader)
+
+	out, err := kubectl.RunWithoutErr("delete", "pod", leader, "--namespace", CattleSystemNS, "--wait=false")
+	Expect(err).To(BeNil(), out)
+	ginkgo.GinkgoLogr.Info(fmt.Sprintf("Deleted the rancher leader %s", leader))
+
+	Eventually(rancherLeader, tools.SetTimeout(5*time.Minute), 2*time.Second).ShouldNot(Or(BeEmpty(), Equal(leader)),
+		"no other rancher replica took the %s lease over from %s", chaos.LeaderLease, leader)
+	ginkgo.GinkgoLogr.Info(fmt.Sprintf("Rancher leader changed from %s to %s", leader, rancherLeader()))
+	Expect(rancherPods()).To(ContainElements(replicas), "the other rancher replicas did not keep running")
+}
+
+// rancherLeader returns the rancher pod holding chaos.LeaderLease, it is empty when the lease has no holder
+func rancherLeader() string {
+	out, err := kubectl.RunWithoutErr("get", "lease", chaos.LeaderLease, "--namespace", chaos.LeaderLeaseNamespace, "-o", "jsonpath={.spec.holderIdentity}")
+	Expect(err).To(BeNil(), out)
+	return chaos.LeaderPod(out)
+}
+
+// rancherPods returns the pods of the rancher deployment
+func rancherPods() []string {
+	out, err := kubectl.RunWithoutErr("get", "pods", "--namespace", CattleSystemNS, "--selector", "app=rancher", "-o", "jsonpath={.items[*].metadata.name}")
+	Expect(err).To(BeNil(), out)
+	return strings.Fields(out)
+}
+
+// WaitUntilClusterIsDeleted waits for Rancher to remove the cluster, i.e. for its operator to delete it on the provider
+func WaitUntilClusterIsDeleted(cluster *management.Cluster, client *rancher.Client) {
+	Eventually(func() error {
+		_, err := client.Management.Cluster.ByID(cluster.ID)
+		return err
+	}, Timeout, 30*time.Second).Should(Satisfy(isNotFound), "cluster %s is not deleted", cluster.Name)
+}
+
+// isNotFound reports whether err is the Rancher API telling that the object does not exist
+func isNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	message := strings.ToLower(err.Error())
+	return strings.Contains(message, "404") || strings.Contains(message, "not found")
+}
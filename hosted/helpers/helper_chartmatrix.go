@@ -0,0 +1,99 @@
+package helpers
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/chartmatrix"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+)
+
+// ChartMatrixWindow selects the operator chart versions walked by ChartMatrixChecks, set by CHART_MATRIX_WINDOW: a number of versions
+// up to the current one, e.g. 4, or a semver constraint, e.g. ">=105.0.0 <106.0.0"; the matrix is not run when it is empty
+var ChartMatrixWindow = os.Getenv("CHART_MATRIX_WINDOW")
+
+// ChartMatrixChecks walks the upgrades and downgrades between every pair of versions of the operator chart of Provider within
+// ChartMatrixWindow, scaling the cluster with scale after each of them to check that it is still reconciled; scale is the
+// ScaleNodePool or ScaleNodeGroup of the provider. Rancher is pinned during the matrix so that it does not reconcile the charts
+// back. The original chart version is restored at the end, and the compatibility table of the transitions is logged and added to
+// the report of the spec; the checks fail when a transition broke the reconciliation.
+func ChartMatrixChecks(cluster *management.Cluster, client *rancher.Client, scale func(cluster *management.Cluster, client *rancher.Client, nodeCount int64, wait, checkClusterConfig bool) (*management.Cluster, error)) *management.Cluster {
+	window, err := chartmatrix.ParseWindow(ChartMatrixWindow)
+	Expect(err).To(BeNil())
+
+	manager := OperatorChartManager()
+	original, err := manager.CurrentVersion()
+	Expect(err).To(BeNil())
+	Expect(original).ToNot(BeEmpty(), "the %s operator chart is not installed", Provider)
+
+	available, err := manager.Versions()
+	Expect(err).To(BeNil())
+	versions, err := window.Select(available, original)
+	Expect(err).To(BeNil())
+	Expect(len(versions)).To(BeNumerically(">", 1), "the chart window %q selects no version besides %s", ChartMatrixWindow, original)
+
+	pinned := skipChartInstallation()
+	ginkgo.By("pinning the operator charts installed by Rancher", func() {
+		PinOperatorCharts()
+	})
+	ginkgo.DeferCleanup(func() {
+		ginkgo.By(fmt.Sprintf("restoring the %s setting %q", skipChartInstallationSetting, pinned), func() {
+			setSkipChartInstallation(pinned)
+		})
+	})
+
+	hops := chartmatrix.Walk(original, versions)
+	ginkgo.GinkgoLogr.Info(fmt.Sprintf("Walking %d chart transitions between the versions %s", len(hops), strings.Join(versions, ", ")))
+
+	initialNodeCount := poolNodeCount(cluster)
+	Expect(initialNodeCount).To(BeNumerically(">", 0), "cluster %s has no node pool", cluster.Name)
+	var results []chartmatrix.Result
+	for _, hop := range hops {
+		ginkgo.By(fmt.Sprintf("walking the chart transition %s", hop), func() {
+			result := chartmatrix.Result{Hop: hop}
+			started := time.Now()
+			failure := InterceptGomegaFailure(func() {
+				Expect(manager.Upgrade(hop.To, nil)).To(Succeed())
+				Expect(manager.CurrentVersion()).To(Equal(hop.To), "the chart was not moved to %s", hop.To)
+				cluster, err = client.Management.Cluster.ByID(cluster.ID)
+				Expect(err).To(BeNil())
+				// scale up and down in turn, so that every transition is followed by a change of the cluster
+				nodeCount := initialNodeCount + 1
+				if poolNodeCount(cluster) > initialNodeCount {
+					nodeCount = initialNodeCount
+				}
+				cluster, err = scale(cluster, client, nodeCount, true, true)
+				Expect(err).To(BeNil())
+			})
+			result.Duration = time.Since(started)
+			if failure != nil {
+				result.Err = failure.Error()
+			}
+			results = append(results, result)
+			ginkgo.GinkgoLogr.Info(result.String())
+		})
+	}
+
+	table := chartmatrix.Table(versions, results)
+	ginkgo.GinkgoLogr.Info(fmt.Sprintf("Compatibility of the %s operator chart transitions:\n%s", Provider, table))
+	ginkgo.AddReportEntry("chart-matrix", table)
+
+	ginkgo.By(fmt.Sprintf("restoring the chart version %s", original), func() {
+		Expect(manager.Upgrade(original, nil)).To(Succeed())
+		Expect(manager.CurrentVersion()).To(Equal(original))
+		cluster, err = WaitUntilClusterIsReady(cluster, client)
+		Expect(err).To(BeNil())
+	})
+
+	var broken []string
+	for _, result := range chartmatrix.Broken(results) {
+		broken = append(broken, result.String())
+	}
+	Expect(broken).To(BeEmpty(), "chart transitions broke the reconciliation of cluster %s:\n%s", cluster.Name, table)
+	return cluster
+}
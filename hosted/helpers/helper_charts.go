@@ -3,7 +3,6 @@ package helpers
 import (
 	"encoding/json"
 	"fmt"
-	"os/exec"
 	"strings"
 	"time"
 
@@ -12,22 +11,37 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/rancher-sandbox/ele-testhelpers/kubectl"
 	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	operatorcharts "github.com/rancher/hosted-providers-e2e/hosted/helpers/charts"
 	"github.com/rancher/shepherd/clients/rancher/catalog"
+	"helm.sh/helm/v3/pkg/cli"
 )
 
+// skipChartInstallationSetting stops Rancher from installing and upgrading the hosted provider operator charts, it defaults to
+// the CATTLE_SKIP_HOSTED_CLUSTER_CHART_INSTALLATION environment variable of Rancher
+const skipChartInstallationSetting = "skip-hosted-cluster-chart-installation"
+
 // AddRancherCharts adds the repo from which rancher operator charts can be installed
 func AddRancherCharts() {
 	err := kubectl.RunHelmBinaryWithCustomErr("repo", "add", catalog.RancherChartRepo, "https://charts.rancher.io")
 	Expect(err).To(BeNil())
 }
 
+// OperatorChartManager returns the Helm SDK manager of the operator charts of Provider in cattle-system; the charts are fetched from
+// the repository added by AddRancherCharts
+func OperatorChartManager() *operatorcharts.Manager {
+	settings := cli.New()
+	cfg, err := operatorcharts.NewConfiguration(settings.RESTClientGetter(), CattleSystemNS, func(format string, v ...interface{}) {
+		ginkgo.GinkgoWriter.Printf(format+"\n", v...)
+	})
+	Expect(err).To(BeNil())
+	return operatorcharts.NewManager(cfg, Provider, CattleSystemNS, operatorcharts.HelmRepository{Name: catalog.RancherChartRepo, Settings: settings})
+}
+
 // GetCurrentOperatorChartVersion returns the current version of a Provider chart.
 func GetCurrentOperatorChartVersion() string {
-	charts := ListOperatorChart()
-	if len(charts) == 0 {
-		return ""
-	}
-	return charts[0].DerivedVersion
+	version, err := OperatorChartManager().CurrentVersion()
+	Expect(err).To(BeNil(), "Failed to get the version of chart %s", Provider)
+	return version
 }
 
 // GetDowngradeOperatorChartVersion returns a version to downgrade to from a given chart version.
@@ -92,37 +106,34 @@ func UpdateOperatorChartsVersion(updateChartVersion string) {
 
 // UninstallOperatorCharts uninstalls the operator charts
 func UninstallOperatorCharts() {
-	for _, chart := range ListOperatorChart() {
-		args := []string{"uninstall", chart.Name, "--namespace", CattleSystemNS}
-		err := kubectl.RunHelmBinaryWithCustomErr(args...)
-		if err != nil {
-			Expect(err).To(BeNil(), "Failed to uninstall chart %s", chart.Name)
-		}
-	}
+	err := OperatorChartManager().Uninstall()
+	Expect(err).To(BeNil(), "Failed to uninstall the charts of %s", Provider)
 }
 
 // ListOperatorChart lists the installed provider charts for a provider in cattle-system; it fetches the provider value using Provider
-func ListOperatorChart() (operatorCharts []HelmChart) {
-	cmd := exec.Command("helm", "list", "--namespace", CattleSystemNS, "-o", "json", "--filter", fmt.Sprintf("%s-operator", Provider))
-	output, err := cmd.Output()
+func ListOperatorChart() (charts []HelmChart) {
+	releases, err := OperatorChartManager().List()
 	Expect(err).To(BeNil(), "Failed to list chart %s", Provider)
-	ginkgo.GinkgoLogr.Info(string(output))
-	err = json.Unmarshal(output, &operatorCharts)
-	Expect(err).To(BeNil(), "Failed to unmarshal chart %s", Provider)
-	for i := range operatorCharts {
-		operatorCharts[i].DerivedVersion = strings.TrimPrefix(operatorCharts[i].Chart, fmt.Sprintf("%s-", operatorCharts[i].Name))
+	for _, release := range releases {
+		ginkgo.GinkgoLogr.Info(release.String())
+		charts = append(charts, HelmChart{
+			Name:           release.Name,
+			Chart:          fmt.Sprintf("%s-%s", release.Chart, release.Version),
+			AppVersion:     release.AppVersion,
+			DerivedVersion: release.Version,
+		})
 	}
 	return
 }
 
 // ListChartVersions lists all the available the chart version for a given chart name
 func ListChartVersions(chartName string) (charts []HelmChart) {
-	cmd := exec.Command("helm", "search", "repo", chartName, "--versions", "-ojson", "--devel")
-	output, err := cmd.Output()
-	Expect(err).To(BeNil())
-	ginkgo.GinkgoLogr.Info(string(output))
-	err = json.Unmarshal(output, &charts)
+	versions, err := OperatorChartManager().Repository.Versions(chartName)
 	Expect(err).To(BeNil())
+	ginkgo.GinkgoLogr.Info(fmt.Sprintf("Versions of chart %s: %v", chartName, versions))
+	for _, version := range versions {
+		charts = append(charts, HelmChart{Name: chartName, Chart: fmt.Sprintf("%s-%s", chartName, version), DerivedVersion: version})
+	}
 	return
 }
 
@@ -138,3 +149,23 @@ func VersionCompare(v, o string) int {
 	Expect(err).To(BeNil())
 	return latestVer.Compare(oldVer)
 }
+
+// PinOperatorCharts stops Rancher from installing and upgrading the hosted provider operator charts, so that the charts installed
+// by the tests are kept; Rancher installed with the operator override already has it from its environment
+func PinOperatorCharts() {
+	setSkipChartInstallation("true")
+}
+
+// skipChartInstallation returns the value of the skip-hosted-cluster-chart-installation setting, empty when it is the default one
+func skipChartInstallation() string {
+	out, err := kubectl.RunWithoutErr("get", "settings.management.cattle.io", skipChartInstallationSetting, "-o", "jsonpath={.value}")
+	Expect(err).To(BeNil(), out)
+	return strings.TrimSpace(out)
+}
+
+func setSkipChartInstallation(value string) {
+	patch, err := json.Marshal(map[string]string{"value": value})
+	Expect(err).To(BeNil())
+	out, err := kubectl.RunWithoutErr("patch", "settings.management.cattle.io", skipChartInstallationSetting, "--type", "merge", "-p", string(patch))
+	Expect(err).To(BeNil(), out)
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/kubeconfig"
 	"github.com/rancher/rancher/tests/v2/actions/clusters"
 	"github.com/rancher/rancher/tests/v2/actions/pipeline"
 	"github.com/rancher/shepherd/clients/rancher"
@@ -134,19 +135,9 @@ func CommonBeforeSuite() RancherContext {
 func CreateStdUserClient(ctx *RancherContext) {
 	ginkgo.GinkgoLogr.Info("Creating Std User client ...")
 
-	var stduser = namegen.AppendRandomString("stduser-")
-	var stduserpassword = password.GenerateUserPassword("testpass-")
-	newuser := &management.User{
-		Username: stduser,
-		Password: stduserpassword,
-		Name:     stduser,
-		Enabled:  pointer.Bool(true),
-	}
-
-	stdUser, err := users.CreateUserWithRole(ctx.RancherAdminClient, newuser, "user")
+	stdUser, err := CreateUserWithGlobalRole(ctx.RancherAdminClient, "stduser-", "user")
 	Expect(err).To(BeNil())
 
-	stdUser.Password = newuser.Password
 	stdUserClient, err := ctx.RancherAdminClient.AsUser(stdUser)
 	Expect(err).To(BeNil())
 
@@ -157,6 +148,25 @@ func CreateStdUserClient(ctx *RancherContext) {
 	ctx.CloudCredID = cloudCredID
 }
 
+// CreateUserWithGlobalRole creates a user with a random name starting with prefix and the given global role;
+// the password of the returned user is set, so that it can be used with client.AsUser
+func CreateUserWithGlobalRole(client *rancher.Client, prefix, globalRole string) (*management.User, error) {
+	username := namegen.AppendRandomString(prefix)
+	newuser := &management.User{
+		Username: username,
+		Password: password.GenerateUserPassword("testpass-"),
+		Name:     username,
+		Enabled:  pointer.Bool(true),
+	}
+
+	user, err := users.CreateUserWithRole(client, newuser, globalRole)
+	if err != nil {
+		return nil, err
+	}
+	user.Password = newuser.Password
+	return user, nil
+}
+
 // WaitUntilClusterIsReady waits until the cluster is in a Ready state,
 // fetch the cluster again once it's ready so that it has everything up to date and then return it.
 // For e.g. once the cluster has been updated, it contains information such as Version.GitVersion which it does not have before it's ready
@@ -313,6 +323,38 @@ func GetCCERegion() string {
 	return region
 }
 
+// GetACKRegion fetches the value of ACK Region;
+// it first obtains the value from env var ACK_REGION, if the value is empty, it fetches the information from config file(cattle_config-import.yaml/cattle_config-provisioning.yaml)
+// if none of the sources can provide a value, it returns the default value
+func GetACKRegion() string {
+	region := os.Getenv("ACK_REGION")
+	if region == "" {
+		ackClusterConfig := new(management.ACKClusterConfigSpec)
+		config.LoadConfig("ackClusterConfig", ackClusterConfig)
+		region = ackClusterConfig.RegionID
+		if region == "" {
+			region = "cn-hangzhou"
+		}
+	}
+	return region
+}
+
+// GetTKERegion fetches the value of TKE Region;
+// it first obtains the value from env var TKE_REGION, if the value is empty, it fetches the information from config file(cattle_config-import.yaml/cattle_config-provisioning.yaml)
+// if none of the sources can provide a value, it returns the default value
+func GetTKERegion() string {
+	region := os.Getenv("TKE_REGION")
+	if region == "" {
+		tkeClusterConfig := new(management.TKEClusterConfigSpec)
+		config.LoadConfig("tkeClusterConfig", tkeClusterConfig)
+		region = tkeClusterConfig.Region
+		if region == "" {
+			region = "ap-guangzhou"
+		}
+	}
+	return region
+}
+
 // GetGKEProjectID returns the value of GKE project by fetching the value of env var GKE_PROJECT_ID
 func GetGKEProjectID() string {
 	return os.Getenv("GKE_PROJECT_ID")
@@ -348,15 +390,16 @@ func GetCommonMetadataLabels() map[string]string {
 	return metadataLabels
 }
 
-func SetTempKubeConfig(clusterName string) {
-	downstreamKubeconfig := os.Getenv(DownstreamKubeconfig(clusterName))
-	if downstreamKubeconfig == "" {
-		tmpKubeConfig, err := os.CreateTemp("", clusterName)
-		Expect(err).To(BeNil())
-		downstreamKubeconfig = tmpKubeConfig.Name()
-		_ = os.Setenv(DownstreamKubeconfig(clusterName), downstreamKubeconfig)
-	}
-	_ = os.Setenv("KUBECONFIG", downstreamKubeconfig)
+// Kubeconfigs hands out the kubeconfig files of the downstream clusters, they are passed explicitly to the commands instead of setting KUBECONFIG
+var Kubeconfigs = kubeconfig.NewManager()
+
+// DownstreamKubeconfig returns the kubeconfig handle of clusterName, backed by an empty file the cloud CLIs can write the cluster credentials to;
+// the file is removed once the current spec (or suite node) is done
+func DownstreamKubeconfig(clusterName string) *kubeconfig.Handle {
+	handle, err := Kubeconfigs.Get(clusterName)
+	Expect(err).To(BeNil())
+	ginkgo.DeferCleanup(Kubeconfigs.Release, clusterName)
+	return handle
 }
 
 // HighestK8sMinorVersionSupportedByUI returns the highest k8s version supported by UI
@@ -420,28 +463,25 @@ func CreateCloudCredentials(client *rancher.Client) (string, error) {
 	case "aks":
 		cloudCredentialConfig = cloudcredentials.LoadCloudCredential("azure")
 		cloudCredential, err = azure.CreateAzureCloudCredentials(client, cloudCredentialConfig)
-		Expect(err).To(BeNil())
 	case "eks":
 		cloudCredentialConfig = cloudcredentials.LoadCloudCredential("aws")
 		cloudCredential, err = aws.CreateAWSCloudCredentials(client, cloudCredentialConfig)
-		Expect(err).To(BeNil())
 	case "gke":
 		cloudCredentialConfig = cloudcredentials.LoadCloudCredential("google")
 		cloudCredential, err = google.CreateGoogleCloudCredentials(client, cloudCredentialConfig)
-		Expect(err).To(BeNil())
 	// PANDARIA:
 	case "cce":
 		cloudCredentialConfig = cloudcredentials.LoadCloudCredential("huawei")
 		cloudCredential, err = huawei.CreateHuaweiCloudCredentials(client, cloudCredentialConfig)
-		Expect(err).To(BeNil())
 	case "ack":
 		cloudCredentialConfig = cloudcredentials.LoadCloudCredential("aliyun")
 		cloudCredential, err = ecs.CreateECSCloudCredentials(client, cloudCredentialConfig)
-		Expect(err).To(BeNil())
 	case "tke":
 		cloudCredentialConfig = cloudcredentials.LoadCloudCredential("tke")
 		cloudCredential, err = tencent.CreateTencentCloudCredentials(client, cloudCredentialConfig)
-		Expect(err).To(BeNil())
+	}
+	if err != nil {
+		return "", err
 	}
 	return fmt.Sprintf("%s:%s", cloudCredential.Namespace, cloudCredential.Name), nil
 }
@@ -0,0 +1,148 @@
+package helpers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/cost"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+)
+
+var (
+	// MaxRunCost is the budget of a run in USD, set by MAX_RUN_COST; the specs whose estimated cost would exceed it are skipped
+	MaxRunCost, _ = strconv.ParseFloat(os.Getenv("MAX_RUN_COST"), 64)
+	// costRunDir holds the cost ledger of the run, set by COST_RUN_DIR to share it between several ginkgo invocations;
+	// by default it is shared by the parallel processes of a single ginkgo invocation
+	costRunDir = func() string {
+		if dir := os.Getenv("COST_RUN_DIR"); dir != "" {
+			return dir
+		}
+		return filepath.Join(os.TempDir(), fmt.Sprintf("hp-cost-%d", os.Getppid()))
+	}()
+	// costSpecHours is the number of hours a spec is estimated to keep its cluster, set by COST_SPEC_HOURS
+	costSpecHours = func() float64 {
+		if hours, err := strconv.ParseFloat(os.Getenv("COST_SPEC_HOURS"), 64); err == nil && hours > 0 {
+			return hours
+		}
+		return 1
+	}()
+)
+
+// costModel is the price of the clusters created from CATTLE_TEST_CONFIG for Provider, and the cost ledger of the run
+type costModel struct {
+	ledger   *cost.Ledger
+	shape    cost.Shape
+	rate     float64
+	unpriced []string
+}
+
+var loadCostModel = sync.OnceValues(func() (*costModel, error) {
+	prices := cost.DefaultPriceTable()
+	if file := os.Getenv("COST_PRICE_TABLE"); file != "" {
+		var err error
+		if prices, err = cost.LoadPriceTable(file); err != nil {
+			return nil, err
+		}
+	}
+	data, err := os.ReadFile(os.Getenv("CATTLE_TEST_CONFIG"))
+	if err != nil {
+		return nil, errors.Wrap(err, "reading CATTLE_TEST_CONFIG")
+	}
+	shape, err := cost.ShapeFromConfig(Provider, data)
+	if err != nil {
+		return nil, err
+	}
+	ledger, err := cost.OpenLedger(costRunDir)
+	if err != nil {
+		return nil, err
+	}
+	model := &costModel{ledger: ledger, shape: shape}
+	model.rate, model.unpriced = prices.HourlyRate(shape)
+	if len(model.unpriced) > 0 {
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("No price for %s, the cost estimates do not include them", strings.Join(model.unpriced, ", ")))
+	}
+	return model, nil
+})
+
+// GuardClusterCost records the estimated cost of the cluster the current spec is about to provision in the cost ledger of the run,
+// and skips the spec if it would exceed MAX_RUN_COST; it must be called before the cluster is created. The cluster-hours actually
+// used are recorded by CompleteClusterCost once the cluster is deleted.
+func GuardClusterCost(clusterName string) {
+	model, err := loadCostModel()
+	if err != nil {
+		// the budget cannot be enforced without the cost model, the estimates are only informative otherwise
+		Expect(MaxRunCost).To(BeZero(), "MAX_RUN_COST is set but the cost model cannot be loaded: %v", err)
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Not estimating the cost of cluster %s: %v", clusterName, err))
+		return
+	}
+
+	wd, _ := os.Getwd()
+	record := cost.Record{
+		// e.g. eks/support_matrix, ginkgo runs the suites from their directory
+		Suite:          filepath.Join(filepath.Base(filepath.Dir(wd)), filepath.Base(wd)),
+		Spec:           ginkgo.CurrentSpecReport().FullText(),
+		ClusterName:    clusterName,
+		Shape:          model.shape,
+		HourlyRate:     model.rate,
+		Unpriced:       model.unpriced,
+		EstimatedHours: costSpecHours,
+		Start:          time.Now(),
+	}
+	err = model.ledger.Reserve(record, MaxRunCost)
+	if errors.Is(err, cost.ErrOverBudget) {
+		ginkgo.Skip(err.Error())
+	}
+	Expect(err).To(BeNil())
+}
+
+// CompleteClusterCost records the cluster-hours used by the cluster reserved by GuardClusterCost, from cluster.Created to now, and
+// returns its record; it must be called once the cluster is deleted, or kept by the run. The reservation is closed at now when the
+// cluster has not been created. Nil is returned for the clusters which have not been reserved, e.g. adopted from a previous run.
+func CompleteClusterCost(clusterName string, cluster *management.Cluster) *cost.Record {
+	model, err := loadCostModel()
+	if err != nil {
+		return nil
+	}
+
+	end := time.Now()
+	start := end
+	if cluster != nil && cluster.Created != "" {
+		start, err = time.Parse(time.RFC3339, cluster.Created)
+		Expect(err).To(BeNil(), "invalid creation time of cluster %s", clusterName)
+	}
+	err = model.ledger.Complete(clusterName, start, end)
+	if errors.Is(err, cost.ErrNotReserved) {
+		return nil
+	}
+	Expect(err).To(BeNil())
+
+	report, err := model.ledger.WriteReport(end)
+	Expect(err).To(BeNil())
+	ginkgo.GinkgoLogr.Info(fmt.Sprintf("Run cost report: %s", report))
+
+	records, err := model.ledger.Records()
+	Expect(err).To(BeNil())
+	for i := range records {
+		if records[i].ClusterName == clusterName {
+			return &records[i]
+		}
+	}
+	return nil
+}
+
+// costReportEntry adds the estimated and actual cost of the cluster of record to the report of the current spec
+func costReportEntry(record *cost.Record) {
+	if record == nil {
+		return
+	}
+	ginkgo.AddReportEntry("cost", fmt.Sprintf("estimated %.2f cluster-hours ($%.2f), actual %.2f cluster-hours ($%.2f)",
+		record.EstimatedHours, record.EstimatedCost(), record.ActualHours(record.End), record.ActualCost(record.End)))
+}
@@ -0,0 +1,232 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/credential"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	v1 "github.com/rancher/shepherd/clients/rancher/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// CredentialOperations are the provider specific parts of the cloud credential lifecycle checks
+type CredentialOperations struct {
+	// Trigger submits a change of the cluster, so that its operator calls the API of the provider with the current cloud credential
+	Trigger func(client *rancher.Client, cluster *management.Cluster) error
+	// Expiry simulates the expiry of a cloud credential, it is nil when the provider does not allow it
+	Expiry *CredentialExpiry
+}
+
+// CredentialExpiry simulates the expiry of a cloud credential: Create creates a cloud credential which the provider refuses
+// as an expired one once Expire is called, until Renew is called
+type CredentialExpiry struct {
+	Create func(client *rancher.Client) (string, error)
+	Expire func() error
+	Renew  func() error
+}
+
+// CloudCredentialLifecycleChecks runs the cloud credential of the cluster through its lifecycle: it is rotated, corrupted and restored, deleted
+// and replaced, then expired and renewed when the provider allows it; after each failure, the cluster must report the matching error
+// and recover once the cloud credential is valid again. The cloud credential of the suite is left untouched.
+func CloudCredentialLifecycleChecks(cluster *management.Cluster, client *rancher.Client, ops CredentialOperations) *management.Cluster {
+	var cloudCredID string
+	ginkgo.By("rotating the cloud credential", func() {
+		cluster, cloudCredID = RotateCloudCredential(cluster, client)
+		Expect(ops.Trigger(client, cluster)).To(Succeed())
+		cluster = WaitForCloudCredentialRecovery(cluster, client)
+	})
+
+	ginkgo.By("corrupting the cloud credential", func() {
+		restore := CorruptCloudCredential(client, cloudCredID)
+		cluster = WaitForCloudCredentialError(cluster, client, ops, credential.Corrupted)
+		restore()
+		cluster = WaitForCloudCredentialRecovery(cluster, client)
+	})
+
+	ginkgo.By("deleting the cloud credential", func() {
+		DeleteCloudCredential(client, cloudCredID)
+		cluster = WaitForCloudCredentialError(cluster, client, ops, credential.Deleted)
+		cluster, cloudCredID = RotateCloudCredential(cluster, client)
+		cluster = WaitForCloudCredentialRecovery(cluster, client)
+	})
+
+	if ops.Expiry == nil {
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Skipping the expiry of the cloud credential, it cannot be simulated on %s", Provider))
+		return cluster
+	}
+	ginkgo.By("expiring the cloud credential", func() {
+		expiringCloudCredID, err := ops.Expiry.Create(client)
+		Expect(err).To(BeNil())
+		cluster = SwitchCloudCredential(cluster, client, expiringCloudCredID)
+		Expect(ops.Expiry.Expire()).To(Succeed())
+		renewed := false
+		defer func() {
+			if !renewed {
+				Expect(ops.Expiry.Renew()).To(Succeed())
+			}
+		}()
+		cluster = WaitForCloudCredentialError(cluster, client, ops, credential.Expired)
+		Expect(ops.Expiry.Renew()).To(Succeed())
+		renewed = true
+		cluster = WaitForCloudCredentialRecovery(cluster, client)
+	})
+	return cluster
+}
+
+// RotateCloudCredential creates a new cloud credential and switches the cluster to it; it returns the cluster and the ID of the new cloud credential
+func RotateCloudCredential(cluster *management.Cluster, client *rancher.Client) (*management.Cluster, string) {
+	cloudCredID, err := CreateCloudCredentials(client)
+	Expect(err).To(BeNil())
+	return SwitchCloudCredential(cluster, client, cloudCredID), cloudCredID
+}
+
+// SwitchCloudCredential switches the cluster to the cloud credential cloudCredID and waits for its operator to use it
+func SwitchCloudCredential(cluster *management.Cluster, client *rancher.Client, cloudCredID string) *management.Cluster {
+	upgradedCluster := cluster
+	setClusterCloudCredential(upgradedCluster, cloudCredID)
+	cluster, err := client.Management.Cluster.Update(cluster, &upgradedCluster)
+	Expect(err).To(BeNil())
+	spec, _ := clusterCloudCredential(cluster)
+	Expect(spec).To(Equal(cloudCredID))
+
+	Eventually(func() string {
+		cluster, err = client.Management.Cluster.ByID(cluster.ID)
+		Expect(err).To(BeNil())
+		_, upstream := clusterCloudCredential(cluster)
+		return upstream
+	}, tools.SetTimeout(5*time.Minute), 5*time.Second).Should(Equal(cloudCredID), "Failed while upstream cloud credentials update")
+	return cluster
+}
+
+// CorruptCloudCredential replaces the secret part of a cloud credential with an invalid value, see credential.Corrupt;
+// it returns the function restoring the cloud credential
+func CorruptCloudCredential(client *rancher.Client, cloudCredID string) (restore func()) {
+	secrets := client.Steve.SteveType("secret")
+	object, err := secrets.ByID(strings.Replace(cloudCredID, ":", "/", 1))
+	Expect(err).To(BeNil())
+	secret := &corev1.Secret{}
+	Expect(v1.ConvertToK8sType(object.JSONResp, secret)).To(Succeed())
+	original := secret.Data
+
+	secret.Data, err = credential.Corrupt(Provider, original)
+	Expect(err).To(BeNil())
+	corrupted, err := secrets.Update(object, secret)
+	Expect(err).To(BeNil())
+	ginkgo.GinkgoLogr.Info(fmt.Sprintf("Corrupted the cloud credential %s", cloudCredID))
+
+	return func() {
+		secret.Data = original
+		_, err := secrets.Update(corrupted, secret)
+		Expect(err).To(BeNil())
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Restored the cloud credential %s", cloudCredID))
+	}
+}
+
+// DeleteCloudCredential deletes a cloud credential
+func DeleteCloudCredential(client *rancher.Client, cloudCredID string) {
+	cloudCredential, err := client.Management.CloudCredential.ByID(cloudCredID)
+	Expect(err).To(BeNil())
+	Expect(client.Management.CloudCredential.Delete(cloudCredential)).To(Succeed())
+	ginkgo.GinkgoLogr.Info(fmt.Sprintf("Deleted the cloud credential %s", cloudCredID))
+}
+
+// WaitForCloudCredentialError triggers a change of the cluster and waits for the cluster to report the given failure of its cloud credential
+func WaitForCloudCredentialError(cluster *management.Cluster, client *rancher.Client, ops CredentialOperations, failure credential.Failure) *management.Cluster {
+	cluster, err := client.Management.Cluster.ByID(cluster.ID)
+	Expect(err).To(BeNil())
+	Expect(ops.Trigger(client, cluster)).To(Succeed())
+
+	Eventually(func() bool {
+		cluster, err = client.Management.Cluster.ByID(cluster.ID)
+		Expect(err).To(BeNil())
+		return cluster.Transitioning == "error" && credential.Matches(Provider, failure, cluster.TransitioningMessage)
+	}, tools.SetTimeout(10*time.Minute), 10*time.Second).Should(BeTrue(), func() string {
+		return fmt.Sprintf("cluster %s did not report a %s cloud credential (%s), its state is %s: %s", cluster.Name, failure,
+			strings.Join(credential.Fragments(Provider, failure), ", "), cluster.State, cluster.TransitioningMessage)
+	})
+	ginkgo.GinkgoLogr.Info(fmt.Sprintf("Cluster %s reports the %s cloud credential: %s", cluster.Name, failure, cluster.TransitioningMessage))
+	return cluster
+}
+
+// WaitForCloudCredentialRecovery waits for the cluster to recover from the failure of its cloud credential and to be ready
+func WaitForCloudCredentialRecovery(cluster *management.Cluster, client *rancher.Client) *management.Cluster {
+	return WaitForClusterRecovery(cluster, client)
+}
+
+// WaitForClusterRecovery waits for the cluster to leave its error state and to be ready
+func WaitForClusterRecovery(cluster *management.Cluster, client *rancher.Client) *management.Cluster {
+	var err error
+	Eventually(func() bool {
+		cluster, err = client.Management.Cluster.ByID(cluster.ID)
+		Expect(err).To(BeNil())
+		return cluster.Transitioning != "error" && cluster.State == "active"
+	}, tools.SetTimeout(20*time.Minute), 15*time.Second).Should(BeTrue(), func() string {
+		return fmt.Sprintf("cluster %s did not recover, its state is %s: %s", cluster.Name, cluster.State, cluster.TransitioningMessage)
+	})
+	cluster, err = WaitUntilClusterIsReady(cluster, client)
+	Expect(err).To(BeNil())
+	return cluster
+}
+
+// clusterCloudCredential returns the cloud credential of the config of the cluster, and the one its operator uses
+func clusterCloudCredential(cluster *management.Cluster) (spec, upstream string) {
+	switch {
+	case cluster.AKSConfig != nil:
+		spec = cluster.AKSConfig.AzureCredentialSecret
+		if cluster.AKSStatus != nil && cluster.AKSStatus.UpstreamSpec != nil {
+			upstream = cluster.AKSStatus.UpstreamSpec.AzureCredentialSecret
+		}
+	case cluster.EKSConfig != nil:
+		spec = cluster.EKSConfig.AmazonCredentialSecret
+		if cluster.EKSStatus != nil && cluster.EKSStatus.UpstreamSpec != nil {
+			upstream = cluster.EKSStatus.UpstreamSpec.AmazonCredentialSecret
+		}
+	case cluster.GKEConfig != nil:
+		spec = cluster.GKEConfig.GoogleCredentialSecret
+		if cluster.GKEStatus != nil && cluster.GKEStatus.UpstreamSpec != nil {
+			upstream = cluster.GKEStatus.UpstreamSpec.GoogleCredentialSecret
+		}
+	// PANDARIA:
+	case cluster.CCEConfig != nil:
+		spec = cluster.CCEConfig.HuaweiCredentialSecret
+		if cluster.CCEStatus != nil && cluster.CCEStatus.UpstreamSpec != nil {
+			upstream = cluster.CCEStatus.UpstreamSpec.HuaweiCredentialSecret
+		}
+	case cluster.ACKConfig != nil:
+		spec = cluster.ACKConfig.AliyunCredentialSecret
+		if cluster.ACKStatus != nil && cluster.ACKStatus.UpstreamSpec != nil {
+			upstream = cluster.ACKStatus.UpstreamSpec.AliyunCredentialSecret
+		}
+	case cluster.TKEConfig != nil:
+		spec = cluster.TKEConfig.TKECredentialSecret
+		if cluster.TKEStatus != nil && cluster.TKEStatus.UpstreamSpec != nil {
+			upstream = cluster.TKEStatus.UpstreamSpec.TKECredentialSecret
+		}
+	}
+	return spec, upstream
+}
+
+// setClusterCloudCredential sets the cloud credential of the config of the cluster
+func setClusterCloudCredential(cluster *management.Cluster, cloudCredID string) {
+	switch {
+	case cluster.AKSConfig != nil:
+		cluster.AKSConfig.AzureCredentialSecret = cloudCredID
+	case cluster.EKSConfig != nil:
+		cluster.EKSConfig.AmazonCredentialSecret = cloudCredID
+	case cluster.GKEConfig != nil:
+		cluster.GKEConfig.GoogleCredentialSecret = cloudCredID
+	// PANDARIA:
+	case cluster.CCEConfig != nil:
+		cluster.CCEConfig.HuaweiCredentialSecret = cloudCredID
+	case cluster.ACKConfig != nil:
+		cluster.ACKConfig.AliyunCredentialSecret = cloudCredID
+	case cluster.TKEConfig != nil:
+		cluster.TKEConfig.TKECredentialSecret = cloudCredID
+	}
+}
@@ -0,0 +1,177 @@
+package helpers
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/egress"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+)
+
+const (
+	// EgressFaultsDir holds the egress faults included by the squid.conf of the proxy mode, InstallK3S mounts it in the squid_proxy container
+	EgressFaultsDir = "/tmp/hp-egress"
+
+	egressFaultsFile = "faults.conf"
+	squidContainer   = "squid_proxy"
+)
+
+// prepareEgressFaults writes the empty egress faults, the squid proxy does not start without them
+func prepareEgressFaults() {
+	Expect(os.MkdirAll(EgressFaultsDir, 0o755)).To(Succeed())
+	writeEgressFaults(nil)
+}
+
+// InjectEgressFaults applies the rules to the squid proxy of the proxy mode, so that the requests of Rancher and its operators to the
+// hosts of the rules are faulted; it returns the function clearing the faults. The proxy is restarted to close the established
+// tunnels, which squid would let through otherwise.
+func InjectEgressFaults(rules ...egress.Rule) (clearFaults func()) {
+	out, err := exec.Command("docker", "inspect", "--format", "{{range .Mounts}}{{.Destination}} {{end}}", squidContainer).Output()
+	Expect(err).To(BeNil(), "the squid proxy of the proxy mode is not running")
+	Expect(string(out)).To(ContainSubstring("/etc/squid/egress"), "the squid proxy was not started with the egress faults, re-install k3s")
+
+	writeEgressFaults(rules)
+	restartSquid()
+	for _, rule := range rules {
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Injected the egress fault %s: %s %s", rule.Name, rule.Action, strings.Join(rule.Hosts, ",")))
+	}
+
+	return func() {
+		writeEgressFaults(nil)
+		restartSquid()
+		ginkgo.GinkgoLogr.Info("Cleared the egress faults")
+	}
+}
+
+// writeEgressFaults renders the rules to the egress faults included by squid.conf
+func writeEgressFaults(rules []egress.Rule) {
+	config, err := egress.Config(rules)
+	Expect(err).To(BeNil())
+	Expect(os.WriteFile(filepath.Join(EgressFaultsDir, egressFaultsFile), []byte(config), 0o644)).To(Succeed())
+}
+
+// restartSquid checks the configuration of the squid proxy, then restarts it and waits for it to serve again
+func restartSquid() {
+	out, err := exec.Command("docker", "exec", squidContainer, "squid", "-k", "parse").CombinedOutput()
+	Expect(err).To(BeNil(), "invalid squid configuration:\n%s", out)
+	out, err = exec.Command("docker", "restart", squidContainer).CombinedOutput()
+	Expect(err).To(BeNil(), string(out))
+	Eventually(func() error {
+		return exec.Command("docker", "exec", squidContainer, "squid", "-k", "check").Run()
+	}, tools.SetTimeout(2*time.Minute), 2*time.Second).Should(Succeed(), "the squid proxy did not restart")
+}
+
+// EgressFaultChecks injects each rule in turn while trigger submits a change of the cluster: the faults which fail the requests must be
+// reported on the cluster, and the cluster must recover once they are cleared; the delays must only slow the change down, whose node count
+// and k8s version must be applied upstream once the cluster recovers.
+func EgressFaultChecks(cluster *management.Cluster, client *rancher.Client, trigger func(client *rancher.Client, cluster *management.Cluster) error, rules ...egress.Rule) *management.Cluster {
+	for _, rule := range rules {
+		ginkgo.By(fmt.Sprintf("injecting the egress fault %s", rule.Name), func() {
+			clearFaults := InjectEgressFaults(rule)
+			cleared := false
+			defer func() {
+				if !cleared {
+					clearFaults()
+				}
+			}()
+
+			var err error
+			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+			Expect(err).To(BeNil())
+			Expect(trigger(client, cluster)).To(Succeed())
+
+			if rule.Action == egress.Delay {
+				// the change goes through, slowly: the cluster is updated, then recovers with the change applied upstream
+				cluster, err = client.Management.Cluster.ByID(cluster.ID)
+				Expect(err).To(BeNil())
+				nodeCount, k8sVersion := poolNodeCount(cluster), clusterKubernetesVersion(cluster)
+				Eventually(func() string {
+					cluster, err = client.Management.Cluster.ByID(cluster.ID)
+					Expect(err).To(BeNil())
+					return cluster.State
+				}, tools.SetTimeout(10*time.Minute), 5*time.Second).ShouldNot(Equal("active"), "cluster %s was not updated under the egress fault %s", cluster.Name, rule.Name)
+				ginkgo.GinkgoLogr.Info(fmt.Sprintf("Cluster %s is %s under the egress fault %s", cluster.Name, cluster.State, rule.Name))
+				cluster = WaitForClusterRecovery(cluster, client)
+
+				Eventually(func() int64 {
+					cluster, err = client.Management.Cluster.ByID(cluster.ID)
+					Expect(err).To(BeNil())
+					return poolNodeCount(upstreamConfig(cluster))
+				}, tools.SetTimeout(5*time.Minute), 5*time.Second).Should(Equal(nodeCount), "the node count of cluster %s was not changed under the egress fault %s", cluster.Name, rule.Name)
+				Expect(clusterKubernetesVersion(upstreamConfig(cluster))).To(Equal(k8sVersion), "the k8s version of cluster %s was not changed under the egress fault %s", cluster.Name, rule.Name)
+			} else {
+				Eventually(func() bool {
+					cluster, err = client.Management.Cluster.ByID(cluster.ID)
+					Expect(err).To(BeNil())
+					return cluster.Transitioning == "error" && rule.Matches(cluster.TransitioningMessage)
+				}, tools.SetTimeout(15*time.Minute), 10*time.Second).Should(BeTrue(), func() string {
+					return fmt.Sprintf("cluster %s did not report the egress fault %s (%s), its state is %s: %s", cluster.Name, rule.Name,
+						strings.Join(rule.Fragments(), ", "), cluster.State, cluster.TransitioningMessage)
+				})
+				ginkgo.GinkgoLogr.Info(fmt.Sprintf("Cluster %s reports the egress fault %s: %s", cluster.Name, rule.Name, cluster.TransitioningMessage))
+			}
+
+			clearFaults()
+			cleared = true
+			cluster = WaitForClusterRecovery(cluster, client)
+		})
+	}
+	return cluster
+}
+
+// upstreamConfig returns a cluster whose config is the upstream spec of the cluster, as reported by its operator
+func upstreamConfig(cluster *management.Cluster) *management.Cluster {
+	upstream := &management.Cluster{Name: cluster.Name}
+	if cluster.AKSStatus != nil {
+		upstream.AKSConfig = cluster.AKSStatus.UpstreamSpec
+	}
+	if cluster.EKSStatus != nil {
+		upstream.EKSConfig = cluster.EKSStatus.UpstreamSpec
+	}
+	if cluster.GKEStatus != nil {
+		upstream.GKEConfig = cluster.GKEStatus.UpstreamSpec
+	}
+	return upstream
+}
+
+// clusterKubernetesVersion returns the k8s version in the config of the cluster, it is empty when the config has none
+func clusterKubernetesVersion(cluster *management.Cluster) string {
+	var version *string
+	switch {
+	case cluster.AKSConfig != nil:
+		version = cluster.AKSConfig.KubernetesVersion
+	case cluster.EKSConfig != nil:
+		version = cluster.EKSConfig.KubernetesVersion
+	case cluster.GKEConfig != nil:
+		version = cluster.GKEConfig.KubernetesVersion
+	}
+	if version == nil {
+		return ""
+	}
+	return *version
+}
+
+// poolNodeCount returns the node count of the first node pool or node group in the config of the cluster, it is 0 when it has none
+func poolNodeCount(cluster *management.Cluster) int64 {
+	var count *int64
+	switch {
+	case cluster.AKSConfig != nil && cluster.AKSConfig.NodePools != nil && len(*cluster.AKSConfig.NodePools) > 0:
+		count = (*cluster.AKSConfig.NodePools)[0].Count
+	case cluster.EKSConfig != nil && cluster.EKSConfig.NodeGroups != nil && len(*cluster.EKSConfig.NodeGroups) > 0:
+		count = (*cluster.EKSConfig.NodeGroups)[0].DesiredSize
+	case cluster.GKEConfig != nil && cluster.GKEConfig.NodePools != nil && len(*cluster.GKEConfig.NodePools) > 0:
+		count = (*cluster.GKEConfig.NodePools)[0].InitialNodeCount
+	}
+	if count == nil {
+		return 0
+	}
+	return *count
+}
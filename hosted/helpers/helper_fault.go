@@ -0,0 +1,190 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/kubectl"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/fault"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+// FaultOperations are the provider specific parts of the operator fault checks
+type FaultOperations struct {
+	// Converged reports whether the UpstreamSpec of the cluster matches its config
+	Converged func(cluster *management.Cluster) bool
+	// UpstreamNodePools returns the names of the node pools of the UpstreamSpec of the cluster
+	UpstreamNodePools func(cluster *management.Cluster) []string
+	// CloudNodePools returns the names of the node pools of the cluster, as seen by the provider
+	CloudNodePools func(cluster *management.Cluster) ([]string, error)
+}
+
+// OperatorFaultChecks runs a mutation of the cluster while the operator is disrupted by the injection, see MutateWithOperatorFault,
+// and checks that the mutation converges: the UpstreamSpec matches the config, the node pools are neither duplicated nor missing
+// on the provider, and no condition of the cluster is stuck
+func OperatorFaultChecks(cluster *management.Cluster, client *rancher.Client, injection fault.Injection, ops FaultOperations, mutate func(cluster *management.Cluster) (*management.Cluster, error)) *management.Cluster {
+	cluster = MutateWithOperatorFault(cluster, client, injection, mutate)
+	return WaitForConvergence(cluster, client, ops)
+}
+
+// MutateWithOperatorFault submits the mutation of the cluster and disrupts the operator at the point of the injection; mutate must only
+// submit the mutation, e.g. UpgradeClusterKubernetesVersion or AddNodePool without waiting nor checking the cluster config.
+// The operator is ready again when it returns.
+func MutateWithOperatorFault(cluster *management.Cluster, client *rancher.Client, injection fault.Injection, mutate func(cluster *management.Cluster) (*management.Cluster, error)) *management.Cluster {
+	Expect(injection.Validate()).To(Succeed())
+	ginkgo.GinkgoLogr.Info(fmt.Sprintf("Mutating cluster %s with the operator fault: %s", cluster.Name, injection))
+
+	var err error
+	switch injection.Point {
+	case fault.BeforeSubmit:
+		restore := ScaleDownOperator()
+		cluster, err = mutate(cluster)
+		if err != nil {
+			restore()
+		}
+		Expect(err).To(BeNil())
+		time.Sleep(injection.Outage)
+		restore()
+	case fault.Submitted:
+		cluster, err = mutate(cluster)
+		Expect(err).To(BeNil())
+		InjectOperatorFault(injection)
+	case fault.InProgress:
+		cluster, err = mutate(cluster)
+		Expect(err).To(BeNil())
+		Eventually(func() string {
+			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+			Expect(err).To(BeNil())
+			return cluster.State
+		}, tools.SetTimeout(5*time.Minute), 2*time.Second).Should(Equal("updating"), "the operator did not start updating cluster %s", cluster.Name)
+		InjectOperatorFault(injection)
+	}
+	return cluster
+}
+
+// InjectOperatorFault disrupts the operator of Provider now, and waits for it to be ready again
+func InjectOperatorFault(injection fault.Injection) {
+	switch injection.Action {
+	case fault.Kill:
+		KillOperator()
+	case fault.ScaleDown:
+		restore := ScaleDownOperator()
+		time.Sleep(injection.Outage)
+		restore()
+	}
+}
+
+// KillOperator deletes the operator pods of Provider and waits for their replacements to be ready
+func KillOperator() {
+	out, err := kubectl.RunWithoutErr("delete", "pod", "--namespace", CattleSystemNS, "-l", fault.OperatorSelector(Provider), "--wait=false")
+	Expect(err).To(BeNil(), out)
+	ginkgo.GinkgoLogr.Info(fmt.Sprintf("Killed the %s operator: %s", Provider, out))
+	// the deleted pods are still listed until they terminate, the rollout status would not wait for their replacements
+	Eventually(func() string {
+		out, _ := kubectl.RunWithoutErr("get", "pod", "--namespace", CattleSystemNS, "-l", fault.OperatorSelector(Provider),
+			"-o", "jsonpath={.items[*].metadata.deletionTimestamp}")
+		return out
+	}, tools.SetTimeout(2*time.Minute), 2*time.Second).Should(BeEmpty())
+	WaitForOperatorReady()
+}
+
+// ScaleDownOperator scales the operator deployment of Provider to zero; it returns the function scaling it back to its replicas
+func ScaleDownOperator() (restore func()) {
+	deployment := operatorDeployment()
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil && *deployment.Spec.Replicas > 0 {
+		replicas = *deployment.Spec.Replicas
+	}
+	scale(deployment.Name, 0)
+	ginkgo.GinkgoLogr.Info(fmt.Sprintf("Scaled down the %s operator %s", Provider, deployment.Name))
+
+	return func() {
+		scale(deployment.Name, replicas)
+		WaitForOperatorReady()
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Scaled the %s operator %s back to %d replicas", Provider, deployment.Name, replicas))
+	}
+}
+
+// WaitForOperatorReady waits for the operator deployment of Provider to be rolled out
+func WaitForOperatorReady() {
+	name := operatorDeployment().Name
+	out, err := kubectl.RunWithoutErr("rollout", "status", "deployment/"+name, "--namespace", CattleSystemNS, "--timeout=5m")
+	Expect(err).To(BeNil(), out)
+}
+
+// WaitForConvergence waits for the mutation of the cluster to converge in its UpstreamSpec and for the cluster to be ready,
+// then checks that the node pools are neither duplicated nor missing on the provider and that no condition of the cluster is stuck
+func WaitForConvergence(cluster *management.Cluster, client *rancher.Client, ops FaultOperations) *management.Cluster {
+	var err error
+	Eventually(func() bool {
+		cluster, err = client.Management.Cluster.ByID(cluster.ID)
+		Expect(err).To(BeNil())
+		return ops.Converged(cluster)
+	}, tools.SetTimeout(30*time.Minute), 15*time.Second).Should(BeTrue(), "the UpstreamSpec of cluster %s did not converge", cluster.Name)
+
+	cluster, err = WaitUntilClusterIsReady(cluster, client)
+	Expect(err).To(BeNil())
+
+	ginkgo.By("checking the node pools on the provider", func() {
+		upstream := ops.UpstreamNodePools(cluster)
+		Eventually(func(g Gomega) {
+			nodePools, err := ops.CloudNodePools(cluster)
+			g.Expect(err).To(BeNil())
+			g.Expect(fault.Duplicates(nodePools)).To(BeEmpty(), "duplicated node pools of cluster %s", cluster.Name)
+			missing, extra := fault.Compare(upstream, nodePools)
+			g.Expect(missing).To(BeEmpty(), "node pools of cluster %s missing on the provider", cluster.Name)
+			g.Expect(extra).To(BeEmpty(), "node pools of cluster %s unknown to Rancher", cluster.Name)
+		}, tools.SetTimeout(5*time.Minute), 15*time.Second).Should(Succeed())
+	})
+
+	ginkgo.By("checking the conditions of the cluster", func() {
+		Eventually(func() string {
+			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+			Expect(err).To(BeNil())
+			var stuck []string
+			for _, condition := range fault.Stuck(clusterConditions(cluster)) {
+				stuck = append(stuck, condition.String())
+			}
+			return strings.Join(stuck, "\n")
+		}, tools.SetTimeout(5*time.Minute), 10*time.Second).Should(BeEmpty(), "stuck conditions of cluster %s", cluster.Name)
+	})
+	return cluster
+}
+
+// operatorDeployment returns the deployment of the operator pods of Provider
+func operatorDeployment() *appsv1.Deployment {
+	out, err := kubectl.RunWithoutErr("get", "deployment", "--namespace", CattleSystemNS, "-o", "json")
+	Expect(err).To(BeNil(), out)
+	deployments := &appsv1.DeploymentList{}
+	Expect(json.Unmarshal([]byte(out), deployments)).To(Succeed())
+	for i := range deployments.Items {
+		if selector := deployments.Items[i].Spec.Selector; selector != nil && selector.MatchLabels[fault.OperatorLabel] == Provider {
+			return &deployments.Items[i]
+		}
+	}
+	ginkgo.Fail(fmt.Sprintf("no deployment of the %s operator in %s", Provider, CattleSystemNS))
+	return nil
+}
+
+// scale sets the replicas of a deployment of cattle-system
+func scale(deployment string, replicas int32) {
+	out, err := kubectl.RunWithoutErr("scale", "deployment/"+deployment, "--namespace", CattleSystemNS, "--replicas", strconv.Itoa(int(replicas)))
+	Expect(err).To(BeNil(), out)
+}
+
+// clusterConditions returns the conditions of the cluster
+func clusterConditions(cluster *management.Cluster) []fault.Condition {
+	var conditions []fault.Condition
+	for _, condition := range cluster.Conditions {
+		conditions = append(conditions, fault.Condition{Type: condition.Type, Status: condition.Status, Reason: condition.Reason, Message: condition.Message})
+	}
+	return conditions
+}
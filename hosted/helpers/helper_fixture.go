@@ -0,0 +1,96 @@
+package helpers
+
+import (
+	"fmt"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	namegen "github.com/rancher/shepherd/pkg/namegenerator"
+)
+
+// qaseReportEntry is the name of the report entry holding the Qase case ID of a spec
+const qaseReportEntry = "qase-case-id"
+
+// Fixture is the state of a single spec: the cluster under test, its name and versions, the clients and the Qase case ID;
+// it is created by NewFixture and passed to the check functions, so that specs running in parallel never share mutable state
+type Fixture struct {
+	Ctx *RancherContext
+	// Client is the client used to create and edit the cluster, RancherAdminClient by default
+	Client           *rancher.Client
+	ClusterName      string
+	K8sVersion       string
+	UpgradeToVersion string
+	Cluster          *management.Cluster
+	cleanups         []func()
+	// costGuarded is set once the cost of the cluster of the fixture is reserved, see GuardCost
+	costGuarded bool
+}
+
+// NewFixture creates the fixture of the current spec with a random cluster name; it must be called from a setup node (e.g. BeforeEach).
+// The cleanup functions of the fixture run in reverse order once the spec is done, after its AfterEach nodes.
+func NewFixture(ctx *RancherContext) *Fixture {
+	f := &Fixture{
+		Ctx:         ctx,
+		Client:      ctx.RancherAdminClient,
+		ClusterName: namegen.AppendRandomString(ClusterNamePrefix),
+	}
+	ginkgo.DeferCleanup(f.cleanup)
+	return f
+}
+
+// GuardCost reserves the cost of the cluster the spec is about to create, see GuardClusterCost; the spec is skipped if it would
+// exceed MAX_RUN_COST. The cluster-hours are recorded once the cluster is deleted by the cleanup of AddClusterCleanup.
+func (f *Fixture) GuardCost() {
+	if f.costGuarded {
+		return
+	}
+	GuardClusterCost(f.ClusterName)
+	f.costGuarded = true
+}
+
+// SetQaseID records the Qase case ID of the current spec, see QaseID
+func (f *Fixture) SetQaseID(id int64) {
+	ginkgo.AddReportEntry(qaseReportEntry, id, ginkgo.ReportEntryVisibilityNever)
+}
+
+// AddCleanup registers a function to run once the spec is done
+func (f *Fixture) AddCleanup(cleanup func()) {
+	f.cleanups = append(f.cleanups, cleanup)
+}
+
+// AddClusterCleanup registers a function deleting the downstream cluster (or its cloud resources) once the spec is done;
+// it is skipped when DOWNSTREAM_CLUSTER_CLEANUP is not set. It is called before the cluster is created, which is when its cost
+// is reserved by GuardCost; the cluster-hours are counted up to the deletion, or to the end of the spec when the cluster is kept.
+func (f *Fixture) AddClusterCleanup(cleanup func()) {
+	f.GuardCost()
+	f.AddCleanup(func() {
+		if !f.Ctx.ClusterCleanup {
+			fmt.Println("Skipping downstream cluster deletion: ", f.ClusterName)
+		} else {
+			// the reservation is left open when the deletion fails, the cluster is still running
+			cleanup()
+		}
+		costReportEntry(CompleteClusterCost(f.ClusterName, f.Cluster))
+	})
+}
+
+func (f *Fixture) cleanup() {
+	for i := len(f.cleanups) - 1; i >= 0; i-- {
+		f.cleanups[i]()
+	}
+}
+
+// QaseID returns the last Qase case ID recorded by Fixture.SetQaseID for the spec of report, or -1 if there is none
+func QaseID(report ginkgo.SpecReport) int64 {
+	id := int64(-1)
+	for _, entry := range report.ReportEntries {
+		if entry.Name != qaseReportEntry {
+			continue
+		}
+		if value, ok := entry.GetRawValue().(int64); ok {
+			id = value
+		}
+	}
+	return id
+}
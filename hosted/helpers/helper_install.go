@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -34,42 +35,56 @@ func RunHelmCmdWithRetry(s ...string) {
 *
 Install k3s
   - @param k kubectl structure
-  - @param k3sVersion version to install
-  - @param proxy, enable proxy
-  - @param proxyHost, if proxy enabled
+  - @param profile environment profile, defines k3s version, proxy, private registry and airgap mode
   - @returns Nothing, the function will fail through Ginkgo in case of issue
 */
-func InstallK3S(k *kubectl.Kubectl, k3sVersion, proxy, proxyHost string) {
-	if proxy == "enabled" {
+func InstallK3S(k *kubectl.Kubectl, profile EnvironmentProfile) {
+	if profile.Proxy.Enabled {
 		By("Run local squid proxy in docker", func() {
 			// Configure proxy before k3s installation if requested
+			// The proxy is kept running when k3s is re-installed (eg. during restore)
+			if out, err := exec.Command("docker", "inspect", "--format", "{{.State.Running}}", "squid_proxy").Output(); err == nil && strings.TrimSpace(string(out)) == "true" {
+				GinkgoLogr.Info("Squid proxy is already running")
+				return
+			}
 			GinkgoLogr.Info("Starting squid proxy")
 
 			cwd, _ := os.Getwd()
 			GinkgoLogr.Info("Current working directory: " + cwd)
 
+			// squid.conf includes the egress faults, none until a spec injects some
+			prepareEgressFaults()
+
 			out, err := exec.Command("docker", "run", "-d", "--rm", "--name", "squid_proxy",
 				"--volume", cwd+"/.github/scripts/squid.conf:/etc/squid/squid.conf",
+				"--volume", EgressFaultsDir+":/etc/squid/egress",
 				"-p", "3128:3128", "ubuntu/squid").CombinedOutput()
 			GinkgoWriter.Println(string(out))
 			Expect(err).To(Not(HaveOccurred()))
 		})
 
 		By("Configure proxy in /etc/default/k3s", func() {
-			k3sConfigPath := "/etc/default/k3s"
 			k3sConfig := fmt.Sprintf(`HTTP_PROXY=http://%s
 HTTPS_PROXY=http://%s
-NO_PROXY=127.0.0.0/8,10.0.0.0/8,cattle-system.svc,172.16.0.0/12,192.168.0.0/16,.svc,.cluster.local`, proxyHost, proxyHost)
-			// Write the k3s proxy config file as root
-			out, err := exec.Command("sh", "-c", fmt.Sprintf("echo '%s' | sudo tee %s", k3sConfig, k3sConfigPath)).CombinedOutput()
-			GinkgoWriter.Println(string(out))
-			Expect(err).To(Not(HaveOccurred()))
+NO_PROXY=%s`, profile.Proxy.Host, profile.Proxy.Host, profile.Proxy.NoProxy)
+			writeRootFile("/etc/default/k3s", k3sConfig)
+		})
+	}
+
+	if registry := profile.mirrorRegistry(); registry.URL != "" {
+		By("Configure private registry in /etc/rancher/k3s/registries.yaml", func() {
+			writeRootFile("/etc/rancher/k3s/registries.yaml", registry.k3sRegistriesConfig())
+		})
+	}
+
+	if profile.Airgap.Enabled {
+		By("Installing k3s airgap artifacts", func() {
+			installK3sAirgapArtifacts(profile)
 		})
 	}
 
 	By("Getting k3s ready", func() {
-		installCmd := exec.Command("sh", "-c", "curl -sfL https://get.k3s.io | sh -s - server --cluster-init")
-		installCmd.Env = append(os.Environ(), "INSTALL_K3S_VERSION="+k3sVersion, "INSTALL_K3S_EXEC=--write-kubeconfig-mode 644")
+		installCmd := k3sInstallCommand(profile)
 
 		// Execute k3s installation
 		count := 1
@@ -101,28 +116,33 @@ NO_PROXY=127.0.0.0/8,10.0.0.0/8,cattle-system.svc,172.16.0.0/12,192.168.0.0/16,.
 *
 Install CertManager
   - @param k kubectl structure
-  - @param proxy, enable proxy
-  - @param proxyHost, if proxy enabled
+  - @param profile environment profile, defines cert-manager version and proxy
   - @returns Nothing, the function will fail through Ginkgo in case of issue
 */
-func InstallCertManager(k *kubectl.Kubectl, proxy, proxyHost string) {
+func InstallCertManager(k *kubectl.Kubectl, profile EnvironmentProfile) {
 	By("Installing CertManager", func() {
-		RunHelmCmdWithRetry("repo", "add", "jetstack", "https://charts.jetstack.io")
-		RunHelmCmdWithRetry("repo", "update")
+		if !profile.Airgap.Enabled {
+			RunHelmCmdWithRetry("repo", "add", "jetstack", "https://charts.jetstack.io")
+			RunHelmCmdWithRetry("repo", "update")
+		}
 
 		// Set flags for cert-manager installation
 		flags := []string{
-			"upgrade", "--install", "cert-manager", "jetstack/cert-manager",
+			"upgrade", "--install", "cert-manager", certManagerChartRef(profile),
 			"--namespace", "cert-manager",
 			"--create-namespace",
 			"--set", "crds.enabled=true",
 			"--wait", "--wait-for-jobs",
 		}
 
-		if proxy == "enabled" {
-			flags = append(flags, "--set", "http_proxy=http://"+proxyHost,
-				"--set", "https_proxy=http://"+proxyHost,
-				"--set", "no_proxy=127.0.0.0/8\\,10.0.0.0/8\\,cattle-system.svc\\,172.16.0.0/12\\,192.168.0.0/16\\,.svc\\,.cluster.local")
+		if profile.CertManagerVersion != "" && !profile.Airgap.Enabled {
+			flags = append(flags, "--version", profile.CertManagerVersion)
+		}
+
+		if profile.Proxy.Enabled {
+			flags = append(flags, "--set", "http_proxy=http://"+profile.Proxy.Host,
+				"--set", "https_proxy=http://"+profile.Proxy.Host,
+				"--set", "no_proxy="+strings.ReplaceAll(profile.Proxy.NoProxy, ",", "\\,"))
 		}
 		GinkgoWriter.Printf("Helm flags: %v\n", flags)
 		RunHelmCmdWithRetry(flags...)
@@ -144,45 +164,43 @@ Install Rancher Manager
 
   - @param k kubectl structure
 
-  - @param rancherHostname, Rancher Hostname
-
-  - @param rancherChannel, Rancher Channel [eg. latest, prime]
-
-  - @param rancherVersion, Rancher Version [eg. 2.9.3-rc2]
-
-  - @param rancherHeadVersion, Rancher Head version [eg. 2.9-head]
-
-  - @param proxy, enable proxy
-
-  - @param nightlyChart, enable operator nightly chart
+  - @param profile environment profile, defines Rancher hostname, channel, version, Helm values, proxy, private registry, nightly operator charts and operator override
 
   - @returns Nothing, the function will fail through Ginkgo in case of issue
 */
-func InstallRancherManager(k *kubectl.Kubectl, rancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, proxy, nightlyChart string) {
-	var proxyEnabled string
-	if proxy == "enabled" {
+func InstallRancherManager(k *kubectl.Kubectl, profile EnvironmentProfile) {
+	proxyEnabled := "none"
+	if profile.Proxy.Enabled {
 		proxyEnabled = "rancher"
-	} else {
-		proxyEnabled = "none"
 	}
 
+	Expect(profile.NightlyOperatorCharts && profile.OperatorOverride.Enabled()).To(BeFalse(), "nightly operator charts and operator override are exclusive")
+
 	var extraFlags []string
-	if nightlyChart == "enabled" {
+	if profile.NightlyOperatorCharts || profile.OperatorOverride.Enabled() {
 		// Ensure proper extraEnv index sequence for helm rendering
 		// All head versions and releases from prime-optimus[-alpha] channel require an extraEnv index of 2
 		// See https://github.com/rancher-sandbox/ele-testhelpers/blob/main/rancher/install.go
 		extraEnvIndex := 1
-		if rancherHeadVersion != "" || strings.Contains(rancherChannel, "prime-optimus") {
+		if profile.Rancher.HeadVersion != "" || strings.Contains(profile.Rancher.Channel, "prime-optimus") {
 			extraEnvIndex = 2
 		}
-		extraFlags = []string{
+		extraFlags = append(extraFlags,
 			"--set", fmt.Sprintf("extraEnv[%d].name=CATTLE_SKIP_HOSTED_CLUSTER_CHART_INSTALLATION", extraEnvIndex),
 			"--set-string", fmt.Sprintf("extraEnv[%d].value=true", extraEnvIndex),
-		}
+		)
+	}
+	if registry := profile.PrivateRegistry.systemDefaultRegistry(); registry != "" {
+		extraFlags = append(extraFlags, "--set", "systemDefaultRegistry="+registry)
 	}
+	extraFlags = append(extraFlags, profile.Rancher.helmValuesFlags()...)
 
-	err := rancher.DeployRancherManager(rancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, "none", proxyEnabled, extraFlags)
-	Expect(err).To(Not(HaveOccurred()))
+	if profile.Airgap.Enabled {
+		deployRancherManagerAirgap(profile, extraFlags)
+	} else {
+		err := rancher.DeployRancherManager(profile.Rancher.Hostname, profile.Rancher.Channel, profile.Rancher.Version, profile.Rancher.HeadVersion, "none", proxyEnabled, extraFlags)
+		Expect(err).To(Not(HaveOccurred()))
+	}
 
 	// Wait for all pods to be started
 	checkList := [][]string{
@@ -191,6 +209,39 @@ func InstallRancherManager(k *kubectl.Kubectl, rancherHostname, rancherChannel,
 	Eventually(func() error {
 		return rancher.CheckPod(k, checkList)
 	}, tools.SetTimeout(4*time.Minute), 30*time.Second).Should(BeNil(), "Rancher pod is not running")
+
+	if profile.NightlyOperatorCharts {
+		InstallNightlyOperatorCharts()
+	}
+	if profile.OperatorOverride.Enabled() {
+		InstallOperatorOverride(profile.OperatorOverride)
+	}
+}
+
+/*
+*
+Install the nightly rancher-<provider>-operator charts, built today
+  - @returns Nothing, the function will fail through Ginkgo in case of issue
+*/
+func InstallNightlyOperatorCharts() {
+	By(fmt.Sprintf("Install nightly rancher-%s-operator via Helm", Provider), func() {
+		// Get the current date to use as the build date
+		buildDate := time.Now().Format("20060102")
+
+		RunHelmCmdWithRetry("upgrade", "--install", "rancher-"+Provider+"-operator-crds",
+			"oci://ghcr.io/rancher/rancher-"+Provider+"-operator-crd-chart/rancher-"+Provider+"-operator-crd",
+			"--version", buildDate)
+		RunHelmCmdWithRetry("upgrade", "--install", "rancher-"+Provider+"-operator",
+			"oci://ghcr.io/rancher/rancher-"+Provider+"-operator-chart/rancher-"+Provider+"-operator",
+			"--version", buildDate, "--namespace", "cattle-system")
+	})
+}
+
+// writeRootFile writes content to path as root, creating the parent directory if needed
+func writeRootFile(path, content string) {
+	out, err := exec.Command("sh", "-c", fmt.Sprintf("sudo mkdir -p %s && echo '%s' | sudo tee %s", filepath.Dir(path), content, path)).CombinedOutput()
+	GinkgoWriter.Println(string(out))
+	Expect(err).To(Not(HaveOccurred()))
 }
 
 /*
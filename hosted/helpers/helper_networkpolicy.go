@@ -0,0 +1,89 @@
+package helpers
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/workload"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	v1 "github.com/rancher/shepherd/clients/rancher/v1"
+	namegen "github.com/rancher/shepherd/pkg/namegenerator"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// NetworkPolicyChecks verifies that the policy engine of the downstream cluster enforces the NetworkPolicies rather than just accepting them:
+// it deploys the app of the workload package and probes it from client pods while a deny-all policy, then an allow policy, are applied.
+// When enforced is false, e.g. for a cluster created without network policy, the app is expected to stay reachable whatever the policies.
+func NetworkPolicyChecks(cluster *management.Cluster, client *rancher.Client, opts workload.Options, enforced bool) {
+	if opts.Namespace == "" {
+		opts.Namespace = namegen.AppendRandomString(workload.Name + "-policy")
+	}
+	if opts.Token == "" {
+		opts.Token = namegen.RandStringLower(16)
+	}
+	opts.EphemeralStorage = true
+	opts = opts.WithDefaults()
+	appID := opts.Namespace + "/" + workload.Name
+
+	steveClient, err := client.Steve.ProxyDownstream(cluster.ID)
+	Expect(err).To(BeNil())
+
+	var namespace *v1.SteveAPIObject
+	ginkgo.By("creating the namespace of the network policy checks", func() {
+		namespace, err = steveClient.SteveType("namespace").Create(workload.Namespace(opts))
+		Expect(err).To(BeNil())
+	})
+	ginkgo.DeferCleanup(func() {
+		Expect(steveClient.SteveType("namespace").Delete(namespace)).To(Succeed())
+	})
+
+	var urls []string
+	ginkgo.By("deploying the app and its ClusterIP service", func() {
+		_, err = steveClient.SteveType("apps.deployment").Create(workload.Deployment(opts))
+		Expect(err).To(BeNil())
+		_, err = steveClient.SteveType("service").Create(workload.Service(opts, corev1.ServiceTypeClusterIP))
+		Expect(err).To(BeNil())
+
+		Eventually(func() bool {
+			deployment := &appsv1.Deployment{}
+			Expect(getSteveObject(steveClient, "apps.deployment", appID, deployment)).To(Succeed())
+			return workload.DeploymentAvailable(deployment)
+		}, tools.SetTimeout(10*time.Minute), 10*time.Second).Should(BeTrue())
+
+		// the probes use IPs so that a DNS failure never passes for denied traffic
+		svc := &corev1.Service{}
+		Expect(getSteveObject(steveClient, "service", appID, svc)).To(Succeed())
+		pods, err := steveClient.SteveType("pod").List(url.Values{"labelSelector": {workload.AppLabel + "=" + workload.Name}})
+		Expect(err).To(BeNil())
+		for _, object := range pods.Data {
+			pod := &corev1.Pod{}
+			Expect(v1.ConvertToK8sType(object.JSONResp, pod)).To(Succeed())
+			if pod.Namespace == opts.Namespace && pod.Status.Phase == corev1.PodRunning && pod.Status.PodIP != "" {
+				urls = []string{"http://" + pod.Status.PodIP, "http://" + svc.Spec.ClusterIP}
+			}
+		}
+		Expect(urls).ToNot(BeEmpty(), "the app has no running pod")
+	})
+
+	ginkgo.By("probing the app without any policy", func() {
+		runProbeJob(steveClient, workload.PolicyProbeJob(opts, workload.PolicyProbe{Name: "open", Reachable: true}, urls...))
+	})
+
+	ginkgo.By("denying all the ingress traffic to the app", func() {
+		_, err = steveClient.SteveType("networking.k8s.io.networkpolicy").Create(workload.DenyAllIngress(opts))
+		Expect(err).To(BeNil())
+		runProbeJob(steveClient, workload.PolicyProbeJob(opts, workload.PolicyProbe{Name: "denied", Reachable: !enforced}, urls...))
+	})
+
+	ginkgo.By("allowing the ingress traffic of the allowed probes only", func() {
+		_, err = steveClient.SteveType("networking.k8s.io.networkpolicy").Create(workload.AllowProbeIngress(opts))
+		Expect(err).To(BeNil())
+		runProbeJob(steveClient, workload.PolicyProbeJob(opts, workload.PolicyProbe{Name: "allowed", Allowed: true, Reachable: true}, urls...))
+		runProbeJob(steveClient, workload.PolicyProbeJob(opts, workload.PolicyProbe{Name: "still-denied", Reachable: !enforced}, urls...))
+	})
+}
@@ -0,0 +1,75 @@
+package helpers
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/charts"
+)
+
+// ExpectNoOperatorOverride fails the suites which check the operator charts shipped with Rancher when the environment profile
+// overrides the operator: reinstalling or upgrading Rancher would replace the overridden operator with them
+func ExpectNoOperatorOverride() {
+	Expect(GetEnvironmentProfile().OperatorOverride.Enabled()).To(BeFalse(), "the operator override of the environment profile is not supported by this suite")
+}
+
+// InstallOperatorOverride installs the locally built operator of Provider in place of the one shipped with Rancher: the image
+// tarball is imported into the containerd of k3s, Rancher is pinned so that it does not reconcile the operator charts back,
+// then the local charts are installed over the releases of Rancher
+func InstallOperatorOverride(override OperatorOverrideProfile) {
+	for _, path := range []string{override.Chart, override.CRDChart, override.ImageTarball} {
+		if path != "" {
+			_, err := os.Stat(path)
+			Expect(err).To(BeNil(), "the operator override %s does not exist", path)
+		}
+	}
+
+	if override.ImageTarball != "" {
+		ginkgo.By(fmt.Sprintf("Import the %s operator image into k3s", Provider), func() {
+			ImportImageTarball(override.ImageTarball)
+		})
+	}
+
+	ginkgo.By("Pin the operator charts installed by Rancher", func() {
+		PinOperatorCharts()
+	})
+
+	ginkgo.By(fmt.Sprintf("Install the local rancher-%s-operator charts via Helm", Provider), func() {
+		if override.CRDChart != "" {
+			RunHelmCmdWithRetry("upgrade", "--install", charts.CRDChart(Provider), override.CRDChart, "--namespace", CattleSystemNS)
+		}
+		args := []string{"upgrade", "--install", charts.OperatorChart(Provider), override.Chart, "--namespace", CattleSystemNS}
+		if override.Image != "" {
+			repository, tag := splitImage(override.Image)
+			args = append(args, "--set", fmt.Sprintf("%sOperator.image.repository=%s", Provider, repository))
+			if tag != "" {
+				args = append(args, "--set", fmt.Sprintf("%sOperator.image.tag=%s", Provider, tag))
+			}
+		}
+		RunHelmCmdWithRetry(args...)
+		WaitForOperatorReady()
+	})
+	ginkgo.GinkgoLogr.Info(fmt.Sprintf("Installed the %s operator from %s", Provider, override.Chart))
+}
+
+// ImportImageTarball imports the images saved in tarball into the containerd of k3s, so that the pods run them without pulling them
+func ImportImageTarball(tarball string) {
+	out, err := exec.Command("sudo", "k3s", "ctr", "--namespace", "k8s.io", "images", "import", tarball).CombinedOutput()
+	ginkgo.GinkgoWriter.Println(string(out))
+	Expect(err).To(BeNil(), "Failed to import %s into k3s", tarball)
+}
+
+// splitImage splits an image reference into its repository and tag, the tag is empty when the reference has none or is a digest
+func splitImage(image string) (repository, tag string) {
+	if strings.Contains(image, "@") {
+		return image, ""
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[:i], image[i+1:]
+	}
+	return image, ""
+}
@@ -0,0 +1,35 @@
+/*
+Copyright © 2022 - 2025 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("splitImage", func() {
+	DescribeTable("splits an image reference into its repository and tag",
+		func(image, repository, tag string) {
+			gotRepository, gotTag := splitImage(image)
+			Expect(gotRepository).To(Equal(repository))
+			Expect(gotTag).To(Equal(tag))
+		},
+		Entry("tagged image", "rancher/aks-operator:v1.10.1", "rancher/aks-operator", "v1.10.1"),
+		Entry("registry with port", "registry.local:5000/rancher/aks-operator:dev", "registry.local:5000/rancher/aks-operator", "dev"),
+		Entry("registry with port and no tag", "registry.local:5000/rancher/aks-operator", "registry.local:5000/rancher/aks-operator", ""),
+		Entry("digest", "rancher/aks-operator@sha256:0123abcd", "rancher/aks-operator@sha256:0123abcd", ""),
+		Entry("no tag", "rancher/aks-operator", "rancher/aks-operator", ""),
+	)
+})
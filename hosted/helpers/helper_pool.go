@@ -0,0 +1,256 @@
+package helpers
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/pool"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"github.com/rancher/shepherd/pkg/config"
+	"github.com/rancher/shepherd/pkg/session"
+)
+
+// ClusterPoolLabel is set on the downstream clusters of a cluster pool, its value is the shape of the cluster;
+// clusters kept by a run without DOWNSTREAM_CLUSTER_CLEANUP are adopted by the next run through it
+const ClusterPoolLabel = "hosted-providers-e2e.cattle.io/pool"
+
+// ClusterProvisioner creates, resets and deletes the clusters of a shape for a ClusterPool
+type ClusterProvisioner struct {
+	// Shape names the kind of cluster created, e.g. "default"
+	Shape string
+	// Create creates a cluster, the pool waits for it to be ready
+	Create func(client *rancher.Client, cloudCredID, clusterName string) (*management.Cluster, error)
+	// Baseline returns the state of a cluster which is restored by Reset
+	Baseline func(cluster *management.Cluster) pool.Baseline
+	// Reset restores the baseline of a cluster; it returns an error when the cluster cannot be restored
+	// (e.g. its k8s version has been upgraded), the cluster is then retired
+	Reset  func(cluster *management.Cluster, client *rancher.Client, baseline pool.Baseline) (*management.Cluster, error)
+	Delete func(cluster *management.Cluster, client *rancher.Client) error
+}
+
+// ClusterPool leases ready clusters to the specs which only need a cluster of a given shape, instead of provisioning one per spec.
+// Its ledger is created once by CreateClusterPool and shared by all the Ginkgo parallel processes through OpenClusterPool.
+type ClusterPool struct {
+	ctx         *RancherContext
+	ledger      *pool.Ledger
+	provisioner ClusterProvisioner
+	size        int
+}
+
+// Lease is a cluster of a ClusterPool leased to a spec, it is reset and returned to the pool once the spec is done
+type Lease struct {
+	pool         *ClusterPool
+	fixture      *Fixture
+	entry        pool.Entry
+	retireReason string
+}
+
+// clusterPoolSize returns the maximum number of clusters of a shape, set by CLUSTER_POOL_SIZE;
+// it defaults to the number of Ginkgo parallel processes so that a spec never waits for a lease
+func clusterPoolSize() int {
+	if size, err := strconv.Atoi(os.Getenv("CLUSTER_POOL_SIZE")); err == nil && size > 0 {
+		return size
+	}
+	suiteConfig, _ := ginkgo.GinkgoConfiguration()
+	return suiteConfig.ParallelTotal
+}
+
+// CreateClusterPool creates the ledger of a cluster pool and adopts the clusters of the shape kept by a previous run;
+// it must be called from the first function of SynchronizedBeforeSuite, after CommonSynchronizedBeforeSuite, and its result passed to OpenClusterPool
+func CreateClusterPool(provisioner ClusterProvisioner) []byte {
+	ledger, err := pool.NewLedger()
+	Expect(err).To(BeNil())
+
+	if !clusterCleanup {
+		// the context of the specs is not created yet, the cloud credential is not needed to list the clusters
+		rancherConfig := new(rancher.Config)
+		config.LoadConfig(rancher.ConfigurationFileKey, rancherConfig)
+		client, err := rancher.NewClient(rancherConfig.AdminToken, session.NewSession())
+		Expect(err).To(BeNil())
+
+		clusters, err := client.Management.Cluster.List(nil)
+		Expect(err).To(BeNil())
+		for i := range clusters.Data {
+			cluster := &clusters.Data[i]
+			if cluster.Labels[ClusterPoolLabel] != provisioner.Shape || cluster.State != "active" || !strings.HasPrefix(cluster.Name, ClusterNamePrefix) {
+				continue
+			}
+			ginkgo.GinkgoLogr.Info(fmt.Sprintf("Adopting pool cluster %s", cluster.Name))
+			Expect(ledger.Adopt(pool.Entry{
+				ClusterName: cluster.Name,
+				ClusterID:   cluster.ID,
+				Shape:       provisioner.Shape,
+				Baseline:    poolBaseline(provisioner, cluster),
+			})).To(Succeed())
+		}
+	}
+	return []byte(ledger.Dir())
+}
+
+// OpenClusterPool opens the cluster pool created by CreateClusterPool, it must be called by every process from the second function of SynchronizedBeforeSuite
+func OpenClusterPool(data []byte, ctx *RancherContext, provisioner ClusterProvisioner) *ClusterPool {
+	ledger, err := pool.OpenLedger(string(data))
+	Expect(err).To(BeNil())
+	return &ClusterPool{
+		ctx:         ctx,
+		ledger:      ledger,
+		provisioner: provisioner,
+		size:        clusterPoolSize(),
+	}
+}
+
+// Lease sets the cluster of the fixture to a ready cluster of the pool, provisioning it if the pool is not full yet;
+// it waits for another spec to return a cluster when all of them are leased. The cluster is returned to the pool once the spec is done.
+// Only the spec provisioning a cluster reserves its cost, which is counted until the cluster is retired or the pool is drained.
+func (p *ClusterPool) Lease(f *Fixture) *Lease {
+	holder := fmt.Sprintf("process-%d", ginkgo.GinkgoParallelProcess())
+	var (
+		entry     pool.Entry
+		provision bool
+	)
+	Eventually(func() error {
+		var err error
+		entry, provision, err = p.ledger.Acquire(p.provisioner.Shape, p.size, holder, f.ClusterName)
+		return err
+	}, Timeout, 30*time.Second).Should(Succeed())
+
+	lease := &Lease{pool: p, fixture: f, entry: entry}
+	f.ClusterName = entry.ClusterName
+	f.AddCleanup(lease.release)
+
+	var err error
+	if provision {
+		ginkgo.By(fmt.Sprintf("provisioning the pool cluster %s", entry.ClusterName))
+		GuardClusterCost(entry.ClusterName)
+		f.Cluster, err = p.provisioner.Create(f.Client, f.Ctx.CloudCredID, entry.ClusterName)
+		Expect(err).To(BeNil())
+		f.Cluster, err = setClusterPoolLabel(f.Cluster, f.Client, p.provisioner.Shape)
+		Expect(err).To(BeNil())
+		f.Cluster, err = WaitUntilClusterIsReady(f.Cluster, f.Client)
+		Expect(err).To(BeNil())
+
+		lease.entry.ClusterID = f.Cluster.ID
+		lease.entry.Baseline = poolBaseline(p.provisioner, f.Cluster)
+		Expect(p.ledger.Register(entry.ClusterName, lease.entry.ClusterID, lease.entry.Baseline)).To(Succeed())
+	} else {
+		ginkgo.By(fmt.Sprintf("leasing the pool cluster %s", entry.ClusterName))
+		f.Cluster, err = f.Client.Management.Cluster.ByID(entry.ClusterID)
+		Expect(err).To(BeNil())
+		f.Cluster, err = WaitUntilClusterIsReady(f.Cluster, f.Client)
+		Expect(err).To(BeNil())
+	}
+	f.K8sVersion = lease.entry.Baseline.KubernetesVersion
+	return lease
+}
+
+// Retire marks the cluster as mutated irreversibly by the spec (e.g. a setting the provisioner does not reset),
+// it is deleted instead of being returned to the pool
+func (l *Lease) Retire(reason string) {
+	l.retireReason = reason
+}
+
+func (l *Lease) release() {
+	f := l.fixture
+	reason := l.retireReason
+	if reason == "" && l.entry.ClusterID == "" {
+		reason = "the cluster has not been provisioned"
+	}
+	if reason == "" {
+		if err := InterceptGomegaFailure(l.reset); err != nil {
+			reason = fmt.Sprintf("the cluster could not be reset: %v", err)
+		}
+	}
+	if reason == "" {
+		Expect(l.pool.ledger.Release(l.entry.ClusterName)).To(Succeed())
+		return
+	}
+
+	ginkgo.GinkgoLogr.Info(fmt.Sprintf("Retiring pool cluster %s: %s", l.entry.ClusterName, reason))
+	Expect(l.pool.ledger.Retire(l.entry.ClusterName, reason)).To(Succeed())
+	if f.Cluster == nil || f.Cluster.ID == "" {
+		costReportEntry(CompleteClusterCost(l.entry.ClusterName, nil))
+		return
+	}
+	if !f.Ctx.ClusterCleanup {
+		fmt.Println("Skipping downstream cluster deletion: ", f.ClusterName)
+		// the cluster is kept, but it must not be adopted by the next run
+		cluster, err := f.Client.Management.Cluster.ByID(f.Cluster.ID)
+		Expect(err).To(BeNil())
+		_, err = setClusterPoolLabel(cluster, f.Client, "")
+		Expect(err).To(BeNil())
+	} else {
+		Expect(l.pool.provisioner.Delete(f.Cluster, f.Client)).To(Succeed())
+	}
+	costReportEntry(CompleteClusterCost(l.entry.ClusterName, f.Cluster))
+}
+
+// reset restores the baseline of the leased cluster and waits for it to be ready
+func (l *Lease) reset() {
+	f := l.fixture
+	cluster, err := f.Client.Management.Cluster.ByID(l.entry.ClusterID)
+	Expect(err).To(BeNil())
+	cluster, err = WaitUntilClusterIsReady(cluster, f.Client)
+	Expect(err).To(BeNil())
+	cluster, err = l.pool.provisioner.Reset(cluster, f.Client, l.entry.Baseline)
+	Expect(err).To(BeNil())
+	if spec, _ := clusterCloudCredential(cluster); l.entry.Baseline.CloudCredential != "" && spec != l.entry.Baseline.CloudCredential {
+		ginkgo.By(fmt.Sprintf("restoring the cloud credential %s of the pool cluster %s", l.entry.Baseline.CloudCredential, cluster.Name), func() {
+			cluster = SwitchCloudCredential(cluster, f.Client, l.entry.Baseline.CloudCredential)
+		})
+	}
+	f.Cluster, err = WaitUntilClusterIsReady(cluster, f.Client)
+	Expect(err).To(BeNil())
+}
+
+// Drain deletes the clusters of the pool and removes its ledger, it must be called from the second function of SynchronizedAfterSuite;
+// the clusters are kept, and adopted by the next run, when DOWNSTREAM_CLUSTER_CLEANUP is not set
+func (p *ClusterPool) Drain() {
+	entries, err := p.ledger.Entries()
+	Expect(err).To(BeNil())
+	for _, entry := range entries {
+		// retired clusters have been deleted by the spec leasing them
+		if entry.ClusterID == "" || entry.State == pool.Retired {
+			continue
+		}
+		cluster, err := p.ctx.RancherAdminClient.Management.Cluster.ByID(entry.ClusterID)
+		Expect(err).To(BeNil())
+		if !p.ctx.ClusterCleanup {
+			fmt.Println("Skipping downstream cluster deletion: ", entry.ClusterName)
+		} else {
+			Expect(p.provisioner.Delete(cluster, p.ctx.RancherAdminClient)).To(Succeed())
+		}
+		// the clusters adopted from a previous run have not been reserved, they are ignored
+		CompleteClusterCost(entry.ClusterName, cluster)
+	}
+	Expect(p.ledger.Remove()).To(Succeed())
+}
+
+// poolBaseline returns the baseline of the cluster defined by the provisioner, with the cloud credential of the cluster
+func poolBaseline(provisioner ClusterProvisioner, cluster *management.Cluster) pool.Baseline {
+	baseline := provisioner.Baseline(cluster)
+	baseline.CloudCredential, _ = clusterCloudCredential(cluster)
+	return baseline
+}
+
+// setClusterPoolLabel sets ClusterPoolLabel to shape, or removes it if shape is empty
+func setClusterPoolLabel(cluster *management.Cluster, client *rancher.Client, shape string) (*management.Cluster, error) {
+	labels := maps.Clone(cluster.Labels)
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	if shape == "" {
+		delete(labels, ClusterPoolLabel)
+	} else {
+		labels[ClusterPoolLabel] = shape
+	}
+	upgradedCluster := cluster
+	upgradedCluster.Labels = labels
+	return client.Management.Cluster.Update(cluster, &upgradedCluster)
+}
@@ -0,0 +1,104 @@
+package helpers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/privateendpoint"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"github.com/rancher/shepherd/extensions/tokenregistration"
+)
+
+// CommandInvoker runs a shell command against a private cluster with cluster-admin permissions and returns its output,
+// through the mechanism of the provider (e.g. `az aks command invoke`) since the API endpoint cannot be reached from the test runner
+type CommandInvoker func(command string) (string, error)
+
+// agentDiagnosticsCommand shows the state of the cluster agent, it is run in the cluster when the registration stalls
+const agentDiagnosticsCommand = "kubectl -n cattle-system get pods -o wide; kubectl -n cattle-system logs deploy/cattle-cluster-agent --tail=30"
+
+// SkipUnsupportedPrivateClusters skips the current spec on the Rancher versions which cannot register the private clusters they provision (older than v2.12)
+func SkipUnsupportedPrivateClusters(client *rancher.Client) {
+	serverVersion, err := GetRancherServerVersion(client)
+	Expect(err).NotTo(HaveOccurred())
+	isSupported, err := IsRancherVersionGreaterThanOrEqualTo(serverVersion, "2.12.0")
+	Expect(err).NotTo(HaveOccurred())
+	if !isSupported {
+		ginkgo.Skip("Private cluster tests are not supported on Rancher versions older than v2.12")
+	}
+}
+
+// RegisterPrivateCluster waits for Rancher to give up on reaching the API endpoint of a private cluster it provisioned, runs the registration
+// command in the cluster with invoke, and waits for the cluster to be ready. When the registration stalls, the failure tells why.
+func RegisterPrivateCluster(cluster *management.Cluster, client *rancher.Client, invoke CommandInvoker) *management.Cluster {
+	ginkgo.By("waiting for the cluster to expect its registration", func() {
+		Eventually(func() bool {
+			current, err := client.Management.Cluster.ByID(cluster.ID)
+			Expect(err).To(BeNil())
+			cluster = current
+			return privateendpoint.RegistrationPending(cluster.TransitioningMessage)
+		}, tools.SetTimeout(20*time.Minute), 10*time.Second).Should(BeTrue(), func() string { return registrationReport(cluster, client, nil) })
+	})
+
+	ginkgo.By("running the registration command in the cluster", func() {
+		registrationToken, err := tokenregistration.GetRegistrationToken(client, cluster.ID)
+		Expect(err).To(BeNil())
+		out, err := invoke(registrationToken.InsecureCommand)
+		Expect(err).To(BeNil(), out)
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("Registration command output: %s", out))
+	})
+
+	ginkgo.By("waiting for the cluster agent to connect", func() {
+		ready, err := WaitUntilClusterIsReady(cluster, client)
+		Expect(err).To(BeNil(), func() string { return registrationReport(cluster, client, invoke) })
+		cluster = ready
+	})
+	return cluster
+}
+
+// registrationReport explains why the registration of cluster stalls; with invoke, the state of the cluster agent is shown as well
+func registrationReport(cluster *management.Cluster, client *rancher.Client, invoke CommandInvoker) string {
+	if current, err := client.Management.Cluster.ByID(cluster.ID); err == nil {
+		cluster = current
+	}
+	var conditions []privateendpoint.Condition
+	for _, condition := range cluster.Conditions {
+		conditions = append(conditions, privateendpoint.Condition{Type: condition.Type, Status: condition.Status, Reason: condition.Reason, Message: condition.Message})
+	}
+	report := fmt.Sprintf("the registration of private cluster %s stalls\n%s", cluster.Name, privateendpoint.Diagnose(cluster.State, cluster.TransitioningMessage, conditions))
+	if invoke != nil {
+		out, err := invoke(agentDiagnosticsCommand)
+		if err != nil {
+			out = fmt.Sprintf("%s\n%v", out, err)
+		}
+		report += "\ncluster agent:\n" + out
+	}
+	return report
+}
+
+// PrivateEndpointChecks verifies that Rancher reaches the private cluster through the tunnel of its agent only: each API endpoint
+// of the cluster (as reported by the provider) is closed to the test runner, which runs Rancher, while Rancher still proxies the API of the cluster.
+func PrivateEndpointChecks(cluster *management.Cluster, client *rancher.Client, endpoints ...string) {
+	Expect(endpoints).ToNot(BeEmpty(), "the provider reports no API endpoint for cluster %s", cluster.Name)
+
+	ginkgo.By("checking the API endpoints are closed to Rancher", func() {
+		for _, endpoint := range endpoints {
+			reachability := privateendpoint.Probe(endpoint, 10*time.Second)
+			ginkgo.GinkgoLogr.Info(fmt.Sprintf("API endpoint %s", reachability))
+			Expect(reachability.Reachable).To(BeFalse(), "the API endpoint of private cluster %s is open: %s", cluster.Name, reachability)
+		}
+	})
+
+	ginkgo.By("checking Rancher reaches the cluster through the agent tunnel", func() {
+		steveClient, err := client.Steve.ProxyDownstream(cluster.ID)
+		Expect(err).To(BeNil())
+		Eventually(func() int {
+			nodes, err := steveClient.SteveType("node").List(nil)
+			Expect(err).To(BeNil())
+			return len(nodes.Data)
+		}, tools.SetTimeout(5*time.Minute), 10*time.Second).ShouldNot(BeZero())
+	})
+}
@@ -0,0 +1,263 @@
+package helpers
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strings"
+
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// defaultProxyHost is the squid proxy started by InstallK3S, as seen from the k3s node
+	defaultProxyHost = "172.17.0.1:3128"
+	defaultNoProxy   = "127.0.0.0/8,10.0.0.0/8,cattle-system.svc,172.16.0.0/12,192.168.0.0/16,.svc,.cluster.local"
+
+	defaultAirgapArtifactsDir = "/tmp/hp-airgap"
+	defaultAirgapRegistryPort = 5000
+)
+
+// EnvironmentProfile describes the local k3s/Rancher environment under test;
+// it is loaded from the file defined by ENVIRONMENT_PROFILE, see environment-profile.example.yaml
+type EnvironmentProfile struct {
+	K3sVersion         string `json:"k3sVersion"`
+	CertManagerVersion string `json:"certManagerVersion,omitempty"`
+	// NightlyOperatorCharts installs the nightly rancher-<provider>-operator charts instead of the ones shipped with Rancher
+	NightlyOperatorCharts bool `json:"nightlyOperatorCharts,omitempty"`
+	// OperatorOverride installs a locally built operator instead of the one shipped with Rancher
+	OperatorOverride OperatorOverrideProfile `json:"operatorOverride,omitempty"`
+	Rancher          RancherProfile          `json:"rancher"`
+	Proxy            ProxyProfile            `json:"proxy,omitempty"`
+	PrivateRegistry  PrivateRegistryProfile  `json:"privateRegistry,omitempty"`
+	Airgap           AirgapProfile           `json:"airgap,omitempty"`
+}
+
+type RancherProfile struct {
+	Hostname string `json:"hostname,omitempty"`
+	// Channel [eg. latest, prime]
+	Channel string `json:"channel"`
+	// Version [eg. 2.9.3-rc2, devel, latest]
+	Version string `json:"version"`
+	// HeadVersion [eg. 2.9-head]
+	HeadVersion string `json:"headVersion,omitempty"`
+	// HelmValues are passed as --set flags to the Rancher chart
+	HelmValues map[string]string `json:"helmValues,omitempty"`
+}
+
+type ProxyProfile struct {
+	Enabled bool   `json:"enabled,omitempty"`
+	Host    string `json:"host,omitempty"`
+	NoProxy string `json:"noProxy,omitempty"`
+}
+
+type PrivateRegistryProfile struct {
+	// URL of the registry mirroring all the public registries [eg. 172.17.0.1:5000]
+	URL      string `json:"url,omitempty"`
+	Insecure bool   `json:"insecure,omitempty"`
+}
+
+// OperatorOverrideProfile replaces the rancher-<provider>-operator charts with local ones, to run the suites against an unmerged
+// change of the operator; Rancher is pinned so that it does not reconcile the charts back
+type OperatorOverrideProfile struct {
+	// Chart is the local directory or tgz of the operator chart
+	Chart string `json:"chart,omitempty"`
+	// CRDChart is the local directory or tgz of the CRD chart, the CRD release is left as is when it is empty
+	CRDChart string `json:"crdChart,omitempty"`
+	// ImageTarball is the operator image saved by `docker save`, imported into the containerd of k3s
+	ImageTarball string `json:"imageTarball,omitempty"`
+	// Image overrides the operator image of the chart [eg. rancher/aks-operator:dev], it must not be a latest tag
+	// so that the imported image is not pulled
+	Image string `json:"image,omitempty"`
+}
+
+// Enabled reports whether the operator charts are overridden
+func (o OperatorOverrideProfile) Enabled() bool {
+	return o.Chart != ""
+}
+
+// AirgapProfile installs k3s, cert-manager and Rancher without reaching the public registries and chart repositories;
+// the artifacts are prepared by PrepareAirgap and the images are mirrored to the private registry
+type AirgapProfile struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// ArtifactsDir stores the k3s artifacts, the charts and the image lists
+	ArtifactsDir string `json:"artifactsDir,omitempty"`
+	// RegistryPort of the local registry:2 container, used when no private registry URL is given
+	RegistryPort int `json:"registryPort,omitempty"`
+	// ImagesFile lists the Rancher images to mirror, defaults to the rancher-images.txt of the Rancher release
+	ImagesFile string `json:"imagesFile,omitempty"`
+	// ImageFilters are regular expressions limiting the Rancher images to mirror
+	ImageFilters []string `json:"imageFilters,omitempty"`
+	// Images are mirrored in addition to the Rancher and cert-manager ones
+	Images []string `json:"images,omitempty"`
+}
+
+// LoadEnvironmentProfile reads an EnvironmentProfile file; unset values are defaulted
+func LoadEnvironmentProfile(profileFile string) (EnvironmentProfile, error) {
+	var profile EnvironmentProfile
+	data, err := os.ReadFile(profileFile)
+	if err != nil {
+		return profile, err
+	}
+	if err = yaml.UnmarshalStrict(data, &profile); err != nil {
+		return profile, fmt.Errorf("invalid environment profile %s: %w", profileFile, err)
+	}
+	profile.setDefaults()
+	return profile, nil
+}
+
+// EnvironmentProfileFromEnv builds an EnvironmentProfile from the historical environment variables
+// INSTALL_K3S_VERSION, RANCHER_VERSION, RANCHER_HOSTNAME, RANCHER_BEHIND_PROXY, PROXY_HOST and NIGHTLY_CHART, and the operator override
+// from OPERATOR_CHART, OPERATOR_CRD_CHART, OPERATOR_IMAGE_TARBALL and OPERATOR_IMAGE
+func EnvironmentProfileFromEnv() EnvironmentProfile {
+	profile := EnvironmentProfile{
+		K3sVersion:            os.Getenv("INSTALL_K3S_VERSION"),
+		NightlyOperatorCharts: os.Getenv("NIGHTLY_CHART") == "enabled",
+		OperatorOverride: OperatorOverrideProfile{
+			Chart:        os.Getenv("OPERATOR_CHART"),
+			CRDChart:     os.Getenv("OPERATOR_CRD_CHART"),
+			ImageTarball: os.Getenv("OPERATOR_IMAGE_TARBALL"),
+			Image:        os.Getenv("OPERATOR_IMAGE"),
+		},
+		Rancher: RancherProfile{
+			Hostname: RancherHostname,
+		},
+		Proxy: ProxyProfile{
+			Enabled: os.Getenv("RANCHER_BEHIND_PROXY") == "enabled",
+			Host:    os.Getenv("PROXY_HOST"),
+		},
+	}
+	if RancherFullVersion != "" {
+		profile = profile.WithRancherVersion(RancherFullVersion)
+	}
+	profile.setDefaults()
+	return profile
+}
+
+// GetEnvironmentProfile returns the profile defined by ENVIRONMENT_PROFILE if set, otherwise the one described by the environment variables
+func GetEnvironmentProfile() EnvironmentProfile {
+	profileFile := os.Getenv("ENVIRONMENT_PROFILE")
+	if profileFile == "" {
+		return EnvironmentProfileFromEnv()
+	}
+	profile, err := LoadEnvironmentProfile(profileFile)
+	Expect(err).To(BeNil())
+	return profile
+}
+
+// StockEnvironmentProfile returns the k3s, cert-manager and Rancher versions of GetEnvironmentProfile on a stock environment: without
+// proxy, nightly operator charts, operator override, private registry nor airgap. The upgrade and backup/restore suites reinstall
+// Rancher and check the operator charts it ships, so they must not pick up the rest of the environment.
+func StockEnvironmentProfile() EnvironmentProfile {
+	profile := GetEnvironmentProfile()
+	stock := EnvironmentProfile{
+		K3sVersion:            profile.K3sVersion,
+		CertManagerVersion:    profile.CertManagerVersion,
+		NightlyOperatorCharts: false,
+		OperatorOverride:      OperatorOverrideProfile{},
+		Rancher:               profile.Rancher,
+		Proxy:                 ProxyProfile{Enabled: false},
+	}
+	stock.setDefaults()
+	return stock
+}
+
+func (p *EnvironmentProfile) setDefaults() {
+	if p.Rancher.Hostname == "" {
+		p.Rancher.Hostname = RancherHostname
+	}
+	if p.Proxy.Host == "" {
+		p.Proxy.Host = defaultProxyHost
+	}
+	if p.Proxy.NoProxy == "" {
+		p.Proxy.NoProxy = defaultNoProxy
+	}
+	if p.Airgap.Enabled {
+		if p.Airgap.ArtifactsDir == "" {
+			p.Airgap.ArtifactsDir = defaultAirgapArtifactsDir
+		}
+		if p.Airgap.RegistryPort == 0 {
+			p.Airgap.RegistryPort = defaultAirgapRegistryPort
+		}
+	}
+}
+
+// mirrorRegistry returns the registry the images are mirrored to and pulled from by k3s: the private registry, or the local registry
+// of the airgap mode when none is given
+func (p EnvironmentProfile) mirrorRegistry() PrivateRegistryProfile {
+	if p.PrivateRegistry.URL == "" && p.Airgap.Enabled {
+		// The local registry is only reachable by k3s containerd on the same host
+		return PrivateRegistryProfile{URL: fmt.Sprintf("localhost:%d", p.Airgap.RegistryPort), Insecure: true}
+	}
+	return p.PrivateRegistry
+}
+
+// WithRancherVersion returns a copy of the profile using a Rancher version in the RANCHER_VERSION format [eg. latest/2.9.3, latest/devel/2.9]
+func (p EnvironmentProfile) WithRancherVersion(rancherFullVersion string) EnvironmentProfile {
+	p.Rancher.Channel, p.Rancher.Version, p.Rancher.HeadVersion = GetRancherVersions(rancherFullVersion)
+	return p
+}
+
+// WithK3sVersion returns a copy of the profile using the given k3s version
+func (p EnvironmentProfile) WithK3sVersion(k3sVersion string) EnvironmentProfile {
+	p.K3sVersion = k3sVersion
+	return p
+}
+
+// RancherFullVersion returns the Rancher version in the RANCHER_VERSION format
+func (p EnvironmentProfile) RancherFullVersion() string {
+	return strings.Join(append([]string{p.Rancher.Channel, p.Rancher.Version}, strings.Fields(p.Rancher.HeadVersion)...), "/")
+}
+
+// helmValuesFlags converts the Helm values overrides to --set flags, sorted for reproducible installs
+func (r RancherProfile) helmValuesFlags() []string {
+	keys := make([]string, 0, len(r.HelmValues))
+	for key := range r.HelmValues {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var flags []string
+	for _, key := range keys {
+		flags = append(flags, "--set", key+"="+r.HelmValues[key])
+	}
+	return flags
+}
+
+// systemDefaultRegistry returns the registry Rancher prefixes the system images with, they are also pulled by the downstream clusters;
+// it is empty when the registry is on the loopback of the Rancher host
+func (r PrivateRegistryProfile) systemDefaultRegistry() string {
+	host := r.URL
+	if h, _, err := net.SplitHostPort(r.URL); err == nil {
+		host = h
+	}
+	if ip := net.ParseIP(host); host == "localhost" || (ip != nil && ip.IsLoopback()) {
+		return ""
+	}
+	return r.URL
+}
+
+// k3sRegistriesConfig returns the k3s registries.yaml content mirroring all the registries to the private registry
+func (r PrivateRegistryProfile) k3sRegistriesConfig() string {
+	scheme := "https"
+	if r.Insecure {
+		scheme = "http"
+	}
+	config := fmt.Sprintf(`mirrors:
+  "*":
+    endpoint:
+      - "%s://%s"
+  "%s":
+    endpoint:
+      - "%s://%s"`, scheme, r.URL, r.URL, scheme, r.URL)
+	if r.Insecure {
+		config += fmt.Sprintf(`
+configs:
+  "%s":
+    tls:
+      insecure_skip_verify: true`, r.URL)
+	}
+	return config
+}
@@ -0,0 +1,199 @@
+/*
+Copyright © 2022 - 2025 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// setenv sets the environment variable for the spec, restoring it afterwards
+func setenv(key, value string) {
+	previous, found := os.LookupEnv(key)
+	DeferCleanup(func() {
+		if found {
+			os.Setenv(key, previous)
+		} else {
+			os.Unsetenv(key)
+		}
+	})
+	Expect(os.Setenv(key, value)).To(Succeed())
+}
+
+func writeProfile(content string) string {
+	path := filepath.Join(GinkgoT().TempDir(), "environment-profile.yaml")
+	Expect(os.WriteFile(path, []byte(content), 0o644)).To(Succeed())
+	return path
+}
+
+var _ = Describe("LoadEnvironmentProfile", func() {
+	It("defaults the unset values", func() {
+		profile, err := LoadEnvironmentProfile(writeProfile(`k3sVersion: v1.31.4+k3s1
+rancher:
+  channel: latest
+  version: 2.10.1
+`))
+		Expect(err).To(BeNil())
+		Expect(profile.K3sVersion).To(Equal("v1.31.4+k3s1"))
+		Expect(profile.Rancher.Hostname).To(Equal(RancherHostname))
+		Expect(profile.RancherFullVersion()).To(Equal("latest/2.10.1"))
+		Expect(profile.NightlyOperatorCharts).To(BeFalse())
+		Expect(profile.OperatorOverride.Enabled()).To(BeFalse())
+		Expect(profile.Proxy).To(Equal(ProxyProfile{Host: defaultProxyHost, NoProxy: defaultNoProxy}))
+		Expect(profile.PrivateRegistry).To(BeZero())
+		Expect(profile.Airgap).To(BeZero())
+	})
+
+	It("keeps the values set", func() {
+		profile, err := LoadEnvironmentProfile(writeProfile(`k3sVersion: v1.31.4+k3s1
+rancher:
+  hostname: rancher.example.com
+  channel: latest
+  version: devel
+  headVersion: "2.10"
+proxy:
+  enabled: true
+  host: 10.0.0.1:3128
+  noProxy: .svc
+operatorOverride:
+  chart: ./charts/rancher-aks-operator
+`))
+		Expect(err).To(BeNil())
+		Expect(profile.Rancher.Hostname).To(Equal("rancher.example.com"))
+		Expect(profile.RancherFullVersion()).To(Equal("latest/devel/2.10"))
+		Expect(profile.Proxy).To(Equal(ProxyProfile{Enabled: true, Host: "10.0.0.1:3128", NoProxy: ".svc"}))
+		Expect(profile.OperatorOverride.Enabled()).To(BeTrue())
+	})
+
+	It("defaults the airgap artifacts and registry", func() {
+		profile, err := LoadEnvironmentProfile(writeProfile(`k3sVersion: v1.31.4+k3s1
+airgap:
+  enabled: true
+`))
+		Expect(err).To(BeNil())
+		Expect(profile.Airgap.ArtifactsDir).To(Equal(defaultAirgapArtifactsDir))
+		Expect(profile.Airgap.RegistryPort).To(Equal(defaultAirgapRegistryPort))
+		Expect(profile.PrivateRegistry).To(BeZero())
+		Expect(profile.mirrorRegistry()).To(Equal(PrivateRegistryProfile{URL: "localhost:5000", Insecure: true}))
+	})
+
+	It("mirrors the airgap images to the private registry given", func() {
+		profile, err := LoadEnvironmentProfile(writeProfile(`k3sVersion: v1.31.4+k3s1
+privateRegistry:
+  url: 10.0.0.2:5000
+airgap:
+  enabled: true
+`))
+		Expect(err).To(BeNil())
+		Expect(profile.mirrorRegistry()).To(Equal(PrivateRegistryProfile{URL: "10.0.0.2:5000"}))
+	})
+
+	It("fails on an unknown field", func() {
+		_, err := LoadEnvironmentProfile(writeProfile("k3sVersion: v1.31.4+k3s1\nnightlyCharts: true\n"))
+		Expect(err).To(MatchError(ContainSubstring("invalid environment profile")))
+	})
+
+	It("fails on a missing file", func() {
+		_, err := LoadEnvironmentProfile(filepath.Join(GinkgoT().TempDir(), "missing.yaml"))
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("EnvironmentProfileFromEnv", func() {
+	BeforeEach(func() {
+		for _, key := range []string{"INSTALL_K3S_VERSION", "NIGHTLY_CHART", "RANCHER_BEHIND_PROXY", "PROXY_HOST",
+			"OPERATOR_CHART", "OPERATOR_CRD_CHART", "OPERATOR_IMAGE_TARBALL", "OPERATOR_IMAGE"} {
+			setenv(key, "")
+		}
+		rancherFullVersion := RancherFullVersion
+		DeferCleanup(func() { RancherFullVersion = rancherFullVersion })
+		RancherFullVersion = ""
+	})
+
+	It("defaults to a stock environment", func() {
+		profile := EnvironmentProfileFromEnv()
+		Expect(profile.K3sVersion).To(BeEmpty())
+		Expect(profile.NightlyOperatorCharts).To(BeFalse())
+		Expect(profile.OperatorOverride).To(BeZero())
+		Expect(profile.Rancher).To(Equal(RancherProfile{Hostname: RancherHostname}))
+		Expect(profile.Proxy).To(Equal(ProxyProfile{Host: defaultProxyHost, NoProxy: defaultNoProxy}))
+		Expect(profile.Airgap).To(BeZero())
+	})
+
+	It("reads the environment variables", func() {
+		setenv("INSTALL_K3S_VERSION", "v1.31.4+k3s1")
+		setenv("NIGHTLY_CHART", "enabled")
+		setenv("RANCHER_BEHIND_PROXY", "enabled")
+		setenv("PROXY_HOST", "10.0.0.1:3128")
+		setenv("OPERATOR_CHART", "./charts/rancher-aks-operator")
+		setenv("OPERATOR_IMAGE", "rancher/aks-operator:dev")
+		RancherFullVersion = "prime/devel/2.10"
+
+		profile := EnvironmentProfileFromEnv()
+		Expect(profile.K3sVersion).To(Equal("v1.31.4+k3s1"))
+		Expect(profile.NightlyOperatorCharts).To(BeTrue())
+		Expect(profile.OperatorOverride).To(Equal(OperatorOverrideProfile{Chart: "./charts/rancher-aks-operator", Image: "rancher/aks-operator:dev"}))
+		Expect(profile.RancherFullVersion()).To(Equal("prime/devel/2.10"))
+		Expect(profile.Proxy).To(Equal(ProxyProfile{Enabled: true, Host: "10.0.0.1:3128", NoProxy: defaultNoProxy}))
+	})
+
+	It("is only enabled by the enabled value", func() {
+		setenv("NIGHTLY_CHART", "true")
+		setenv("RANCHER_BEHIND_PROXY", "true")
+		profile := EnvironmentProfileFromEnv()
+		Expect(profile.NightlyOperatorCharts).To(BeFalse())
+		Expect(profile.Proxy.Enabled).To(BeFalse())
+	})
+})
+
+var _ = Describe("StockEnvironmentProfile", func() {
+	It("keeps the versions of the environment only", func() {
+		setenv("ENVIRONMENT_PROFILE", writeProfile(`k3sVersion: v1.31.4+k3s1
+certManagerVersion: v1.16.2
+nightlyOperatorCharts: true
+rancher:
+  channel: latest
+  version: 2.10.1
+proxy:
+  enabled: true
+privateRegistry:
+  url: 172.17.0.1:5000
+`))
+		profile := StockEnvironmentProfile()
+		Expect(profile.K3sVersion).To(Equal("v1.31.4+k3s1"))
+		Expect(profile.CertManagerVersion).To(Equal("v1.16.2"))
+		Expect(profile.RancherFullVersion()).To(Equal("latest/2.10.1"))
+		Expect(profile.NightlyOperatorCharts).To(BeFalse())
+		Expect(profile.OperatorOverride.Enabled()).To(BeFalse())
+		Expect(profile.Proxy.Enabled).To(BeFalse())
+		Expect(profile.PrivateRegistry).To(BeZero())
+	})
+})
+
+var _ = Describe("PrivateRegistryProfile", func() {
+	DescribeTable("systemDefaultRegistry",
+		func(url, expected string) {
+			Expect(PrivateRegistryProfile{URL: url}.systemDefaultRegistry()).To(Equal(expected))
+		},
+		Entry("no registry", "", ""),
+		Entry("a reachable registry", "10.0.0.2:5000", "10.0.0.2:5000"),
+		Entry("a registry hostname", "registry.example.com", "registry.example.com"),
+		Entry("the localhost", "localhost:5000", ""),
+		Entry("the loopback", "127.0.0.1:5000", ""),
+	)
+})
@@ -0,0 +1,78 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/kubectl"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/egress"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/fault"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/proxy"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const squidAccessLog = "/var/log/squid/access.log"
+
+// ProxyRoutingChecks verifies that the operator of Provider routes its requests through the squid proxy of the proxy mode: its pods
+// received the proxy variables of the Rancher chart, the cloud API hosts of Provider were reached through the proxy and no host of
+// the NO_PROXY ranges was. It must run after the operator called the cloud API, e.g. once a cluster is provisioned.
+func ProxyRoutingChecks() {
+	values := RancherProxyValues()
+	Expect(values.Proxy).ToNot(BeEmpty(), "the Rancher chart has no proxy value")
+
+	ginkgo.By("checking the proxy variables of the operator pods", func() {
+		OperatorProxyEnvChecks(values)
+	})
+
+	ginkgo.By("checking the access log of the proxy", func() {
+		routing := proxy.Verify(SquidAccessLog(), egress.CloudAPIHosts[Provider], values.NoProxy)
+		Expect(routing.Missing).To(BeEmpty(), "the cloud API hosts of %s were not reached through the proxy", Provider)
+		var excluded []string
+		for _, entry := range routing.Excluded {
+			excluded = append(excluded, entry.String())
+		}
+		Expect(excluded).To(BeEmpty(), "requests to the NO_PROXY ranges went through the proxy")
+		ginkgo.GinkgoLogr.Info(fmt.Sprintf("The %s cloud API hosts %s were reached through the proxy", Provider, strings.Join(egress.CloudAPIHosts[Provider], ",")))
+	})
+}
+
+// RancherProxyValues returns the proxy values of the Rancher chart
+func RancherProxyValues() proxy.ChartValues {
+	out, err := exec.Command("helm", "get", "values", "rancher", "--namespace", CattleSystemNS, "-o", "json").Output()
+	Expect(err).To(BeNil(), "Failed to get the values of the Rancher chart")
+	var values proxy.ChartValues
+	Expect(json.Unmarshal(out, &values)).To(Succeed())
+	return values
+}
+
+// OperatorProxyEnvChecks checks that every container of the operator pods of Provider received the proxy variables of the Rancher chart
+func OperatorProxyEnvChecks(values proxy.ChartValues) {
+	out, err := kubectl.RunWithoutErr("get", "pod", "--namespace", CattleSystemNS, "-l", fault.OperatorSelector(Provider), "-o", "json")
+	Expect(err).To(BeNil(), out)
+	pods := &corev1.PodList{}
+	Expect(json.Unmarshal([]byte(out), pods)).To(Succeed())
+	Expect(pods.Items).ToNot(BeEmpty(), "no pod of the %s operator in %s", Provider, CattleSystemNS)
+
+	for _, pod := range pods.Items {
+		for _, container := range pod.Spec.Containers {
+			env := map[string]string{}
+			for _, variable := range container.Env {
+				env[variable.Name] = variable.Value
+			}
+			Expect(proxy.EnvMismatches(values, env)).To(BeEmpty(), "proxy variables of the container %s of the pod %s", container.Name, pod.Name)
+		}
+	}
+}
+
+// SquidAccessLog returns the requests logged by the squid proxy of the proxy mode; squid logs the tunnels once they are closed, so the
+// proxy is restarted first to close the established ones
+func SquidAccessLog() []proxy.Entry {
+	restartSquid()
+	out, err := exec.Command("docker", "exec", squidContainer, "cat", squidAccessLog).Output()
+	Expect(err).To(BeNil(), "Failed to read the access log of the squid proxy")
+	return proxy.ParseAccessLog(string(out))
+}
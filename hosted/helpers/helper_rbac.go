@@ -0,0 +1,152 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/rbac"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"github.com/rancher/shepherd/extensions/users"
+	namegen "github.com/rancher/shepherd/pkg/namegenerator"
+)
+
+// RBACOperations are the provider specific operations of the RBAC matrix; each runs as the user of a subject and returns the error of Rancher.
+// The updates are only submitted: the cluster under test converges once every subject is done, so Scale must toggle the node count
+// (see ToggledNodeCount) for the cluster to end up with its original size, and Upgrade is only applied by the first allowed subject.
+type RBACOperations struct {
+	// Create provisions a cluster; it is deleted as soon as Rancher accepts it
+	Create func(client *rancher.Client, clusterName, cloudCredID string) (*management.Cluster, error)
+	// Import imports a cluster, which does not need to exist on the provider since only the authorization of the request matters
+	Import  func(client *rancher.Client, clusterName, cloudCredID string) (*management.Cluster, error)
+	Edit    func(client *rancher.Client, cluster *management.Cluster) error
+	Scale   func(client *rancher.Client, cluster *management.Cluster) error
+	Upgrade func(client *rancher.Client, cluster *management.Cluster) error
+}
+
+// RBACMatrixChecks runs the operations of the matrix on the cluster of the fixture as a new user of each subject, and checks that Rancher
+// allows or denies each of them as expected. The cluster roles are bound on the cluster of the fixture, and on an imported cluster for the
+// Delete operation. The subjects whose global role does not exist on the Rancher server are skipped.
+func RBACMatrixChecks(f *Fixture, ops RBACOperations, matrix []rbac.Expectation) {
+	admin := f.Ctx.RancherAdminClient
+	var results []rbac.Result
+	for _, expectation := range matrix {
+		subject := expectation.Subject
+		if _, err := admin.Management.GlobalRole.ByID(subject.GlobalRole); err != nil {
+			ginkgo.GinkgoLogr.Info(fmt.Sprintf("Skipping the RBAC subject %s, its global role is not available: %v", subject, err))
+			continue
+		}
+		ginkgo.By(fmt.Sprintf("running the operations as %s", subject), func() {
+			for _, result := range runRBACSubject(f, ops, expectation) {
+				ginkgo.GinkgoLogr.Info(result.String())
+				results = append(results, result)
+			}
+		})
+	}
+	ginkgo.GinkgoLogr.Info("RBAC matrix:\n" + rbac.Report(results))
+
+	var mismatches []string
+	for _, result := range rbac.Mismatches(results) {
+		mismatches = append(mismatches, result.String())
+	}
+	Expect(mismatches).To(BeEmpty(), "the outcome of %d operations does not match the RBAC matrix:\n%s", len(mismatches), strings.Join(mismatches, "\n"))
+
+	ginkgo.By("waiting for the cluster to apply the updates of the allowed subjects", func() {
+		var err error
+		f.Cluster, err = WaitUntilClusterIsReady(f.Cluster, admin)
+		Expect(err).To(BeNil())
+	})
+}
+
+// runRBACSubject creates the user of the subject, binds its cluster role and runs its operations
+func runRBACSubject(f *Fixture, ops RBACOperations, expectation rbac.Expectation) []rbac.Result {
+	admin := f.Ctx.RancherAdminClient
+	subject := expectation.Subject
+
+	user, err := CreateUserWithGlobalRole(admin, "rbac-", subject.GlobalRole)
+	Expect(err).To(BeNil())
+	f.AddCleanup(func() {
+		Expect(admin.Management.User.Delete(user)).To(Succeed())
+	})
+
+	// the cluster deleted by the Delete operation, the user has the cluster role of the subject on it
+	disposable, err := ops.Import(admin, namegen.AppendRandomString(ClusterNamePrefix+"-rbac"), f.Ctx.CloudCredID)
+	Expect(err).To(BeNil())
+	f.AddCleanup(func() {
+		deleteIfExists(admin, disposable.ID)
+	})
+	if subject.ClusterRole != "" {
+		for _, cluster := range []*management.Cluster{f.Cluster, disposable} {
+			Expect(users.AddClusterRoleToUser(admin, cluster, user, subject.ClusterRole, nil)).To(Succeed())
+		}
+	}
+	client, err := admin.AsUser(user)
+	Expect(err).To(BeNil())
+
+	var results []rbac.Result
+	cloudCredID := f.Ctx.CloudCredID
+	for _, operation := range subject.Operations() {
+		var err error
+		switch operation {
+		case rbac.CreateCloudCredential:
+			var ownCloudCredID string
+			if ownCloudCredID, err = CreateCloudCredentials(client); err == nil {
+				f.AddCleanup(func() {
+					if cloudCredential, err := admin.Management.CloudCredential.ByID(ownCloudCredID); err == nil {
+						Expect(admin.Management.CloudCredential.Delete(cloudCredential)).To(Succeed())
+					}
+				})
+				_, err = client.Management.CloudCredential.ByID(ownCloudCredID)
+				cloudCredID = ownCloudCredID
+			}
+		case rbac.Create, rbac.Import:
+			create := ops.Create
+			if operation == rbac.Import {
+				create = ops.Import
+			}
+			var cluster *management.Cluster
+			if cluster, err = create(client, namegen.AppendRandomString(ClusterNamePrefix+"-rbac"), cloudCredID); err == nil {
+				deleteIfExists(admin, cluster.ID)
+			}
+		case rbac.ViewCloudCredential:
+			_, err = client.Management.CloudCredential.ByID(f.Ctx.CloudCredID)
+		case rbac.Edit:
+			err = ops.Edit(client, clusterByAdmin(f))
+		case rbac.Scale:
+			err = ops.Scale(client, clusterByAdmin(f))
+		case rbac.Upgrade:
+			err = ops.Upgrade(client, clusterByAdmin(f))
+		case rbac.Delete:
+			err = client.Management.Cluster.Delete(disposable)
+		}
+		results = append(results, rbac.Result{Subject: subject, Operation: operation, Expected: expectation.Allows(operation), Err: err})
+	}
+	return results
+}
+
+// clusterByAdmin fetches the up-to-date cluster of the fixture as the admin, since the subject may not see it
+func clusterByAdmin(f *Fixture) *management.Cluster {
+	cluster, err := f.Ctx.RancherAdminClient.Management.Cluster.ByID(f.Cluster.ID)
+	Expect(err).To(BeNil())
+	return cluster
+}
+
+// deleteIfExists deletes a cluster as the admin, unless it is already gone
+func deleteIfExists(admin *rancher.Client, clusterID string) {
+	cluster, err := admin.Management.Cluster.ByID(clusterID)
+	if err != nil {
+		return
+	}
+	Expect(admin.Management.Cluster.Delete(cluster)).To(Succeed())
+}
+
+// ToggledNodeCount returns the node count the Scale operation of the RBAC matrix sets: it toggles between an odd count and the next even one,
+// so that the node pools get back to their original size after an even number of allowed Scale operations
+func ToggledNodeCount(count int64) int64 {
+	if count%2 == 1 {
+		return count + 1
+	}
+	return count - 1
+}
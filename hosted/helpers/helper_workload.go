@@ -0,0 +1,154 @@
+package helpers
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/workload"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	v1 "github.com/rancher/shepherd/clients/rancher/v1"
+	namegen "github.com/rancher/shepherd/pkg/namegenerator"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// WorkloadChecks goes beyond ClusterIsReadyChecks: it deploys the smoke test app of the workload package to the downstream cluster
+// through the Rancher client, and checks the Deployment and its PVC, the ClusterIP and LoadBalancer Services and the pod network between nodes.
+// The app is removed once the spec is done. Provider suites opt into it with the workload options of their provider.
+func WorkloadChecks(cluster *management.Cluster, client *rancher.Client, opts workload.Options) {
+	if opts.Namespace == "" {
+		// the namespace of a previous spec may still be terminating on a pool cluster
+		opts.Namespace = namegen.AppendRandomString(workload.Name)
+	}
+	if opts.Token == "" {
+		opts.Token = namegen.RandStringLower(16)
+	}
+	opts = opts.WithDefaults()
+	appID := opts.Namespace + "/" + workload.Name
+
+	steveClient, err := client.Steve.ProxyDownstream(cluster.ID)
+	Expect(err).To(BeNil())
+
+	var namespace, lbService *v1.SteveAPIObject
+	ginkgo.By("creating the namespace of the workload", func() {
+		namespace, err = steveClient.SteveType("namespace").Create(workload.Namespace(opts))
+		Expect(err).To(BeNil())
+	})
+	ginkgo.DeferCleanup(func() {
+		if lbService != nil {
+			// the cloud load balancer must be released before the cluster is deleted
+			Expect(steveClient.SteveType("service").Delete(lbService)).To(Succeed())
+			Eventually(func() error {
+				_, err := steveClient.SteveType("service").ByID(lbService.ID)
+				return err
+			}, tools.SetTimeout(10*time.Minute), 10*time.Second).ShouldNot(Succeed())
+		}
+		Expect(steveClient.SteveType("namespace").Delete(namespace)).To(Succeed())
+	})
+
+	ginkgo.By("deploying the app with its PVC and ClusterIP service", func() {
+		_, err = steveClient.SteveType("persistentvolumeclaim").Create(workload.PersistentVolumeClaim(opts))
+		Expect(err).To(BeNil())
+		_, err = steveClient.SteveType("apps.deployment").Create(workload.Deployment(opts))
+		Expect(err).To(BeNil())
+		_, err = steveClient.SteveType("service").Create(workload.Service(opts, corev1.ServiceTypeClusterIP))
+		Expect(err).To(BeNil())
+	})
+
+	ginkgo.By("checking the deployment is available and its PVC is bound", func() {
+		Eventually(func() bool {
+			deployment := &appsv1.Deployment{}
+			Expect(getSteveObject(steveClient, "apps.deployment", appID, deployment)).To(Succeed())
+			return workload.DeploymentAvailable(deployment)
+		}, tools.SetTimeout(10*time.Minute), 10*time.Second).Should(BeTrue())
+
+		pvc := &corev1.PersistentVolumeClaim{}
+		Expect(getSteveObject(steveClient, "persistentvolumeclaim", appID, pvc)).To(Succeed())
+		Expect(pvc.Status.Phase).To(Equal(corev1.ClaimBound))
+	})
+
+	ginkgo.By("probing the app through the pod network and the ClusterIP service", func() {
+		pods, err := steveClient.SteveType("pod").List(url.Values{"labelSelector": {workload.AppLabel + "=" + workload.Name}})
+		Expect(err).To(BeNil())
+		var appPod *corev1.Pod
+		for _, object := range pods.Data {
+			pod := &corev1.Pod{}
+			Expect(v1.ConvertToK8sType(object.JSONResp, pod)).To(Succeed())
+			if pod.Namespace == opts.Namespace && pod.Status.Phase == corev1.PodRunning && pod.Status.PodIP != "" {
+				appPod = pod
+			}
+		}
+		Expect(appPod).ToNot(BeNil(), "the app has no running pod")
+
+		nodes, err := steveClient.SteveType("node").List(nil)
+		Expect(err).To(BeNil())
+		var avoidNode string
+		if len(nodes.Data) > 1 {
+			avoidNode = appPod.Spec.NodeName
+		} else {
+			ginkgo.GinkgoLogr.Info("The cluster has a single node, the app is probed from its own node")
+		}
+
+		runProbeJob(steveClient, workload.ProbeJob(opts, avoidNode,
+			"http://"+appPod.Status.PodIP, "http://"+workload.Name+"."+opts.Namespace+".svc.cluster.local"))
+	})
+
+	if opts.SkipLoadBalancer {
+		return
+	}
+	ginkgo.By("checking the load balancer serves the app", func() {
+		lbService, err = steveClient.SteveType("service").Create(workload.Service(opts, corev1.ServiceTypeLoadBalancer))
+		Expect(err).To(BeNil())
+
+		var address string
+		Eventually(func() string {
+			svc := &corev1.Service{}
+			Expect(getSteveObject(steveClient, "service", lbService.ID, svc)).To(Succeed())
+			address = workload.LoadBalancerAddress(svc)
+			return address
+		}, tools.SetTimeout(10*time.Minute), 15*time.Second).ShouldNot(BeEmpty())
+
+		// the DNS record of the load balancer (e.g. on EKS) takes a few minutes to propagate
+		httpClient := &http.Client{Timeout: 10 * time.Second}
+		Eventually(func() (string, error) {
+			resp, err := httpClient.Get("http://" + address)
+			if err != nil {
+				return "", err
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			return strings.TrimSpace(string(body)), err
+		}, tools.SetTimeout(10*time.Minute), 15*time.Second).Should(Equal(opts.Token))
+	})
+}
+
+// runProbeJob creates the probe Job and waits for it to succeed
+func runProbeJob(client *v1.Client, job *batchv1.Job) {
+	_, err := client.SteveType("batch.job").Create(job)
+	Expect(err).To(BeNil())
+	jobID := job.Namespace + "/" + job.Name
+	Eventually(func() bool {
+		job := &batchv1.Job{}
+		Expect(getSteveObject(client, "batch.job", jobID, job)).To(Succeed())
+		done, err := workload.JobResult(job)
+		Expect(err).To(BeNil())
+		return done
+	}, tools.SetTimeout(5*time.Minute), 10*time.Second).Should(BeTrue())
+}
+
+// getSteveObject fetches the object id of steveType and converts it to obj, a k8s type
+func getSteveObject(client *v1.Client, steveType, id string, obj interface{}) error {
+	steveObject, err := client.SteveType(steveType).ByID(id)
+	if err != nil {
+		return err
+	}
+	return v1.ConvertToK8sType(steveObject.JSONResp, obj)
+}
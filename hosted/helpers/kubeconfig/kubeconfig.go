@@ -0,0 +1,166 @@
+// Package kubeconfig hands out per-cluster kubeconfig files which are passed explicitly to the kubectl, helm and cloud CLI invocations,
+// so that the process-wide KUBECONFIG is never mutated while specs run in parallel
+package kubeconfig
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// EnvVar is the environment variable read by kubectl, helm and the cloud CLIs
+const EnvVar = "KUBECONFIG"
+
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+
+// Manager keeps a kubeconfig file per cluster name in a private temporary directory;
+// it is safe for concurrent use, the directory is removed once the last handle is released
+type Manager struct {
+	mu      sync.Mutex
+	dir     string
+	handles map[string]*Handle
+}
+
+// Handle is the kubeconfig file of a single cluster
+type Handle struct {
+	ClusterName string
+	Path        string
+}
+
+// NewManager returns an empty Manager, its temporary directory is only created with the first handle
+func NewManager() *Manager {
+	return &Manager{handles: map[string]*Handle{}}
+}
+
+// Get returns the handle of clusterName, creating an empty kubeconfig file for it if needed;
+// an empty file is what the cloud CLIs (e.g. `gcloud container clusters create`) expect to write the cluster credentials to
+func (m *Manager) Get(clusterName string) (*Handle, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if handle, ok := m.handles[clusterName]; ok {
+		return handle, nil
+	}
+	if clusterName == "" {
+		return nil, errors.New("a cluster name is required")
+	}
+	if m.dir == "" {
+		dir, err := os.MkdirTemp("", "hp-kubeconfig-")
+		if err != nil {
+			return nil, errors.Wrap(err, "creating the kubeconfig directory")
+		}
+		m.dir = dir
+	}
+
+	path := filepath.Join(m.dir, unsafeFileChars.ReplaceAllString(clusterName, "_")+".yaml")
+	if err := os.WriteFile(path, nil, 0600); err != nil {
+		return nil, errors.Wrapf(err, "creating the kubeconfig of cluster %s", clusterName)
+	}
+	handle := &Handle{ClusterName: clusterName, Path: path}
+	m.handles[clusterName] = handle
+	return handle, nil
+}
+
+// Write returns the handle of clusterName with its kubeconfig file set to content
+func (m *Manager) Write(clusterName string, content []byte) (*Handle, error) {
+	handle, err := m.Get(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	if err = os.WriteFile(handle.Path, content, 0600); err != nil {
+		return nil, errors.Wrapf(err, "writing the kubeconfig of cluster %s", clusterName)
+	}
+	return handle, nil
+}
+
+// Fetch returns the handle of clusterName with its kubeconfig file set to the content returned by fetch,
+// e.g. the output of Rancher's generateKubeconfig action
+func (m *Manager) Fetch(clusterName string, fetch func() (string, error)) (*Handle, error) {
+	content, err := fetch()
+	if err != nil {
+		return nil, errors.Wrapf(err, "fetching the kubeconfig of cluster %s", clusterName)
+	}
+	return m.Write(clusterName, []byte(content))
+}
+
+// Release removes the kubeconfig file of clusterName; it is a no-op if the cluster has no handle
+func (m *Manager) Release(clusterName string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	handle, ok := m.handles[clusterName]
+	if !ok {
+		return nil
+	}
+	delete(m.handles, clusterName)
+	if err := os.Remove(handle.Path); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "removing the kubeconfig of cluster %s", clusterName)
+	}
+	if len(m.handles) == 0 {
+		return m.removeDir()
+	}
+	return nil
+}
+
+// Cleanup removes all the kubeconfig files and their directory
+func (m *Manager) Cleanup() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.handles = map[string]*Handle{}
+	return m.removeDir()
+}
+
+// ClusterNames returns the sorted names of the clusters which have a handle
+func (m *Manager) ClusterNames() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	names := make([]string, 0, len(m.handles))
+	for name := range m.handles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (m *Manager) removeDir() error {
+	if m.dir == "" {
+		return nil
+	}
+	if err := os.RemoveAll(m.dir); err != nil {
+		return errors.Wrap(err, "removing the kubeconfig directory")
+	}
+	m.dir = ""
+	return nil
+}
+
+// Env returns the environment of the current process with KUBECONFIG pointing to the handle
+func (h *Handle) Env() []string {
+	env := []string{EnvVar + "=" + h.Path}
+	for _, v := range os.Environ() {
+		if !strings.HasPrefix(v, EnvVar+"=") {
+			env = append(env, v)
+		}
+	}
+	return env
+}
+
+// Command returns a command which runs with KUBECONFIG pointing to the handle
+func (h *Handle) Command(name string, args ...string) *exec.Cmd {
+	cmd := exec.Command(name, args...)
+	cmd.Env = h.Env()
+	return cmd
+}
+
+// Run runs a command with KUBECONFIG pointing to the handle and returns its combined output
+func (h *Handle) Run(name string, args ...string) (string, error) {
+	out, err := h.Command(name, args...).CombinedOutput()
+	return string(out), err
+}
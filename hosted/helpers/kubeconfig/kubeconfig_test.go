@@ -0,0 +1,140 @@
+/*
+Copyright © 2022 - 2025 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/kubeconfig"
+)
+
+var _ = Describe("Manager", func() {
+	var m *kubeconfig.Manager
+
+	BeforeEach(func() {
+		m = kubeconfig.NewManager()
+		DeferCleanup(m.Cleanup)
+	})
+
+	It("hands out one empty kubeconfig file per cluster", func() {
+		first, err := m.Get("eks-hp-ci-abc")
+		Expect(err).To(BeNil())
+		Expect(first.ClusterName).To(Equal("eks-hp-ci-abc"))
+		Expect(first.Path).To(BeAnExistingFile())
+		content, err := os.ReadFile(first.Path)
+		Expect(err).To(BeNil())
+		Expect(content).To(BeEmpty())
+
+		again, err := m.Get("eks-hp-ci-abc")
+		Expect(err).To(BeNil())
+		Expect(again).To(BeIdenticalTo(first))
+
+		other, err := m.Get("gke/hp ci")
+		Expect(err).To(BeNil())
+		Expect(other.Path).ToNot(Equal(first.Path))
+		Expect(filepath.Dir(other.Path)).To(Equal(filepath.Dir(first.Path)))
+		Expect(filepath.Base(other.Path)).To(Equal("gke_hp_ci.yaml"))
+
+		Expect(m.ClusterNames()).To(Equal([]string{"eks-hp-ci-abc", "gke/hp ci"}))
+	})
+
+	It("rejects an empty cluster name", func() {
+		_, err := m.Get("")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("writes and fetches the kubeconfig content", func() {
+		handle, err := m.Write("aks-hp-ci", []byte("apiVersion: v1\n"))
+		Expect(err).To(BeNil())
+		Expect(os.ReadFile(handle.Path)).To(BeEquivalentTo("apiVersion: v1\n"))
+
+		handle, err = m.Fetch("aks-hp-ci", func() (string, error) { return "kind: Config\n", nil })
+		Expect(err).To(BeNil())
+		Expect(os.ReadFile(handle.Path)).To(BeEquivalentTo("kind: Config\n"))
+
+		_, err = m.Fetch("aks-hp-ci", func() (string, error) { return "", errors.New("forbidden") })
+		Expect(err).To(MatchError(ContainSubstring("forbidden")))
+	})
+
+	It("removes the files and their directory once released", func() {
+		first, err := m.Get("first")
+		Expect(err).To(BeNil())
+		second, err := m.Get("second")
+		Expect(err).To(BeNil())
+		dir := filepath.Dir(first.Path)
+
+		Expect(m.Release("first")).To(Succeed())
+		Expect(first.Path).ToNot(BeAnExistingFile())
+		Expect(second.Path).To(BeAnExistingFile())
+		Expect(m.Release("first")).To(Succeed())
+
+		Expect(m.Release("second")).To(Succeed())
+		Expect(dir).ToNot(BeADirectory())
+		Expect(m.ClusterNames()).To(BeEmpty())
+
+		third, err := m.Get("third")
+		Expect(err).To(BeNil())
+		Expect(third.Path).To(BeAnExistingFile())
+		Expect(m.Cleanup()).To(Succeed())
+		Expect(filepath.Dir(third.Path)).ToNot(BeADirectory())
+	})
+
+	It("is safe for concurrent use", func() {
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				defer GinkgoRecover()
+				name := fmt.Sprintf("cluster-%d", i%5)
+				_, err := m.Write(name, []byte(name))
+				Expect(err).To(BeNil())
+			}(i)
+		}
+		wg.Wait()
+		Expect(m.ClusterNames()).To(HaveLen(5))
+	})
+})
+
+var _ = Describe("Handle", func() {
+	It("points KUBECONFIG to its file without touching the process environment", func() {
+		GinkgoT().Setenv(kubeconfig.EnvVar, "/etc/rancher/k3s/k3s.yaml")
+		m := kubeconfig.NewManager()
+		DeferCleanup(m.Cleanup)
+		handle, err := m.Get("downstream")
+		Expect(err).To(BeNil())
+
+		var kubeconfigs []string
+		for _, v := range handle.Env() {
+			if strings.HasPrefix(v, kubeconfig.EnvVar+"=") {
+				kubeconfigs = append(kubeconfigs, v)
+			}
+		}
+		Expect(kubeconfigs).To(Equal([]string{kubeconfig.EnvVar + "=" + handle.Path}))
+
+		out, err := handle.Run("sh", "-c", "echo $KUBECONFIG")
+		Expect(err).To(BeNil())
+		Expect(strings.TrimSpace(out)).To(Equal(handle.Path))
+		Expect(os.Getenv(kubeconfig.EnvVar)).To(Equal("/etc/rancher/k3s/k3s.yaml"))
+	})
+})
@@ -0,0 +1,265 @@
+// Package pool keeps the ledger of a cluster pool: which downstream clusters exist for a shape and which spec leases them.
+// The ledger is a JSON file guarded by a file lock, so that the Ginkgo parallel processes of a suite can share it.
+package pool
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	ledgerFile = "ledger.json"
+	lockFile   = "ledger.lock"
+)
+
+// ErrExhausted is returned by Acquire when every cluster of the shape is leased and the pool is full
+var ErrExhausted = errors.New("all the clusters of the pool are leased")
+
+// State is the state of a pool cluster
+type State string
+
+const (
+	// Free clusters are ready to be leased
+	Free State = "free"
+	// Leased clusters are used by a spec, or being provisioned for it when their ClusterID is empty
+	Leased State = "leased"
+	// Retired clusters were left in a state which cannot be reset, they are never leased again
+	Retired State = "retired"
+)
+
+// Baseline is the state a cluster is reset to before being returned to the pool
+type Baseline struct {
+	KubernetesVersion string            `json:"kubernetesVersion"`
+	NodePools         int               `json:"nodePools"`
+	NodeCount         int64             `json:"nodeCount"`
+	Tags              map[string]string `json:"tags,omitempty"`
+	// CloudCredential is the cloud credential the cluster is created with, specs may switch the cluster to another one
+	CloudCredential string `json:"cloudCredential,omitempty"`
+}
+
+// Entry is a cluster of the pool
+type Entry struct {
+	ClusterName  string    `json:"clusterName"`
+	ClusterID    string    `json:"clusterID,omitempty"`
+	Shape        string    `json:"shape"`
+	State        State     `json:"state"`
+	Holder       string    `json:"holder,omitempty"`
+	LeasedAt     time.Time `json:"leasedAt,omitempty"`
+	Leases       int       `json:"leases"`
+	Baseline     Baseline  `json:"baseline"`
+	RetireReason string    `json:"retireReason,omitempty"`
+}
+
+// Ledger is the shared state of a cluster pool, stored in a directory
+type Ledger struct {
+	dir string
+}
+
+// NewLedger creates an empty ledger in a new temporary directory
+func NewLedger() (*Ledger, error) {
+	dir, err := os.MkdirTemp("", "hp-cluster-pool-")
+	if err != nil {
+		return nil, errors.Wrap(err, "creating the cluster pool directory")
+	}
+	l := &Ledger{dir: dir}
+	if err = l.write(nil); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// OpenLedger opens the ledger created by NewLedger in dir, e.g. by another Ginkgo process
+func OpenLedger(dir string) (*Ledger, error) {
+	if _, err := os.Stat(filepath.Join(dir, ledgerFile)); err != nil {
+		return nil, errors.Wrapf(err, "opening the cluster pool in %s", dir)
+	}
+	return &Ledger{dir: dir}, nil
+}
+
+// Dir returns the directory of the ledger, it is what the other processes pass to OpenLedger
+func (l *Ledger) Dir() string {
+	return l.dir
+}
+
+// Acquire leases a free cluster of shape to holder. If there is none and the pool holds less than size clusters of shape,
+// a new entry named newClusterName is leased instead and provision is true: the holder must provision the cluster and Register it.
+// ErrExhausted is returned when the pool is full.
+func (l *Ledger) Acquire(shape string, size int, holder, newClusterName string) (entry Entry, provision bool, err error) {
+	err = l.update(func(entries []Entry) ([]Entry, error) {
+		active := 0
+		for i := range entries {
+			if entries[i].Shape != shape || entries[i].State == Retired {
+				continue
+			}
+			if entries[i].State == Free {
+				entries[i].lease(holder)
+				entry = entries[i]
+				return entries, nil
+			}
+			active++
+		}
+		if active >= size {
+			return nil, ErrExhausted
+		}
+		for _, e := range entries {
+			if e.ClusterName == newClusterName {
+				return nil, errors.Errorf("cluster %s is already in the pool", newClusterName)
+			}
+		}
+		entry = Entry{ClusterName: newClusterName, Shape: shape}
+		entry.lease(holder)
+		provision = true
+		return append(entries, entry), nil
+	})
+	return entry, provision, err
+}
+
+// Register records the ID and the baseline of a cluster provisioned after Acquire
+func (l *Ledger) Register(clusterName, clusterID string, baseline Baseline) error {
+	return l.updateEntry(clusterName, func(e *Entry) error {
+		e.ClusterID = clusterID
+		e.Baseline = baseline
+		return nil
+	})
+}
+
+// Adopt adds an existing cluster to the pool as a free cluster, e.g. one kept by a previous run
+func (l *Ledger) Adopt(entry Entry) error {
+	return l.update(func(entries []Entry) ([]Entry, error) {
+		for _, e := range entries {
+			if e.ClusterName == entry.ClusterName {
+				return nil, errors.Errorf("cluster %s is already in the pool", entry.ClusterName)
+			}
+		}
+		entry.State = Free
+		entry.Holder = ""
+		return append(entries, entry), nil
+	})
+}
+
+// Release returns a leased cluster to the pool
+func (l *Ledger) Release(clusterName string) error {
+	return l.updateEntry(clusterName, func(e *Entry) error {
+		if e.State != Leased {
+			return errors.Errorf("cluster %s is not leased", clusterName)
+		}
+		if e.ClusterID == "" {
+			return errors.Errorf("cluster %s has not been registered", clusterName)
+		}
+		e.State = Free
+		e.Holder = ""
+		return nil
+	})
+}
+
+// Retire removes a cluster from the leasable clusters, its slot can be used by a new cluster
+func (l *Ledger) Retire(clusterName, reason string) error {
+	return l.updateEntry(clusterName, func(e *Entry) error {
+		e.State = Retired
+		e.Holder = ""
+		e.RetireReason = reason
+		return nil
+	})
+}
+
+// Entries returns all the clusters of the pool sorted by name, including the retired ones
+func (l *Ledger) Entries() ([]Entry, error) {
+	var entries []Entry
+	err := l.locked(func() error {
+		var err error
+		entries, err = l.read()
+		return err
+	})
+	return entries, err
+}
+
+// Remove deletes the ledger and its directory
+func (l *Ledger) Remove() error {
+	if err := os.RemoveAll(l.dir); err != nil {
+		return errors.Wrap(err, "removing the cluster pool directory")
+	}
+	return nil
+}
+
+func (e *Entry) lease(holder string) {
+	e.State = Leased
+	e.Holder = holder
+	e.LeasedAt = time.Now()
+	e.Leases++
+}
+
+func (l *Ledger) updateEntry(clusterName string, update func(e *Entry) error) error {
+	return l.update(func(entries []Entry) ([]Entry, error) {
+		for i := range entries {
+			if entries[i].ClusterName == clusterName {
+				return entries, update(&entries[i])
+			}
+		}
+		return nil, errors.Errorf("cluster %s is not in the pool", clusterName)
+	})
+}
+
+func (l *Ledger) update(update func(entries []Entry) ([]Entry, error)) error {
+	return l.locked(func() error {
+		entries, err := l.read()
+		if err != nil {
+			return err
+		}
+		if entries, err = update(entries); err != nil {
+			return err
+		}
+		return l.write(entries)
+	})
+}
+
+// locked runs fn holding the lock of the ledger, which is shared by all the processes using it
+func (l *Ledger) locked(fn func() error) error {
+	lock, err := os.OpenFile(filepath.Join(l.dir, lockFile), os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return errors.Wrap(err, "opening the cluster pool lock")
+	}
+	// closing the file releases the lock
+	defer lock.Close()
+	if err = syscall.Flock(int(lock.Fd()), syscall.LOCK_EX); err != nil {
+		return errors.Wrap(err, "locking the cluster pool")
+	}
+	return fn()
+}
+
+func (l *Ledger) read() ([]Entry, error) {
+	content, err := os.ReadFile(filepath.Join(l.dir, ledgerFile))
+	if err != nil {
+		return nil, errors.Wrap(err, "reading the cluster pool")
+	}
+	var entries []Entry
+	if err = json.Unmarshal(content, &entries); err != nil {
+		return nil, errors.Wrap(err, "decoding the cluster pool")
+	}
+	return entries, nil
+}
+
+func (l *Ledger) write(entries []Entry) error {
+	if entries == nil {
+		entries = []Entry{}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ClusterName < entries[j].ClusterName })
+	content, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "encoding the cluster pool")
+	}
+	// the ledger is replaced atomically so that a crashed process never leaves it half written
+	tmp := filepath.Join(l.dir, ledgerFile+".tmp")
+	if err = os.WriteFile(tmp, content, 0600); err != nil {
+		return errors.Wrap(err, "writing the cluster pool")
+	}
+	if err = os.Rename(tmp, filepath.Join(l.dir, ledgerFile)); err != nil {
+		return errors.Wrap(err, "writing the cluster pool")
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+/*
+Copyright © 2022 - 2025 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool_test
+
+import (
+	"fmt"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/pool"
+)
+
+var _ = Describe("Ledger", func() {
+	var ledger *pool.Ledger
+
+	BeforeEach(func() {
+		var err error
+		ledger, err = pool.NewLedger()
+		Expect(err).To(BeNil())
+		DeferCleanup(ledger.Remove)
+	})
+
+	It("provisions new clusters until the pool is full", func() {
+		first, provision, err := ledger.Acquire("default", 2, "process-1", "eks-hp-ci-a")
+		Expect(err).To(BeNil())
+		Expect(provision).To(BeTrue())
+		Expect(first.State).To(Equal(pool.Leased))
+		Expect(first.Holder).To(Equal("process-1"))
+		Expect(first.ClusterID).To(BeEmpty())
+
+		_, provision, err = ledger.Acquire("default", 2, "process-2", "eks-hp-ci-b")
+		Expect(err).To(BeNil())
+		Expect(provision).To(BeTrue())
+
+		_, _, err = ledger.Acquire("default", 2, "process-3", "eks-hp-ci-c")
+		Expect(err).To(MatchError(pool.ErrExhausted))
+
+		// the size is per shape
+		_, provision, err = ledger.Acquire("multiple-nodepools", 2, "process-3", "eks-hp-ci-c")
+		Expect(err).To(BeNil())
+		Expect(provision).To(BeTrue())
+	})
+
+	It("leases the released clusters again", func() {
+		baseline := pool.Baseline{KubernetesVersion: "1.31", NodePools: 1, NodeCount: 1, Tags: map[string]string{"owner": "hp-ci"}, CloudCredential: "cattle-global-data:cc-abcde"}
+		_, _, err := ledger.Acquire("default", 1, "process-1", "eks-hp-ci-a")
+		Expect(err).To(BeNil())
+		Expect(ledger.Release("eks-hp-ci-a")).ToNot(Succeed(), "a cluster which has not been registered cannot be released")
+		Expect(ledger.Register("eks-hp-ci-a", "c-abcde", baseline)).To(Succeed())
+		Expect(ledger.Release("eks-hp-ci-a")).To(Succeed())
+		Expect(ledger.Release("eks-hp-ci-a")).ToNot(Succeed(), "a free cluster cannot be released")
+
+		entry, provision, err := ledger.Acquire("default", 1, "process-2", "eks-hp-ci-b")
+		Expect(err).To(BeNil())
+		Expect(provision).To(BeFalse())
+		Expect(entry.ClusterName).To(Equal("eks-hp-ci-a"))
+		Expect(entry.ClusterID).To(Equal("c-abcde"))
+		Expect(entry.Holder).To(Equal("process-2"))
+		Expect(entry.Leases).To(Equal(2))
+		Expect(entry.Baseline).To(Equal(baseline))
+	})
+
+	It("replaces the retired clusters", func() {
+		_, _, err := ledger.Acquire("default", 1, "process-1", "eks-hp-ci-a")
+		Expect(err).To(BeNil())
+		Expect(ledger.Retire("eks-hp-ci-a", "the k8s version was upgraded")).To(Succeed())
+
+		entry, provision, err := ledger.Acquire("default", 1, "process-1", "eks-hp-ci-b")
+		Expect(err).To(BeNil())
+		Expect(provision).To(BeTrue())
+		Expect(entry.ClusterName).To(Equal("eks-hp-ci-b"))
+
+		entries, err := ledger.Entries()
+		Expect(err).To(BeNil())
+		Expect(entries).To(HaveLen(2))
+		Expect(entries[0].State).To(Equal(pool.Retired))
+		Expect(entries[0].RetireReason).To(Equal("the k8s version was upgraded"))
+	})
+
+	It("adopts existing clusters as free clusters", func() {
+		Expect(ledger.Adopt(pool.Entry{ClusterName: "eks-hp-ci-kept", ClusterID: "c-kept", Shape: "default", State: pool.Retired})).To(Succeed())
+		Expect(ledger.Adopt(pool.Entry{ClusterName: "eks-hp-ci-kept", ClusterID: "c-kept", Shape: "default"})).ToNot(Succeed())
+
+		entry, provision, err := ledger.Acquire("default", 1, "process-1", "eks-hp-ci-a")
+		Expect(err).To(BeNil())
+		Expect(provision).To(BeFalse())
+		Expect(entry.ClusterID).To(Equal("c-kept"))
+	})
+
+	It("is shared by the processes opening it", func() {
+		other, err := pool.OpenLedger(ledger.Dir())
+		Expect(err).To(BeNil())
+		_, _, err = other.Acquire("default", 1, "process-2", "eks-hp-ci-a")
+		Expect(err).To(BeNil())
+		_, _, err = ledger.Acquire("default", 1, "process-1", "eks-hp-ci-b")
+		Expect(err).To(MatchError(pool.ErrExhausted))
+
+		_, err = pool.OpenLedger(GinkgoT().TempDir())
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("never leases a cluster twice to concurrent holders", func() {
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		leased := map[string]string{}
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				defer GinkgoRecover()
+				// every holder opens its own ledger, like the Ginkgo parallel processes do
+				l, err := pool.OpenLedger(ledger.Dir())
+				Expect(err).To(BeNil())
+				holder := fmt.Sprintf("process-%d", i)
+				entry, _, err := l.Acquire("default", 4, holder, fmt.Sprintf("eks-hp-ci-%d", i))
+				if err != nil {
+					Expect(err).To(MatchError(pool.ErrExhausted))
+					return
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				Expect(leased).ToNot(HaveKey(entry.ClusterName))
+				leased[entry.ClusterName] = holder
+			}(i)
+		}
+		wg.Wait()
+		Expect(leased).To(HaveLen(4))
+
+		entries, err := ledger.Entries()
+		Expect(err).To(BeNil())
+		Expect(entries).To(HaveLen(4))
+		for _, entry := range entries {
+			Expect(entry.Holder).To(Equal(leased[entry.ClusterName]))
+		}
+	})
+})
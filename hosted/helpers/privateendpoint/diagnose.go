@@ -0,0 +1,87 @@
+package privateendpoint
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Condition is a condition of the Rancher cluster
+type Condition struct {
+	Type    string
+	Status  string
+	Reason  string
+	Message string
+}
+
+// registrationPendingMessages are reported by Rancher once the cluster is provisioned but cannot be reached without its agent
+var registrationPendingMessages = []string{
+	"cluster agent disconnected",
+	"waiting for cluster agent to connect",
+}
+
+// RegistrationPending returns whether the transitioning message of a private cluster tells it is waiting for the registration command
+func RegistrationPending(transitioningMessage string) bool {
+	message := strings.ToLower(transitioningMessage)
+	for _, pending := range registrationPendingMessages {
+		if strings.Contains(message, pending) {
+			return true
+		}
+	}
+	return false
+}
+
+// causes map the fragments of the messages Rancher reports to the likely cause of the stall, the first match wins;
+// Rancher tries the endpoint directly before falling back to the agent tunnel, so a disconnected agent explains a dial error as well
+var causes = []struct {
+	fragments []string
+	cause     string
+}{
+	{[]string{"x509", "certificate"}, "the cluster agent does not trust the certificate of Rancher; the insecure registration command, or the CA checksum of the server URL, must be used"},
+	{[]string{"unauthorized", "forbidden"}, "the registration command was run without cluster-admin permissions, or the credentials of the cluster agent were rejected"},
+	{registrationPendingMessages, "the cluster agent is not connected: the registration command has not run in the cluster, or the agent cannot reach the Rancher server URL from the cluster network"},
+	{[]string{"no such host"}, "Rancher cannot resolve the API endpoint: it is still trying to reach the private endpoint directly, without the agent tunnel"},
+	{[]string{"i/o timeout", "connection refused", "dial tcp"}, "Rancher cannot connect to the API endpoint: it is still trying to reach the private endpoint directly, without the agent tunnel"},
+}
+
+// Diagnose explains why the registration of a private cluster stalls, from the state Rancher reports for it:
+// the transitioning message, the conditions which are not true, and the likely cause of the stall
+func Diagnose(state, transitioningMessage string, conditions []Condition) string {
+	lines := []string{fmt.Sprintf("state: %s", state)}
+	if transitioningMessage != "" {
+		lines = append(lines, fmt.Sprintf("message: %s", transitioningMessage))
+	}
+	messages := []string{transitioningMessage}
+	for _, condition := range conditions {
+		if condition.Status == "True" {
+			continue
+		}
+		line := fmt.Sprintf("condition %s is %s", condition.Type, condition.Status)
+		if condition.Reason != "" {
+			line += fmt.Sprintf(" (%s)", condition.Reason)
+		}
+		if condition.Message != "" {
+			line += ": " + condition.Message
+		}
+		lines = append(lines, line)
+		messages = append(messages, condition.Message)
+	}
+
+	all := strings.ToLower(strings.Join(messages, "\n"))
+	cause := "unknown, see the conditions of the cluster"
+	for _, c := range causes {
+		if containsAny(all, c.fragments) {
+			cause = c.cause
+			break
+		}
+	}
+	return strings.Join(append(lines, "likely cause: "+cause), "\n")
+}
+
+func containsAny(s string, fragments []string) bool {
+	for _, fragment := range fragments {
+		if strings.Contains(s, fragment) {
+			return true
+		}
+	}
+	return false
+}
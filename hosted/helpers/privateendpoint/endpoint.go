@@ -0,0 +1,78 @@
+// Package privateendpoint tells whether the API endpoint of a private cluster is really closed to the test runner, and explains why the registration
+// of a private cluster with Rancher stalls. Rancher runs on the test runner, so an endpoint the runner cannot reach is an endpoint Rancher cannot reach
+// either: once such a cluster is active, Rancher can only be talking to it through the tunnel of the cluster agent.
+package privateendpoint
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Reachability is the view of an API endpoint from the test runner
+type Reachability struct {
+	Endpoint string
+	// Addresses the endpoint resolves to
+	Addresses []string
+	Reachable bool
+	// Reason tells why the endpoint is reachable or not
+	Reason string
+}
+
+func (r Reachability) String() string {
+	return fmt.Sprintf("%s (%s): %s", r.Endpoint, strings.Join(r.Addresses, ", "), r.Reason)
+}
+
+// Probe tries to open a TCP connection to endpoint, a URL, a host:port or a bare host or IP on the port 443, within timeout for each of its addresses
+func Probe(endpoint string, timeout time.Duration) Reachability {
+	r := Reachability{Endpoint: endpoint}
+	host, port := splitEndpoint(endpoint)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	addresses, err := net.DefaultResolver.LookupHost(ctx, host)
+	if err != nil {
+		r.Reason = fmt.Sprintf("does not resolve: %v", err)
+		return r
+	}
+	r.Addresses = addresses
+
+	var failures []string
+	for _, address := range addresses {
+		conn, err := net.DialTimeout("tcp", net.JoinHostPort(address, port), timeout)
+		if err == nil {
+			_ = conn.Close()
+			r.Reachable = true
+			r.Reason = fmt.Sprintf("accepts connections on %s", net.JoinHostPort(address, port))
+			return r
+		}
+		failures = append(failures, err.Error())
+	}
+	r.Reason = strings.Join(failures, "; ")
+	if privateOnly(addresses) {
+		r.Reason = "resolves to private addresses only: " + r.Reason
+	}
+	return r
+}
+
+func splitEndpoint(endpoint string) (host, port string) {
+	if u, err := url.Parse(endpoint); err == nil && u.Host != "" {
+		endpoint = u.Host
+	}
+	if host, port, err := net.SplitHostPort(endpoint); err == nil {
+		return host, port
+	}
+	return strings.Trim(endpoint, "[]"), "443"
+}
+
+func privateOnly(addresses []string) bool {
+	for _, address := range addresses {
+		if ip := net.ParseIP(address); ip == nil || !(ip.IsPrivate() || ip.IsLoopback()) {
+			return false
+		}
+	}
+	return len(addresses) > 0
+}
@@ -0,0 +1,93 @@
+/*
+Copyright © 2022 - 2025 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package privateendpoint_test
+
+import (
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/privateendpoint"
+)
+
+var _ = Describe("Probe", func() {
+	It("reaches an endpoint accepting connections", func() {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(BeNil())
+		defer listener.Close()
+
+		for _, endpoint := range []string{"https://" + listener.Addr().String(), listener.Addr().String()} {
+			r := privateendpoint.Probe(endpoint, time.Second)
+			Expect(r.Reachable).To(BeTrue(), r.String())
+			Expect(r.Addresses).To(Equal([]string{"127.0.0.1"}))
+			Expect(r.Reason).To(ContainSubstring("accepts connections"))
+		}
+	})
+
+	It("does not reach a closed endpoint", func() {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(BeNil())
+		endpoint := "https://" + listener.Addr().String() + "/version"
+		Expect(listener.Close()).To(Succeed())
+
+		r := privateendpoint.Probe(endpoint, time.Second)
+		Expect(r.Reachable).To(BeFalse())
+		Expect(r.Reason).To(HavePrefix("resolves to private addresses only"))
+		Expect(r.String()).To(ContainSubstring(endpoint))
+	})
+
+	It("does not reach an endpoint which does not resolve", func() {
+		r := privateendpoint.Probe("hp-private.privatelink.invalid", time.Second)
+		Expect(r.Reachable).To(BeFalse())
+		Expect(r.Addresses).To(BeEmpty())
+		Expect(r.Reason).To(HavePrefix("does not resolve"))
+	})
+})
+
+var _ = Describe("Diagnose", func() {
+	const pendingMessage = `Failed to communicate with cluster: error generating service account token: Post "https://hp-dns.privatelink.centralindia.azmk8s.io:443/api/v1/namespaces": cluster agent disconnected`
+
+	It("tells when the registration command is expected", func() {
+		Expect(privateendpoint.RegistrationPending(pendingMessage)).To(BeTrue())
+		Expect(privateendpoint.RegistrationPending("Waiting for cluster agent to connect")).To(BeTrue())
+		Expect(privateendpoint.RegistrationPending("waiting for API to be available")).To(BeFalse())
+	})
+
+	It("explains a disconnected agent", func() {
+		report := privateendpoint.Diagnose("updating", pendingMessage, []privateendpoint.Condition{
+			{Type: "Provisioned", Status: "True"},
+			{Type: "Ready", Status: "False", Reason: "Disconnected", Message: "Cluster agent is not connected"},
+		})
+		Expect(report).To(ContainSubstring("state: updating"))
+		Expect(report).To(ContainSubstring("message: " + pendingMessage))
+		Expect(report).To(ContainSubstring("condition Ready is False (Disconnected): Cluster agent is not connected"))
+		Expect(report).ToNot(ContainSubstring("condition Provisioned"))
+		Expect(report).To(ContainSubstring("likely cause: the cluster agent is not connected"))
+	})
+
+	It("explains the other stalls", func() {
+		for message, cause := range map[string]string{
+			"x509: certificate signed by unknown authority":               "does not trust the certificate",
+			`dial tcp: lookup hp-dns.privatelink.azmk8s.io: no such host`: "cannot resolve the API endpoint",
+			"dial tcp 10.0.0.4:443: i/o timeout":                          "cannot connect to the API endpoint",
+			"":                                                            "unknown",
+		} {
+			Expect(privateendpoint.Diagnose("updating", "", []privateendpoint.Condition{{Type: "Ready", Status: "Unknown", Message: message}})).
+				To(MatchRegexp("likely cause: .*"+cause), message)
+		}
+	})
+})
@@ -0,0 +1,196 @@
+// Package proxy verifies that Rancher and its operators route their requests through the squid proxy of the proxy mode: it parses
+// the access log of squid, matches the requested hosts against the cloud API hosts and the NO_PROXY ranges, and compares the proxy
+// variables of the operator pods with the values of the Rancher chart.
+package proxy
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/egress"
+)
+
+// Entry is a request logged by squid, in its native access log format
+type Entry struct {
+	Time   time.Time
+	Client string
+	// Result is the squid result code and HTTP status, e.g. TCP_TUNNEL/200
+	Result string
+	Method string
+	// Host is the requested host, without port
+	Host string
+}
+
+func (e Entry) String() string {
+	return fmt.Sprintf("%s %s %s %s %s", e.Time.UTC().Format(time.RFC3339), e.Client, e.Result, e.Method, e.Host)
+}
+
+// ParseAccessLog parses the native access log of squid; the lines which are not requests are ignored
+func ParseAccessLog(log string) []Entry {
+	var entries []Entry
+	for _, line := range strings.Split(log, "\n") {
+		if entry, ok := parseLine(line); ok {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// parseLine parses "time elapsed client result bytes method URL user hierarchy type"
+func parseLine(line string) (Entry, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 7 {
+		return Entry{}, false
+	}
+	seconds, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return Entry{}, false
+	}
+	host := requestHost(fields[5], fields[6])
+	if host == "" {
+		return Entry{}, false
+	}
+	return Entry{
+		Time:   time.Unix(0, int64(seconds*float64(time.Second))),
+		Client: fields[2],
+		Result: fields[3],
+		Method: fields[5],
+		Host:   host,
+	}, true
+}
+
+// requestHost returns the host of a request: CONNECT requests log host:port, the others the URL
+func requestHost(method, target string) string {
+	if method == "CONNECT" {
+		if host, _, err := net.SplitHostPort(target); err == nil {
+			return strings.ToLower(host)
+		}
+		return strings.ToLower(target)
+	}
+	u, err := url.Parse(target)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// NoProxyMatches reports whether host is excluded from the proxy by noProxy, a comma separated list of IPs, CIDRs and domains;
+// a domain matches itself and its subdomains, with or without a leading dot
+func NoProxyMatches(noProxy, host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	ip := net.ParseIP(host)
+	for _, entry := range strings.Split(noProxy, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		switch {
+		case entry == "":
+			continue
+		case entry == "*":
+			return true
+		case strings.Contains(entry, "/"):
+			if _, cidr, err := net.ParseCIDR(entry); err == nil && ip != nil && cidr.Contains(ip) {
+				return true
+			}
+		case net.ParseIP(entry) != nil:
+			if ip != nil && ip.Equal(net.ParseIP(entry)) {
+				return true
+			}
+		default:
+			domain := strings.TrimPrefix(entry, ".")
+			if host == domain || strings.HasSuffix(host, "."+domain) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Routing is what the access log of the proxy tells about the routing of the requests
+type Routing struct {
+	// Missing are the cloud API hosts no request was proxied to
+	Missing []string
+	// Excluded are the proxied requests to hosts of the NO_PROXY ranges, which must go direct
+	Excluded []Entry
+}
+
+// Verify checks the entries of the access log against the cloud API hosts, where '*' matches a single label, and the NO_PROXY ranges
+func Verify(entries []Entry, hosts []string, noProxy string) Routing {
+	var routing Routing
+	for _, host := range hosts {
+		pattern := regexp.MustCompile("(?i)" + egress.HostPattern(host))
+		found := false
+		for _, entry := range entries {
+			if pattern.MatchString(entry.Host) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			routing.Missing = append(routing.Missing, host)
+		}
+	}
+	for _, entry := range entries {
+		if NoProxyMatches(noProxy, entry.Host) {
+			routing.Excluded = append(routing.Excluded, entry)
+		}
+	}
+	return routing
+}
+
+// ChartValues are the proxy values of the Rancher chart, Rancher passes them on to the operator charts
+type ChartValues struct {
+	Proxy   string `json:"proxy,omitempty"`
+	NoProxy string `json:"noProxy,omitempty"`
+}
+
+// Env returns the proxy variables the operator pods must receive
+func (v ChartValues) Env() map[string]string {
+	return map[string]string{
+		"HTTP_PROXY":  v.Proxy,
+		"HTTPS_PROXY": v.Proxy,
+		"NO_PROXY":    v.NoProxy,
+	}
+}
+
+// EnvMismatches returns the proxy variables of env differing from the chart values; the NO_PROXY entries may be in any order
+func EnvMismatches(values ChartValues, env map[string]string) []string {
+	var mismatches []string
+	expected := values.Env()
+	names := make([]string, 0, len(expected))
+	for name := range expected {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		actual, ok := env[name]
+		switch {
+		case !ok:
+			mismatches = append(mismatches, fmt.Sprintf("%s is not set, expected %q", name, expected[name]))
+		case name == "NO_PROXY" && !sameEntries(actual, expected[name]):
+			mismatches = append(mismatches, fmt.Sprintf("%s is %q, expected %q", name, actual, expected[name]))
+		case name != "NO_PROXY" && actual != expected[name]:
+			mismatches = append(mismatches, fmt.Sprintf("%s is %q, expected %q", name, actual, expected[name]))
+		}
+	}
+	return mismatches
+}
+
+func sameEntries(a, b string) bool {
+	return strings.Join(entries(a), ",") == strings.Join(entries(b), ",")
+}
+
+func entries(list string) []string {
+	var result []string
+	for _, entry := range strings.Split(list, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			result = append(result, entry)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,116 @@
+/*
+Copyright © 2022 - 2025 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/egress"
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/proxy"
+)
+
+const noProxy = "127.0.0.0/8,10.0.0.0/8,cattle-system.svc,172.16.0.0/12,192.168.0.0/16,.svc,.cluster.local"
+
+const accessLog = `1760000000.123    456 172.17.0.2 TCP_TUNNEL/200 12345 CONNECT management.azure.com:443 - HIER_DIRECT/20.1.2.3 -
+1760000001.000     12 172.17.0.2 TCP_MISS/200 512 GET http://login.microsoftonline.com/common - HIER_DIRECT/20.1.2.4 text/html
+not a request
+1760000002.500      3 172.17.0.2 TCP_TUNNEL/200 42 CONNECT rancher.cattle-system.svc:443 - HIER_DIRECT/10.43.0.10 -
+`
+
+var _ = Describe("ParseAccessLog", func() {
+	It("parses the requests of the native format", func() {
+		entries := proxy.ParseAccessLog(accessLog)
+		Expect(entries).To(HaveLen(3))
+		Expect(entries[0].Time).To(BeTemporally("~", time.Unix(1760000000, 123000000), time.Millisecond))
+		Expect(entries[0].Client).To(Equal("172.17.0.2"))
+		Expect(entries[0].Result).To(Equal("TCP_TUNNEL/200"))
+		Expect(entries[0].Method).To(Equal("CONNECT"))
+		Expect(entries[0].Host).To(Equal("management.azure.com"))
+		Expect(entries[1].Host).To(Equal("login.microsoftonline.com"))
+		Expect(entries[2].Host).To(Equal("rancher.cattle-system.svc"))
+	})
+
+	It("ignores an empty log", func() {
+		Expect(proxy.ParseAccessLog("")).To(BeEmpty())
+	})
+})
+
+var _ = Describe("NoProxyMatches", func() {
+	It("matches the CIDRs and IPs", func() {
+		Expect(proxy.NoProxyMatches(noProxy, "10.43.0.10")).To(BeTrue())
+		Expect(proxy.NoProxyMatches(noProxy, "172.17.0.1")).To(BeTrue())
+		Expect(proxy.NoProxyMatches(noProxy, "20.1.2.3")).To(BeFalse())
+		Expect(proxy.NoProxyMatches("20.1.2.3", "20.1.2.3")).To(BeTrue())
+	})
+
+	It("matches the domains and their subdomains", func() {
+		Expect(proxy.NoProxyMatches(noProxy, "rancher.cattle-system.svc")).To(BeTrue())
+		Expect(proxy.NoProxyMatches(noProxy, "cattle-system.svc")).To(BeTrue())
+		Expect(proxy.NoProxyMatches(noProxy, "kubernetes.default.svc.cluster.local")).To(BeTrue())
+		Expect(proxy.NoProxyMatches(noProxy, "management.azure.com")).To(BeFalse())
+		Expect(proxy.NoProxyMatches("azure.com", "management.azure.com")).To(BeTrue())
+		Expect(proxy.NoProxyMatches("azure.com", "notazure.com")).To(BeFalse())
+	})
+
+	It("matches everything with a wildcard and nothing when empty", func() {
+		Expect(proxy.NoProxyMatches("*", "management.azure.com")).To(BeTrue())
+		Expect(proxy.NoProxyMatches("", "10.43.0.10")).To(BeFalse())
+	})
+})
+
+var _ = Describe("Verify", func() {
+	It("reports the missing cloud API hosts and the proxied NO_PROXY hosts", func() {
+		entries := proxy.ParseAccessLog(accessLog)
+		routing := proxy.Verify(entries, egress.CloudAPIHosts["aks"], noProxy)
+		Expect(routing.Missing).To(BeEmpty())
+		Expect(routing.Excluded).To(HaveLen(1))
+		Expect(routing.Excluded[0].Host).To(Equal("rancher.cattle-system.svc"))
+
+		routing = proxy.Verify(entries, egress.CloudAPIHosts["eks"], noProxy)
+		Expect(routing.Missing).To(Equal([]string{"eks.*.amazonaws.com"}))
+	})
+
+	It("matches the wildcard hosts", func() {
+		entries := []proxy.Entry{{Method: "CONNECT", Host: "eks.us-west-2.amazonaws.com"}}
+		Expect(proxy.Verify(entries, egress.CloudAPIHosts["eks"], noProxy).Missing).To(BeEmpty())
+	})
+})
+
+var _ = Describe("EnvMismatches", func() {
+	values := proxy.ChartValues{Proxy: "http://172.17.0.1:3128", NoProxy: noProxy}
+
+	It("accepts the values of the chart", func() {
+		Expect(proxy.EnvMismatches(values, map[string]string{
+			"HTTP_PROXY":  "http://172.17.0.1:3128",
+			"HTTPS_PROXY": "http://172.17.0.1:3128",
+			"NO_PROXY":    ".svc,.cluster.local,127.0.0.0/8,10.0.0.0/8,cattle-system.svc,172.16.0.0/12,192.168.0.0/16",
+			"OTHER":       "ignored",
+		})).To(BeEmpty())
+	})
+
+	It("reports the missing and differing variables", func() {
+		Expect(proxy.EnvMismatches(values, map[string]string{
+			"HTTP_PROXY": "http://10.0.0.1:3128",
+			"NO_PROXY":   ".svc",
+		})).To(Equal([]string{
+			`HTTPS_PROXY is not set, expected "http://172.17.0.1:3128"`,
+			`HTTP_PROXY is "http://10.0.0.1:3128", expected "http://172.17.0.1:3128"`,
+			`NO_PROXY is ".svc", expected "` + noProxy + `"`,
+		}))
+	})
+})
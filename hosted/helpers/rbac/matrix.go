@@ -0,0 +1,204 @@
+// Package rbac is the role matrix of the hosted cluster operations: which Rancher users, by their global role and their role on a cluster,
+// may create, import, edit, scale, upgrade and delete hosted clusters and see the cloud credentials. The matrix is a declarative table of
+// expectations, the results of the operations run as each user are checked against it.
+package rbac
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Global roles of the Rancher users
+const (
+	Admin           = "admin"
+	RestrictedAdmin = "restricted-admin"
+	User            = "user"
+	UserBase        = "user-base"
+)
+
+// Cluster roles, bound to the user on the cluster under test
+const (
+	ClusterOwner  = "cluster-owner"
+	ClusterMember = "cluster-member"
+	ReadOnly      = "read-only"
+)
+
+// Operation is an operation of the matrix, run as the user of a subject
+type Operation string
+
+const (
+	// CreateCloudCredential creates a cloud credential of the user, and reads it back
+	CreateCloudCredential Operation = "create-cloud-credential"
+	// Create provisions a hosted cluster with the cloud credential of the user
+	Create Operation = "create"
+	// Import imports a hosted cluster with the cloud credential of the user
+	Import Operation = "import"
+	// ViewCloudCredential reads the cloud credential of the admin, which the cluster under test was provisioned with
+	ViewCloudCredential Operation = "view-cloud-credential"
+	// Edit, Scale and Upgrade update the cluster under test
+	Edit    Operation = "edit"
+	Scale   Operation = "scale"
+	Upgrade Operation = "upgrade"
+	// Delete deletes a cluster the user has the cluster role of the subject on
+	Delete Operation = "delete"
+)
+
+// Operations lists all the operations, in the order they run
+var Operations = []Operation{CreateCloudCredential, Create, Import, ViewCloudCredential, Edit, Scale, Upgrade, Delete}
+
+// Global reports whether the outcome of the operation depends on the global role of the user only;
+// such operations run once per global role, for the subject without a cluster role
+func (o Operation) Global() bool {
+	return o == CreateCloudCredential || o == Create || o == Import
+}
+
+// Subject is a user of the matrix: a global role, and optionally a role on the cluster under test
+type Subject struct {
+	GlobalRole  string
+	ClusterRole string
+}
+
+func (s Subject) String() string {
+	if s.ClusterRole == "" {
+		return s.GlobalRole
+	}
+	return s.GlobalRole + "+" + s.ClusterRole
+}
+
+// Operations returns the operations run as the user of the subject
+func (s Subject) Operations() []Operation {
+	var operations []Operation
+	for _, operation := range Operations {
+		if s.ClusterRole == "" || !operation.Global() {
+			operations = append(operations, operation)
+		}
+	}
+	return operations
+}
+
+// Expectation is a row of the matrix: the operations a subject may run, any other operation must be denied
+type Expectation struct {
+	Subject Subject
+	Allowed []Operation
+}
+
+// Allows reports whether the subject of the expectation may run the operation
+func (e Expectation) Allows(operation Operation) bool {
+	for _, allowed := range e.Allowed {
+		if allowed == operation {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	allOperations     = Operations
+	userOperations    = []Operation{CreateCloudCredential, Create, Import}
+	clusterOperations = []Operation{Edit, Scale, Upgrade, Delete}
+)
+
+// Matrix is the expected outcome of each operation for the users of the hosted clusters. The cloud credentials are secrets of the
+// cattle-global-data namespace, only the admins see the ones of other users; the cluster roles grant no access to them.
+var Matrix = []Expectation{
+	{Subject: Subject{GlobalRole: Admin}, Allowed: allOperations},
+	{Subject: Subject{GlobalRole: RestrictedAdmin}, Allowed: allOperations},
+	{Subject: Subject{GlobalRole: User}, Allowed: userOperations},
+	{Subject: Subject{GlobalRole: User, ClusterRole: ClusterOwner}, Allowed: clusterOperations},
+	{Subject: Subject{GlobalRole: User, ClusterRole: ClusterMember}},
+	{Subject: Subject{GlobalRole: User, ClusterRole: ReadOnly}},
+	{Subject: Subject{GlobalRole: UserBase}},
+	{Subject: Subject{GlobalRole: UserBase, ClusterRole: ClusterOwner}, Allowed: clusterOperations},
+}
+
+// Result is the outcome of an operation run as the user of a subject
+type Result struct {
+	Subject   Subject
+	Operation Operation
+	Expected  bool
+	Err       error
+}
+
+// Matches reports whether the outcome of the operation is the expected one: it succeeded when allowed,
+// or Rancher refused it when denied; any other error is a mismatch
+func (r Result) Matches() bool {
+	if r.Expected {
+		return r.Err == nil
+	}
+	return Denied(r.Err)
+}
+
+func (r Result) String() string {
+	got := r.outcome()
+	if r.Err != nil {
+		got = fmt.Sprintf("%s: %v", got, r.Err)
+	}
+	return fmt.Sprintf("%s %s: expected %s, got %s", r.Subject, r.Operation, outcome(r.Expected), got)
+}
+
+// Denied reports whether err is Rancher refusing an operation to the user: forbidden, or not found for the objects the user cannot see
+func Denied(err error) bool {
+	if err == nil {
+		return false
+	}
+	message := strings.ToLower(err.Error())
+	for _, fragment := range []string{"403", "forbidden", "404", "not found", "notfound"} {
+		if strings.Contains(message, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// Mismatches returns the results which do not match the matrix
+func Mismatches(results []Result) []Result {
+	var mismatches []Result
+	for _, result := range results {
+		if !result.Matches() {
+			mismatches = append(mismatches, result)
+		}
+	}
+	return mismatches
+}
+
+// Report is a table of the results, one line per subject with the outcome of each operation it ran
+func Report(results []Result) string {
+	var (
+		subjects []Subject
+		lines    = map[Subject][]string{}
+	)
+	for _, result := range results {
+		if _, ok := lines[result.Subject]; !ok {
+			subjects = append(subjects, result.Subject)
+		}
+		cell := fmt.Sprintf("%s=%s", result.Operation, result.outcome())
+		if !result.Matches() {
+			cell += "(!)"
+		}
+		lines[result.Subject] = append(lines[result.Subject], cell)
+	}
+	var b strings.Builder
+	for _, subject := range subjects {
+		fmt.Fprintf(&b, "%-28s %s\n", subject, strings.Join(lines[subject], " "))
+	}
+	return b.String()
+}
+
+// outcome tells whether the operation was allowed, denied, or failed for another reason
+func (r Result) outcome() string {
+	switch {
+	case r.Err == nil:
+		return "allowed"
+	case Denied(r.Err):
+		return "denied"
+	default:
+		return "failed"
+	}
+}
+
+func outcome(allowed bool) string {
+	if allowed {
+		return "allowed"
+	}
+	return "denied"
+}
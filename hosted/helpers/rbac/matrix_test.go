@@ -0,0 +1,98 @@
+/*
+Copyright © 2022 - 2025 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rbac_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/rbac"
+)
+
+var (
+	forbidden = errors.New(`403 Forbidden : {"code":"Forbidden","message":"clusters.management.cattle.io is forbidden"}`)
+	notFound  = errors.New(`404 Not Found : {"code":"NotFound","message":"clusters.management.cattle.io \"c-abcde\" not found"}`)
+	conflict  = errors.New(`409 Conflict : {"code":"Conflict","message":"the object has been modified"}`)
+)
+
+var _ = Describe("Matrix", func() {
+	It("runs the global operations once per global role", func() {
+		Expect(rbac.Subject{GlobalRole: rbac.User}.Operations()).To(Equal(rbac.Operations))
+		Expect(rbac.Subject{GlobalRole: rbac.User, ClusterRole: rbac.ClusterOwner}.Operations()).To(Equal([]rbac.Operation{
+			rbac.ViewCloudCredential, rbac.Edit, rbac.Scale, rbac.Upgrade, rbac.Delete,
+		}))
+	})
+
+	It("expects a single row per subject", func() {
+		seen := map[rbac.Subject]bool{}
+		for _, expectation := range rbac.Matrix {
+			Expect(seen).NotTo(HaveKey(expectation.Subject))
+			seen[expectation.Subject] = true
+		}
+	})
+
+	It("only lets the admins see the cloud credentials of other users", func() {
+		for _, expectation := range rbac.Matrix {
+			isAdmin := expectation.Subject.GlobalRole == rbac.Admin || expectation.Subject.GlobalRole == rbac.RestrictedAdmin
+			Expect(expectation.Allows(rbac.ViewCloudCredential)).To(Equal(isAdmin), expectation.Subject.String())
+		}
+	})
+
+	It("only lets the cluster owners update clusters they do not own", func() {
+		for _, expectation := range rbac.Matrix {
+			subject := expectation.Subject
+			if subject.GlobalRole == rbac.Admin || subject.GlobalRole == rbac.RestrictedAdmin {
+				continue
+			}
+			for _, operation := range []rbac.Operation{rbac.Edit, rbac.Scale, rbac.Upgrade, rbac.Delete} {
+				Expect(expectation.Allows(operation)).To(Equal(subject.ClusterRole == rbac.ClusterOwner), "%s %s", subject, operation)
+			}
+		}
+	})
+})
+
+var _ = Describe("Result", func() {
+	user := rbac.Subject{GlobalRole: rbac.User, ClusterRole: rbac.ReadOnly}
+
+	DescribeTable("matches the expectation",
+		func(expected bool, err error, matches bool) {
+			result := rbac.Result{Subject: user, Operation: rbac.Edit, Expected: expected, Err: err}
+			Expect(result.Matches()).To(Equal(matches))
+		},
+		Entry("allowed and succeeded", true, nil, true),
+		Entry("allowed but forbidden", true, forbidden, false),
+		Entry("denied and forbidden", false, forbidden, true),
+		Entry("denied and not found", false, notFound, true),
+		Entry("denied but succeeded", false, nil, false),
+		Entry("denied but failed for another reason", false, conflict, false),
+	)
+
+	It("tells the outcome of the mismatches", func() {
+		results := []rbac.Result{
+			{Subject: user, Operation: rbac.ViewCloudCredential, Expected: false, Err: notFound},
+			{Subject: user, Operation: rbac.Edit, Expected: false, Err: nil},
+			{Subject: user, Operation: rbac.Scale, Expected: false, Err: conflict},
+		}
+		mismatches := rbac.Mismatches(results)
+		Expect(mismatches).To(HaveLen(2))
+		Expect(mismatches[0].String()).To(Equal("user+read-only edit: expected denied, got allowed"))
+		Expect(mismatches[1].String()).To(HavePrefix("user+read-only scale: expected denied, got failed: 409 Conflict"))
+
+		Expect(rbac.Report(results)).To(Equal(
+			"user+read-only               view-cloud-credential=denied edit=allowed(!) scale=failed(!)\n"))
+	})
+})
@@ -15,6 +15,8 @@ import (
 const (
 	Timeout        = 30 * time.Minute
 	CattleSystemNS = "cattle-system"
+	// QaseCasePending is the Qase case ID of the specs which have no test case in Qase yet, they are not reported
+	QaseCasePending int64 = -1
 )
 
 var (
@@ -32,10 +34,8 @@ var (
 	}()
 	RancherFullVersion        = os.Getenv("RANCHER_VERSION")
 	RancherUpgradeFullVersion = os.Getenv("RANCHER_UPGRADE_VERSION")
+	K3sUpgradeVersion         = os.Getenv("INSTALL_K3S_UPGRADE_VERSION")
 	Kubeconfig                = os.Getenv("KUBECONFIG")
-	DownstreamKubeconfig      = func(clusterName string) string {
-		return fmt.Sprintf("%s_KUBECONFIG", clusterName)
-	}
 	K8sUpgradedMinorVersion   = os.Getenv("K8S_UPGRADE_MINOR_VERSION")
 	DownstreamK8sMinorVersion = os.Getenv("DOWNSTREAM_K8S_MINOR_VERSION")
 	IsImport                  = func() bool {
@@ -0,0 +1,74 @@
+package workload
+
+import (
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DenyAllName is the name of the NetworkPolicy denying all the ingress traffic to the app
+	DenyAllName = Name + "-deny-all"
+	// AllowProbeName is the name of the NetworkPolicy letting the allowed probes in
+	AllowProbeName = Name + "-allow-probe"
+	// AllowedLabel marks the pods of the probes which AllowProbeName lets in
+	AllowedLabel = "hosted-providers-e2e.cattle.io/allowed"
+)
+
+// DenyAllIngress returns the NetworkPolicy denying all the ingress traffic to the app: it selects the pods of the app without any ingress rule
+func DenyAllIngress(o Options) *networkingv1.NetworkPolicy {
+	return &networkingv1.NetworkPolicy{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "networking.k8s.io/v1", Kind: "NetworkPolicy"},
+		ObjectMeta: metav1.ObjectMeta{Name: DenyAllName, Namespace: o.Namespace, Labels: labels()},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: labels()},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+		},
+	}
+}
+
+// AllowProbeIngress returns the NetworkPolicy letting the allowed probes of the namespace reach the app; the policies are additive,
+// so the other pods are still denied by DenyAllIngress
+func AllowProbeIngress(o Options) *networkingv1.NetworkPolicy {
+	return &networkingv1.NetworkPolicy{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "networking.k8s.io/v1", Kind: "NetworkPolicy"},
+		ObjectMeta: metav1.ObjectMeta{Name: AllowProbeName, Namespace: o.Namespace, Labels: labels()},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: labels()},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{{
+				From: []networkingv1.NetworkPolicyPeer{{
+					PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{AllowedLabel: "true"}},
+				}},
+			}},
+		},
+	}
+}
+
+// PolicyProbe is a probe of the NetworkPolicies of the app
+type PolicyProbe struct {
+	// Name of the Job, unique in the namespace
+	Name string
+	// Allowed labels the pods of the probe so that AllowProbeIngress lets them in
+	Allowed bool
+	// Reachable is whether the probe expects to reach the app
+	Reachable bool
+}
+
+// PolicyProbeJob returns the Job which succeeds when the app is reachable from each URL as the probe expects; it is retried
+// so that the probe outlives the delay of the policy engine. The URLs must not need DNS, whose failures would pass for denied traffic.
+func PolicyProbeJob(o Options, p PolicyProbe, urls ...string) *batchv1.Job {
+	var checks []string
+	for _, url := range urls {
+		if p.Reachable {
+			checks = append(checks, fetchToken(o, url))
+		} else {
+			checks = append(checks, fmt.Sprintf("! wget -qO- -T 5 %s", url))
+		}
+	}
+	job := probeJob(o, p.Name, checks)
+	job.Spec.Template.Labels[AllowedLabel] = fmt.Sprint(p.Allowed)
+	return job
+}
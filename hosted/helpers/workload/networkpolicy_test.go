@@ -0,0 +1,70 @@
+/*
+Copyright © 2022 - 2025 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/workload"
+)
+
+var _ = Describe("NetworkPolicy", func() {
+	o := workload.Options{Namespace: "policy", Token: "abc123", EphemeralStorage: true}.WithDefaults()
+
+	It("serves the token from an emptyDir when the storage is ephemeral", func() {
+		volume := workload.Deployment(o).Spec.Template.Spec.Volumes[0]
+		Expect(volume.PersistentVolumeClaim).To(BeNil())
+		Expect(volume.EmptyDir).ToNot(BeNil())
+	})
+
+	It("denies all the ingress traffic to the app", func() {
+		policy := workload.DenyAllIngress(o)
+		Expect(policy.Namespace).To(Equal("policy"))
+		Expect(policy.Spec.PodSelector.MatchLabels).To(Equal(workload.Deployment(o).Spec.Template.Labels))
+		Expect(policy.Spec.PolicyTypes).To(Equal([]networkingv1.PolicyType{networkingv1.PolicyTypeIngress}))
+		Expect(policy.Spec.Ingress).To(BeEmpty())
+	})
+
+	It("lets the allowed probes in", func() {
+		policy := workload.AllowProbeIngress(o)
+		Expect(policy.Name).ToNot(Equal(workload.DenyAllIngress(o).Name))
+		Expect(policy.Spec.PodSelector.MatchLabels).To(Equal(workload.Deployment(o).Spec.Template.Labels))
+		peer := policy.Spec.Ingress[0].From[0]
+		Expect(peer.NamespaceSelector).To(BeNil(), "only the probes of the namespace are let in")
+
+		allowed := workload.PolicyProbeJob(o, workload.PolicyProbe{Name: "allowed", Allowed: true, Reachable: true}, "http://10.42.0.5")
+		Expect(allowed.Spec.Template.Labels).To(HaveKeyWithValue(workload.AllowedLabel, "true"))
+		Expect(peer.PodSelector.MatchLabels).To(HaveKeyWithValue(workload.AllowedLabel, "true"))
+
+		denied := workload.PolicyProbeJob(o, workload.PolicyProbe{Name: "denied", Reachable: false}, "http://10.42.0.5")
+		Expect(denied.Spec.Template.Labels).To(HaveKeyWithValue(workload.AllowedLabel, "false"))
+	})
+
+	It("probes the app as expected", func() {
+		reachable := workload.PolicyProbeJob(o, workload.PolicyProbe{Name: "open", Reachable: true}, "http://10.42.0.5", "http://10.43.0.10")
+		Expect(reachable.Name).To(Equal("open"))
+		Expect(reachable.Spec.Template.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+		Expect(reachable.Spec.Template.Spec.Containers[0].Command[2]).To(Equal(
+			"wget -qO- -T 5 http://10.42.0.5 | grep -qx abc123 && wget -qO- -T 5 http://10.43.0.10 | grep -qx abc123"))
+
+		unreachable := workload.PolicyProbeJob(o, workload.PolicyProbe{Name: "denied"}, "http://10.42.0.5", "http://10.43.0.10")
+		Expect(unreachable.Spec.Template.Spec.Containers[0].Command[2]).To(Equal(
+			"! wget -qO- -T 5 http://10.42.0.5 && ! wget -qO- -T 5 http://10.43.0.10"))
+		Expect(*unreachable.Spec.BackoffLimit).To(BeNumerically(">", 0), "the probe outlives the delay of the policy engine")
+	})
+})
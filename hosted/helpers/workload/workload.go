@@ -0,0 +1,252 @@
+// Package workload builds the smoke test app deployed to the downstream clusters: an nginx Deployment serving a token from a PVC,
+// a ClusterIP and a LoadBalancer Service in front of it, and a Job probing it from another node. It also tells whether each piece is functional.
+// The NetworkPolicies isolating the app, and the probes telling whether they are enforced, are built here as well.
+package workload
+
+import (
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
+)
+
+const (
+	// Name of all the objects of the app, they live in their own namespace
+	Name = "hp-smoke"
+	// ProbeName is the name of the Job probing the app
+	ProbeName = Name + "-probe"
+	// AppLabel selects the pods of the app
+	AppLabel = "app.kubernetes.io/name"
+
+	DefaultServerImage = "nginx:stable-alpine"
+	DefaultProbeImage  = "busybox:stable"
+	DefaultStorageSize = "1Gi"
+)
+
+// Options of the app; the zero value deploys it with the defaults of the cluster
+type Options struct {
+	Namespace string
+	// Token is served by the app, it is written to the PVC by an init container
+	Token       string
+	ServerImage string
+	ProbeImage  string
+	// StorageClass of the PVC, the default StorageClass of the cluster when empty
+	StorageClass string
+	// StorageSize of the PVC, it must be at least the minimum size of the provider disks
+	StorageSize string
+	// LoadBalancerAnnotations are set on the LoadBalancer Service, e.g. to let the cloud controller create the load balancer
+	LoadBalancerAnnotations map[string]string
+	// SkipLoadBalancer does not create the LoadBalancer Service, e.g. when the load balancers cannot be reached from the test runner
+	SkipLoadBalancer bool
+	// EphemeralStorage serves the token from an emptyDir instead of the PVC, for the checks which are not about storage
+	EphemeralStorage bool
+}
+
+// WithDefaults returns the options with the unset values defaulted
+func (o Options) WithDefaults() Options {
+	if o.Namespace == "" {
+		o.Namespace = Name
+	}
+	if o.Token == "" {
+		o.Token = Name
+	}
+	if o.ServerImage == "" {
+		o.ServerImage = DefaultServerImage
+	}
+	if o.ProbeImage == "" {
+		o.ProbeImage = DefaultProbeImage
+	}
+	if o.StorageSize == "" {
+		o.StorageSize = DefaultStorageSize
+	}
+	return o
+}
+
+func labels() map[string]string {
+	return map[string]string{AppLabel: Name}
+}
+
+// Namespace returns the namespace of the app
+func Namespace(o Options) *corev1.Namespace {
+	return &corev1.Namespace{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Namespace"},
+		ObjectMeta: metav1.ObjectMeta{Name: o.Namespace},
+	}
+}
+
+// PersistentVolumeClaim returns the PVC the app serves its token from
+func PersistentVolumeClaim(o Options) *corev1.PersistentVolumeClaim {
+	pvc := &corev1.PersistentVolumeClaim{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "PersistentVolumeClaim"},
+		ObjectMeta: metav1.ObjectMeta{Name: Name, Namespace: o.Namespace, Labels: labels()},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(o.StorageSize)},
+			},
+		},
+	}
+	if o.StorageClass != "" {
+		pvc.Spec.StorageClassName = pointer.String(o.StorageClass)
+	}
+	return pvc
+}
+
+// Deployment returns the nginx Deployment serving the token; its init container writes the token to the PVC, so that a served token proves the volume is writable;
+// with EphemeralStorage the token is written to an emptyDir instead
+func Deployment(o Options) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: Name, Namespace: o.Namespace, Labels: labels()},
+		Spec: appsv1.DeploymentSpec{
+			// the PVC is ReadWriteOnce
+			Replicas: pointer.Int32(1),
+			Strategy: appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType},
+			Selector: &metav1.LabelSelector{MatchLabels: labels()},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels()},
+				Spec: corev1.PodSpec{
+					InitContainers: []corev1.Container{{
+						Name:         "write-token",
+						Image:        o.ProbeImage,
+						Command:      []string{"sh", "-c", fmt.Sprintf("echo %s > /data/index.html", o.Token)},
+						VolumeMounts: []corev1.VolumeMount{{Name: "data", MountPath: "/data"}},
+					}},
+					Containers: []corev1.Container{{
+						Name:  "nginx",
+						Image: o.ServerImage,
+						Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 80}},
+						ReadinessProbe: &corev1.Probe{
+							ProbeHandler: corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Path: "/", Port: intstr.FromString("http")}},
+						},
+						VolumeMounts: []corev1.VolumeMount{{Name: "data", MountPath: "/usr/share/nginx/html", ReadOnly: true}},
+					}},
+					Volumes: []corev1.Volume{{Name: "data", VolumeSource: dataVolumeSource(o)}},
+				},
+			},
+		},
+	}
+}
+
+func dataVolumeSource(o Options) corev1.VolumeSource {
+	if o.EphemeralStorage {
+		return corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}
+	}
+	return corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: Name}}
+}
+
+// Service returns the Service of type serviceType in front of the app, the LoadBalancer Service is named after its type
+func Service(o Options, serviceType corev1.ServiceType) *corev1.Service {
+	svc := &corev1.Service{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+		ObjectMeta: metav1.ObjectMeta{Name: Name, Namespace: o.Namespace, Labels: labels()},
+		Spec: corev1.ServiceSpec{
+			Type:     serviceType,
+			Selector: labels(),
+			Ports:    []corev1.ServicePort{{Name: "http", Port: 80, TargetPort: intstr.FromString("http")}},
+		},
+	}
+	if serviceType == corev1.ServiceTypeLoadBalancer {
+		svc.Name = Name + "-lb"
+		svc.Annotations = o.LoadBalancerAnnotations
+	}
+	return svc
+}
+
+// ProbeJob returns the Job fetching the token from each URL; when avoidNode is set, it never runs on that node (the node of the app)
+// so that the probe goes through the pod network between nodes
+func ProbeJob(o Options, avoidNode string, urls ...string) *batchv1.Job {
+	var checks []string
+	for _, url := range urls {
+		checks = append(checks, fetchToken(o, url))
+	}
+	job := probeJob(o, ProbeName, checks)
+	if avoidNode != "" {
+		job.Spec.Template.Spec.Affinity = &corev1.Affinity{
+			NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+						MatchExpressions: []corev1.NodeSelectorRequirement{{
+							Key:      corev1.LabelHostname,
+							Operator: corev1.NodeSelectorOpNotIn,
+							Values:   []string{avoidNode},
+						}},
+					}},
+				},
+			},
+		}
+	}
+	return job
+}
+
+func fetchToken(o Options, url string) string {
+	return fmt.Sprintf("wget -qO- -T 5 %s | grep -qx %s", url, o.Token)
+}
+
+// probeJob returns a Job running all the checks; it is retried so that a probe outlives a network which is still converging
+func probeJob(o Options, name string, checks []string) *batchv1.Job {
+	return &batchv1.Job{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "batch/v1", Kind: "Job"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: o.Namespace, Labels: labels()},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: pointer.Int32(5),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{AppLabel: ProbeName}},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{{
+						Name:    "probe",
+						Image:   o.ProbeImage,
+						Command: []string{"sh", "-c", strings.Join(checks, " && ")},
+					}},
+				},
+			},
+		},
+	}
+}
+
+// DeploymentAvailable returns whether all the replicas of the Deployment are updated and available
+func DeploymentAvailable(deployment *appsv1.Deployment) bool {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	return deployment.Status.ObservedGeneration >= deployment.Generation &&
+		deployment.Status.UpdatedReplicas == replicas &&
+		deployment.Status.AvailableReplicas == replicas
+}
+
+// LoadBalancerAddress returns the IP or hostname of the load balancer of the Service, or an empty string until it is provisioned
+func LoadBalancerAddress(svc *corev1.Service) string {
+	for _, ingress := range svc.Status.LoadBalancer.Ingress {
+		if ingress.IP != "" {
+			return ingress.IP
+		}
+		if ingress.Hostname != "" {
+			return ingress.Hostname
+		}
+	}
+	return ""
+}
+
+// JobResult returns whether the Job is done, and an error if it failed
+func JobResult(job *batchv1.Job) (done bool, err error) {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return true, nil
+		case batchv1.JobFailed:
+			return true, fmt.Errorf("job %s failed: %s", job.Name, condition.Message)
+		}
+	}
+	return false, nil
+}
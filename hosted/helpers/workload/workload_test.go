@@ -0,0 +1,119 @@
+/*
+Copyright © 2022 - 2025 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workload_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/workload"
+)
+
+var _ = Describe("App", func() {
+	It("defaults the options", func() {
+		o := workload.Options{StorageSize: "20Gi"}.WithDefaults()
+		Expect(o.Namespace).To(Equal(workload.Name))
+		Expect(o.ServerImage).To(Equal(workload.DefaultServerImage))
+		Expect(o.ProbeImage).To(Equal(workload.DefaultProbeImage))
+		Expect(o.StorageSize).To(Equal("20Gi"))
+		Expect(o.Token).ToNot(BeEmpty())
+	})
+
+	It("serves the token from the PVC", func() {
+		o := workload.Options{Namespace: "smoke", Token: "abc123", StorageClass: "csi-disk"}.WithDefaults()
+
+		pvc := workload.PersistentVolumeClaim(o)
+		Expect(pvc.Namespace).To(Equal("smoke"))
+		Expect(*pvc.Spec.StorageClassName).To(Equal("csi-disk"))
+		Expect(pvc.Spec.Resources.Requests.Storage().String()).To(Equal("1Gi"))
+		Expect(workload.PersistentVolumeClaim(workload.Options{}.WithDefaults()).Spec.StorageClassName).To(BeNil(), "the default StorageClass is used")
+
+		deployment := workload.Deployment(o)
+		Expect(deployment.Spec.Selector.MatchLabels).To(Equal(deployment.Spec.Template.Labels))
+		podSpec := deployment.Spec.Template.Spec
+		Expect(podSpec.Volumes[0].PersistentVolumeClaim.ClaimName).To(Equal(pvc.Name))
+		Expect(podSpec.InitContainers[0].Command).To(ContainElement(ContainSubstring("echo abc123 > /data/index.html")))
+		Expect(podSpec.Containers[0].VolumeMounts[0].MountPath).To(Equal("/usr/share/nginx/html"))
+	})
+
+	It("puts the services in front of the app", func() {
+		o := workload.Options{LoadBalancerAnnotations: map[string]string{"kubernetes.io/elb.class": "union"}}.WithDefaults()
+		clusterIP := workload.Service(o, corev1.ServiceTypeClusterIP)
+		lb := workload.Service(o, corev1.ServiceTypeLoadBalancer)
+		Expect(clusterIP.Name).ToNot(Equal(lb.Name))
+		Expect(clusterIP.Annotations).To(BeEmpty())
+		Expect(lb.Annotations).To(HaveKeyWithValue("kubernetes.io/elb.class", "union"))
+		Expect(lb.Spec.Selector).To(Equal(workload.Deployment(o).Spec.Template.Labels))
+	})
+
+	It("probes the app from another node", func() {
+		o := workload.Options{Token: "abc123"}.WithDefaults()
+		job := workload.ProbeJob(o, "node-1", "http://10.42.0.5", "http://hp-smoke.hp-smoke.svc")
+		Expect(job.Spec.Template.Spec.Containers[0].Command[2]).To(Equal(
+			"wget -qO- -T 5 http://10.42.0.5 | grep -qx abc123 && wget -qO- -T 5 http://hp-smoke.hp-smoke.svc | grep -qx abc123"))
+		term := job.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0]
+		Expect(term.MatchExpressions[0].Values).To(Equal([]string{"node-1"}))
+
+		Expect(workload.ProbeJob(o, "", "http://10.42.0.5").Spec.Template.Spec.Affinity).To(BeNil())
+	})
+})
+
+var _ = Describe("Status", func() {
+	It("tells when the deployment is available", func() {
+		deployment := &appsv1.Deployment{Spec: appsv1.DeploymentSpec{Replicas: pointer.Int32(1)}}
+		deployment.Generation = 2
+		deployment.Status = appsv1.DeploymentStatus{ObservedGeneration: 1, UpdatedReplicas: 1, AvailableReplicas: 1}
+		Expect(workload.DeploymentAvailable(deployment)).To(BeFalse())
+		deployment.Status.ObservedGeneration = 2
+		Expect(workload.DeploymentAvailable(deployment)).To(BeTrue())
+		deployment.Status.AvailableReplicas = 0
+		Expect(workload.DeploymentAvailable(deployment)).To(BeFalse())
+	})
+
+	It("returns the load balancer address", func() {
+		svc := &corev1.Service{}
+		Expect(workload.LoadBalancerAddress(svc)).To(BeEmpty())
+		svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{Hostname: "a1b2.elb.amazonaws.com"}}
+		Expect(workload.LoadBalancerAddress(svc)).To(Equal("a1b2.elb.amazonaws.com"))
+		svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "34.1.2.3"}}
+		Expect(workload.LoadBalancerAddress(svc)).To(Equal("34.1.2.3"))
+	})
+
+	It("returns the result of the probe", func() {
+		job := &batchv1.Job{}
+		job.Name = workload.ProbeName
+		done, err := workload.JobResult(job)
+		Expect(done).To(BeFalse())
+		Expect(err).To(BeNil())
+
+		job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionFalse}}
+		done, _ = workload.JobResult(job)
+		Expect(done).To(BeFalse())
+
+		job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "BackoffLimitExceeded"}}
+		done, err = workload.JobResult(job)
+		Expect(done).To(BeTrue())
+		Expect(err).To(MatchError(ContainSubstring("BackoffLimitExceeded")))
+
+		job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+		done, err = workload.JobResult(job)
+		Expect(done).To(BeTrue())
+		Expect(err).To(BeNil())
+	})
+})
@@ -0,0 +1,34 @@
+/*
+Copyright © 2022 - 2025 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup_restore_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/rancher-sandbox/ele-testhelpers/kubectl"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+var _ = Describe("BackupRestoreMigrationImport", Label("migration"), func() {
+	k := kubectl.New()
+
+	It("Do a backup on the current Rancher and restore it on the upgraded Rancher", func() {
+		GinkgoLogr.Info(fmt.Sprintf("Migrating Rancher from %s to %s", helpers.RancherFullVersion, helpers.RancherUpgradeFullVersion))
+		f := newFixture()
+		MigrationBackupRestoreChecks(f, k)
+	})
+})
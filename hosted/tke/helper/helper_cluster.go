@@ -1,12 +1,19 @@
 package helper
 
 import (
+	"encoding/json"
 	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 	"github.com/rancher-sandbox/ele-testhelpers/tools"
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
 	"github.com/rancher/shepherd/clients/rancher"
@@ -15,6 +22,10 @@ import (
 	"github.com/rancher/shepherd/extensions/clusters/tke"
 	"github.com/rancher/shepherd/pkg/config"
 	namegen "github.com/rancher/shepherd/pkg/namegenerator"
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common"
+	tcerr "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/errors"
+	tchttp "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/http"
+	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/profile"
 )
 
 // CreateTKEHostedCluster is a helper function that creates an TKE hosted cluster
@@ -39,6 +50,29 @@ func CreateTKEHostedCluster(client *rancher.Client, displayName, cloudCredential
 	return tke.CreateTKEHostedCluster(client, displayName, cloudCredentialID, tkeClusterConfig, false, false, false, false, nil)
 }
 
+// ImportTKEHostedCluster imports the TKE cluster clusterID of region into Rancher
+func ImportTKEHostedCluster(client *rancher.Client, displayName, cloudCredentialID, clusterID, region string) (*management.Cluster, error) {
+	cluster := &management.Cluster{
+		DockerRootDir: "/var/lib/docker",
+		TKEConfig: &management.TKEClusterConfigSpec{
+			TKECredentialSecret: cloudCredentialID,
+			ClusterID:           clusterID,
+			Imported:            true,
+			Region:              region,
+			ClusterBasicSettings: &management.ClusterBasicSettings{
+				ClusterName: displayName,
+			},
+		},
+		Name: displayName,
+	}
+
+	clusterResp, err := client.Management.Cluster.Create(cluster)
+	if err != nil {
+		return nil, err
+	}
+	return clusterResp, err
+}
+
 func ListTKEAllVersions(client *rancher.Client) (allVersions []string, err error) {
 	serverVersion, err := helpers.GetRancherServerVersion(client)
 	if err != nil {
@@ -60,6 +94,39 @@ func ListTKEAllVersions(client *rancher.Client) (allVersions []string, err error
 	return helpers.FilterUIUnsupportedVersions(allVersions, client), nil
 }
 
+// ListTKEAvailableVersions lists the UI supported TKE versions the cluster can be upgraded to, latest first;
+// TKE only upgrades one minor version at a time, so the versions of later minors are skipped
+func ListTKEAvailableVersions(client *rancher.Client, cluster *management.Cluster) (availableVersions []string, err error) {
+	currentVersion, err := semver.NewVersion(cluster.Version.GitVersion)
+	if err != nil {
+		return
+	}
+	allVersions, err := ListTKEAllVersions(client)
+	if err != nil {
+		return
+	}
+
+	// the vendor suffix of the versions is ignored, it is not relevant to the upgrade path
+	current := semver.New(currentVersion.Major(), currentVersion.Minor(), currentVersion.Patch(), "", "")
+	var validVersions []*semver.Version
+	for _, version := range allVersions {
+		v, err := semver.NewVersion(version)
+		if err != nil {
+			continue
+		}
+		if v.Minor() > current.Minor()+1 || !semver.New(v.Major(), v.Minor(), v.Patch(), "", "").GreaterThan(current) {
+			continue
+		}
+		validVersions = append(validVersions, v)
+	}
+
+	sort.Sort(sort.Reverse(semver.Collection(validVersions)))
+	for _, v := range validVersions {
+		availableVersions = append(availableVersions, v.Original())
+	}
+	return
+}
+
 // GetK8sVersion returns the k8s version to be used by the test;
 // this value can either be a variant of envvar DOWNSTREAM_K8S_MINOR_VERSION or the highest available version
 // or second-highest minor version in case of upgrade scenarios
@@ -274,3 +341,431 @@ func DeleteNodePool(cluster *management.Cluster, client *rancher.Client, wait, c
 	}
 	return cluster, nil
 }
+
+// UpdateCluster is a generic function to update a cluster
+func UpdateCluster(cluster *management.Cluster, client *rancher.Client, updateFunc func(*management.Cluster)) (*management.Cluster, error) {
+	upgradedCluster := cluster
+
+	updateFunc(upgradedCluster)
+
+	return client.Management.Cluster.Update(cluster, &upgradedCluster)
+}
+
+// <==============================TKE on Tencent Cloud==============================>
+
+// tkeCloudRequest calls the TKE API action with params and decodes the response into result;
+// it is authenticated with TENCENT_ACCESS_KEY_ID and TENCENT_ACCESS_KEY_SECRET
+func tkeCloudRequest(region, action string, params, result interface{}) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	request := tchttp.NewCommonRequest("tke", "2018-05-25", action)
+	if err = request.SetActionParameters(body); err != nil {
+		return err
+	}
+	response := tchttp.NewCommonResponse()
+	credential := common.NewCredential(os.Getenv("TENCENT_ACCESS_KEY_ID"), os.Getenv("TENCENT_ACCESS_KEY_SECRET"))
+	if err = common.NewCommonClient(credential, region, profile.NewClientProfile()).Send(request, response); err != nil {
+		return err
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(response.GetBody(), &struct{ Response interface{} }{Response: result})
+}
+
+type tkeTag struct {
+	Key   string
+	Value string
+}
+
+type tkeTagSpecification struct {
+	ResourceType string
+	Tags         []tkeTag
+}
+
+type tkeDataDisk struct {
+	DiskType string
+	DiskSize int64
+}
+
+// tkeCreateClusterRequest is the request of the CreateCluster API; the nodes are added afterwards with a node pool
+type tkeCreateClusterRequest struct {
+	ClusterType         string
+	ClusterCIDRSettings struct {
+		ClusterCIDR               string
+		IgnoreClusterCIDRConflict bool
+		MaxNodePodNum             int64 `json:",omitempty"`
+		MaxClusterServiceNum      int64 `json:",omitempty"`
+		ServiceCIDR               string
+		EniSubnetIds              []string `json:",omitempty"`
+		IgnoreServiceCIDRConflict bool
+	}
+	ClusterBasicSettings struct {
+		ClusterOs               string
+		ClusterVersion          string
+		ClusterName             string
+		ClusterDescription      string
+		VpcId                   string
+		ProjectId               int64
+		ClusterLevel            string                `json:",omitempty"`
+		TagSpecification        []tkeTagSpecification `json:",omitempty"`
+		AutoUpgradeClusterLevel struct {
+			IsAutoUpgrade bool
+		}
+	}
+	ClusterAdvancedSettings struct {
+		IPVS                    bool
+		ContainerRuntime        string `json:",omitempty"`
+		RuntimeVersion          string `json:",omitempty"`
+		NodeNameType            string `json:",omitempty"`
+		NetworkType             string `json:",omitempty"`
+		KubeProxyMode           string `json:",omitempty"`
+		VpcCniType              string `json:",omitempty"`
+		DeletionProtection      bool
+		AuditEnabled            bool
+		EnableCustomizedPodCIDR bool
+		BasePodNumber           int64 `json:",omitempty"`
+		IsDualStack             bool
+	}
+}
+
+// tkeCreateClusterNodePoolRequest is the request of the CreateClusterNodePool API,
+// AutoScalingGroupPara and LaunchConfigurePara are JSON documents of the Auto Scaling API
+type tkeCreateClusterNodePoolRequest struct {
+	ClusterId            string
+	Name                 string
+	AutoScalingGroupPara string
+	LaunchConfigurePara  string
+	EnableAutoscale      bool
+	NodePoolOs           string `json:",omitempty"`
+	OsCustomizeType      string `json:",omitempty"`
+	Tags                 []tkeTag
+}
+
+// newTKECreateClusterRequest converts the tkeClusterConfig used for provisioning to a CreateCluster request
+func newTKECreateClusterRequest(tkeClusterConfig tke.ClusterConfig, tags map[string]string) tkeCreateClusterRequest {
+	var request tkeCreateClusterRequest
+	if basic := tkeClusterConfig.ClusterBasicSettings; basic != nil {
+		request.ClusterType = basic.ClusterType
+		request.ClusterBasicSettings.ClusterOs = basic.ClusterOs
+		request.ClusterBasicSettings.ClusterVersion = basic.ClusterVersion
+		request.ClusterBasicSettings.ClusterName = basic.ClusterName
+		request.ClusterBasicSettings.ClusterDescription = basic.ClusterDescription
+		request.ClusterBasicSettings.VpcId = basic.VpcID
+		request.ClusterBasicSettings.ProjectId = basic.ProjectID
+		request.ClusterBasicSettings.ClusterLevel = basic.ClusterLevel
+		request.ClusterBasicSettings.AutoUpgradeClusterLevel.IsAutoUpgrade = basic.IsAutoUpgrade
+	}
+	if request.ClusterType == "" {
+		request.ClusterType = "MANAGED_CLUSTER"
+	}
+	if len(tags) > 0 {
+		request.ClusterBasicSettings.TagSpecification = []tkeTagSpecification{{ResourceType: "cluster", Tags: toTKETags(tags)}}
+	}
+	if cidr := tkeClusterConfig.ClusterCIDRSettings; cidr != nil {
+		request.ClusterCIDRSettings.ClusterCIDR = cidr.ClusterCIDR
+		request.ClusterCIDRSettings.IgnoreClusterCIDRConflict = cidr.IgnoreClusterCIDRConflict
+		request.ClusterCIDRSettings.MaxNodePodNum = cidr.MaxNodePodNum
+		request.ClusterCIDRSettings.MaxClusterServiceNum = cidr.MaxClusterServiceNum
+		request.ClusterCIDRSettings.ServiceCIDR = cidr.ServiceCIDR
+		request.ClusterCIDRSettings.EniSubnetIds = cidr.EniSubnetIDs
+		request.ClusterCIDRSettings.IgnoreServiceCIDRConflict = cidr.IgnoreServiceCIDRConflict
+	}
+	if advanced := tkeClusterConfig.ClusterAdvancedSettings; advanced != nil {
+		request.ClusterAdvancedSettings.IPVS = advanced.IPVS
+		request.ClusterAdvancedSettings.ContainerRuntime = advanced.ContainerRuntime
+		request.ClusterAdvancedSettings.RuntimeVersion = advanced.RuntimeVersion
+		request.ClusterAdvancedSettings.NodeNameType = advanced.NodeNameType
+		request.ClusterAdvancedSettings.NetworkType = advanced.NetworkType
+		request.ClusterAdvancedSettings.KubeProxyMode = advanced.KubeProxyMode
+		request.ClusterAdvancedSettings.VpcCniType = advanced.VpcCniType
+		request.ClusterAdvancedSettings.DeletionProtection = advanced.DeletionProtection
+		request.ClusterAdvancedSettings.AuditEnabled = advanced.AuditEnabled
+		request.ClusterAdvancedSettings.EnableCustomizedPodCIDR = advanced.EnableCustomizedPodCIDR
+		request.ClusterAdvancedSettings.BasePodNumber = advanced.BasePodNumber
+		request.ClusterAdvancedSettings.IsDualStack = advanced.IsDualStack
+	}
+	return request
+}
+
+// newTKECreateClusterNodePoolRequest converts a node pool of the tkeClusterConfig to a CreateClusterNodePool request
+func newTKECreateClusterNodePoolRequest(clusterID string, nodePool tke.NodePoolDetail, tags map[string]string) (tkeCreateClusterNodePoolRequest, error) {
+	scaling := nodePool.AutoScalingGroupPara
+	autoScalingGroupPara, err := json.Marshal(map[string]interface{}{
+		"MaxSize":         scaling.MaxSize,
+		"MinSize":         scaling.MinSize,
+		"DesiredCapacity": scaling.DesiredCapacity,
+		"VpcId":           scaling.VpcID,
+		"SubnetIds":       scaling.SubnetIDs,
+	})
+	if err != nil {
+		return tkeCreateClusterNodePoolRequest{}, err
+	}
+
+	launch := nodePool.LaunchConfigurePara
+	dataDisks := []tkeDataDisk{}
+	for _, disk := range launch.DataDisks {
+		dataDisks = append(dataDisks, tkeDataDisk{DiskType: disk.DiskType, DiskSize: disk.DiskSize})
+	}
+	launchConfigurePara, err := json.Marshal(map[string]interface{}{
+		"InstanceType": launch.InstanceType,
+		"SystemDisk":   tkeDataDisk{DiskType: launch.SystemDisk.DiskType, DiskSize: launch.SystemDisk.DiskSize},
+		"DataDisks":    dataDisks,
+		"InternetAccessible": map[string]interface{}{
+			"InternetChargeType":      launch.InternetChargeType,
+			"InternetMaxBandwidthOut": launch.InternetMaxBandwidthOut,
+			"PublicIpAssigned":        launch.PublicIPAssigned,
+		},
+		"LoginSettings":      map[string]interface{}{"KeyIds": launch.KeyIDs},
+		"SecurityGroupIds":   launch.SecurityGroupIDs,
+		"InstanceChargeType": launch.InstanceChargeType,
+	})
+	if err != nil {
+		return tkeCreateClusterNodePoolRequest{}, err
+	}
+
+	return tkeCreateClusterNodePoolRequest{
+		ClusterId:            clusterID,
+		Name:                 nodePool.Name,
+		AutoScalingGroupPara: string(autoScalingGroupPara),
+		LaunchConfigurePara:  string(launchConfigurePara),
+		EnableAutoscale:      nodePool.EnableAutoscale,
+		NodePoolOs:           nodePool.NodePoolOs,
+		OsCustomizeType:      nodePool.OsCustomizeType,
+		Tags:                 toTKETags(tags),
+	}, nil
+}
+
+func toTKETags(tags map[string]string) []tkeTag {
+	tkeTags := []tkeTag{}
+	for _, key := range slices.Sorted(maps.Keys(tags)) {
+		tkeTags = append(tkeTags, tkeTag{Key: key, Value: tags[key]})
+	}
+	return tkeTags
+}
+
+// CreateTKEClusterOnTencent creates a TKE cluster on Tencent Cloud from the tkeClusterConfig of CATTLE_TEST_CONFIG,
+// adds the node pools of the config and waits until they are ready; it returns the ID of the TKE cluster
+func CreateTKEClusterOnTencent(region, clusterName, k8sVersion string, id int64, tags map[string]string, updateFunc func(clusterConfig *tke.ClusterConfig)) (string, error) {
+	var tkeClusterConfig tke.ClusterConfig
+	config.LoadConfig(tke.TKEClusterConfigConfigurationFileKey, &tkeClusterConfig)
+
+	if tkeClusterConfig.ClusterBasicSettings == nil {
+		tkeClusterConfig.ClusterBasicSettings = &tke.ClusterBasicSettings{}
+	}
+	tkeClusterConfig.ClusterBasicSettings.ClusterName = clusterName
+	tkeClusterConfig.ClusterBasicSettings.ClusterVersion = k8sVersion
+	if tkeClusterConfig.ClusterCIDRSettings == nil {
+		tkeClusterConfig.ClusterCIDRSettings = &tke.ClusterCIDRSettings{}
+	}
+	tkeClusterConfig.ClusterCIDRSettings.ClusterCIDR = fmt.Sprintf("10.%v.0.0/16", id%255)
+	if updateFunc != nil {
+		updateFunc(&tkeClusterConfig)
+	}
+
+	fmt.Println("Creating TKE cluster ...")
+	var created struct {
+		ClusterId string
+	}
+	if err := tkeCloudRequest(region, "CreateCluster", newTKECreateClusterRequest(tkeClusterConfig, tags), &created); err != nil {
+		return "", errors.Wrap(err, "Failed to create cluster")
+	}
+
+	var status string
+	Eventually(func() string {
+		var err error
+		status, err = tkeClusterStatus(region, created.ClusterId)
+		Expect(err).To(BeNil())
+		return status
+	}, tools.SetTimeout(30*time.Minute), 30*time.Second).Should(BeElementOf("Running", "Abnormal"))
+	if status != "Running" {
+		return created.ClusterId, errors.Errorf("TKE cluster %s (%s) is %s", clusterName, created.ClusterId, status)
+	}
+
+	for _, nodePool := range tkeClusterConfig.NodePoolList {
+		request, err := newTKECreateClusterNodePoolRequest(created.ClusterId, nodePool, tags)
+		if err != nil {
+			return created.ClusterId, err
+		}
+		fmt.Println("Creating TKE node pool ...")
+		var createdNodePool struct {
+			NodePoolId string
+		}
+		if err = tkeCloudRequest(region, "CreateClusterNodePool", request, &createdNodePool); err != nil {
+			return created.ClusterId, errors.Wrap(err, "Failed to create node pool")
+		}
+		Eventually(func() string {
+			lifeState, err := tkeNodePoolLifeState(region, created.ClusterId, createdNodePool.NodePoolId)
+			Expect(err).To(BeNil())
+			return lifeState
+		}, tools.SetTimeout(20*time.Minute), 30*time.Second).Should(Equal("normal"))
+	}
+	fmt.Println("Created TKE cluster: ", clusterName, created.ClusterId)
+	return created.ClusterId, nil
+}
+
+// DeleteTKEClusterOnTencent deletes a TKE cluster, terminating its nodes, and waits until it is gone
+func DeleteTKEClusterOnTencent(region, clusterID string) error {
+	fmt.Println("Deleting TKE cluster ...")
+	err := tkeCloudRequest(region, "DeleteCluster", map[string]interface{}{
+		"ClusterId":          clusterID,
+		"InstanceDeleteMode": "terminate",
+		"ResourceDeleteOptions": []map[string]string{
+			{"ResourceType": "CBS", "DeleteMode": "terminate"},
+		},
+	}, nil)
+	if err != nil {
+		var sdkErr *tcerr.TencentCloudSDKError
+		if errors.As(err, &sdkErr) && strings.Contains(sdkErr.GetCode(), "NotFound") {
+			return nil
+		}
+		return errors.Wrap(err, "Failed to delete cluster")
+	}
+
+	Eventually(func() string {
+		status, err := tkeClusterStatus(region, clusterID)
+		Expect(err).To(BeNil())
+		return status
+	}, tools.SetTimeout(30*time.Minute), 30*time.Second).Should(BeEmpty())
+	fmt.Println("Deleted TKE cluster: ", clusterID)
+	return nil
+}
+
+// UpgradeTKEClusterOnTencent upgrades the control plane of a TKE cluster to k8sVersion and waits until it is running again
+func UpgradeTKEClusterOnTencent(region, clusterID, k8sVersion string) error {
+	fmt.Println("Upgrading TKE cluster ...")
+	if err := tkeCloudRequest(region, "UpdateClusterVersion", map[string]interface{}{"ClusterId": clusterID, "DstVersion": k8sVersion}, nil); err != nil {
+		return errors.Wrap(err, "Failed to upgrade cluster")
+	}
+
+	Eventually(func() bool {
+		described, err := describeTKECluster(region, clusterID)
+		Expect(err).To(BeNil())
+		return described != nil && described.ClusterStatus == "Running" && described.ClusterVersion == k8sVersion
+	}, tools.SetTimeout(40*time.Minute), 30*time.Second).Should(BeTrue())
+	fmt.Println("Upgraded TKE cluster: ", clusterID, k8sVersion)
+	return nil
+}
+
+// TKENodePool is the Tencent Cloud view of a TKE node pool
+type TKENodePool struct {
+	NodePoolId      string
+	Name            string
+	LifeState       string
+	DesiredNodesNum int64
+}
+
+// ListTKENodePoolsOnTencent lists the node pools of a TKE cluster
+func ListTKENodePoolsOnTencent(region, clusterID string) ([]TKENodePool, error) {
+	var described struct {
+		NodePoolSet []TKENodePool
+	}
+	if err := tkeCloudRequest(region, "DescribeClusterNodePools", map[string]interface{}{"ClusterId": clusterID}, &described); err != nil {
+		return nil, errors.Wrapf(err, "Failed to list node pools of cluster %s", clusterID)
+	}
+	return described.NodePoolSet, nil
+}
+
+// ScaleTKENodePoolOnTencent changes the desired capacity of a TKE node pool and waits until the nodes are ready
+func ScaleTKENodePoolOnTencent(region, clusterID, nodePoolID string, desiredCapacity int64) error {
+	fmt.Println("Scaling TKE node pool ...")
+	err := tkeCloudRequest(region, "ModifyNodePoolDesiredCapacityAboutAsg", map[string]interface{}{
+		"ClusterId":       clusterID,
+		"NodePoolId":      nodePoolID,
+		"DesiredCapacity": desiredCapacity,
+	}, nil)
+	if err != nil {
+		return errors.Wrap(err, "Failed to scale node pool")
+	}
+
+	Eventually(func() bool {
+		nodePool, err := describeTKENodePool(region, clusterID, nodePoolID)
+		Expect(err).To(BeNil())
+		return nodePool != nil && nodePool.LifeState == "normal" && nodePool.DesiredNodesNum == desiredCapacity
+	}, tools.SetTimeout(20*time.Minute), 30*time.Second).Should(BeTrue())
+	fmt.Println("Scaled TKE node pool: ", nodePoolID, desiredCapacity)
+	return nil
+}
+
+// DeleteTKENodePoolOnTencent deletes a TKE node pool, terminating its nodes, and waits until it is gone
+func DeleteTKENodePoolOnTencent(region, clusterID, nodePoolID string) error {
+	fmt.Println("Deleting TKE node pool ...")
+	err := tkeCloudRequest(region, "DeleteClusterNodePool", map[string]interface{}{
+		"ClusterId":    clusterID,
+		"NodePoolIds":  []string{nodePoolID},
+		"KeepInstance": false,
+	}, nil)
+	if err != nil {
+		var sdkErr *tcerr.TencentCloudSDKError
+		if errors.As(err, &sdkErr) && strings.Contains(sdkErr.GetCode(), "NotFound") {
+			return nil
+		}
+		return errors.Wrap(err, "Failed to delete node pool")
+	}
+
+	Eventually(func() *TKENodePool {
+		nodePool, err := describeTKENodePool(region, clusterID, nodePoolID)
+		Expect(err).To(BeNil())
+		return nodePool
+	}, tools.SetTimeout(20*time.Minute), 30*time.Second).Should(BeNil())
+	fmt.Println("Deleted TKE node pool: ", nodePoolID)
+	return nil
+}
+
+// describeTKENodePool returns the node pool nodePoolID of a TKE cluster, nil if it does not exist
+func describeTKENodePool(region, clusterID, nodePoolID string) (*TKENodePool, error) {
+	nodePools, err := ListTKENodePoolsOnTencent(region, clusterID)
+	if err != nil {
+		return nil, err
+	}
+	for _, nodePool := range nodePools {
+		if nodePool.NodePoolId == nodePoolID {
+			return &nodePool, nil
+		}
+	}
+	return nil, nil
+}
+
+type tkeCluster struct {
+	// ClusterStatus is the status of the cluster, e.g. Creating, Running, Upgrading or Abnormal
+	ClusterStatus  string
+	ClusterVersion string
+}
+
+// describeTKECluster returns a TKE cluster, nil once the cluster is deleted
+func describeTKECluster(region, clusterID string) (*tkeCluster, error) {
+	var described struct {
+		Clusters []tkeCluster
+	}
+	if err := tkeCloudRequest(region, "DescribeClusters", map[string]interface{}{"ClusterIds": []string{clusterID}}, &described); err != nil {
+		return nil, err
+	}
+	if len(described.Clusters) == 0 {
+		return nil, nil
+	}
+	return &described.Clusters[0], nil
+}
+
+// tkeClusterStatus returns the status of a TKE cluster, e.g. Creating, Running or Abnormal; it is empty once the cluster is deleted
+func tkeClusterStatus(region, clusterID string) (string, error) {
+	described, err := describeTKECluster(region, clusterID)
+	if err != nil || described == nil {
+		return "", err
+	}
+	return described.ClusterStatus, nil
+}
+
+// tkeNodePoolLifeState returns the life state of a TKE node pool, e.g. creating or normal
+func tkeNodePoolLifeState(region, clusterID, nodePoolID string) (string, error) {
+	var described struct {
+		NodePool struct {
+			LifeState string
+		}
+	}
+	err := tkeCloudRequest(region, "DescribeClusterNodePoolDetail", map[string]interface{}{"ClusterId": clusterID, "NodePoolId": nodePoolID}, &described)
+	return described.NodePool.LifeState, err
+}
+
+// <==============================TKE on Tencent Cloud(end)==============================>
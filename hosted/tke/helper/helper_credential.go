@@ -0,0 +1,25 @@
+package helper
+
+import (
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+)
+
+// CredentialOperations returns the operations of the cloud credential lifecycle checks on TKE clusters;
+// the cloud credentials of Tencent Cloud hold long-term API keys, whose expiry cannot be simulated
+func CredentialOperations() helpers.CredentialOperations {
+	return helpers.CredentialOperations{Trigger: toggleNodeCount}
+}
+
+// toggleNodeCount scales the node pools of the cluster to their toggled node count, see helpers.ToggledNodeCount
+func toggleNodeCount(client *rancher.Client, cluster *management.Cluster) error {
+	_, err := UpdateCluster(cluster, client, func(upgradedCluster *management.Cluster) {
+		for i := range upgradedCluster.TKEConfig.NodePoolList {
+			autoScalingGroup := upgradedCluster.TKEConfig.NodePoolList[i].AutoScalingGroupPara
+			autoScalingGroup.DesiredCapacity = helpers.ToggledNodeCount(autoScalingGroup.DesiredCapacity)
+		}
+	})
+	return err
+}
@@ -0,0 +1,14 @@
+package helper
+
+import (
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers/workload"
+)
+
+// WorkloadOptions returns the options of the workload checks on TKE clusters: a CBS disk, and a public CLB created by the cloud controller
+func WorkloadOptions() workload.Options {
+	return workload.Options{
+		StorageClass: "cbs",
+		// CBS disks are at least 10GiB
+		StorageSize: "10Gi",
+	}
+}
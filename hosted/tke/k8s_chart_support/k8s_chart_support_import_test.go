@@ -0,0 +1,46 @@
+package k8s_chart_support_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/tke/helper"
+)
+
+var _ = Describe("K8sChartSupportImport", func() {
+	var (
+		cluster *management.Cluster
+		// tkeClusterID is the ID of the cluster on Tencent Cloud, it is needed to import and delete it
+		tkeClusterID string
+	)
+	BeforeEach(func() {
+		var err error
+		tkeClusterID, err = helper.CreateTKEClusterOnTencent(region, clusterName, k8sVersion, 65, helpers.GetCommonMetadataLabels(), nil)
+		Expect(err).To(BeNil())
+
+		cluster, err = helper.ImportTKEHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, tkeClusterID, region)
+		Expect(err).To(BeNil())
+		cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+		Expect(err).To(BeNil())
+
+	})
+	AfterEach(func() {
+		if ctx.ClusterCleanup && cluster != nil {
+			err := helper.DeleteTKEHostCluster(cluster, ctx.RancherAdminClient)
+			Expect(err).To(BeNil())
+			err = helper.DeleteTKEClusterOnTencent(region, tkeClusterID)
+			Expect(err).To(BeNil())
+		} else {
+			fmt.Println("Skipping downstream cluster deletion: ", clusterName)
+		}
+	})
+
+	It("should successfully test k8s chart support import", func() {
+		testCaseID = 65 // Report to Qase
+		commonchecks(ctx.RancherAdminClient, cluster)
+	})
+})
@@ -0,0 +1,43 @@
+package k8s_chart_support_upgrade_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/tke/helper"
+)
+
+var _ = Describe("K8sChartSupportUpgradeImport", func() {
+	var (
+		f *helpers.Fixture
+		// tkeClusterID is the ID of the cluster on Tencent Cloud, it is needed to import and delete it
+		tkeClusterID string
+	)
+	BeforeEach(func() {
+		f = newFixture()
+		f.GuardCost()
+		var err error
+		tkeClusterID, err = helper.CreateTKEClusterOnTencent(region, f.ClusterName, f.K8sVersion, 167, helpers.GetCommonMetadataLabels(), nil)
+		Expect(err).To(BeNil())
+
+		f.Cluster, err = helper.ImportTKEHostedCluster(f.Client, f.ClusterName, ctx.CloudCredID, tkeClusterID, region)
+		Expect(err).To(BeNil())
+		f.AddClusterCleanup(func() {
+			err := helper.DeleteTKEHostCluster(f.Cluster, f.Client)
+			Expect(err).To(BeNil())
+			err = helper.DeleteTKEClusterOnTencent(region, tkeClusterID)
+			Expect(err).To(BeNil())
+		})
+		f.Cluster, err = helpers.WaitUntilClusterIsReady(f.Cluster, f.Client)
+		Expect(err).To(BeNil())
+	})
+	It("should successfully test k8s chart support import in an upgrade scenario", func() {
+		GinkgoLogr.Info(fmt.Sprintf("Testing K8s %s chart support for import on Rancher upgraded from %s to %s", helpers.K8sUpgradedMinorVersion, helpers.RancherFullVersion, helpers.RancherUpgradeFullVersion))
+		f.SetQaseID(167) // Report to Qase
+
+		commonchecks(f, helpers.RancherUpgradeFullVersion, helpers.K8sUpgradedMinorVersion)
+	})
+})
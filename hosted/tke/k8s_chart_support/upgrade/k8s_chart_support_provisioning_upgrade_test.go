@@ -0,0 +1,35 @@
+package k8s_chart_support_upgrade_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/tke/helper"
+)
+
+var _ = Describe("K8sChartSupportUpgradeProvisioning", func() {
+	var f *helpers.Fixture
+	BeforeEach(func() {
+		f = newFixture()
+		f.GuardCost()
+		var err error
+		f.Cluster, err = helper.CreateTKEHostedCluster(f.Client, f.ClusterName, ctx.CloudCredID, f.K8sVersion, 165, nil)
+		Expect(err).To(BeNil())
+		f.AddClusterCleanup(func() {
+			err := helper.DeleteTKEHostCluster(f.Cluster, f.Client)
+			Expect(err).To(BeNil())
+		})
+		f.Cluster, err = helpers.WaitUntilClusterIsReady(f.Cluster, f.Client)
+		Expect(err).To(BeNil())
+	})
+	It("should successfully test k8s chart support provisioning in an upgrade scenario", func() {
+		GinkgoLogr.Info(fmt.Sprintf("Testing K8s %s chart support for provisioning on Rancher upgraded from %s to %s", helpers.K8sUpgradedMinorVersion, helpers.RancherFullVersion, helpers.RancherUpgradeFullVersion))
+
+		f.SetQaseID(165)
+		commonchecks(f, helpers.RancherUpgradeFullVersion, helpers.K8sUpgradedMinorVersion)
+	})
+
+})
@@ -0,0 +1,94 @@
+/*
+Copyright © 2022 - 2025 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package p0_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/tke/helper"
+)
+
+var _ = Describe("P0Import", func() {
+	// tkeClusterID is the ID of the cluster on Tencent Cloud, it is needed to import and delete it
+	var tkeClusterID string
+
+	for _, testData := range []struct {
+		id        int64
+		isUpgrade bool
+		testBody  func(f *helpers.Fixture)
+		testTitle string
+	}{
+		{
+			id:        217,
+			isUpgrade: false,
+			testBody:  p0NodesChecks,
+			testTitle: "should successfully import the cluster & add, delete, scale nodepool",
+		},
+		{
+			id:        218,
+			isUpgrade: true,
+			testBody:  p0UpgradeK8sVersionChecks,
+			testTitle: "should be able to upgrade k8s version of the imported cluster",
+		},
+	} {
+		testData := testData
+		When("a cluster is created", func() {
+			var f *helpers.Fixture
+
+			BeforeEach(func() {
+				tkeClusterID = ""
+				if testData.isUpgrade && helpers.SkipUpgradeTests {
+					Skip(helpers.SkipUpgradeTestsLog)
+				}
+
+				f = helpers.NewFixture(&ctx)
+				f.AddClusterCleanup(func() {
+					if f.Cluster != nil && f.Cluster.ID != "" {
+						GinkgoLogr.Info(fmt.Sprintf("Cleaning up resource cluster: %s %s", f.Cluster.Name, f.Cluster.ID))
+						err := helper.DeleteTKEHostCluster(f.Cluster, f.Client)
+						Expect(err).To(BeNil())
+					}
+					if tkeClusterID != "" {
+						err := helper.DeleteTKEClusterOnTencent(region, tkeClusterID)
+						Expect(err).To(BeNil())
+					}
+				})
+
+				var err error
+				f.K8sVersion, err = helper.GetK8sVersion(f.Client, testData.isUpgrade)
+				Expect(err).To(BeNil())
+				GinkgoLogr.Info(fmt.Sprintf("Using K8s version %s for cluster %s", f.K8sVersion, f.ClusterName))
+				tkeClusterID, err = helper.CreateTKEClusterOnTencent(region, f.ClusterName, f.K8sVersion, testData.id, helpers.GetCommonMetadataLabels(), nil)
+				Expect(err).To(BeNil())
+
+				f.Cluster, err = helper.ImportTKEHostedCluster(f.Client, f.ClusterName, ctx.CloudCredID, tkeClusterID, region)
+				Expect(err).To(BeNil())
+				// WaitUntilClusterIsReady replaces TKEConfig with TKEStatus.UpstreamSpec for imported clusters,
+				// the node pools and version of the imported cluster are only known from the upstream spec
+				f.Cluster, err = helpers.WaitUntilClusterIsReady(f.Cluster, f.Client)
+				Expect(err).To(BeNil())
+				Expect(f.Cluster.TKEConfig.NodePoolList).ToNot(BeEmpty())
+			})
+
+			It(testData.testTitle, func() {
+				testData.testBody(f)
+			})
+		})
+	}
+})
@@ -8,10 +8,6 @@ import (
 	. "github.com/onsi/gomega"
 	. "github.com/rancher-sandbox/qase-ginkgo"
 
-	"github.com/rancher/shepherd/clients/rancher"
-	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
-	namegen "github.com/rancher/shepherd/pkg/namegenerator"
-
 	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
 	tkehelper "github.com/rancher/hosted-providers-e2e/hosted/tke/helper"
 )
@@ -21,10 +17,8 @@ const (
 )
 
 var (
-	ctx         helpers.RancherContext
-	cluster     *management.Cluster
-	clusterName string
-	testCaseID  int64
+	ctx    helpers.RancherContext
+	region = helpers.GetTKERegion()
 )
 
 // go test 入口：注册断言失败处理并启动 Ginkgo
@@ -40,55 +34,51 @@ var _ = SynchronizedBeforeSuite(func() []byte {
 	ctx = helpers.CommonBeforeSuite()
 })
 
-var _ = BeforeEach(func() {
-	cluster = nil
-	clusterName = namegen.AppendRandomString(helpers.ClusterNamePrefix)
-})
-
-var _ = ReportBeforeEach(func(_ SpecReport) { testCaseID = -1 })
-var _ = ReportAfterEach(func(report SpecReport) { Qase(testCaseID, report) })
+var _ = ReportAfterEach(func(report SpecReport) { Qase(helpers.QaseID(report), report) })
 
-func p0UpgradeK8sVersionChecks(c *management.Cluster, client *rancher.Client, name string) {
-	helpers.ClusterIsReadyChecks(c, client, name)
+func p0UpgradeK8sVersionChecks(f *helpers.Fixture) {
+	helpers.ClusterIsReadyChecks(f.Cluster, f.Client, f.ClusterName)
 
 	// tke upgrade k8s version to 1.34.1 is not available yet, it should change to false when it is ready.
-	upgradeTo, err := tkehelper.GetK8sVersion(client, true)
+	var err error
+	f.UpgradeToVersion, err = tkehelper.GetK8sVersion(f.Client, true)
 	Expect(err).To(BeNil())
-	GinkgoLogr.Info(fmt.Sprintf("Upgrading TKE cluster to version %s", upgradeTo))
+	GinkgoLogr.Info(fmt.Sprintf("Upgrading TKE cluster to version %s", f.UpgradeToVersion))
 
 	By("upgrading the TKE cluster", func() {
-		c, err = tkehelper.UpgradeClusterKubernetesVersion(c, upgradeTo, client, true)
+		f.Cluster, err = tkehelper.UpgradeClusterKubernetesVersion(f.Cluster, f.UpgradeToVersion, f.Client, true)
 		Expect(err).To(BeNil())
 	})
 }
 
-func p0NodesChecks(c *management.Cluster, client *rancher.Client, name string) {
-	helpers.ClusterIsReadyChecks(c, client, name)
+func p0NodesChecks(f *helpers.Fixture) {
+	helpers.ClusterIsReadyChecks(f.Cluster, f.Client, f.ClusterName)
+	helpers.WorkloadChecks(f.Cluster, f.Client, tkehelper.WorkloadOptions())
 
-	cfgPools := c.TKEConfig.NodePoolList
+	cfgPools := f.Cluster.TKEConfig.NodePoolList
 	initial := cfgPools[0].AutoScalingGroupPara.DesiredCapacity
 
 	By("scaling up the NodePool", func() {
 		var err error
-		c, err = tkehelper.ScaleNodeGroup(c, client, initial+increaseBy, true, true)
+		f.Cluster, err = tkehelper.ScaleNodeGroup(f.Cluster, f.Client, initial+increaseBy, true, true)
 		Expect(err).To(BeNil())
 	})
 
 	By("scaling down the NodePool", func() {
 		var err error
-		c, err = tkehelper.ScaleNodeGroup(c, client, initial, true, true)
+		f.Cluster, err = tkehelper.ScaleNodeGroup(f.Cluster, f.Client, initial, true, true)
 		Expect(err).To(BeNil())
 	})
 
 	By("adding a NodePool", func() {
 		var err error
-		c, err = tkehelper.AddNodePool(c, increaseBy, client, true, true)
+		f.Cluster, err = tkehelper.AddNodePool(f.Cluster, increaseBy, f.Client, true, true)
 		Expect(err).To(BeNil())
 	})
 
 	By("deleting the NodePool", func() {
 		var err error
-		c, err = tkehelper.DeleteNodePool(c, client, true, true)
+		f.Cluster, err = tkehelper.DeleteNodePool(f.Cluster, f.Client, true, true)
 		Expect(err).To(BeNil())
 	})
 }
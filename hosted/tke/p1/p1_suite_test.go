@@ -0,0 +1,292 @@
+/*
+Copyright © 2022 - 2025 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package p1_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher-sandbox/ele-testhelpers/tools"
+	. "github.com/rancher-sandbox/qase-ginkgo"
+	"github.com/rancher/shepherd/clients/rancher"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	"github.com/rancher/shepherd/extensions/clusters"
+	namegen "github.com/rancher/shepherd/pkg/namegenerator"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/tke/helper"
+)
+
+const (
+	increaseBy = 1
+)
+
+var (
+	ctx         helpers.RancherContext
+	cluster     *management.Cluster
+	clusterName string
+	testCaseID  int64
+	region      = helpers.GetTKERegion()
+)
+
+func TestP1(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "P1 Suite")
+}
+
+var _ = SynchronizedBeforeSuite(func() []byte {
+	helpers.CommonSynchronizedBeforeSuite()
+	return nil
+}, func() {
+	ctx = helpers.CommonBeforeSuite()
+})
+
+var _ = BeforeEach(func() {
+	// Setting this to nil ensures we do not use the `cluster` variable value from another test running in parallel with this one.
+	cluster = nil
+	clusterName = namegen.AppendRandomString(helpers.ClusterNamePrefix)
+})
+
+var _ = ReportBeforeEach(func(report SpecReport) {
+	// Reset case ID
+	testCaseID = -1
+})
+
+var _ = ReportAfterEach(func(report SpecReport) {
+	// Add result in Qase if asked
+	Qase(testCaseID, report)
+})
+
+// deleteTKECluster deletes the cluster from Rancher
+func deleteTKECluster(cluster *management.Cluster, client *rancher.Client) {
+	GinkgoLogr.Info(fmt.Sprintf("Cleaning up resource cluster: %s %s", cluster.Name, cluster.ID))
+	err := helper.DeleteTKEHostCluster(cluster, client)
+	Expect(err).To(BeNil())
+}
+
+// updateClusterInUpdatingState runs checks to ensure cluster in an updating state can be updated
+func updateClusterInUpdatingState(cluster *management.Cluster, client *rancher.Client, upgradeToVersion string) {
+	var err error
+	nodeCount := cluster.TKEConfig.NodePoolList[0].AutoScalingGroupPara.DesiredCapacity + increaseBy
+
+	cluster, err = helper.UpgradeClusterKubernetesVersion(cluster, upgradeToVersion, client, false)
+	Expect(err).To(BeNil())
+	Expect(cluster.TKEConfig.ClusterBasicSettings.ClusterVersion).To(Equal(upgradeToVersion))
+
+	err = clusters.WaitClusterToBeInUpgrade(client, cluster.ID)
+	Expect(err).To(BeNil())
+
+	cluster, err = helper.ScaleNodeGroup(cluster, client, nodeCount, false, false)
+	Expect(err).To(BeNil())
+
+	err = clusters.WaitClusterToBeUpgraded(client, cluster.ID)
+	Expect(err).To(BeNil())
+
+	Eventually(func() bool {
+		GinkgoLogr.Info("Waiting for the updated changes to appear in TKEStatus.UpstreamSpec ...")
+		cluster, err = client.Management.Cluster.ByID(cluster.ID)
+		Expect(err).To(BeNil())
+		for _, np := range cluster.TKEStatus.UpstreamSpec.NodePoolList {
+			if np.AutoScalingGroupPara.DesiredCapacity != nodeCount {
+				return false
+			}
+		}
+		return cluster.TKEStatus.UpstreamSpec.ClusterBasicSettings.ClusterVersion == upgradeToVersion
+	}, tools.SetTimeout(15*time.Minute), 30*time.Second).Should(BeTrue())
+}
+
+// updateCloudCredentialsCheck switches the cluster to a new cloud credential and makes sure it is still usable
+func updateCloudCredentialsCheck(cluster *management.Cluster, client *rancher.Client) {
+	cluster, _ = helpers.RotateCloudCredential(cluster, client)
+
+	if helpers.IsImport {
+		cluster.TKEConfig = cluster.TKEStatus.UpstreamSpec
+	}
+	cluster, err := helper.ScaleNodeGroup(cluster, client, cluster.TKEConfig.NodePoolList[0].AutoScalingGroupPara.DesiredCapacity+increaseBy, true, true)
+	Expect(err).To(BeNil())
+}
+
+// deleteAllNodePoolsCheck removes every node pool from the config, the operator must refuse it and keep the node pools on Tencent Cloud
+func deleteAllNodePoolsCheck(cluster *management.Cluster, client *rancher.Client) {
+	tkeClusterID := cluster.TKEStatus.UpstreamSpec.ClusterID
+	nodePools, err := helper.ListTKENodePoolsOnTencent(region, tkeClusterID)
+	Expect(err).To(BeNil())
+
+	updateFunc := func(cluster *management.Cluster) {
+		// setting this to nil will do nothing, so we set it to empty array
+		cluster.TKEConfig.NodePoolList = []management.NodePoolDetail{}
+	}
+	cluster, err = helper.UpdateCluster(cluster, client, updateFunc)
+	Expect(err).To(BeNil())
+
+	Eventually(func() bool {
+		cluster, err = client.Management.Cluster.ByID(cluster.ID)
+		Expect(err).To(BeNil())
+		return cluster.Transitioning == "error"
+	}, "5m", "5s").Should(BeTrue())
+
+	Consistently(func() int {
+		tkeNodePools, err := helper.ListTKENodePoolsOnTencent(region, tkeClusterID)
+		Expect(err).To(BeNil())
+		return len(tkeNodePools)
+	}, "1m", "15s").Should(Equal(len(nodePools)))
+}
+
+// duplicateNodePoolNameCheck adds a node pool named after an existing one, the operator must refuse it
+func duplicateNodePoolNameCheck(cluster *management.Cluster, client *rancher.Client) {
+	tkeClusterID := cluster.TKEStatus.UpstreamSpec.ClusterID
+	nodePools, err := helper.ListTKENodePoolsOnTencent(region, tkeClusterID)
+	Expect(err).To(BeNil())
+
+	updateFunc := func(cluster *management.Cluster) {
+		duplicate := cluster.TKEConfig.NodePoolList[0]
+		duplicate.NodePoolID = ""
+		cluster.TKEConfig.NodePoolList = append(cluster.TKEConfig.NodePoolList, duplicate)
+	}
+	cluster, err = helper.UpdateCluster(cluster, client, updateFunc)
+	Expect(err).To(BeNil())
+
+	Eventually(func() bool {
+		cluster, err = client.Management.Cluster.ByID(cluster.ID)
+		Expect(err).To(BeNil())
+		return cluster.Transitioning == "error"
+	}, "5m", "5s").Should(BeTrue())
+
+	tkeNodePools, err := helper.ListTKENodePoolsOnTencent(region, tkeClusterID)
+	Expect(err).To(BeNil())
+	Expect(tkeNodePools).To(HaveLen(len(nodePools)))
+}
+
+func syncK8sVersionUpgradeCheck(cluster *management.Cluster, client *rancher.Client, upgradeToVersion string) {
+	GinkgoLogr.Info("Upgrading cluster to version:" + upgradeToVersion)
+
+	err := helper.UpgradeTKEClusterOnTencent(region, cluster.TKEStatus.UpstreamSpec.ClusterID, upgradeToVersion)
+	Expect(err).To(BeNil())
+
+	Eventually(func() string {
+		GinkgoLogr.Info("Waiting for k8s upgrade to appear in TKEStatus.UpstreamSpec ...")
+		cluster, err = client.Management.Cluster.ByID(cluster.ID)
+		Expect(err).To(BeNil())
+		return cluster.TKEStatus.UpstreamSpec.ClusterBasicSettings.ClusterVersion
+	}, tools.SetTimeout(10*time.Minute), 10*time.Second).Should(Equal(upgradeToVersion), "Failed while waiting for k8s upgrade to appear in TKEStatus.UpstreamSpec")
+
+	if !helpers.IsImport {
+		// For imported clusters, TKEConfig only holds the values edited in Rancher; so we check TKEConfig only when testing provisioned clusters
+		Expect(cluster.TKEConfig.ClusterBasicSettings.ClusterVersion).To(Equal(upgradeToVersion))
+	}
+}
+
+func syncTencentToRancherCheck(cluster *management.Cluster, client *rancher.Client, upgradeToVersion string) {
+	var err error
+	tkeClusterID := cluster.TKEStatus.UpstreamSpec.ClusterID
+
+	By("upgrading control plane", func() {
+		syncK8sVersionUpgradeCheck(cluster, client, upgradeToVersion)
+	})
+
+	By("scaling up the node pool", func() {
+		nodePool := cluster.TKEStatus.UpstreamSpec.NodePoolList[0]
+		nodeCount := nodePool.AutoScalingGroupPara.DesiredCapacity + increaseBy
+		err = helper.ScaleTKENodePoolOnTencent(region, tkeClusterID, nodePool.NodePoolID, nodeCount)
+		Expect(err).To(BeNil())
+
+		Eventually(func() bool {
+			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+			Expect(err).To(BeNil())
+			updated := cluster.TKEStatus.UpstreamSpec.NodePoolList[0].AutoScalingGroupPara.DesiredCapacity == nodeCount
+			if !helpers.IsImport {
+				updated = updated && cluster.TKEConfig.NodePoolList[0].AutoScalingGroupPara.DesiredCapacity == nodeCount
+			}
+			return updated
+		}, "10m", "10s").Should(BeTrue(), "Timed out waiting for node pool scale to show in Rancher")
+	})
+
+	By("deleting a node pool", func() {
+		if helpers.IsImport {
+			cluster.TKEConfig = cluster.TKEStatus.UpstreamSpec
+		}
+		cluster, err = helper.AddNodePool(cluster, increaseBy, client, true, true)
+		Expect(err).To(BeNil())
+		upstreamNodePools := cluster.TKEStatus.UpstreamSpec.NodePoolList
+		nodePool := upstreamNodePools[len(upstreamNodePools)-1]
+
+		err = helper.DeleteTKENodePoolOnTencent(region, tkeClusterID, nodePool.NodePoolID)
+		Expect(err).To(BeNil())
+
+		Eventually(func() bool {
+			cluster, err = client.Management.Cluster.ByID(cluster.ID)
+			Expect(err).To(BeNil())
+			nodePools := cluster.TKEStatus.UpstreamSpec.NodePoolList
+			if !helpers.IsImport {
+				nodePools = append(nodePools, cluster.TKEConfig.NodePoolList...)
+			}
+			for _, np := range nodePools {
+				if np.Name == nodePool.Name {
+					return false
+				}
+			}
+			return true
+		}, "10m", "10s").Should(BeTrue(), "Timed out waiting for node pool to delete from Rancher")
+	})
+}
+
+func syncRancherToTencentCheck(cluster *management.Cluster, client *rancher.Client, upgradeToVersion string) {
+	var err error
+	tkeClusterID := cluster.TKEStatus.UpstreamSpec.ClusterID
+	if helpers.IsImport {
+		cluster.TKEConfig = cluster.TKEStatus.UpstreamSpec
+	}
+	currentNodePoolNumber := len(cluster.TKEConfig.NodePoolList)
+	initialNodeCount := cluster.TKEConfig.NodePoolList[0].AutoScalingGroupPara.DesiredCapacity
+
+	By("upgrading control plane", func() {
+		syncK8sVersionUpgradeCheck(cluster, client, upgradeToVersion)
+	})
+
+	By("scaling up the node pool", func() {
+		// fetch the cluster again so that the update does not revert the upgrade
+		cluster, err = client.Management.Cluster.ByID(cluster.ID)
+		Expect(err).To(BeNil())
+		if helpers.IsImport {
+			cluster.TKEConfig = cluster.TKEStatus.UpstreamSpec
+		}
+		cluster, err = helper.ScaleNodeGroup(cluster, client, initialNodeCount+increaseBy, true, true)
+		Expect(err).To(BeNil())
+
+		// Verify the new edits reflect in Tencent Cloud and existing details do NOT change
+		tkeNodePools, err := helper.ListTKENodePoolsOnTencent(region, tkeClusterID)
+		Expect(err).To(BeNil())
+		Expect(tkeNodePools).To(HaveLen(currentNodePoolNumber))
+		for _, np := range tkeNodePools {
+			Expect(np.DesiredNodesNum).To(Equal(initialNodeCount + increaseBy))
+		}
+	})
+
+	By("adding a node pool", func() {
+		cluster, err = helper.AddNodePool(cluster, increaseBy, client, true, true)
+		Expect(err).To(BeNil())
+
+		// Verify the existing details do NOT change in Rancher
+		Expect(cluster.TKEStatus.UpstreamSpec.ClusterBasicSettings.ClusterVersion).To(Equal(upgradeToVersion))
+
+		// Verify the new edits reflect in Tencent Cloud
+		tkeNodePools, err := helper.ListTKENodePoolsOnTencent(region, tkeClusterID)
+		Expect(err).To(BeNil())
+		Expect(tkeNodePools).To(HaveLen(currentNodePoolNumber + increaseBy))
+	})
+}
@@ -0,0 +1,81 @@
+/*
+Copyright © 2022 - 2025 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package p1_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/tke/helper"
+)
+
+var _ = Describe("SyncImport", func() {
+	var (
+		cluster    *management.Cluster
+		k8sVersion string
+		// tkeClusterID is the ID of the cluster on Tencent Cloud, it is needed to import and delete it
+		tkeClusterID string
+	)
+
+	AfterEach(func() {
+		if ctx.ClusterCleanup && (cluster != nil && cluster.ID != "") {
+			deleteTKECluster(cluster, ctx.RancherAdminClient)
+			err := helper.DeleteTKEClusterOnTencent(region, tkeClusterID)
+			Expect(err).To(BeNil())
+		} else {
+			GinkgoLogr.Info(fmt.Sprintf("Skipping downstream cluster deletion: %s", clusterName))
+		}
+	})
+
+	When("a cluster is imported for sync", func() {
+		var upgradeToVersion string
+		BeforeEach(func() {
+			if helpers.SkipUpgradeTests {
+				Skip(helpers.SkipUpgradeTestsLog)
+			}
+			var err error
+			k8sVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, true)
+			Expect(err).To(BeNil())
+			upgradeToVersion, err = helper.GetK8sVersion(ctx.RancherAdminClient, false)
+			Expect(err).To(BeNil())
+			GinkgoLogr.Info(fmt.Sprintf("Using kubernetes version %s for cluster %s", k8sVersion, clusterName))
+			tkeClusterID, err = helper.CreateTKEClusterOnTencent(region, clusterName, k8sVersion, 214, helpers.GetCommonMetadataLabels(), nil)
+			Expect(err).To(BeNil())
+
+			cluster, err = helper.ImportTKEHostedCluster(ctx.RancherAdminClient, clusterName, ctx.CloudCredID, tkeClusterID, region)
+			Expect(err).To(BeNil())
+			cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+			Expect(err).To(BeNil())
+		})
+
+		It("Upgrade k8s version of cluster from TKE and verify it is synced back to Rancher", func() {
+			By("upgrading the control plane", func() {
+				syncK8sVersionUpgradeCheck(cluster, ctx.RancherAdminClient, upgradeToVersion)
+			})
+		})
+
+		It("Sync from Tencent Cloud console to Rancher", func() {
+			syncTencentToRancherCheck(cluster, ctx.RancherAdminClient, upgradeToVersion)
+		})
+
+		It("Sync from Rancher to Tencent Cloud console after a sync from Tencent Cloud console to Rancher", func() {
+			syncRancherToTencentCheck(cluster, ctx.RancherAdminClient, upgradeToVersion)
+		})
+	})
+})
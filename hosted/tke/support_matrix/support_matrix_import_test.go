@@ -0,0 +1,79 @@
+/*
+Copyright © 2022 - 2025 SUSE LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package support_matrix_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"fmt"
+
+	management "github.com/rancher/shepherd/clients/rancher/generated/management/v3"
+	namegen "github.com/rancher/shepherd/pkg/namegenerator"
+
+	"github.com/rancher/hosted-providers-e2e/hosted/helpers"
+	"github.com/rancher/hosted-providers-e2e/hosted/tke/helper"
+)
+
+var _ = Describe("SupportMatrixImport", func() {
+
+	for i, version := range availableVersionList {
+		version := version
+		// the clusters run in parallel, each of them needs its own container CIDR
+		id := int64(70 + i)
+
+		When(fmt.Sprintf("a cluster is created with kubernetes version %s", version), func() {
+			var (
+				clusterName string
+				// tkeClusterID is the ID of the cluster on Tencent Cloud, it is needed to import and delete it
+				tkeClusterID string
+				cluster      *management.Cluster
+			)
+			BeforeEach(func() {
+				clusterName = namegen.AppendRandomString(helpers.ClusterNamePrefix)
+				helpers.GuardClusterCost(clusterName)
+				var err error
+				tkeClusterID, err = helper.CreateTKEClusterOnTencent(region, clusterName, version, id, helpers.GetCommonMetadataLabels(), nil)
+				Expect(err).To(BeNil())
+				cluster, err = helper.ImportTKEHostedCluster(ctx.StdUserClient, clusterName, ctx.CloudCredID, tkeClusterID, region)
+				Expect(err).To(BeNil())
+				// Requires RancherAdminClient
+				cluster, err = helpers.WaitUntilClusterIsReady(cluster, ctx.RancherAdminClient)
+				Expect(err).To(BeNil())
+			})
+			AfterEach(func() {
+				if ctx.ClusterCleanup {
+					if cluster != nil {
+						err := helper.DeleteTKEHostCluster(cluster, ctx.StdUserClient)
+						Expect(err).To(BeNil())
+					}
+					if tkeClusterID != "" {
+						err := helper.DeleteTKEClusterOnTencent(region, tkeClusterID)
+						Expect(err).To(BeNil())
+					}
+				} else {
+					fmt.Println("Skipping downstream cluster deletion: ", clusterName)
+				}
+			})
+
+			It("should successfully import the cluster", func() {
+				// Report to Qase
+				testCaseID = 70
+
+				helpers.ClusterIsReadyChecks(cluster, ctx.StdUserClient, clusterName)
+			})
+		})
+	}
+})
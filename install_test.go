@@ -15,9 +15,6 @@ limitations under the License.
 package e2e_test
 
 import (
-	"fmt"
-	"time"
-
 	. "github.com/onsi/ginkgo/v2"
 	"github.com/rancher-sandbox/ele-testhelpers/kubectl"
 
@@ -28,17 +25,23 @@ var _ = Describe("Provision k3s cluster and Rancher", Label("install"), func() {
 	k := kubectl.New()
 
 	It("Install upstream k3s cluster", func() {
+		if environment.Airgap.Enabled {
+			By("Preparing airgap artifacts and registry", func() {
+				helpers.PrepareAirgap(environment)
+			})
+		}
+
 		By("Installing K3S", func() {
-			helpers.InstallK3S(k, k3sVersion, proxy, proxyHost)
+			helpers.InstallK3S(k, environment)
 		})
 
 		By("Installing CertManager", func() {
-			helpers.InstallCertManager(k, proxy, proxyHost)
+			helpers.InstallCertManager(k, environment)
 		})
 
 		if skipInstallRancher != "true" {
 			By("Installing Rancher Manager", func() {
-				helpers.InstallRancherManager(k, rancherHostname, rancherChannel, rancherVersion, rancherHeadVersion, proxy, nightlyChart)
+				helpers.InstallRancherManager(k, environment)
 			})
 
 			By("Checking Rancher Deployments", func() {
@@ -46,20 +49,13 @@ var _ = Describe("Provision k3s cluster and Rancher", Label("install"), func() {
 			})
 		} else {
 			GinkgoLogr.Info("Skipping Rancher Manager installation; SKIP_RANCHER_INSTALL=\"true\"")
-		}
-
-		if nightlyChart == "enabled" {
-			By(fmt.Sprintf("Install nightly rancher-%s-operator via Helm", providerOperator), func() {
-				// Get the current date to use as the build date
-				buildDate := time.Now().Format("20060102")
 
-				RunHelmCmdWithRetry("upgrade", "--install", "rancher-"+providerOperator+"-operator-crds",
-					"oci://ghcr.io/rancher/rancher-"+providerOperator+"-operator-crd-chart/rancher-"+providerOperator+"-operator-crd",
-					"--version", buildDate)
-				RunHelmCmdWithRetry("upgrade", "--install", "rancher-"+providerOperator+"-operator",
-					"oci://ghcr.io/rancher/rancher-"+providerOperator+"-operator-chart/rancher-"+providerOperator+"-operator",
-					"--version", buildDate, "--namespace", "cattle-system")
-			})
+			if environment.NightlyOperatorCharts {
+				helpers.InstallNightlyOperatorCharts()
+			}
+			if environment.OperatorOverride.Enabled() {
+				helpers.InstallOperatorOverride(environment.OperatorOverride)
+			}
 		}
 	})
 })